@@ -1,12 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"golangTestTask/configs"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/fx"
 	"golangTestTask/internal/handler"
+	"golangTestTask/internal/metrics"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/quote"
 	"golangTestTask/internal/repository"
+	"golangTestTask/internal/server"
 	"golangTestTask/internal/service"
+	"golangTestTask/pkg/money"
 	"log"
-	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	_ "golangTestTask/docs"
 )
@@ -16,7 +29,18 @@ import (
 // @description API для управления транзакциями и кошельками
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Токен доступа в формате "Bearer <token>"
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		os.Exit(reconcile(os.Args[2:]))
+	}
+
 	config, err := configs.LoadConfig()
 	if err != nil {
 		log.Fatal(err)
@@ -28,12 +52,104 @@ func main() {
 	if err := repository.Migrate(db); err != nil {
 		log.Fatal(err)
 	}
+	tokens, err := auth.LoadTokenIssuer(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	quotes, err := quote.LoadSigner(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rates, err := fx.LoadProvider(config)
+	if err != nil {
+		log.Fatal(err)
+	}
 	repos := repository.NewRepository(db)
-	services := service.NewService(repos)
-	handlers := handler.NewHandler(services)
+	services := service.NewService(repos, config, tokens, quotes, rates)
+	handlers := handler.NewHandler(services, config)
+
+	metrics.RegisterDBStats(db, config.DBName)
+	metrics.RegisterWalletStats(services.GetWalletStats)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	services.BaseWallets(10, 100)
+	if config.AdminAPIKey != "" {
+		if err := services.EnsureAPIKey(ctx, "admin", config.AdminAPIKey, []string{auth.ScopeAdmin}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	services.BaseWallets(ctx, 10, money.FromInt(100), config.BaseWalletCurrencies)
+	// Кошельки комиссий создаются после базовых кошельков, иначе BaseWallets сочтет их уже созданными.
+	// Существующий кошелек комиссий может иметь устаревший адрес, с которым новый кошелек создать нельзя,
+	// поэтому кошелек создается, только если его еще нет.
+	for currency, address := range config.FeeWallets {
+		if _, err := services.GetWallet(ctx, address); errors.Is(err, domain.ErrWalletNotFound) {
+			_, err = services.CreateWallet(ctx, models.Wallet{Address: address, Currency: currency})
+			if err != nil && !errors.Is(err, domain.ErrWalletAlreadyExists) {
+				log.Fatal(err)
+			}
+		} else if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		services.RunIdempotencySweeper(workersCtx, config.IdempotencySweepInterval)
+	}()
+	if config.ReconcileInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			services.RunReconciler(workersCtx, config.ReconcileInterval)
+		}()
+	}
+	if config.SchedulerInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			services.RunScheduler(workersCtx, config.SchedulerInterval)
+		}()
+	}
+	if config.HoldExpiryInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			services.RunHoldExpirer(workersCtx, config.HoldExpiryInterval)
+		}()
+	}
+
+	srv := server.NewServer(config, handlers.InitRoutes())
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.Run()
+	}()
+	log.Printf("Server started on %s", config.HTTPAddr)
 
-	log.Println("Server started on :8080")
-	log.Fatal(http.ListenAndServe(":8080", handlers.InitRoutes()))
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down")
+	case err := <-serverErr:
+		log.Printf("Server stopped: %v", err)
+	}
+
+	// Сначала дожидаемся завершения обрабатываемых запросов, затем останавливаем фоновые задачи
+	// и только после этого закрываем соединения с БД, которыми пользуются и те, и другие.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to drain in-flight requests: %v", err)
+	}
+
+	stopWorkers()
+	workers.Wait()
+
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+	log.Println("Server stopped")
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"golangTestTask/configs"
+	"golangTestTask/internal/repository"
+	"golangTestTask/internal/service"
+	"log"
+	"os"
+)
+
+// Коды завершения подкоманды reconcile.
+const (
+	exitConsistent    = 0
+	exitError         = 1
+	exitDiscrepancies = 2
+)
+
+// reconcile выполняет подкоманду reconcile: однократно сверяет балансы кошельков с журналом и выводит отчет
+// в stdout в формате JSON или CSV. Возвращает exitDiscrepancies, если найдены расхождения, чтобы сверку
+// можно было запускать из cron или CI и реагировать на код завершения.
+func reconcile(args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	format := flags.String("format", "json", "формат отчета: json или csv")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "unknown report format %q: must be json or csv\n", *format)
+		return exitError
+	}
+
+	config, err := configs.LoadConfig()
+	if err != nil {
+		log.Print(err)
+		return exitError
+	}
+	db, err := repository.NewPostgresDB(config)
+	if err != nil {
+		log.Print(err)
+		return exitError
+	}
+	defer db.Close()
+	// Сверка только читает данные, поэтому не применяет миграции, а отказывается работать со схемой старой версии.
+	if err := repository.CheckSchema(db); err != nil {
+		log.Print(err)
+		return exitError
+	}
+
+	ledger := service.NewLedgerService(repository.NewRepository(db))
+	report, err := ledger.Reconcile(context.Background())
+	if err != nil {
+		log.Print(err)
+		return exitError
+	}
+
+	if *format == "csv" {
+		err = report.WriteCSV(os.Stdout)
+	} else {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	}
+	if err != nil {
+		log.Printf("Failed to write reconciliation report: %v", err)
+		return exitError
+	}
+
+	if !report.Consistent() {
+		return exitDiscrepancies
+	}
+	return exitConsistent
+}
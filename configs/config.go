@@ -1,19 +1,102 @@
 package configs
 
 import (
+	"fmt"
+	"golangTestTask/internal/fee"
+	"golangTestTask/pkg/address"
+	"golangTestTask/pkg/money"
 	"log"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
+	// HTTPAddr — адрес, на котором HTTP-сервер принимает соединения.
+	HTTPAddr              string
+	HTTPReadTimeout       time.Duration
+	HTTPReadHeaderTimeout time.Duration
+	// HTTPWriteTimeout должен превышать DBRequestTimeout, иначе ответ на долгий запрос не успеет дойти до клиента.
+	HTTPWriteTimeout   time.Duration
+	HTTPIdleTimeout    time.Duration
+	HTTPMaxHeaderBytes int
+	// ShutdownTimeout — время, которое дается обрабатываемым запросам на завершение при остановке сервиса.
+	ShutdownTimeout time.Duration
+
 	DBHost     string
 	DBPort     string
 	DBUsername string
 	DBPassword string
 	DBName     string
 	DBSSLMode  string
+	// DBRequestTimeout — максимальное время обработки одного HTTP-запроса вместе с запросами к БД; 0 отключает ограничение.
+	DBRequestTimeout time.Duration
+
+	// RateLimitRPS — число запросов в секунду, доступное одному клиенту; 0 отключает ограничение.
+	// RateLimitBurst — сколько запросов клиент может отправить подряд сверх этой скорости.
+	RateLimitRPS   float64
+	RateLimitBurst int
+	// IPRateLimitRPS и IPRateLimitBurst — то же для всех запросов с одного IP-адреса, включая запросы с неверными
+	// учетными данными; ограничение проверяется до аутентификации. 0 отключает ограничение.
+	IPRateLimitRPS   float64
+	IPRateLimitBurst int
+	// TransferMaxPerMinute — максимальное число переводов с одного кошелька за минуту; 0 отключает ограничение.
+	TransferMaxPerMinute int
+	// TransferMaxDailyVolume — максимальная сумма переводов с одного кошелька за сутки (UTC) в money.DefaultCurrency;
+	// переводы в других валютах пересчитываются по среднему курсу. 0 отключает ограничение.
+	TransferMaxDailyVolume money.Amount
+
+	// FeeSchedule — тариф комиссии за перевод; нулевое значение отключает комиссию.
+	// FeeSchedules — тарифы для переводов с кошельков в отдельных валютах, заменяющие FeeSchedule.
+	FeeSchedule  fee.Schedule
+	FeeSchedules map[string]fee.Schedule
+	// FeeWallets — адреса системных кошельков комиссий по валютам: комиссия зачисляется на кошелек в валюте отправителя.
+	FeeWallets map[string]string
+
+	// BaseWalletCurrencies — валюты, в каждой из которых при первом запуске создаются тестовые кошельки.
+	BaseWalletCurrencies []string
+
+	// QuoteSecretFile — путь к файлу с секретом HMAC для подписи предварительных расчетов перевода.
+	QuoteSecretFile string
+	// QuoteTTL — срок действия предварительного расчета перевода.
+	QuoteTTL time.Duration
+
+	// FXRatesFile — путь к файлу с таблицей курсов обмена; без него переводы с конвертацией недоступны.
+	FXRatesFile string
+
+	// IdempotencyTTL — срок хранения ключей идемпотентности и ответов на запросы с ними.
+	IdempotencyTTL time.Duration
+	// IdempotencySweepInterval — период удаления истекших ключей идемпотентности.
+	IdempotencySweepInterval time.Duration
+
+	// ReconcileInterval — период сверки балансов кошельков с журналом; 0 отключает сверку по расписанию.
+	ReconcileInterval time.Duration
+
+	// SchedulerInterval — период проверки наступивших регулярных переводов; 0 отключает их выполнение.
+	SchedulerInterval time.Duration
+	// HoldExpiryInterval — период отметки истекших блокировок средств; 0 отключает ее.
+	// Истекшие блокировки не учитываются в заблокированных средствах и без отметки.
+	HoldExpiryInterval time.Duration
+
+	// AdminAPIKey — ключ API с областью доступа admin, который сохраняется в БД при запуске, если его там еще нет.
+	// Нужен для первичной настройки: выдачи остальных ключей через POST /api/keys.
+	AdminAPIKey string
+
+	// JWTSigningMethod — алгоритм подписи токенов доступа: HS256 или RS256.
+	JWTSigningMethod string
+	// JWTSecretFile — путь к файлу с секретом HMAC для HS256.
+	JWTSecretFile string
+	// JWTPrivateKeyFile и JWTPublicKeyFile — пути к ключам RSA в формате PEM для RS256.
+	JWTPrivateKeyFile string
+	JWTPublicKeyFile  string
+	JWTIssuer         string
+	// JWTAccessTTL — срок действия токена доступа, JWTRefreshTTL — токена обновления.
+	JWTAccessTTL  time.Duration
+	JWTRefreshTTL time.Duration
 }
 
 // LoadConfig загружает конфигурацию из .env файла или переменных окружения
@@ -26,19 +109,229 @@ func LoadConfig() (Config, error) {
 		}
 	}
 
+	baseWalletCurrencies := make([]string, 0)
+	for _, code := range strings.Split(getEnv("BASE_WALLET_CURRENCIES", money.DefaultCurrency), ",") {
+		currency, ok := money.LookupCurrency(strings.TrimSpace(code))
+		if !ok {
+			return Config{}, fmt.Errorf("BASE_WALLET_CURRENCIES: unsupported currency %q", code)
+		}
+		baseWalletCurrencies = append(baseWalletCurrencies, currency.Code)
+	}
+	fxRatesFile := getEnv("FX_RATES_FILE", "")
+	if fxRatesFile == "" && slices.ContainsFunc(baseWalletCurrencies, func(code string) bool { return code != money.DefaultCurrency }) {
+		return Config{}, fmt.Errorf("FX_RATES_FILE is required for BASE_WALLET_CURRENCIES other than %s: transfer limits are set in %s",
+			money.DefaultCurrency, money.DefaultCurrency)
+	}
+
+	feeSchedule, feeSchedules, feeWallets, err := loadFees(baseWalletCurrencies)
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
+		HTTPAddr:              getEnv("HTTP_ADDR", ":8080"),
+		HTTPReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", 10*time.Second),
+		HTTPReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		HTTPWriteTimeout:      getEnvDuration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		HTTPIdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		HTTPMaxHeaderBytes:    getEnvInt("HTTP_MAX_HEADER_BYTES", 1<<20),
+		ShutdownTimeout:       getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUsername: getEnv("DB_USERNAME", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "postgres"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+		DBRequestTimeout: getEnvDuration("DB_REQUEST_TIMEOUT", 5*time.Second),
+
+		RateLimitRPS:           getEnvFloat("RATE_LIMIT_RPS", 10),
+		RateLimitBurst:         getEnvInt("RATE_LIMIT_BURST", 20),
+		IPRateLimitRPS:         getEnvFloat("IP_RATE_LIMIT_RPS", 50),
+		IPRateLimitBurst:       getEnvInt("IP_RATE_LIMIT_BURST", 100),
+		TransferMaxPerMinute:   getEnvInt("TRANSFER_MAX_PER_MINUTE", 30),
+		TransferMaxDailyVolume: getEnvAmount("TRANSFER_MAX_DAILY_VOLUME", money.FromInt(10000)),
+
+		FeeSchedule:  feeSchedule,
+		FeeSchedules: feeSchedules,
+		FeeWallets:   feeWallets,
+
+		BaseWalletCurrencies: baseWalletCurrencies,
+
+		QuoteSecretFile: getEnv("QUOTE_SECRET_FILE", ""),
+		QuoteTTL:        getEnvDuration("QUOTE_TTL", time.Minute),
+
+		FXRatesFile: fxRatesFile,
+
+		IdempotencyTTL:           getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencySweepInterval: getEnvDuration("IDEMPOTENCY_SWEEP_INTERVAL", time.Hour),
+
+		ReconcileInterval: getEnvDuration("RECONCILE_INTERVAL", time.Hour),
+
+		SchedulerInterval:  getEnvDuration("SCHEDULER_INTERVAL", time.Minute),
+		HoldExpiryInterval: getEnvDuration("HOLD_EXPIRY_INTERVAL", time.Minute),
+
+		AdminAPIKey: getEnv("ADMIN_API_KEY", ""),
+
+		JWTSigningMethod:  getEnv("JWT_SIGNING_METHOD", "HS256"),
+		JWTSecretFile:     getEnv("JWT_SECRET_FILE", ""),
+		JWTPrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTPublicKeyFile:  getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTIssuer:         getEnv("JWT_ISSUER", "payment-system"),
+		JWTAccessTTL:      getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+		JWTRefreshTTL:     getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
 	}, nil
 }
 
+// FeeScheduleFor возвращает тариф комиссии за переводы с кошельков в валюте currency.
+func (c Config) FeeScheduleFor(currency string) fee.Schedule {
+	if schedule, ok := c.FeeSchedules[currency]; ok {
+		return schedule
+	}
+	return c.FeeSchedule
+}
+
+// FeesEnabled сообщает, взимается ли комиссия за переводы хотя бы в одной валюте.
+func (c Config) FeesEnabled() bool {
+	if c.FeeSchedule.Kind != fee.KindNone {
+		return true
+	}
+	for _, schedule := range c.FeeSchedules {
+		if schedule.Kind != fee.KindNone {
+			return true
+		}
+	}
+	return false
+}
+
+// loadFees читает тарифы комиссии и кошельки комиссий: общий тариф FEE_SCHEDULE, тарифы FEE_SCHEDULE_<валюта>
+// для отдельных валют и кошельки FEE_WALLETS в формате "USD:адрес,EUR:адрес". Для совместимости кошелек комиссий
+// можно задать и парой FEE_WALLET_ADDRESS и FEE_WALLET_CURRENCY. Комиссия за перевод зачисляется на кошелек
+// в валюте отправителя, поэтому каждая валюта из baseWalletCurrencies или FEE_SCHEDULE_<валюта>, в которой
+// взимается комиссия, должна иметь кошелек комиссий.
+func loadFees(baseWalletCurrencies []string) (fee.Schedule, map[string]fee.Schedule, map[string]string, error) {
+	schedule, err := fee.Parse(os.Getenv("FEE_SCHEDULE"))
+	if err != nil {
+		return fee.Schedule{}, nil, nil, fmt.Errorf("FEE_SCHEDULE: %w", err)
+	}
+
+	schedules := make(map[string]fee.Schedule)
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		code, ok := strings.CutPrefix(key, "FEE_SCHEDULE_")
+		if !ok {
+			continue
+		}
+		currency, ok := money.LookupCurrency(code)
+		if !ok {
+			return fee.Schedule{}, nil, nil, fmt.Errorf("%s: unsupported currency %q", key, code)
+		}
+		if schedules[currency.Code], err = fee.Parse(value); err != nil {
+			return fee.Schedule{}, nil, nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	wallets := make(map[string]string)
+	addWallet := func(key string, code string, wallet string) error {
+		currency, ok := money.LookupCurrency(code)
+		if !ok {
+			return fmt.Errorf("%s: unsupported currency %q", key, code)
+		}
+		if err := address.ValidateFormat(wallet); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if _, ok := wallets[currency.Code]; ok {
+			return fmt.Errorf("%s: duplicate fee wallet for %s", key, currency.Code)
+		}
+		wallets[currency.Code] = wallet
+		return nil
+	}
+	if value := os.Getenv("FEE_WALLETS"); value != "" {
+		for _, entry := range strings.Split(value, ",") {
+			code, wallet, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			if !ok {
+				return fee.Schedule{}, nil, nil, fmt.Errorf("FEE_WALLETS: entry %q must be CURRENCY:ADDRESS", entry)
+			}
+			if err := addWallet("FEE_WALLETS", code, wallet); err != nil {
+				return fee.Schedule{}, nil, nil, err
+			}
+		}
+	}
+	if wallet := os.Getenv("FEE_WALLET_ADDRESS"); wallet != "" {
+		if err := addWallet("FEE_WALLET_ADDRESS", getEnv("FEE_WALLET_CURRENCY", money.DefaultCurrency), wallet); err != nil {
+			return fee.Schedule{}, nil, nil, err
+		}
+	}
+
+	config := Config{FeeSchedule: schedule, FeeSchedules: schedules}
+	currencies := slices.Clone(baseWalletCurrencies)
+	for code := range schedules {
+		currencies = append(currencies, code)
+	}
+	for _, code := range currencies {
+		if config.FeeScheduleFor(code).Kind != fee.KindNone && wallets[code] == "" {
+			return fee.Schedule{}, nil, nil, fmt.Errorf("FEE_WALLETS: fee wallet for %s is required when fees are charged in %s", code, code)
+		}
+	}
+	return schedule, schedules, wallets, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid integer %q in %s, using default %d\n", value, key, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid duration %q in %s, using default %s\n", value, key, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Warning: invalid number %q in %s, using default %g\n", value, key, defaultValue)
+		return defaultValue
+	}
+	return f
+}
+
+func getEnvAmount(key string, defaultValue money.Amount) money.Amount {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	a, err := money.Parse(value)
+	if err != nil {
+		log.Printf("Warning: invalid amount %q in %s, using default %s\n", value, key, defaultValue)
+		return defaultValue
+	}
+	return a
+}
@@ -15,189 +15,2622 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/send": {
+        "/api/auth/login": {
             "post": {
-                "description": "Переводит денежные средства с одного кошелька на другой",
+                "description": "Проверяет имя и пароль пользователя и выдает токен доступа (JWT) и токен обновления",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Отправить денежные средства",
+                "summary": "Войти в систему",
                 "parameters": [
                     {
-                        "description": "Данные транзакции",
-                        "name": "transaction",
+                        "description": "Имя и пароль",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTransactionRequest"
+                            "$ref": "#/definitions/models.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.StatusResponse"
+                            "$ref": "#/definitions/models.TokenPair"
                         }
                     },
                     "400": {
                         "description": "Invalid request payload",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Wallet not found",
+                    "401": {
+                        "description": "Invalid username or password",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/transactions": {
-            "get": {
-                "description": "Возвращает N последних по времени переводов средств",
+        "/api/auth/logout": {
+            "post": {
+                "description": "Отзывает токен обновления. Токен доступа остается действительным до истечения срока действия",
+                "consumes": [
+                    "application/json"
+                ],
+                "summary": "Выйти из системы",
+                "parameters": [
+                    {
+                        "description": "Токен обновления",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/refresh": {
+            "post": {
+                "description": "Обменивает токен обновления на новую пару токенов; использованный токен обновления отзывается",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Получить последние транзакции",
+                "summary": "Обновить токены",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Количество транзакций",
-                        "name": "count",
-                        "in": "query"
+                        "description": "Токен обновления",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
+                            "$ref": "#/definitions/models.TokenPair"
                         }
                     },
                     "400": {
                         "description": "Invalid request payload",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/wallet/{address}/balance": {
-            "get": {
-                "description": "Возвращает баланс по адресу кошелька",
+        "/api/holds": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Блокирует сумму на кошельке отправителя для последующего перевода получателю (первая фаза двухфазного перевода).\nЗаблокированные средства остаются на кошельке, но недоступны для других переводов и блокировок,\nпока блокировка не будет списана (POST /api/holds/{id}/capture), отменена (POST /api/holds/{id}/void)\nили не истечет ее срок expires_in (по умолчанию 24 часа, не более 30 дней).\nКомиссия и ограничения на переводы применяются при списании. Поддерживает заголовок Idempotency-Key, как POST /api/send.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Получить баланс кошелька",
+                "summary": "Заблокировать средства",
                 "parameters": [
+                    {
+                        "description": "Данные блокировки",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateHoldRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Адрес кошелька",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
+                        "description": "Ключ идемпотентности: повторный запрос того же клиента с тем же ключом вернет исходный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Wallet"
+                            "$ref": "#/definitions/models.Hold"
                         }
                     },
                     "400": {
-                        "description": "Invalid address",
+                        "description": "Invalid request payload, wallet address or expiry, same wallet, insufficient available funds or amount too precise for the currency",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Wallet not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is frozen or closed, or request with this idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key reused with a different request, wallet currencies differ without conversion or no exchange rate",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/wallets": {
+        "/api/holds/{id}": {
             "get": {
-                "description": "Возвращает все кошельки из БД",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает блокировку средств: сумму, статус (active, captured, voided или expired), срок действия\nи, для списанной блокировки, списанную сумму и транзакцию перевода.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Получить список всех кошельков (для удобства проверки работоспособности API проверяющими)",
+                "summary": "Получить блокировку средств",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID блокировки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Wallet"
-                            }
+                            "$ref": "#/definitions/models.Hold"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hold ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or wallets are not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Hold not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CreateTransactionRequest": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "number",
-                    "minimum": 0.01,
-                    "example": 10
-                },
-                "from": {
-                    "type": "string",
-                    "example": "e240d825d255af751f5f55af8d9671be"
-                },
-                "to": {
-                    "type": "string",
-                    "example": "abdf2236c0a3b4e2639b3e182d994c88e"
+        },
+        "/api/holds/{id}/capture": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переводит получателю всю заблокированную сумму или ее часть amount (вторая фаза двухфазного перевода);\nостаток разблокируется. Перевод выполняется с теми же проверками, комиссией и ограничениями, что и POST /api/send.\nБлокировку можно списать только один раз и только до истечения ее срока.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Списать заблокированные средства",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID блокировки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Списываемая сумма; без тела списывается вся сумма блокировки",
+                        "name": "capture",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CaptureHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Hold"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hold ID or amount, amount exceeds the held amount, insufficient funds or amount outside the sender tier limits",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Hold or wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold is not active or has expired, wallet is frozen or closed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "No exchange rate, no fee wallet in the sender currency, or daily, monthly or balance limit of the wallet tier exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or wallet transfer limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "models.StatusResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Transaction completed"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "success"
+        "/api/holds/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет активную блокировку, заблокированные средства снова становятся доступны для переводов.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Отменить блокировку средств",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID блокировки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Hold"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hold ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Hold not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold is not active or has expired",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keys": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает ключ API с указанными областями доступа и передает ему во владение существующие кошельки. Значение ключа возвращается только в этом ответе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать ключ API",
+                "parameters": [
+                    {
+                        "description": "Данные ключа",
+                        "name": "key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or wallet address",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/ledger/rebuild": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет баланс каждого кошелька суммой его проводок в журнале двойной записи и возвращает количество исправленных кошельков. На время пересчета переводы блокируются",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Пересчитать балансы по журналу",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RebuildBalancesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/ledger/reconciliation": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сравнивает баланс каждого кошелька с суммой его проводок в журнале двойной записи, а ее — с балансом, восстановленным по начальным зачислениям и истории транзакций.\nВозвращает отчет о расхождениях балансов, несбалансированных записях журнала, записях, не совпадающих со своей транзакцией, и завершенных транзакциях без записи в журнале.\nДанные не изменяются. В формате CSV отчет содержит только расхождения балансов",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "summary": "Сверить балансы с журналом",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Формат отчета: json (по умолчанию) или csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReconciliationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/scheduled-transfers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает регулярные переводы с кошелька wallet от новых к старым, включая отмененные.\nБез параметра wallet возвращает переводы со всех кошельков вызывающего, а администратору — все регулярные переводы.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить регулярные переводы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Адрес кошелька отправителя",
+                        "name": "wallet",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduledTransfer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid wallet address",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает перевод, который сервис выполняет по расписанию от имени отправителя, например каждое 1-е число месяца.\nРасписание задается в формате cron из пяти полей (минута, час, день месяца, месяц, день недели) по UTC\nили сокращением @monthly, @weekly, @daily, @hourly, @every \u003cинтервал\u003e; выполнять перевод чаще раза в минуту нельзя.\nКаждое выполнение проходит те же проверки, что и POST /api/send; его итог доступен в GET /api/scheduled-transfers/{id}/runs.\nВыполнение, не удавшееся из-за временной ошибки или лимита переводов, повторяется с растущей задержкой.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать регулярный перевод",
+                "parameters": [
+                    {
+                        "description": "Данные регулярного перевода",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateScheduledTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduledTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, wallet address or schedule, same wallet or amount too precise for the currency",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is closed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Wallet currencies differ without conversion",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/scheduled-transfers/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет регулярный перевод: следующие выполнения не производятся. Уже выполненные переводы не отменяются.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Отменить регулярный перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID регулярного перевода",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduledTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid scheduled transfer ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled transfer not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheduled transfer is already cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/scheduled-transfers/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает последние 50 попыток выполнить регулярный перевод от новых к старым: срок, номер попытки, итог\n(completed, retrying или failed), созданную транзакцию и причину неудачи.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить выполнения регулярного перевода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID регулярного перевода",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduledTransferRun"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid scheduled transfer ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled transfer not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/send": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переводит денежные средства с одного кошелька на другой.\nКомиссия по тарифу сервиса списывается с отправителя сверх суммы перевода и возвращается в поле fee.\nЕсли передан quote_id из POST /api/send/quote, применяется рассчитанная в нем комиссия. Расчет одноразовый:\nповторный перевод с тем же quote_id отклоняется с кодом quote_used.\nСумма указывается в валюте кошелька отправителя. Перевод между кошельками в разных валютах выполняется только с \"convert\": true:\nполучателю зачисляется сумма по текущему курсу (или курсу из quote_id) за вычетом спреда, сведения о ней возвращаются в поле conversion.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Отправить денежные средства",
+                "parameters": [
+                    {
+                        "description": "Данные транзакции",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransactionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повторный запрос того же клиента с тем же ключом вернет исходный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, wallet address or quote, same wallet, insufficient funds, amount outside the sender tier limits or too precise for the currency",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is frozen or closed, quote has already been used, or request with this idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key reused with a different request, quote expired or issued for another transfer, wallet currencies differ without conversion or no exchange rate, no fee wallet in the sender currency, or daily, monthly or balance limit of the wallet tier exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or wallet transfer limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/send/quote": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выполняет все проверки перевода, не изменяя кошельки, и возвращает комиссию, балансы после перевода\nи подписанный идентификатор расчета. Переданный в POST /api/send до истечения срока, он гарантирует рассчитанную комиссию.\nБаланс получателя возвращается, только если вызывающий может просматривать его кошелек. Для перевода с конвертацией\nрасчет фиксирует курс, а баланс получателя указывается в его валюте.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Рассчитать перевод",
+                "parameters": [
+                    {
+                        "description": "Данные транзакции (quote_id не используется)",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferQuote"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or wallet address, same wallet, insufficient funds, amount outside the sender tier limits or too precise for the currency",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or sender wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is frozen or closed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Wallet currencies differ without conversion or no exchange rate, no fee wallet in the sender currency, or daily, monthly or balance limit of the wallet tier exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or wallet transfer limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tiers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все уровни кошельков с ограничениями на переводы. Нулевое ограничение, кроме min_transfer, означает его отсутствие",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить уровни кошельков",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WalletTier"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает уровень кошельков с ограничениями на сумму одного перевода, суммы переводов за сутки и месяц и максимальный баланс",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать уровень кошельков",
+                "parameters": [
+                    {
+                        "description": "Уровень кошельков",
+                        "name": "tier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletTier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletTier"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tier already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tiers/{name}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет ограничения уровня кошельков. Новые ограничения применяются ко всем кошелькам уровня",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Изменить уровень кошельков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя уровня",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ограничения уровня; поле name игнорируется",
+                        "name": "tier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletTier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletTier"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tier not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу истории переводов от новых к старым с фильтрами и курсором следующей страницы.\nЕсли передан параметр count, возвращает массив из count последних транзакций без постраничной выборки (устаревший режим).",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить историю транзакций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор страницы из next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 20, не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес кошелька",
+                        "name": "wallet",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "sender",
+                            "recipient"
+                        ],
+                        "type": "string",
+                        "description": "Роль кошелька в транзакции",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "completed",
+                            "failed",
+                            "reversed"
+                        ],
+                        "type": "string",
+                        "description": "Статус транзакции",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная сумма",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная сумма",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC 3339), включительно",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC 3339), не включительно",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество последних транзакций (устаревший режим)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters, wallet address or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}/reverse": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет завершенный перевод полностью или частично: создает компенсирующую транзакцию с получателя на отправителя,\nсвязанную с исходной через reversal_of, и переводит исходную транзакцию в статус reversed. Сумма возврата указывается\nв валюте отправителя; без тела запроса или без amount возвращается вся сумма перевода. Для перевода с конвертацией\nс получателя списывается сумма по курсу исходного перевода. Комиссия не возвращается. Перевод можно отменить только один раз.\nДоступно только администратору.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Отменить перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сумма возврата",
+                        "name": "reversal",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReverseTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionReversal"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid transaction ID or amount, or recipient has insufficient available funds",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction or wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction is not a completed transfer or has already been reversed, or sender wallet is closed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает пользователя с ролью customer, operator, auditor или admin и передает ему во владение существующие кошельки. Требует роли admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, wallet address or role",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/{address}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает адрес, баланс и статус кошелька",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить кошелек",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Адрес кошелька",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Wallet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/{address}/balance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает баланс по адресу кошелька: общий баланс, сумму активных блокировок средств (held)\nи доступные для переводов средства (available).",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить баланс кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Адрес кошелька",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletBalance"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/{address}/status": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Замораживает, размораживает или закрывает кошелек. Закрыть можно только кошелек с нулевым балансом, закрытый кошелек изменить нельзя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Изменить статус кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Адрес кошелька",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateWalletStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Wallet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address or status",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is closed or not empty",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/{address}/tier": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Присваивает кошельку уровень, определяющий ограничения на переводы. Уровень закрытого кошелька изменить нельзя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Изменить уровень кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Адрес кошелька",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый уровень",
+                        "name": "tier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateWalletTierRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Wallet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address or request payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet or tier not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is closed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/{address}/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу истории переводов, в которых участвовал кошелек, от новых к старым",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить историю транзакций кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Адрес кошелька",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор страницы из next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 20, не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "sender",
+                            "recipient"
+                        ],
+                        "type": "string",
+                        "description": "Роль кошелька в транзакции",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "completed",
+                            "failed",
+                            "reversed"
+                        ],
+                        "type": "string",
+                        "description": "Статус транзакции",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная сумма",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная сумма",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC 3339), включительно",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC 3339), не включительно",
+                        "name": "to_time",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address, query parameters or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet is not owned by the caller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallets": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все кошельки из БД",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Получить список всех кошельков (для удобства проверки работоспособности API проверяющими)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Wallet"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает кошелек с нулевым балансом. Если адрес не указан, он генерируется сервером; адрес, указанный клиентом, должен быть в формате с контрольной суммой. Если валюта не указана — используется USD",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Создать кошелек",
+                "parameters": [
+                    {
+                        "description": "Данные кошелька",
+                        "name": "wallet",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWalletRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Wallet"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or address, or unsupported currency",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BalanceDiscrepancy": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "difference": {
+                    "type": "string",
+                    "example": "10.00"
+                },
+                "expected": {
+                    "type": "string",
+                    "example": "90.00"
+                },
+                "ledger": {
+                    "type": "string",
+                    "example": "90.00"
+                },
+                "stored": {
+                    "type": "string",
+                    "example": "100.00"
+                }
+            }
+        },
+        "models.CaptureHoldRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "25.00"
+                }
+            }
+        },
+        "models.Conversion": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "9.61"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "0.91540000"
+                },
+                "spread_bp": {
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "models.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "merchant-42"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin"
+                    ]
+                },
+                "wallets": {
+                    "description": "Wallets — адреса существующих кошельков, которые передаются во владение ключу.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "key": {
+                    "type": "string",
+                    "example": "pk_6f1c0e4b2a9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "merchant-42"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin"
+                    ]
+                },
+                "wallets": {
+                    "description": "Wallets — адреса кошельков, принадлежащих владельцу ключа; списывать средства можно только с них.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CreateHoldRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "30.00"
+                },
+                "convert": {
+                    "description": "Convert разрешает списание на кошелек в другой валюте с конвертацией по курсу на момент списания.",
+                    "type": "boolean"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn — срок действия блокировки в секундах; если не указан, применяется срок по умолчанию.",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "from": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"
+                }
+            }
+        },
+        "models.CreateScheduledTransferRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "50.00"
+                },
+                "convert": {
+                    "description": "Convert разрешает перевод между кошельками в разных валютах с конвертацией по курсу на момент выполнения.",
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "schedule": {
+                    "description": "Schedule — расписание в формате cron из пяти полей (минута, час, день месяца, месяц, день недели) по UTC\nили одно из сокращений @monthly, @weekly, @daily, @hourly, @every \u003cинтервал\u003e.",
+                    "type": "string",
+                    "example": "0 9 1 * *"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"
+                }
+            }
+        },
+        "models.CreateTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "10.50"
+                },
+                "convert": {
+                    "description": "Convert разрешает перевод между кошельками в разных валютах с конвертацией суммы по текущему курсу.",
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "quote_id": {
+                    "description": "QuoteID — идентификатор предварительного расчета из POST /api/send/quote, гарантирующий рассчитанную комиссию.",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"
+                }
+            }
+        },
+        "models.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                },
+                "role": {
+                    "description": "Role — роль пользователя; по умолчанию customer.",
+                    "type": "string",
+                    "enum": [
+                        "customer",
+                        "operator",
+                        "auditor",
+                        "admin",
+                        "metrics"
+                    ],
+                    "example": "customer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "wallets": {
+                    "description": "Wallets — адреса существующих кошельков, которые передаются во владение пользователю.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CreateWalletRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address — адрес нового кошелька; если не указан, генерируется сервером.",
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "currency": {
+                    "description": "Currency — код валюты кошелька по ISO 4217; по умолчанию USD.",
+                    "type": "string",
+                    "example": "EUR"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code — машиночитаемый код ошибки, на который может опираться клиент.",
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "details": {
+                    "description": "Details — дополнительные сведения об ошибке, например роль и адрес кошелька или имя некорректного поля.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "insufficient funds"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f2a9c4e1b7d4a6f8e0c5b2d9a1f7e3c"
+                }
+            }
+        },
+        "models.Hold": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "30.00"
+                },
+                "captured_amount": {
+                    "type": "string",
+                    "example": "25.00"
+                },
+                "convert": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "finalized_at": {
+                    "description": "FinalizedAt — время списания, отмены или истечения блокировки.",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "status": {
+                    "enum": [
+                        "active",
+                        "captured",
+                        "voided",
+                        "expired"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HoldStatus"
+                        }
+                    ],
+                    "example": "active"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.HoldStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "captured",
+                "voided",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "HoldStatusActive",
+                "HoldStatusCaptured",
+                "HoldStatusVoided",
+                "HoldStatusExpired"
+            ]
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "alice"
+                }
+            }
+        },
+        "models.RebuildBalancesResponse": {
+            "type": "object",
+            "properties": {
+                "corrected": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "models.ReconciliationReport": {
+            "type": "object",
+            "properties": {
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BalanceDiscrepancy"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "mismatched_entries": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "unbalanced_entries": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "unrecorded_transactions": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "wallets_checked": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "rt_9b1f0c2e4d6a8b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c"
+                }
+            }
+        },
+        "models.ReverseTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "5.00"
+                }
+            }
+        },
+        "models.ScheduledTransfer": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "50.00"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "convert": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "retry_at": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "type": "string",
+                    "example": "0 9 1 * *"
+                },
+                "status": {
+                    "enum": [
+                        "active",
+                        "cancelled"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScheduledTransferStatus"
+                        }
+                    ],
+                    "example": "active"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"
+                }
+            }
+        },
+        "models.ScheduledTransferRun": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "insufficient funds"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "scheduled_for": {
+                    "type": "string"
+                },
+                "scheduled_transfer_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "enum": [
+                        "completed",
+                        "retrying",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScheduledTransferRunStatus"
+                        }
+                    ],
+                    "example": "completed"
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.ScheduledTransferRunStatus": {
+            "type": "string",
+            "enum": [
+                "completed",
+                "retrying",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ScheduledTransferRunCompleted",
+                "ScheduledTransferRunRetrying",
+                "ScheduledTransferRunFailed"
+            ]
+        },
+        "models.ScheduledTransferStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "ScheduledTransferStatusActive",
+                "ScheduledTransferStatusCancelled"
+            ]
+        },
+        "models.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn — срок действия токена доступа в секундах.",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
                 }
             }
         },
@@ -205,7 +2638,24 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "amount": {
-                    "type": "number"
+                    "type": "string",
+                    "example": "10.50"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency — валюта суммы перевода (валюта кошелька отправителя); пуста, если отправитель не найден.",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "failure_reason": {
+                    "description": "FailureReason — причина отказа в переводе; заполняется только для транзакций в статусе failed.",
+                    "type": "string",
+                    "example": "insufficient funds"
                 },
                 "from": {
                     "type": "string"
@@ -213,8 +2663,246 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "reversal_of": {
+                    "description": "ReversalOf — ID перевода, который отменяет эта компенсирующая транзакция; nil для обычных переводов.",
+                    "type": "integer",
+                    "example": 42
+                },
+                "scheduled_for": {
+                    "type": "string"
+                },
+                "scheduled_transfer_id": {
+                    "description": "ScheduledTransferID и ScheduledFor — регулярный перевод, по которому выполнена транзакция, и срок, за который\nона выполнена; nil для переводов, запрошенных через API.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "completed",
+                        "failed",
+                        "reversed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionStatus"
+                        }
+                    ],
+                    "example": "completed"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransactionPage": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor — курсор следующей страницы; пуст, если страница последняя.",
+                    "type": "string",
+                    "example": "djE6MTA0"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                }
+            }
+        },
+        "models.TransactionReversal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "5.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"
+                },
+                "original_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "refund": {
+                    "type": "string",
+                    "example": "5.00"
+                },
+                "refund_currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 43
+                }
+            }
+        },
+        "models.TransactionStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "completed",
+                "failed",
+                "reversed"
+            ],
+            "x-enum-varnames": [
+                "TransactionStatusPending",
+                "TransactionStatusCompleted",
+                "TransactionStatusFailed",
+                "TransactionStatusReversed"
+            ]
+        },
+        "models.TransferQuote": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "10.50"
+                },
+                "conversion": {
+                    "$ref": "#/definitions/models.Conversion"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "string",
+                    "example": "0.30"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"
+                },
+                "quote_id": {
+                    "type": "string"
+                },
+                "recipient_balance_after": {
+                    "type": "string",
+                    "example": "60.50"
+                },
+                "sender_balance_after": {
+                    "type": "string",
+                    "example": "89.20"
+                },
                 "to": {
+                    "type": "string",
+                    "example": "01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"
+                },
+                "total": {
+                    "type": "string",
+                    "example": "10.80"
+                }
+            }
+        },
+        "models.TransferResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "10.50"
+                },
+                "conversion": {
+                    "$ref": "#/definitions/models.Conversion"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "fee": {
+                    "type": "string",
+                    "example": "0.30"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Transaction completed"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "total": {
+                    "type": "string",
+                    "example": "10.80"
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.UpdateWalletStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "enum": [
+                        "active",
+                        "frozen",
+                        "closed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WalletStatus"
+                        }
+                    ],
+                    "example": "frozen"
+                }
+            }
+        },
+        "models.UpdateWalletTierRequest": {
+            "type": "object",
+            "properties": {
+                "tier": {
+                    "type": "string",
+                    "example": "verified"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "customer",
+                        "operator",
+                        "auditor",
+                        "admin",
+                        "metrics"
+                    ],
+                    "example": "customer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "wallets": {
+                    "description": "Wallets — адреса кошельков, принадлежащих пользователю.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -225,10 +2913,116 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "balance": {
-                    "type": "number"
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "currency": {
+                    "description": "Currency — код валюты кошелька по ISO 4217. Баланс и все суммы переводов с кошелька выражены в этой валюте.",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "status": {
+                    "enum": [
+                        "active",
+                        "frozen",
+                        "closed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WalletStatus"
+                        }
+                    ],
+                    "example": "active"
+                },
+                "tier": {
+                    "description": "Tier — имя уровня кошелька, определяющего ограничения на переводы с него и на него.",
+                    "type": "string",
+                    "example": "standard"
+                }
+            }
+        },
+        "models.WalletBalance": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "available": {
+                    "type": "string",
+                    "example": "70.00"
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "held": {
+                    "type": "string",
+                    "example": "30.00"
+                }
+            }
+        },
+        "models.WalletStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "frozen",
+                "closed"
+            ],
+            "x-enum-varnames": [
+                "WalletStatusActive",
+                "WalletStatusFrozen",
+                "WalletStatusClosed"
+            ]
+        },
+        "models.WalletTier": {
+            "type": "object",
+            "properties": {
+                "daily_limit": {
+                    "description": "DailyLimit и MonthlyLimit ограничивают сумму переводов с кошелька за календарные сутки и месяц по UTC.",
+                    "type": "string",
+                    "example": "5000.00"
+                },
+                "max_balance": {
+                    "description": "MaxBalance — максимальный баланс, до которого можно пополнить кошелек переводом.",
+                    "type": "string",
+                    "example": "100000.00"
+                },
+                "max_transfer": {
+                    "type": "string",
+                    "example": "1000.00"
+                },
+                "min_transfer": {
+                    "description": "MinTransfer и MaxTransfer ограничивают сумму одного перевода с кошелька.",
+                    "type": "string",
+                    "example": "0.01"
+                },
+                "monthly_limit": {
+                    "type": "string",
+                    "example": "50000.00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "standard"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Токен доступа в формате \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -0,0 +1,109 @@
+// Package auth описывает участника запроса (Principal), его роли и разрешения, выпускает и проверяет токены доступа
+// и передает участника через context.Context от промежуточного обработчика аутентификации к сервисам.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+)
+
+const (
+	// ScopeAdmin — область доступа ключа API, дающая его владельцу роль admin.
+	ScopeAdmin = "admin"
+	// ScopeMetrics — область доступа ключа API, дающая его владельцу роль metrics; выдается системам мониторинга.
+	ScopeMetrics = "metrics"
+
+	// Префиксы отличают секреты разных видов друг от друга, например в логах и сканерах утечек.
+	apiKeyPrefix       = "pk_"
+	refreshTokenPrefix = "rt_"
+	secretBytes        = 32
+)
+
+// Principal — аутентифицированный участник запроса: владелец ключа API или пользователь с токеном доступа.
+type Principal struct {
+	// KeyID — идентификатор ключа API, которым аутентифицирован запрос.
+	KeyID int
+	// UserID — идентификатор пользователя, которому выдан токен доступа.
+	UserID  int
+	Name    string
+	Role    Role
+	Wallets []string
+}
+
+// System возвращает участника для внутренних задач сервиса, не связанных с HTTP-запросом.
+func System() *Principal {
+	return &Principal{Name: "system", Role: RoleAdmin}
+}
+
+// Subject возвращает идентификатор участника, уникальный среди ключей API и пользователей.
+func (p *Principal) Subject() string {
+	switch {
+	case p.KeyID != 0:
+		return "key:" + strconv.Itoa(p.KeyID)
+	case p.UserID != 0:
+		return "user:" + strconv.Itoa(p.UserID)
+	}
+	return p.Name
+}
+
+// HasPermission сообщает, есть ли у участника разрешение permission.
+func (p *Principal) HasPermission(permission Permission) bool {
+	return p.Role.HasPermission(permission)
+}
+
+// OwnsWallet сообщает, принадлежит ли кошелек address участнику.
+func (p *Principal) OwnsWallet(address string) bool {
+	return slices.Contains(p.Wallets, address)
+}
+
+// CanReadWallet сообщает, может ли участник просматривать кошелек address и его историю.
+func (p *Principal) CanReadWallet(address string) bool {
+	return p.HasPermission(PermissionReadAllWallets) || p.HasPermission(PermissionReadOwnWallets) && p.OwnsWallet(address)
+}
+
+// CanDebit сообщает, может ли участник списывать средства с кошелька address.
+func (p *Principal) CanDebit(address string) bool {
+	return p.HasPermission(PermissionTransfer) && (p.Role == RoleAdmin || p.OwnsWallet(address))
+}
+
+type principalKey struct{}
+
+// WithPrincipal возвращает копию ctx, содержащую участника p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext возвращает участника из ctx или nil, если запрос не аутентифицирован.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// GenerateKey создает новый случайный ключ API.
+func GenerateKey() (string, error) {
+	return generateSecret(apiKeyPrefix)
+}
+
+// GenerateRefreshToken создает новый случайный токен обновления.
+func GenerateRefreshToken() (string, error) {
+	return generateSecret(refreshTokenPrefix)
+}
+
+func generateSecret(prefix string) (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
+
+// HashKey возвращает хеш ключа API или токена обновления, под которым он хранится в БД.
+// Секреты содержат 256 бит случайных данных, поэтому медленная хеш-функция для них не нужна.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
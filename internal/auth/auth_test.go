@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal_CanDebit(t *testing.T) {
+	owner := &Principal{KeyID: 1, Role: RoleCustomer, Wallets: []string{"addr1"}}
+	admin := &Principal{KeyID: 2, Role: RoleAdmin}
+	auditor := &Principal{UserID: 3, Role: RoleAuditor, Wallets: []string{"addr1"}}
+
+	assert.True(t, owner.CanDebit("addr1"))
+	assert.False(t, owner.CanDebit("addr2"))
+	assert.True(t, admin.CanDebit("addr2"))
+	assert.False(t, auditor.CanDebit("addr1"))
+	assert.True(t, System().CanDebit("addr2"))
+}
+
+func TestPrincipal_CanReadWallet(t *testing.T) {
+	owner := &Principal{UserID: 1, Role: RoleCustomer, Wallets: []string{"addr1"}}
+	auditor := &Principal{UserID: 2, Role: RoleAuditor}
+
+	assert.True(t, owner.CanReadWallet("addr1"))
+	assert.False(t, owner.CanReadWallet("addr2"))
+	assert.True(t, auditor.CanReadWallet("addr2"))
+}
+
+func TestRole_HasPermission(t *testing.T) {
+	tests := []struct {
+		role       Role
+		permission Permission
+		expected   bool
+	}{
+		{RoleCustomer, PermissionTransfer, true},
+		{RoleCustomer, PermissionReadAllWallets, false},
+		{RoleAuditor, PermissionReadAllTransactions, true},
+		{RoleAuditor, PermissionTransfer, false},
+		{RoleAuditor, PermissionChangeWalletStatus, false},
+		{RoleAuditor, PermissionReadLedger, true},
+		{RoleAuditor, PermissionManageLedger, false},
+		{RoleOperator, PermissionChangeWalletStatus, true},
+		{RoleOperator, PermissionTransfer, false},
+		{RoleOperator, PermissionReverseTransactions, false},
+		{RoleAdmin, PermissionReverseTransactions, true},
+		{RoleAdmin, PermissionManageUsers, true},
+		{RoleOperator, PermissionReadMetrics, false},
+		{RoleAdmin, PermissionReadMetrics, true},
+		{RoleMetrics, PermissionReadMetrics, true},
+		{RoleMetrics, PermissionTransfer, false},
+		{RoleMetrics, PermissionReadOwnWallets, false},
+		{Role("root"), PermissionTransfer, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+" "+string(tt.permission), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.role.HasPermission(tt.permission))
+		})
+	}
+}
+
+func TestRoleForScopes(t *testing.T) {
+	assert.Equal(t, RoleAdmin, RoleForScopes([]string{ScopeAdmin}))
+	assert.Equal(t, RoleMetrics, RoleForScopes([]string{ScopeMetrics}))
+	assert.Equal(t, RoleAdmin, RoleForScopes([]string{ScopeMetrics, ScopeAdmin}))
+	assert.Equal(t, RoleCustomer, RoleForScopes(nil))
+}
+
+func TestPrincipal_Subject(t *testing.T) {
+	assert.Equal(t, "key:1", (&Principal{KeyID: 1}).Subject())
+	assert.Equal(t, "user:2", (&Principal{UserID: 2}).Subject())
+	assert.Equal(t, "system", System().Subject())
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+
+	p := &Principal{KeyID: 1}
+	assert.Same(t, p, FromContext(WithPrincipal(context.Background(), p)))
+}
+
+func TestGenerateKey(t *testing.T) {
+	key1, err := GenerateKey()
+	assert.NoError(t, err)
+	key2, err := GenerateKey()
+	assert.NoError(t, err)
+	refreshToken, err := GenerateRefreshToken()
+	assert.NoError(t, err)
+
+	assert.Regexp(t, "^pk_[0-9a-f]{64}$", key1)
+	assert.Regexp(t, "^rt_[0-9a-f]{64}$", refreshToken)
+	assert.NotEqual(t, key1, key2)
+	assert.Len(t, HashKey(key1), 64)
+	assert.Equal(t, HashKey(key1), HashKey(key1))
+	assert.NotEqual(t, HashKey(key1), HashKey(key2))
+}
@@ -0,0 +1,106 @@
+package auth
+
+import "slices"
+
+// Role — роль участника, определяющая набор его разрешений.
+type Role string
+
+const (
+	// RoleCustomer — владелец кошельков: переводит средства со своих кошельков и просматривает их.
+	RoleCustomer Role = "customer"
+	// RoleOperator — сотрудник поддержки: просматривает любые кошельки и транзакции, замораживает и закрывает кошельки.
+	RoleOperator Role = "operator"
+	// RoleAuditor просматривает любые кошельки и транзакции, но не может ничего изменять.
+	RoleAuditor Role = "auditor"
+	// RoleAdmin имеет все разрешения и может списывать средства с любого кошелька.
+	RoleAdmin Role = "admin"
+	// RoleMetrics только читает метрики сервиса; выдается ключам API систем мониторинга.
+	RoleMetrics Role = "metrics"
+)
+
+// Valid сообщает, является ли r одной из известных ролей.
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permission — разрешение на выполнение группы операций API.
+type Permission string
+
+const (
+	PermissionTransfer            Permission = "transfers:create"
+	PermissionCreateWallet        Permission = "wallets:create"
+	PermissionReadOwnWallets      Permission = "wallets:read"
+	PermissionReadAllWallets      Permission = "wallets:read_all"
+	PermissionReadAllTransactions Permission = "transactions:read_all"
+	PermissionReverseTransactions Permission = "transactions:reverse"
+	PermissionChangeWalletStatus  Permission = "wallets:status"
+	PermissionManageAPIKeys       Permission = "api_keys:manage"
+	PermissionManageUsers         Permission = "users:manage"
+	PermissionReadTiers           Permission = "tiers:read"
+	PermissionManageTiers         Permission = "tiers:manage"
+	PermissionReadLedger          Permission = "ledger:read"
+	PermissionManageLedger        Permission = "ledger:manage"
+	PermissionReadMetrics         Permission = "metrics:read"
+)
+
+// rolePermissions задает разрешения каждой роли.
+var rolePermissions = map[Role][]Permission{
+	RoleCustomer: {
+		PermissionTransfer,
+		PermissionCreateWallet,
+		PermissionReadOwnWallets,
+		PermissionReadTiers,
+	},
+	RoleAuditor: {
+		PermissionReadOwnWallets,
+		PermissionReadAllWallets,
+		PermissionReadAllTransactions,
+		PermissionReadLedger,
+		PermissionReadTiers,
+	},
+	RoleOperator: {
+		PermissionReadOwnWallets,
+		PermissionReadAllWallets,
+		PermissionReadAllTransactions,
+		PermissionReadLedger,
+		PermissionChangeWalletStatus,
+		PermissionReadTiers,
+	},
+	RoleAdmin: {
+		PermissionTransfer,
+		PermissionCreateWallet,
+		PermissionReadOwnWallets,
+		PermissionReadAllWallets,
+		PermissionReadAllTransactions,
+		PermissionReverseTransactions,
+		PermissionReadLedger,
+		PermissionChangeWalletStatus,
+		PermissionManageAPIKeys,
+		PermissionManageUsers,
+		PermissionReadTiers,
+		PermissionManageTiers,
+		PermissionManageLedger,
+		PermissionReadMetrics,
+	},
+	RoleMetrics: {
+		PermissionReadMetrics,
+	},
+}
+
+// HasPermission сообщает, есть ли у роли r разрешение permission.
+func (r Role) HasPermission(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// RoleForScopes возвращает роль владельца ключа API с областями доступа scopes.
+// Если ключу выдано несколько областей, он получает роль с наибольшими разрешениями.
+func RoleForScopes(scopes []string) Role {
+	switch {
+	case slices.Contains(scopes, ScopeAdmin):
+		return RoleAdmin
+	case slices.Contains(scopes, ScopeMetrics):
+		return RoleMetrics
+	}
+	return RoleCustomer
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"golangTestTask/configs"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Claims — утверждения токена доступа. Subject содержит идентификатор пользователя.
+type Claims struct {
+	Role Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// TokenIssuer выпускает и проверяет подписанные токены доступа (JWT).
+type TokenIssuer struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+	ttl       time.Duration
+}
+
+// NewTokenIssuer создает TokenIssuer, подписывающий токены методом method ключом signKey
+// и проверяющий их ключом verifyKey. Для HMAC оба ключа — один и тот же []byte.
+func NewTokenIssuer(method jwt.SigningMethod, signKey interface{}, verifyKey interface{}, issuer string, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		method:    method,
+		signKey:   signKey,
+		verifyKey: verifyKey,
+		issuer:    issuer,
+		ttl:       ttl,
+	}
+}
+
+// LoadTokenIssuer создает TokenIssuer по настройкам config, читая ключи подписи из указанных в ней файлов.
+// Для HS256 используется секрет из JWTSecretFile, для RS256 — ключи в формате PEM из JWTPrivateKeyFile и JWTPublicKeyFile.
+// Если для HS256 файл секрета не указан, генерируется случайный секрет: токены перестанут действовать после перезапуска.
+func LoadTokenIssuer(config configs.Config) (*TokenIssuer, error) {
+	switch config.JWTSigningMethod {
+	case jwt.SigningMethodHS256.Alg():
+		var secret []byte
+		if config.JWTSecretFile == "" {
+			log.Println("Warning: JWT_SECRET_FILE is not set, access tokens will be signed with a random secret")
+			secret = make([]byte, secretBytes)
+			if _, err := rand.Read(secret); err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			if secret, err = os.ReadFile(config.JWTSecretFile); err != nil {
+				return nil, fmt.Errorf("failed to read JWT secret: %w", err)
+			}
+			if len(secret) < secretBytes {
+				return nil, fmt.Errorf("JWT secret must be at least %d bytes long", secretBytes)
+			}
+		}
+		return NewTokenIssuer(jwt.SigningMethodHS256, secret, secret, config.JWTIssuer, config.JWTAccessTTL), nil
+	case jwt.SigningMethodRS256.Alg():
+		privatePEM, err := os.ReadFile(config.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+		}
+		publicPEM, err := os.ReadFile(config.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key: %w", err)
+		}
+		return NewTokenIssuer(jwt.SigningMethodRS256, privateKey, publicKey, config.JWTIssuer, config.JWTAccessTTL), nil
+	}
+	return nil, fmt.Errorf("unsupported JWT signing method %q", config.JWTSigningMethod)
+}
+
+// TTL возвращает срок действия выпускаемых токенов доступа.
+func (t *TokenIssuer) TTL() time.Duration {
+	return t.ttl
+}
+
+// Issue выпускает токен доступа для пользователя userID с ролью role.
+func (t *TokenIssuer) Issue(userID int, role Role) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    t.issuer,
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(t.ttl)),
+		},
+	}
+	return jwt.NewWithClaims(t.method, claims).SignedString(t.signKey)
+}
+
+// Parse проверяет подпись, издателя и срок действия токена и возвращает идентификатор пользователя.
+// Любая ошибка проверки возвращается как ErrInvalidToken.
+func (t *TokenIssuer) Parse(token string) (int, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return t.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{t.method.Alg()}),
+		jwt.WithIssuer(t.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return 0, ErrInvalidToken
+	}
+	return userID, nil
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golangTestTask/configs"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenIssuer_IssueAndParse(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	issuer := NewTokenIssuer(jwt.SigningMethodHS256, secret, secret, "payment-system", time.Minute)
+
+	token, err := issuer.Issue(42, RoleAuditor)
+	require.NoError(t, err)
+
+	userID, err := issuer.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, userID)
+}
+
+func TestTokenIssuer_Parse_Invalid(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	issuer := NewTokenIssuer(jwt.SigningMethodHS256, secret, secret, "payment-system", time.Minute)
+
+	expired, err := NewTokenIssuer(jwt.SigningMethodHS256, secret, secret, "payment-system", -time.Minute).Issue(1, RoleCustomer)
+	require.NoError(t, err)
+	otherIssuer, err := NewTokenIssuer(jwt.SigningMethodHS256, secret, secret, "other", time.Minute).Issue(1, RoleCustomer)
+	require.NoError(t, err)
+	otherSecret := []byte("fedcba9876543210fedcba9876543210")
+	forged, err := NewTokenIssuer(jwt.SigningMethodHS256, otherSecret, otherSecret, "payment-system", time.Minute).Issue(1, RoleAdmin)
+	require.NoError(t, err)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:    "payment-system",
+		Subject:   "1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"expired":      expired,
+		"other issuer": otherIssuer,
+		"forged":       forged,
+		"unsigned":     unsigned,
+		"malformed":    "not-a-token",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := issuer.Parse(token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestLoadTokenIssuer(t *testing.T) {
+	dir := t.TempDir()
+
+	secretFile := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("0123456789abcdef0123456789abcdef"), 0o600))
+	shortSecretFile := filepath.Join(dir, "short")
+	require.NoError(t, os.WriteFile(shortSecretFile, []byte("short"), 0o600))
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKeyFile := filepath.Join(dir, "private.pem")
+	require.NoError(t, os.WriteFile(privateKeyFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}), 0o600))
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicKeyFile := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+
+	tests := []struct {
+		name    string
+		config  configs.Config
+		wantErr bool
+	}{
+		{
+			name:   "HS256 From File",
+			config: configs.Config{JWTSigningMethod: "HS256", JWTSecretFile: secretFile},
+		},
+		{
+			name:   "HS256 Random Secret",
+			config: configs.Config{JWTSigningMethod: "HS256"},
+		},
+		{
+			name:    "HS256 Short Secret",
+			config:  configs.Config{JWTSigningMethod: "HS256", JWTSecretFile: shortSecretFile},
+			wantErr: true,
+		},
+		{
+			name:   "RS256",
+			config: configs.Config{JWTSigningMethod: "RS256", JWTPrivateKeyFile: privateKeyFile, JWTPublicKeyFile: publicKeyFile},
+		},
+		{
+			name:    "RS256 Missing Key",
+			config:  configs.Config{JWTSigningMethod: "RS256", JWTPrivateKeyFile: filepath.Join(dir, "missing.pem")},
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported Method",
+			config:  configs.Config{JWTSigningMethod: "none"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.JWTIssuer = "payment-system"
+			tt.config.JWTAccessTTL = time.Minute
+
+			issuer, err := LoadTokenIssuer(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			token, err := issuer.Issue(7, RoleCustomer)
+			require.NoError(t, err)
+			userID, err := issuer.Parse(token)
+			assert.NoError(t, err)
+			assert.Equal(t, 7, userID)
+		})
+	}
+}
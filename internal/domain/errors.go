@@ -0,0 +1,142 @@
+// Package domain содержит ошибки предметной области, общие для репозиториев, сервисов и обработчиков.
+// Вызывающий код должен сравнивать ошибки через errors.Is и errors.As, а не по тексту.
+package domain
+
+import (
+	"errors"
+	"golangTestTask/internal/models"
+	"time"
+)
+
+var (
+	ErrWalletNotFound      = errors.New("wallet not found")
+	ErrWalletAlreadyExists = errors.New("wallet already exists")
+	ErrWalletFrozen        = errors.New("wallet is frozen")
+	ErrWalletClosed        = errors.New("wallet is closed")
+	ErrWalletNotEmpty      = errors.New("wallet balance is not zero")
+	ErrInvalidWalletStatus = errors.New("invalid wallet status")
+
+	ErrTierNotFound      = errors.New("wallet tier not found")
+	ErrTierAlreadyExists = errors.New("wallet tier already exists")
+
+	ErrAmountBelowMinimum   = errors.New("amount is below the minimum transfer amount")
+	ErrAmountAboveMaximum   = errors.New("amount exceeds the maximum transfer amount")
+	ErrDailyLimitExceeded   = errors.New("daily transfer limit exceeded")
+	ErrMonthlyLimitExceeded = errors.New("monthly transfer limit exceeded")
+	ErrMaxBalanceExceeded   = errors.New("maximum wallet balance exceeded")
+
+	ErrUnsupportedCurrency    = errors.New("unsupported currency")
+	ErrInvalidAmountPrecision = errors.New("amount has more fractional digits than the currency allows")
+	ErrCurrencyMismatch       = errors.New("sender and recipient wallets have different currencies")
+	ErrConversionUnavailable  = errors.New("currency conversion is not available")
+	ErrFeeUnavailable         = errors.New("transfer fee is not configured for the sender wallet currency")
+
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSameWallet        = errors.New("sender and recipient wallets must differ")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+
+	ErrTransactionNotFound        = errors.New("transaction not found")
+	ErrTransactionNotReversible   = errors.New("only completed transfers can be reversed")
+	ErrTransactionAlreadyReversed = errors.New("transaction has already been reversed")
+
+	ErrScheduledTransferNotFound  = errors.New("scheduled transfer not found")
+	ErrScheduledTransferCancelled = errors.New("scheduled transfer is cancelled")
+	// ErrScheduledTransferExecuted сообщает, что перевод за этот срок регулярного перевода уже выполнен.
+	// Ее обрабатывает планировщик, поэтому она не сопоставляется с кодом ответа API.
+	ErrScheduledTransferExecuted = errors.New("scheduled transfer has already been executed for this date")
+	// ErrScheduledTransferUnauthorized сообщает, что создателю регулярного перевода больше не разрешено списывать средства
+	// с кошелька отправителя. Ее обрабатывает планировщик, поэтому она не сопоставляется с кодом ответа API.
+	ErrScheduledTransferUnauthorized = errors.New("creator of the scheduled transfer may no longer debit the sender wallet")
+
+	ErrHoldNotFound  = errors.New("hold not found")
+	ErrHoldNotActive = errors.New("hold has already been captured, voided or expired")
+	ErrHoldExpired   = errors.New("hold has expired")
+
+	// ErrUnbalancedEntry сообщает о нарушении инварианта журнала: сумма проводок записи не равна нулю.
+	// Это ошибка сервиса, а не клиента, поэтому она не сопоставляется с кодом ответа API.
+	ErrUnbalancedEntry = errors.New("unbalanced journal entry")
+
+	ErrInvalidQuote  = errors.New("invalid quote")
+	ErrQuoteExpired  = errors.New("quote has expired")
+	ErrQuoteMismatch = errors.New("transfer does not match the quote")
+	ErrQuoteUsed     = errors.New("quote has already been used")
+
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrInvalidAPIKey   = errors.New("invalid api key")
+	ErrForbidden       = errors.New("insufficient permissions")
+	ErrWalletNotOwned  = errors.New("wallet is not owned by the caller")
+	ErrInvalidScope    = errors.New("unknown api key scope")
+
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrInvalidRole        = errors.New("invalid role")
+
+	ErrLimitExceeded = errors.New("limit exceeded")
+
+	ErrIdempotencyKeyReused         = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyRequestInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+// WalletError — ошибка, относящаяся к одному из кошельков перевода. Role указывает, отправитель это или получатель.
+type WalletError struct {
+	Role    models.TransactionRole
+	Address string
+	Err     error
+}
+
+// NewWalletError создает ошибку err для кошелька address в роли role.
+func NewWalletError(role models.TransactionRole, address string, err error) *WalletError {
+	return &WalletError{Role: role, Address: address, Err: err}
+}
+
+func (e *WalletError) Error() string {
+	return string(e.Role) + " " + e.Err.Error()
+}
+
+func (e *WalletError) Unwrap() error {
+	return e.Err
+}
+
+// Ограничения, о превышении которых сообщает LimitError.
+const (
+	LimitRequests           = "requests"
+	LimitTransfersPerMinute = "transfers_per_minute"
+	LimitDailyVolume        = "daily_volume"
+)
+
+// LimitError — ошибка превышения ограничения Limit на частоту запросов или объем переводов.
+// RetryAfter — время, через которое операцию можно повторить.
+type LimitError struct {
+	Limit      string
+	RetryAfter time.Duration
+}
+
+// NewLimitError создает ошибку превышения ограничения limit, которое снимется через retryAfter.
+func NewLimitError(limit string, retryAfter time.Duration) *LimitError {
+	return &LimitError{Limit: limit, RetryAfter: retryAfter}
+}
+
+func (e *LimitError) Error() string {
+	return "limit exceeded: " + e.Limit
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// ValidationError — ошибка проверки входных данных. Field содержит имя некорректного поля или параметра, если оно известно.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// NewValidationError создает ошибку проверки поля field с текстом message.
+func NewValidationError(field string, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,163 @@
+// Package fee рассчитывает комиссию за перевод по тарифу.
+package fee
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golangTestTask/pkg/money"
+	"strings"
+)
+
+// Kind — вид тарифа комиссии.
+type Kind string
+
+const (
+	// KindNone — комиссия не взимается.
+	KindNone Kind = ""
+	// KindFlat — фиксированная комиссия Flat с каждого перевода.
+	KindFlat Kind = "flat"
+	// KindPercentage — комиссия RateBP от суммы перевода, к которой добавляется Flat.
+	KindPercentage Kind = "percentage"
+	// KindTiered — комиссия по ступеням Brackets в зависимости от суммы перевода.
+	KindTiered Kind = "tiered"
+)
+
+// basisPointsPerUnit — число базисных пунктов в единице: 1 б.п. = 0.01%.
+const basisPointsPerUnit = 10000
+
+var ErrInvalidSchedule = errors.New("invalid fee schedule")
+
+// Schedule — тариф комиссии за перевод. Нулевое значение означает перевод без комиссии.
+type Schedule struct {
+	Kind Kind `json:"kind"`
+	// Flat — фиксированная часть комиссии для тарифов flat и percentage.
+	Flat money.Amount `json:"flat,omitempty"`
+	// RateBP — ставка комиссии в базисных пунктах для тарифа percentage.
+	RateBP int64 `json:"rate_bp,omitempty"`
+	// Brackets — ступени тарифа tiered в порядке возрастания UpTo.
+	Brackets []Bracket `json:"brackets,omitempty"`
+	// Min и Max ограничивают рассчитанную комиссию; нулевой Max означает отсутствие верхней границы.
+	Min money.Amount `json:"min,omitempty"`
+	Max money.Amount `json:"max,omitempty"`
+}
+
+// Bracket — ступень тарифа tiered: к переводам на сумму не больше UpTo применяются Flat и RateBP.
+// Нулевой UpTo допустим только у последней ступени и означает отсутствие верхней границы;
+// к переводам больше UpTo всех ступеней применяется последняя ступень.
+type Bracket struct {
+	UpTo   money.Amount `json:"up_to,omitempty"`
+	Flat   money.Amount `json:"flat,omitempty"`
+	RateBP int64        `json:"rate_bp,omitempty"`
+}
+
+// Parse разбирает тариф из JSON. Пустая строка означает перевод без комиссии.
+func Parse(s string) (Schedule, error) {
+	var schedule Schedule
+	if strings.TrimSpace(s) == "" {
+		return schedule, nil
+	}
+	decoder := json.NewDecoder(bytes.NewBufferString(s))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&schedule); err != nil {
+		return Schedule{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	if err := schedule.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// Validate проверяет, что тариф согласован: параметры соответствуют виду тарифа, суммы и ставки неотрицательны,
+// ставки не больше 100%, ступени упорядочены, а Max не меньше Min.
+func (s Schedule) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidSchedule, fmt.Sprintf(format, args...))
+	}
+
+	if s.Flat < 0 || s.RateBP < 0 || s.Min < 0 || s.Max < 0 {
+		return invalid("amounts and rates must not be negative")
+	}
+	if s.RateBP > basisPointsPerUnit {
+		return invalid("rate_bp must not exceed %d", basisPointsPerUnit)
+	}
+	if s.Max > 0 && s.Max < s.Min {
+		return invalid("max must not be less than min")
+	}
+	switch s.Kind {
+	case KindNone:
+		if s.Flat != 0 || s.RateBP != 0 || s.Min != 0 || s.Max != 0 || len(s.Brackets) != 0 {
+			return invalid("kind is required")
+		}
+	case KindFlat:
+		if s.RateBP != 0 || len(s.Brackets) != 0 {
+			return invalid("flat schedule accepts only flat, min and max")
+		}
+	case KindPercentage:
+		if len(s.Brackets) != 0 {
+			return invalid("percentage schedule does not accept brackets")
+		}
+	case KindTiered:
+		if s.Flat != 0 || s.RateBP != 0 {
+			return invalid("tiered schedule sets flat and rate_bp per bracket")
+		}
+		if len(s.Brackets) == 0 {
+			return invalid("tiered schedule requires brackets")
+		}
+		for i, b := range s.Brackets {
+			if b.UpTo < 0 || b.Flat < 0 || b.RateBP < 0 {
+				return invalid("bracket %d: amounts and rates must not be negative", i)
+			}
+			if b.RateBP > basisPointsPerUnit {
+				return invalid("bracket %d: rate_bp must not exceed %d", i, basisPointsPerUnit)
+			}
+			last := i == len(s.Brackets)-1
+			if b.UpTo == 0 && !last {
+				return invalid("bracket %d: only the last bracket may be unbounded", i)
+			}
+			if i > 0 && b.UpTo != 0 && b.UpTo <= s.Brackets[i-1].UpTo {
+				return invalid("bracket %d: up_to must increase", i)
+			}
+		}
+	default:
+		return invalid("unknown kind %q", s.Kind)
+	}
+	return nil
+}
+
+// Fee возвращает комиссию за перевод суммы amount. Процентная часть округляется до минимальной единицы
+// по правилам математического округления, затем комиссия ограничивается Min и Max.
+func (s Schedule) Fee(amount money.Amount) money.Amount {
+	var fee money.Amount
+	switch s.Kind {
+	case KindNone:
+		return 0
+	case KindFlat, KindPercentage:
+		fee = s.Flat + percent(amount, s.RateBP)
+	case KindTiered:
+		bracket := s.Brackets[len(s.Brackets)-1]
+		for _, b := range s.Brackets {
+			if b.UpTo == 0 || amount <= b.UpTo {
+				bracket = b
+				break
+			}
+		}
+		fee = bracket.Flat + percent(amount, bracket.RateBP)
+	}
+	if fee < s.Min {
+		fee = s.Min
+	}
+	if s.Max > 0 && fee > s.Max {
+		fee = s.Max
+	}
+	return fee
+}
+
+// percent возвращает rateBP базисных пунктов от amount, округленные до минимальной единицы.
+// Сумма делится на basisPointsPerUnit до умножения на ставку, чтобы произведение не переполняло int64
+// для любой суммы до money.MaxAmount и ставки до 100%; на ставку умножается только остаток от деления.
+func percent(amount money.Amount, rateBP int64) money.Amount {
+	whole, rest := int64(amount)/basisPointsPerUnit, int64(amount)%basisPointsPerUnit
+	return money.Amount(whole*rateBP + (rest*rateBP+basisPointsPerUnit/2)/basisPointsPerUnit)
+}
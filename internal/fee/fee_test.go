@@ -0,0 +1,106 @@
+package fee
+
+import (
+	"errors"
+	"testing"
+
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Fee(t *testing.T) {
+	tiered := Schedule{
+		Kind: KindTiered,
+		Brackets: []Bracket{
+			{UpTo: money.FromInt(100), Flat: money.MustParse("0.50")},
+			{UpTo: money.FromInt(1000), RateBP: 100},
+			{RateBP: 50},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		amount   money.Amount
+		want     money.Amount
+	}{
+		{name: "no fee", schedule: Schedule{}, amount: money.FromInt(100), want: 0},
+		{name: "flat", schedule: Schedule{Kind: KindFlat, Flat: money.MustParse("0.30")}, amount: money.FromInt(100), want: money.MustParse("0.30")},
+		{name: "percentage", schedule: Schedule{Kind: KindPercentage, RateBP: 150}, amount: money.FromInt(100), want: money.MustParse("1.50")},
+		{name: "percentage rounds half up", schedule: Schedule{Kind: KindPercentage, RateBP: 50}, amount: money.MustParse("1.01"), want: money.MustParse("0.01")},
+		{name: "percentage rounds down", schedule: Schedule{Kind: KindPercentage, RateBP: 50}, amount: money.MustParse("0.99"), want: 0},
+		{name: "percentage with flat part", schedule: Schedule{Kind: KindPercentage, Flat: money.MustParse("0.30"), RateBP: 290}, amount: money.FromInt(10), want: money.MustParse("0.59")},
+		{name: "min cap", schedule: Schedule{Kind: KindPercentage, RateBP: 10, Min: money.MustParse("0.25")}, amount: money.FromInt(10), want: money.MustParse("0.25")},
+		{name: "max cap", schedule: Schedule{Kind: KindPercentage, RateBP: 100, Max: money.FromInt(5)}, amount: money.FromInt(1000), want: money.FromInt(5)},
+		{name: "full rate", schedule: Schedule{Kind: KindPercentage, RateBP: 10000}, amount: money.MustParse("12.34"), want: money.MustParse("12.34")},
+		{name: "largest amount", schedule: Schedule{Kind: KindPercentage, RateBP: 10000}, amount: money.MaxAmount, want: money.MaxAmount},
+		{name: "largest amount rounds half up", schedule: Schedule{Kind: KindPercentage, RateBP: 50}, amount: money.MaxAmount, want: money.MustParse("50000000000.00")},
+		{name: "tiered first bracket", schedule: tiered, amount: money.FromInt(100), want: money.MustParse("0.50")},
+		{name: "tiered second bracket", schedule: tiered, amount: money.MustParse("100.01"), want: money.FromInt(1)},
+		{name: "tiered unbounded bracket", schedule: tiered, amount: money.FromInt(5000), want: money.FromInt(25)},
+		{
+			name: "tiered above last bounded bracket",
+			schedule: Schedule{Kind: KindTiered, Brackets: []Bracket{
+				{UpTo: money.FromInt(10), Flat: money.FromInt(1)},
+				{UpTo: money.FromInt(100), Flat: money.FromInt(2)},
+			}},
+			amount: money.FromInt(500),
+			want:   money.FromInt(2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.Fee(tt.amount))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Schedule
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: Schedule{}},
+		{
+			name:  "percentage",
+			input: `{"kind":"percentage","rate_bp":50,"min":"0.10","max":"25.00"}`,
+			want:  Schedule{Kind: KindPercentage, RateBP: 50, Min: money.MustParse("0.10"), Max: money.FromInt(25)},
+		},
+		{
+			name:  "tiered",
+			input: `{"kind":"tiered","brackets":[{"up_to":"100","flat":"0.50"},{"rate_bp":30}]}`,
+			want: Schedule{Kind: KindTiered, Brackets: []Bracket{
+				{UpTo: money.FromInt(100), Flat: money.MustParse("0.50")},
+				{RateBP: 30},
+			}},
+		},
+		{name: "malformed json", input: `{"kind":`, wantErr: true},
+		{name: "unknown field", input: `{"kind":"flat","fee":"1"}`, wantErr: true},
+		{name: "unknown kind", input: `{"kind":"weekly"}`, wantErr: true},
+		{name: "missing kind", input: `{"flat":"1.00"}`, wantErr: true},
+		{name: "negative rate", input: `{"kind":"percentage","rate_bp":-1}`, wantErr: true},
+		{name: "rate above 100%", input: `{"kind":"percentage","rate_bp":10001}`, wantErr: true},
+		{name: "bracket rate above 100%", input: `{"kind":"tiered","brackets":[{"rate_bp":20000}]}`, wantErr: true},
+		{name: "max below min", input: `{"kind":"flat","flat":"1","min":"2","max":"1"}`, wantErr: true},
+		{name: "flat with rate", input: `{"kind":"flat","flat":"1","rate_bp":10}`, wantErr: true},
+		{name: "tiered without brackets", input: `{"kind":"tiered"}`, wantErr: true},
+		{name: "tiered unbounded bracket not last", input: `{"kind":"tiered","brackets":[{"flat":"1"},{"up_to":"100","flat":"2"}]}`, wantErr: true},
+		{name: "tiered brackets out of order", input: `{"kind":"tiered","brackets":[{"up_to":"100"},{"up_to":"50"}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidSchedule), "expected ErrInvalidSchedule, got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package fx предоставляет курсы обмена валют для переводов с конвертацией.
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
+	"golangTestTask/pkg/money"
+	"os"
+)
+
+// basisPointsPerUnit — число базисных пунктов в единице: 1 б.п. = 0.01%.
+const basisPointsPerUnit = 10000
+
+var ErrInvalidRates = errors.New("invalid exchange rates")
+
+// Rate — курс обмена валюты From на валюту To: за единицу From зачисляется Applied единиц To.
+// Applied — рыночный курс Mid, уменьшенный на спред SpreadBP (в базисных пунктах) в пользу сервиса.
+type Rate struct {
+	From     string     `json:"from"`
+	To       string     `json:"to"`
+	Mid      money.Rate `json:"mid"`
+	Applied  money.Rate `json:"applied"`
+	SpreadBP int64      `json:"spread_bp"`
+}
+
+// RateProvider — источник курсов обмена.
+type RateProvider interface {
+	// Rate возвращает текущий курс обмена валюты from на валюту to.
+	// Если курс недоступен, возвращает ошибку, оборачивающую domain.ErrConversionUnavailable.
+	Rate(ctx context.Context, from string, to string) (Rate, error)
+}
+
+// Table — фиксированные курсы: Rates содержит стоимость единицы базовой валюты Base в других валютах,
+// а ко всем курсам применяется спред SpreadBP.
+type Table struct {
+	Base     string                `json:"base"`
+	Rates    map[string]money.Rate `json:"rates"`
+	SpreadBP int64                 `json:"spread_bp"`
+}
+
+// StaticProvider — RateProvider с фиксированными курсами из Table для локальной разработки и тестов.
+// Курс между двумя небазовыми валютами рассчитывается через базовую.
+type StaticProvider struct {
+	rates    map[string]money.Rate
+	spreadBP int64
+}
+
+// NewStaticProvider создает StaticProvider с курсами table. Валюты таблицы должны поддерживаться сервисом.
+func NewStaticProvider(table Table) (*StaticProvider, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidRates, fmt.Sprintf(format, args...))
+	}
+
+	base, ok := money.LookupCurrency(table.Base)
+	if !ok {
+		return nil, invalid("unsupported base currency %q", table.Base)
+	}
+	if table.SpreadBP < 0 || table.SpreadBP >= basisPointsPerUnit {
+		return nil, invalid("spread_bp must be between 0 and %d", basisPointsPerUnit-1)
+	}
+	rates := map[string]money.Rate{base.Code: money.MustParseRate("1")}
+	for code, rate := range table.Rates {
+		currency, ok := money.LookupCurrency(code)
+		if !ok {
+			return nil, invalid("unsupported currency %q", code)
+		}
+		if rate <= 0 {
+			return nil, invalid("rate for %s must be positive", currency.Code)
+		}
+		if currency.Code != base.Code {
+			rates[currency.Code] = rate
+		}
+	}
+	return &StaticProvider{rates: rates, spreadBP: table.SpreadBP}, nil
+}
+
+// LoadProvider создает RateProvider по настройкам config, читая таблицу курсов из FXRatesFile.
+// Если файл курсов не указан, возвращает nil: переводы с конвертацией недоступны.
+func LoadProvider(config configs.Config) (RateProvider, error) {
+	if config.FXRatesFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(config.FXRatesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRates, err)
+	}
+	provider, err := NewStaticProvider(table)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// Rate возвращает курс обмена валюты from на валюту to.
+func (p *StaticProvider) Rate(ctx context.Context, from string, to string) (Rate, error) {
+	rate_from, ok_from := p.rates[from]
+	rate_to, ok_to := p.rates[to]
+	if !ok_from || !ok_to {
+		return Rate{}, fmt.Errorf("%w: no rate for %s/%s", domain.ErrConversionUnavailable, from, to)
+	}
+
+	mid, err := rate_to.Div(rate_from)
+	applied := mid * money.Rate(basisPointsPerUnit-p.spreadBP) / basisPointsPerUnit
+	if err != nil || applied == 0 {
+		return Rate{}, fmt.Errorf("%w: rate for %s/%s is too small", domain.ErrConversionUnavailable, from, to)
+	}
+	return Rate{From: from, To: to, Mid: mid, Applied: applied, SpreadBP: p.spreadBP}, nil
+}
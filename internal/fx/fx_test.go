@@ -0,0 +1,80 @@
+package fx
+
+import (
+	"context"
+	"testing"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticProvider_Rate(t *testing.T) {
+	provider, err := NewStaticProvider(Table{
+		Base:     "USD",
+		Rates:    map[string]money.Rate{"EUR": money.MustParseRate("0.92"), "JPY": money.MustParseRate("150")},
+		SpreadBP: 50,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    Rate
+		wantErr error
+	}{
+		{
+			name: "from base",
+			from: "USD",
+			to:   "EUR",
+			want: Rate{From: "USD", To: "EUR", Mid: money.MustParseRate("0.92"), Applied: money.MustParseRate("0.9154"), SpreadBP: 50},
+		},
+		{
+			name: "to base",
+			from: "EUR",
+			to:   "USD",
+			want: Rate{From: "EUR", To: "USD", Mid: money.MustParseRate("1.08695652"), Applied: money.MustParseRate("1.08152173"), SpreadBP: 50},
+		},
+		{
+			name: "cross rate",
+			from: "EUR",
+			to:   "JPY",
+			want: Rate{From: "EUR", To: "JPY", Mid: money.MustParseRate("163.04347826"), Applied: money.MustParseRate("162.22826086"), SpreadBP: 50},
+		},
+		{name: "unknown currency", from: "USD", to: "GBP", wantErr: domain.ErrConversionUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provider.Rate(context.Background(), tt.from, tt.to)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewStaticProvider_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		table Table
+	}{
+		{name: "unknown base", table: Table{Base: "XXX"}},
+		{name: "unknown currency", table: Table{Base: "USD", Rates: map[string]money.Rate{"XXX": money.MustParseRate("1")}}},
+		{name: "zero rate", table: Table{Base: "USD", Rates: map[string]money.Rate{"EUR": 0}}},
+		{name: "spread too large", table: Table{Base: "USD", SpreadBP: 10000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStaticProvider(tt.table)
+			assert.ErrorIs(t, err, ErrInvalidRates)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/json"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"net/http"
+)
+
+// CreateAPIKey создает ключ API
+// @Summary Создать ключ API
+// @Description Создает ключ API с указанными областями доступа и передает ему во владение существующие кошельки. Значение ключа возвращается только в этом ответе
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param key body models.CreateAPIKeyRequest true "Данные ключа"
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload or wallet address"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/keys [post]
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if err := validateAddresses("wallets", req.Wallets); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	key, err := h.services.CreateAPIKey(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
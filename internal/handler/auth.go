@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+// isPublicPath сообщает, доступен ли путь без аутентификации: это вход и обновление сессии и документация API.
+func isPublicPath(path string) bool {
+	return strings.HasPrefix(path, "/swagger/") || strings.HasPrefix(path, "/api/auth/")
+}
+
+// authenticate проверяет ключ API из заголовка X-API-Key или токен доступа из заголовка Authorization: Bearer
+// и передает аутентифицированного участника в контексте запроса.
+// Запросы без учетных данных или с недействительными учетными данными отклоняются с кодом 401.
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isPublicPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var principal *auth.Principal
+		var err error
+		if key := r.Header.Get(apiKeyHeader); key != "" {
+			principal, err = h.services.Authenticate(r.Context(), key)
+		} else if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), bearerPrefix); ok && token != "" {
+			principal, err = h.services.AuthenticateToken(r.Context(), token)
+		} else {
+			err = domain.ErrUnauthenticated
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// requirePermission пропускает к обработчику только участников с разрешением permission, остальным отвечает кодом 403.
+func requirePermission(permission auth.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := auth.FromContext(r.Context())
+		if principal == nil {
+			writeError(w, r, domain.ErrUnauthenticated)
+			return
+		}
+		if !principal.HasPermission(permission) {
+			writeError(w, r, domain.ErrForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireWalletAccess пропускает к обработчику маршрута с параметром {address} только участников,
+// которым разрешено просматривать этот кошелек: его владельцев и роли с доступом ко всем кошелькам.
+func requireWalletAccess(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := auth.FromContext(r.Context())
+		if principal == nil {
+			writeError(w, r, domain.ErrUnauthenticated)
+			return
+		}
+		if !principal.CanReadWallet(r.PathValue("address")) {
+			writeError(w, r, domain.ErrWalletNotOwned)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golangTestTask/configs"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_Authentication(t *testing.T) {
+	customer := &auth.Principal{KeyID: 2, Role: auth.RoleCustomer, Wallets: []string{addr1}}
+	auditor := &auth.Principal{UserID: 3, Role: auth.RoleAuditor}
+	operator := &auth.Principal{UserID: 4, Role: auth.RoleOperator}
+
+	tests := []struct {
+		name                 string
+		method               string
+		path                 string
+		body                 string
+		headers              map[string]string
+		mockBehavior         func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Missing Credentials",
+			method:               "GET",
+			path:                 "/api/wallets",
+			mockBehavior:         func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"code":"unauthenticated","message":"authentication required","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:    "Invalid Key",
+			method:  "GET",
+			path:    "/api/wallets",
+			headers: map[string]string{"X-API-Key": "pk_invalid"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				a.EXPECT().Authenticate(gomock.Any(), "pk_invalid").Return(nil, domain.ErrInvalidAPIKey)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"code":"invalid_api_key","message":"invalid api key","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:    "Invalid Bearer Token",
+			method:  "GET",
+			path:    "/api/wallets",
+			headers: map[string]string{"Authorization": "Bearer expired"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				s.EXPECT().AuthenticateToken(gomock.Any(), "expired").Return(nil, domain.ErrInvalidToken)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"code":"invalid_token","message":"invalid or expired token","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:    "Customer Cannot List Wallets",
+			method:  "GET",
+			path:    "/api/wallets",
+			headers: map[string]string{"X-API-Key": "pk_merchant"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				a.EXPECT().Authenticate(gomock.Any(), "pk_merchant").Return(customer, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"code":"forbidden","message":"insufficient permissions","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:    "Auditor Lists Wallets",
+			method:  "GET",
+			path:    "/api/wallets",
+			headers: map[string]string{"Authorization": "Bearer auditor"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				s.EXPECT().AuthenticateToken(gomock.Any(), "auditor").Return(auditor, nil)
+				w.EXPECT().GetAllWallets(gomock.Any()).Return([]models.Wallet{{Address: addr1, Balance: money.FromInt(100)}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"address":"` + addr1 + `","balance":"100.00"}]` + "\n",
+		},
+		{
+			name:    "Auditor Cannot Send",
+			method:  "POST",
+			path:    "/api/send",
+			body:    `{"from":"` + addr1 + `","to":"` + addr2 + `","amount":"1.00"}`,
+			headers: map[string]string{"Authorization": "Bearer auditor"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				s.EXPECT().AuthenticateToken(gomock.Any(), "auditor").Return(auditor, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"code":"forbidden","message":"insufficient permissions","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:    "Operator Freezes Wallet",
+			method:  "PUT",
+			path:    "/api/wallet/" + addr1 + "/status",
+			body:    `{"status":"frozen"}`,
+			headers: map[string]string{"Authorization": "Bearer operator"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				s.EXPECT().AuthenticateToken(gomock.Any(), "operator").Return(operator, nil)
+				w.EXPECT().SetWalletStatus(gomock.Any(), addr1, models.WalletStatusFrozen).
+					Return(&models.Wallet{Address: addr1, Balance: money.FromInt(100), Status: models.WalletStatusFrozen}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"address":"` + addr1 + `","balance":"100.00","status":"frozen"}` + "\n",
+		},
+		{
+			name:    "Operator Cannot Change Wallet Tier",
+			method:  "PUT",
+			path:    "/api/wallet/" + addr1 + "/tier",
+			body:    `{"tier":"verified"}`,
+			headers: map[string]string{"Authorization": "Bearer operator"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				s.EXPECT().AuthenticateToken(gomock.Any(), "operator").Return(operator, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"code":"forbidden","message":"insufficient permissions","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:    "Customer Reads Own Wallet",
+			method:  "GET",
+			path:    "/api/wallet/" + addr1 + "/balance",
+			headers: map[string]string{"X-API-Key": "pk_merchant"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				a.EXPECT().Authenticate(gomock.Any(), "pk_merchant").Return(customer, nil)
+				w.EXPECT().GetWalletBalance(gomock.Any(), addr1).Return(&models.WalletBalance{
+					Address: addr1, Balance: money.FromInt(100), Available: money.FromInt(100),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"address":"` + addr1 + `","balance":"100.00","held":"0.00","available":"100.00"}` + "\n",
+		},
+		{
+			name:    "Customer Cannot Read Foreign Wallet",
+			method:  "GET",
+			path:    "/api/wallet/" + addr2 + "/balance",
+			headers: map[string]string{"X-API-Key": "pk_merchant"},
+			mockBehavior: func(a *service_mocks.MockAuth, s *service_mocks.MockSession, w *service_mocks.MockWallet) {
+				a.EXPECT().Authenticate(gomock.Any(), "pk_merchant").Return(customer, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"code":"wallet_not_owned","message":"wallet is not owned by the caller","request_id":"req-1"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authMock := service_mocks.NewMockAuth(c)
+			sessionMock := service_mocks.NewMockSession(c)
+			walletMock := service_mocks.NewMockWallet(c)
+			tt.mockBehavior(authMock, sessionMock, walletMock)
+
+			services := &service.Service{Auth: authMock, Session: sessionMock, Wallet: walletMock}
+			handler := NewHandler(services, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("X-Request-ID", "req-1")
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			handler.InitRoutes().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_Authentication_Metrics(t *testing.T) {
+	tests := []struct {
+		name               string
+		headers            map[string]string
+		mockBehavior       func(a *service_mocks.MockAuth)
+		expectedStatusCode int
+	}{
+		{
+			name:               "Missing Credentials",
+			mockBehavior:       func(a *service_mocks.MockAuth) {},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "Customer",
+			headers: map[string]string{"X-API-Key": "pk_merchant"},
+			mockBehavior: func(a *service_mocks.MockAuth) {
+				a.EXPECT().Authenticate(gomock.Any(), "pk_merchant").Return(&auth.Principal{KeyID: 2, Role: auth.RoleCustomer}, nil)
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:    "Metrics",
+			headers: map[string]string{"X-API-Key": "pk_prometheus"},
+			mockBehavior: func(a *service_mocks.MockAuth) {
+				a.EXPECT().Authenticate(gomock.Any(), "pk_prometheus").Return(&auth.Principal{KeyID: 3, Role: auth.RoleMetrics}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:    "Admin",
+			headers: map[string]string{"X-API-Key": "pk_admin"},
+			mockBehavior: func(a *service_mocks.MockAuth) {
+				a.EXPECT().Authenticate(gomock.Any(), "pk_admin").Return(&auth.Principal{KeyID: 1, Role: auth.RoleAdmin}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authMock := service_mocks.NewMockAuth(c)
+			tt.mockBehavior(authMock)
+			handler := NewHandler(&service.Service{Auth: authMock}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			handler.InitRoutes().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_Authentication_RateLimit(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Запросы сверх лимита IP-адреса отклоняются до проверки ключа.
+	authMock := service_mocks.NewMockAuth(c)
+	authMock.EXPECT().Authenticate(gomock.Any(), "pk_invalid").Return(nil, domain.ErrInvalidAPIKey).Times(2)
+	handler := NewHandler(&service.Service{Auth: authMock}, configs.Config{IPRateLimitRPS: 1, IPRateLimitBurst: 2})
+	routes := handler.InitRoutes()
+
+	for _, expected := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/wallets", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-API-Key", "pk_invalid")
+
+		routes.ServeHTTP(w, req)
+
+		assert.Equal(t, expected, w.Code)
+	}
+}
+
+func TestHandler_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         func(s *service_mocks.MockAuth)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"merchant","wallets":["` + addr1 + `"]}`,
+			mockBehavior: func(s *service_mocks.MockAuth) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), models.CreateAPIKeyRequest{Name: "merchant", Wallets: []string{addr1}}).
+					Return(&models.CreateAPIKeyResponse{
+						APIKey: models.APIKey{ID: 3, Name: "merchant", Scopes: []string{}, Wallets: []string{addr1}},
+						Key:    "pk_secret",
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":3,"name":"merchant","scopes":[],"wallets":["` + addr1 + `"],"created_at":"0001-01-01T00:00:00Z","key":"pk_secret"}` + "\n",
+		},
+		{
+			name:                 "Invalid Wallet",
+			inputBody:            `{"name":"merchant","wallets":["` + addr1 + `","wallet-1"]}`,
+			mockBehavior:         func(s *service_mocks.MockAuth) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"invalid wallet address: must be 64 characters long","details":{"field":"wallets"}}` + "\n",
+		},
+		{
+			name:                 "Invalid JSON",
+			inputBody:            `{"name":`,
+			mockBehavior:         func(s *service_mocks.MockAuth) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Invalid request body"}` + "\n",
+		},
+		{
+			name:      "Unknown Scope",
+			inputBody: `{"name":"merchant","scopes":["root"]}`,
+			mockBehavior: func(s *service_mocks.MockAuth) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidScope)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_scope","message":"unknown api key scope"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			authMock := service_mocks.NewMockAuth(c)
+			tt.mockBehavior(authMock)
+
+			handler := NewHandler(&service.Service{Auth: authMock}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/keys", bytes.NewBufferString(tt.inputBody))
+
+			handler.CreateAPIKey(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	codeInvalidRequest               = "invalid_request"
+	codeMethodNotAllowed             = "method_not_allowed"
+	codeInternalError                = "internal_error"
+	codeTimeout                      = "timeout"
+	codeWalletNotFound               = "wallet_not_found"
+	codeWalletAlreadyExists          = "wallet_already_exists"
+	codeWalletFrozen                 = "wallet_frozen"
+	codeWalletClosed                 = "wallet_closed"
+	codeWalletNotEmpty               = "wallet_not_empty"
+	codeInvalidWalletStatus          = "invalid_wallet_status"
+	codeTierNotFound                 = "tier_not_found"
+	codeTierAlreadyExists            = "tier_already_exists"
+	codeAmountBelowMinimum           = "amount_below_minimum"
+	codeAmountAboveMaximum           = "amount_above_maximum"
+	codeDailyLimitExceeded           = "daily_limit_exceeded"
+	codeMonthlyLimitExceeded         = "monthly_limit_exceeded"
+	codeMaxBalanceExceeded           = "max_balance_exceeded"
+	codeUnsupportedCurrency          = "unsupported_currency"
+	codeInvalidAmountPrecision       = "invalid_amount_precision"
+	codeCurrencyMismatch             = "currency_mismatch"
+	codeConversionUnavailable        = "conversion_unavailable"
+	codeFeeUnavailable               = "fee_unavailable"
+	codeInsufficientFunds            = "insufficient_funds"
+	codeSameWallet                   = "same_wallet"
+	codeInvalidCursor                = "invalid_cursor"
+	codeTransactionNotFound          = "transaction_not_found"
+	codeTransactionNotReversible     = "transaction_not_reversible"
+	codeTransactionAlreadyReversed   = "transaction_already_reversed"
+	codeScheduledTransferNotFound    = "scheduled_transfer_not_found"
+	codeScheduledTransferCancelled   = "scheduled_transfer_cancelled"
+	codeHoldNotFound                 = "hold_not_found"
+	codeHoldNotActive                = "hold_not_active"
+	codeHoldExpired                  = "hold_expired"
+	codeInvalidQuote                 = "invalid_quote"
+	codeQuoteExpired                 = "quote_expired"
+	codeQuoteMismatch                = "quote_mismatch"
+	codeQuoteUsed                    = "quote_used"
+	codeUnauthenticated              = "unauthenticated"
+	codeInvalidAPIKey                = "invalid_api_key"
+	codeForbidden                    = "forbidden"
+	codeWalletNotOwned               = "wallet_not_owned"
+	codeInvalidScope                 = "invalid_scope"
+	codeInvalidCredentials           = "invalid_credentials"
+	codeInvalidToken                 = "invalid_token"
+	codeUserNotFound                 = "user_not_found"
+	codeUserAlreadyExists            = "user_already_exists"
+	codeInvalidRole                  = "invalid_role"
+	codeLimitExceeded                = "limit_exceeded"
+	codeIdempotencyKeyReused         = "idempotency_key_reused"
+	codeIdempotencyRequestInProgress = "idempotency_request_in_progress"
+)
+
+// errorMappings сопоставляет ошибки предметной области кодам ответа HTTP и кодам ошибок API.
+// Ошибки проверяются по порядку через errors.Is, поэтому более частные ошибки должны идти раньше.
+var errorMappings = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{domain.ErrWalletNotFound, http.StatusNotFound, codeWalletNotFound},
+	{domain.ErrWalletAlreadyExists, http.StatusConflict, codeWalletAlreadyExists},
+	{domain.ErrWalletFrozen, http.StatusConflict, codeWalletFrozen},
+	{domain.ErrWalletClosed, http.StatusConflict, codeWalletClosed},
+	{domain.ErrWalletNotEmpty, http.StatusConflict, codeWalletNotEmpty},
+	{domain.ErrInvalidWalletStatus, http.StatusBadRequest, codeInvalidWalletStatus},
+	{domain.ErrTierNotFound, http.StatusNotFound, codeTierNotFound},
+	{domain.ErrTierAlreadyExists, http.StatusConflict, codeTierAlreadyExists},
+	{domain.ErrAmountBelowMinimum, http.StatusBadRequest, codeAmountBelowMinimum},
+	{domain.ErrAmountAboveMaximum, http.StatusBadRequest, codeAmountAboveMaximum},
+	{domain.ErrDailyLimitExceeded, http.StatusUnprocessableEntity, codeDailyLimitExceeded},
+	{domain.ErrMonthlyLimitExceeded, http.StatusUnprocessableEntity, codeMonthlyLimitExceeded},
+	{domain.ErrMaxBalanceExceeded, http.StatusUnprocessableEntity, codeMaxBalanceExceeded},
+	{domain.ErrUnsupportedCurrency, http.StatusBadRequest, codeUnsupportedCurrency},
+	{domain.ErrInvalidAmountPrecision, http.StatusBadRequest, codeInvalidAmountPrecision},
+	{domain.ErrCurrencyMismatch, http.StatusUnprocessableEntity, codeCurrencyMismatch},
+	{domain.ErrConversionUnavailable, http.StatusUnprocessableEntity, codeConversionUnavailable},
+	{domain.ErrFeeUnavailable, http.StatusUnprocessableEntity, codeFeeUnavailable},
+	{domain.ErrInsufficientFunds, http.StatusBadRequest, codeInsufficientFunds},
+	{domain.ErrSameWallet, http.StatusBadRequest, codeSameWallet},
+	{domain.ErrInvalidCursor, http.StatusBadRequest, codeInvalidCursor},
+	{domain.ErrTransactionNotFound, http.StatusNotFound, codeTransactionNotFound},
+	{domain.ErrTransactionNotReversible, http.StatusConflict, codeTransactionNotReversible},
+	{domain.ErrTransactionAlreadyReversed, http.StatusConflict, codeTransactionAlreadyReversed},
+	{domain.ErrScheduledTransferNotFound, http.StatusNotFound, codeScheduledTransferNotFound},
+	{domain.ErrScheduledTransferCancelled, http.StatusConflict, codeScheduledTransferCancelled},
+	{domain.ErrHoldNotFound, http.StatusNotFound, codeHoldNotFound},
+	{domain.ErrHoldNotActive, http.StatusConflict, codeHoldNotActive},
+	{domain.ErrHoldExpired, http.StatusConflict, codeHoldExpired},
+	{domain.ErrInvalidQuote, http.StatusBadRequest, codeInvalidQuote},
+	{domain.ErrQuoteExpired, http.StatusUnprocessableEntity, codeQuoteExpired},
+	{domain.ErrQuoteMismatch, http.StatusUnprocessableEntity, codeQuoteMismatch},
+	{domain.ErrQuoteUsed, http.StatusConflict, codeQuoteUsed},
+	{domain.ErrUnauthenticated, http.StatusUnauthorized, codeUnauthenticated},
+	{domain.ErrInvalidAPIKey, http.StatusUnauthorized, codeInvalidAPIKey},
+	{domain.ErrForbidden, http.StatusForbidden, codeForbidden},
+	{domain.ErrWalletNotOwned, http.StatusForbidden, codeWalletNotOwned},
+	{domain.ErrInvalidScope, http.StatusBadRequest, codeInvalidScope},
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized, codeInvalidCredentials},
+	{domain.ErrInvalidToken, http.StatusUnauthorized, codeInvalidToken},
+	{domain.ErrUserNotFound, http.StatusNotFound, codeUserNotFound},
+	{domain.ErrUserAlreadyExists, http.StatusConflict, codeUserAlreadyExists},
+	{domain.ErrInvalidRole, http.StatusBadRequest, codeInvalidRole},
+	{domain.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, codeIdempotencyKeyReused},
+	{domain.ErrIdempotencyRequestInProgress, http.StatusConflict, codeIdempotencyRequestInProgress},
+}
+
+// writeError отправляет клиенту ошибку err в формате models.ErrorResponse.
+// Код ответа определяется по типу ошибки; текст неизвестных ошибок не раскрывается клиенту, а пишется в лог.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		var details map[string]string
+		if validationErr.Field != "" {
+			details = map[string]string{"field": validationErr.Field}
+		}
+		writeErrorResponse(w, r, http.StatusBadRequest, codeInvalidRequest, validationErr.Message, details)
+		return
+	}
+
+	var limitErr *domain.LimitError
+	if errors.As(err, &limitErr) {
+		w.Header().Set("Retry-After", retryAfterSeconds(limitErr.RetryAfter))
+		writeErrorResponse(w, r, http.StatusTooManyRequests, codeLimitExceeded, limitErr.Error(), map[string]string{"limit": limitErr.Limit})
+		return
+	}
+
+	// Драйвер PostgreSQL может вернуть вместо context.DeadlineExceeded собственную ошибку отмены запроса,
+	// поэтому истечение времени определяется и по контексту запроса.
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		log.Printf("Request %s timed out: %v", requestIDFromContext(r.Context()), err)
+		writeErrorResponse(w, r, http.StatusGatewayTimeout, codeTimeout, "request timed out", nil)
+		return
+	}
+
+	for _, m := range errorMappings {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+		var details map[string]string
+		var walletErr *domain.WalletError
+		if errors.As(err, &walletErr) {
+			details = map[string]string{
+				"role":    string(walletErr.Role),
+				"address": walletErr.Address,
+			}
+		}
+		writeErrorResponse(w, r, m.status, m.code, err.Error(), details)
+		return
+	}
+
+	log.Printf("Request %s failed: %v", requestIDFromContext(r.Context()), err)
+	writeErrorResponse(w, r, http.StatusInternalServerError, codeInternalError, "internal server error", nil)
+}
+
+// retryAfterSeconds возвращает значение заголовка Retry-After: число секунд, округленное вверх и не меньшее 1.
+func retryAfterSeconds(d time.Duration) string {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
+
+// writeErrorResponse отправляет клиенту ошибку с кодом ответа status, кодом ошибки code и текстом message.
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, status int, code string, message string, details map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: requestIDFromContext(r.Context()),
+	})
+}
+
+// writeMethodNotAllowed отправляет клиенту ошибку о неподдерживаемом методе запроса.
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeErrorResponse(w, r, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Method not allowed", nil)
+}
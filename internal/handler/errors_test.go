@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name                 string
+		err                  error
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedRetryAfter   string
+	}{
+		{
+			name:                 "Wrapped Sentinel",
+			err:                  fmt.Errorf("transfer: %w", domain.ErrInsufficientFunds),
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"insufficient_funds","message":"transfer: insufficient funds","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Wallet Error",
+			err:                  domain.NewWalletError(models.TransactionRoleRecipient, "addr2", domain.ErrWalletClosed),
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"wallet_closed","message":"recipient wallet is closed","details":{"address":"addr2","role":"recipient"},"request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Validation Error",
+			err:                  domain.NewValidationError("limit", "limit is too large"),
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"limit is too large","details":{"field":"limit"},"request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Same Wallet",
+			err:                  domain.ErrSameWallet,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"same_wallet","message":"sender and recipient wallets must differ","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Invalid Quote",
+			err:                  domain.ErrInvalidQuote,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_quote","message":"invalid quote","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Currency Mismatch",
+			err:                  domain.ErrCurrencyMismatch,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"code":"currency_mismatch","message":"sender and recipient wallets have different currencies","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Tier Limit Exceeded",
+			err:                  domain.NewWalletError(models.TransactionRoleSender, "addr1", domain.ErrDailyLimitExceeded),
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"code":"daily_limit_exceeded","message":"sender daily transfer limit exceeded","details":{"address":"addr1","role":"sender"},"request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Limit Exceeded",
+			err:                  domain.NewLimitError(domain.LimitTransfersPerMinute, 1500*time.Millisecond),
+			expectedStatusCode:   http.StatusTooManyRequests,
+			expectedResponseBody: `{"code":"limit_exceeded","message":"limit exceeded: transfers_per_minute","details":{"limit":"transfers_per_minute"},"request_id":"req-1"}` + "\n",
+			expectedRetryAfter:   "2",
+		},
+		{
+			name:                 "Deadline Exceeded",
+			err:                  fmt.Errorf("failed to execute query: %w", context.DeadlineExceeded),
+			expectedStatusCode:   http.StatusGatewayTimeout,
+			expectedResponseBody: `{"code":"timeout","message":"request timed out","request_id":"req-1"}` + "\n",
+		},
+		{
+			name:                 "Unknown Error",
+			err:                  errors.New("connection refused"),
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error","request_id":"req-1"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeError(w, r, tt.err)
+			}))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/wallets", nil)
+			req.Header.Set("X-Request-ID", "req-1")
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			assert.Equal(t, tt.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestWithRequestID_Generated(t *testing.T) {
+	var fromContext string
+	handler := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromContext = requestIDFromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/wallets", nil))
+
+	assert.Len(t, fromContext, 32)
+	assert.Equal(t, fromContext, w.Header().Get("X-Request-ID"))
+}
+
+func TestWithTimeout(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	handler := withTimeout(time.Second, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/wallets", nil))
+
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second)
+
+	handler = withTimeout(0, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/wallets", nil))
+
+	assert.False(t, hasDeadline)
+}
@@ -1,28 +1,77 @@
 package handler
 
 import (
+	"golangTestTask/configs"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/metrics"
 	"golangTestTask/internal/service"
 	"net/http"
+	"time"
 
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 type Handler struct {
-	services *service.Service
+	services         *service.Service
+	requestTimeout   time.Duration
+	rateLimitRPS     float64
+	rateLimitBurst   int
+	ipRateLimitRPS   float64
+	ipRateLimitBurst int
 }
 
 // NewHandler создает новый экземпляр Handler.
-func NewHandler(services *service.Service) *Handler {
-	return &Handler{services: services}
+func NewHandler(services *service.Service, config configs.Config) *Handler {
+	return &Handler{
+		services:         services,
+		requestTimeout:   config.DBRequestTimeout,
+		rateLimitRPS:     config.RateLimitRPS,
+		rateLimitBurst:   config.RateLimitBurst,
+		ipRateLimitRPS:   config.IPRateLimitRPS,
+		ipRateLimitBurst: config.IPRateLimitBurst,
+	}
 }
 
-// InitRoutes инициализирует маршруты HTTP для обработчика Handler и возвращает настроенный мультиплексор (*http.ServeMux).
-func (h *Handler) InitRoutes() *http.ServeMux {
+// InitRoutes инициализирует маршруты HTTP для обработчика Handler и возвращает мультиплексор,
+// обернутый промежуточными обработчиками, которые присваивают запросам идентификатор, ограничивают время их обработки,
+// аутентифицируют клиента и собирают метрики. Каждый маршрут требует разрешения, которое выдается ролям участников.
+func (h *Handler) InitRoutes() http.Handler {
 	router := http.NewServeMux()
-	router.HandleFunc("POST /api/send", h.Send)
-	router.HandleFunc("GET /api/transactions", h.GetLast)
-	router.HandleFunc("GET /api/wallet/{address}/balance", h.GetBalance)
-	router.HandleFunc("GET /api/wallets", h.GetAllWallets)
+	router.HandleFunc("POST /api/auth/login", h.Login)
+	router.HandleFunc("POST /api/auth/refresh", h.Refresh)
+	router.HandleFunc("POST /api/auth/logout", h.Logout)
+	router.HandleFunc("POST /api/send", requirePermission(auth.PermissionTransfer, h.idempotent(h.Send)))
+	router.HandleFunc("POST /api/send/quote", requirePermission(auth.PermissionTransfer, h.Quote))
+	router.HandleFunc("GET /api/transactions", requirePermission(auth.PermissionReadAllTransactions, h.ListTransactions))
+	router.HandleFunc("POST /api/transactions/{id}/reverse", requirePermission(auth.PermissionReverseTransactions, h.ReverseTransaction))
+	router.HandleFunc("POST /api/scheduled-transfers", requirePermission(auth.PermissionTransfer, h.CreateScheduledTransfer))
+	router.HandleFunc("GET /api/scheduled-transfers", requirePermission(auth.PermissionReadOwnWallets, h.ListScheduledTransfers))
+	router.HandleFunc("DELETE /api/scheduled-transfers/{id}", requirePermission(auth.PermissionTransfer, h.CancelScheduledTransfer))
+	router.HandleFunc("GET /api/scheduled-transfers/{id}/runs", requirePermission(auth.PermissionReadOwnWallets, h.ListScheduledTransferRuns))
+	router.HandleFunc("POST /api/holds", requirePermission(auth.PermissionTransfer, h.idempotent(h.CreateHold)))
+	router.HandleFunc("GET /api/holds/{id}", requirePermission(auth.PermissionReadOwnWallets, h.GetHold))
+	router.HandleFunc("POST /api/holds/{id}/capture", requirePermission(auth.PermissionTransfer, h.CaptureHold))
+	router.HandleFunc("POST /api/holds/{id}/void", requirePermission(auth.PermissionTransfer, h.VoidHold))
+	router.HandleFunc("POST /api/wallets", requirePermission(auth.PermissionCreateWallet, h.CreateWallet))
+	router.HandleFunc("GET /api/wallets", requirePermission(auth.PermissionReadAllWallets, h.GetAllWallets))
+	router.HandleFunc("GET /api/wallet/{address}", requireWalletAccess(h.GetWallet))
+	router.HandleFunc("GET /api/wallet/{address}/balance", requireWalletAccess(h.GetBalance))
+	router.HandleFunc("GET /api/wallet/{address}/transactions", requireWalletAccess(h.GetWalletTransactions))
+	router.HandleFunc("PUT /api/wallet/{address}/status", requirePermission(auth.PermissionChangeWalletStatus, h.UpdateWalletStatus))
+	router.HandleFunc("PUT /api/wallet/{address}/tier", requirePermission(auth.PermissionManageTiers, h.UpdateWalletTier))
+	router.HandleFunc("GET /api/tiers", requirePermission(auth.PermissionReadTiers, h.GetAllTiers))
+	router.HandleFunc("POST /api/tiers", requirePermission(auth.PermissionManageTiers, h.CreateTier))
+	router.HandleFunc("PUT /api/tiers/{name}", requirePermission(auth.PermissionManageTiers, h.UpdateTier))
+	router.HandleFunc("GET /api/ledger/reconciliation", requirePermission(auth.PermissionReadLedger, h.Reconcile))
+	router.HandleFunc("POST /api/ledger/rebuild", requirePermission(auth.PermissionManageLedger, h.RebuildBalances))
+	router.HandleFunc("POST /api/keys", requirePermission(auth.PermissionManageAPIKeys, h.CreateAPIKey))
+	router.HandleFunc("POST /api/users", requirePermission(auth.PermissionManageUsers, h.CreateUser))
+	router.HandleFunc("GET /metrics", requirePermission(auth.PermissionReadMetrics, metrics.Handler().ServeHTTP))
 	router.Handle("/swagger/", httpSwagger.WrapHandler)
-	return router
+	// Ограничение по IP-адресу действует до аутентификации, чтобы перебор ключей API и токенов тоже ограничивался,
+	// а ограничение по клиенту — после нее, чтобы клиенты за одним IP-адресом не делили общий лимит.
+	// Метрики собираются снаружи всей цепочки, чтобы в них попадали и ответы промежуточных обработчиков.
+	clients := withRateLimit(h.rateLimitRPS, h.rateLimitBurst, rateLimitKey, router)
+	chain := withRequestID(withTimeout(h.requestTimeout, withRateLimit(h.ipRateLimitRPS, h.ipRateLimitBurst, clientIPKey, h.authenticate(clients))))
+	return metrics.Middleware(router, chain)
 }
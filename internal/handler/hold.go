@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// CreateHold
+// @Summary Заблокировать средства
+// @Description Блокирует сумму на кошельке отправителя для последующего перевода получателю (первая фаза двухфазного перевода).
+// @Description Заблокированные средства остаются на кошельке, но недоступны для других переводов и блокировок,
+// @Description пока блокировка не будет списана (POST /api/holds/{id}/capture), отменена (POST /api/holds/{id}/void)
+// @Description или не истечет ее срок expires_in (по умолчанию 24 часа, не более 30 дней).
+// @Description Комиссия и ограничения на переводы применяются при списании. Поддерживает заголовок Idempotency-Key, как POST /api/send.
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param hold body models.CreateHoldRequest true "Данные блокировки"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повторный запрос того же клиента с тем же ключом вернет исходный ответ"
+// @Success 201 {object} models.Hold
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload, wallet address or expiry, same wallet, insufficient available funds or amount too precise for the currency"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Wallet not found"
+// @Failure 409 {object} models.ErrorResponse "Wallet is frozen or closed, or request with this idempotency key is in progress"
+// @Failure 422 {object} models.ErrorResponse "Idempotency key reused with a different request, wallet currencies differ without conversion or no exchange rate"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/holds [post]
+func (h *Handler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, money.ErrTooManyFractionDigits) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must have at most 2 fractional digits"))
+			return
+		}
+		if errors.Is(err, money.ErrAmountOverflow) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must not exceed "+money.MaxAmount.String()))
+			return
+		}
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if req.From == "" || req.To == "" || req.Amount <= 0 {
+		writeError(w, r, domain.NewValidationError("", "Missing required fields or invalid amount"))
+		return
+	}
+	if req.ExpiresIn < 0 {
+		writeError(w, r, domain.NewValidationError("expires_in", "Expiry must be positive"))
+		return
+	}
+	if err := validateAddress("from", req.From); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := validateAddress("to", req.To); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if req.From == req.To {
+		writeError(w, r, domain.ErrSameWallet)
+		return
+	}
+
+	hold, err := h.services.AuthorizeHold(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+// GetHold
+// @Summary Получить блокировку средств
+// @Description Возвращает блокировку средств: сумму, статус (active, captured, voided или expired), срок действия
+// @Description и, для списанной блокировки, списанную сумму и транзакцию перевода.
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path int true "ID блокировки"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} models.ErrorResponse "Invalid hold ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or wallets are not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Hold not found"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/holds/{id} [get]
+func (h *Handler) GetHold(w http.ResponseWriter, r *http.Request) {
+	id, err := parseHoldID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hold, err := h.services.GetHold(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// CaptureHold
+// @Summary Списать заблокированные средства
+// @Description Переводит получателю всю заблокированную сумму или ее часть amount (вторая фаза двухфазного перевода);
+// @Description остаток разблокируется. Перевод выполняется с теми же проверками, комиссией и ограничениями, что и POST /api/send.
+// @Description Блокировку можно списать только один раз и только до истечения ее срока.
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path int true "ID блокировки"
+// @Param capture body models.CaptureHoldRequest false "Списываемая сумма; без тела списывается вся сумма блокировки"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} models.ErrorResponse "Invalid hold ID or amount, amount exceeds the held amount, insufficient funds or amount outside the sender tier limits"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Hold or wallet not found"
+// @Failure 409 {object} models.ErrorResponse "Hold is not active or has expired, wallet is frozen or closed"
+// @Failure 422 {object} models.ErrorResponse "No exchange rate, no fee wallet in the sender currency, or daily, monthly or balance limit of the wallet tier exceeded"
+// @Failure 429 {object} models.ErrorResponse "Rate limit or wallet transfer limit exceeded"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/holds/{id}/capture [post]
+func (h *Handler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	id, err := parseHoldID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req models.CaptureHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		if errors.Is(err, money.ErrTooManyFractionDigits) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must have at most 2 fractional digits"))
+			return
+		}
+		if errors.Is(err, money.ErrAmountOverflow) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must not exceed "+money.MaxAmount.String()))
+			return
+		}
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if req.Amount != nil && *req.Amount <= 0 {
+		writeError(w, r, domain.NewValidationError("amount", "Amount must be positive"))
+		return
+	}
+
+	hold, err := h.services.CaptureHold(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// VoidHold
+// @Summary Отменить блокировку средств
+// @Description Отменяет активную блокировку, заблокированные средства снова становятся доступны для переводов.
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path int true "ID блокировки"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} models.ErrorResponse "Invalid hold ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Hold not found"
+// @Failure 409 {object} models.ErrorResponse "Hold is not active or has expired"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/holds/{id}/void [post]
+func (h *Handler) VoidHold(w http.ResponseWriter, r *http.Request) {
+	id, err := parseHoldID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hold, err := h.services.VoidHold(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// parseHoldID читает ID блокировки средств из параметра маршрута {id}.
+func parseHoldID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, domain.NewValidationError("id", "Hold ID must be a positive integer")
+	}
+	return id, nil
+}
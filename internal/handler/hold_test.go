@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_CreateHold(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockHold)
+
+	expiresAt := time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "30.00", "expires_in": 3600}`,
+			mockBehavior: func(s *service_mocks.MockHold) {
+				s.EXPECT().AuthorizeHold(gomock.Any(), models.CreateHoldRequest{
+					From: addr1, To: addr2, Amount: money.FromInt(30), ExpiresIn: 3600,
+				}).Return(&models.Hold{
+					ID: 5, From: addr1, To: addr2, Amount: money.FromInt(30), Currency: "USD",
+					Status: models.HoldStatusActive, ExpiresAt: expiresAt, CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{"id":5,"from":"` + addr1 + `","to":"` + addr2 + `","amount":"30.00","currency":"USD","status":"active",` +
+				`"expires_at":"2025-01-15T13:00:00Z","created_at":"2025-01-15T12:00:00Z"}` + "\n",
+		},
+		{
+			name:                 "Missing Amount",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr2 + `"}`,
+			mockBehavior:         func(s *service_mocks.MockHold) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Missing required fields or invalid amount"}` + "\n",
+		},
+		{
+			name:                 "Negative Expiry",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "30.00", "expires_in": -1}`,
+			mockBehavior:         func(s *service_mocks.MockHold) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Expiry must be positive","details":{"field":"expires_in"}}` + "\n",
+		},
+		{
+			name:                 "Same Wallet",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr1 + `", "amount": "30.00"}`,
+			mockBehavior:         func(s *service_mocks.MockHold) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"same_wallet","message":"sender and recipient wallets must differ"}` + "\n",
+		},
+		{
+			name:      "Insufficient Funds",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "30.00"}`,
+			mockBehavior: func(s *service_mocks.MockHold) {
+				s.EXPECT().AuthorizeHold(gomock.Any(), gomock.Any()).Return(nil, domain.ErrInsufficientFunds)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"insufficient_funds","message":"insufficient funds"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			holdMock := service_mocks.NewMockHold(c)
+			tt.mockBehavior(holdMock)
+
+			services := &service.Service{Hold: holdMock}
+			handler := NewHandler(services, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/holds", bytes.NewBufferString(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			handler.CreateHold(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_CaptureHold(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockHold)
+
+	partial := money.FromInt(25)
+	transactionID := 42
+	finalizedAt := time.Date(2025, 1, 15, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name                 string
+		id                   string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Partial",
+			id:        "5",
+			inputBody: `{"amount": "25.00"}`,
+			mockBehavior: func(s *service_mocks.MockHold) {
+				s.EXPECT().CaptureHold(gomock.Any(), 5, models.CaptureHoldRequest{Amount: &partial}).Return(&models.Hold{
+					ID: 5, Amount: money.FromInt(30), Status: models.HoldStatusCaptured,
+					CapturedAmount: &partial, TransactionID: &transactionID, FinalizedAt: &finalizedAt,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"id":5,"from":"","to":"","amount":"30.00","currency":"","status":"captured","captured_amount":"25.00","transaction_id":42,` +
+				`"expires_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z","finalized_at":"2025-01-15T12:30:00Z"}` + "\n",
+		},
+		{
+			name: "Full Without Body",
+			id:   "5",
+			mockBehavior: func(s *service_mocks.MockHold) {
+				s.EXPECT().CaptureHold(gomock.Any(), 5, models.CaptureHoldRequest{}).Return(nil, domain.ErrHoldExpired)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"hold_expired","message":"hold has expired"}` + "\n",
+		},
+		{
+			name:                 "Zero Amount",
+			id:                   "5",
+			inputBody:            `{"amount": "0"}`,
+			mockBehavior:         func(s *service_mocks.MockHold) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Amount must be positive","details":{"field":"amount"}}` + "\n",
+		},
+		{
+			name:                 "Invalid ID",
+			id:                   "abc",
+			mockBehavior:         func(s *service_mocks.MockHold) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Hold ID must be a positive integer","details":{"field":"id"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			holdMock := service_mocks.NewMockHold(c)
+			tt.mockBehavior(holdMock)
+
+			services := &service.Service{Hold: holdMock}
+			handler := NewHandler(services, configs.Config{})
+
+			r := http.NewServeMux()
+			r.HandleFunc("POST /api/holds/{id}/capture", handler.CaptureHold)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/holds/"+tt.id+"/capture", bytes.NewBufferString(tt.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_VoidHold(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockHold)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *service_mocks.MockHold) {
+				s.EXPECT().VoidHold(gomock.Any(), 5).Return(&models.Hold{ID: 5, Amount: money.FromInt(30), Status: models.HoldStatusVoided}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"id":5,"from":"","to":"","amount":"30.00","currency":"","status":"voided",` +
+				`"expires_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name: "Already Captured",
+			mockBehavior: func(s *service_mocks.MockHold) {
+				s.EXPECT().VoidHold(gomock.Any(), 5).Return(nil, domain.ErrHoldNotActive)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"hold_not_active","message":"hold has already been captured, voided or expired"}` + "\n",
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *service_mocks.MockHold) {
+				s.EXPECT().VoidHold(gomock.Any(), 5).Return(nil, domain.ErrHoldNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"code":"hold_not_found","message":"hold not found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			holdMock := service_mocks.NewMockHold(c)
+			tt.mockBehavior(holdMock)
+
+			services := &service.Service{Hold: holdMock}
+			handler := NewHandler(services, configs.Config{})
+
+			r := http.NewServeMux()
+			r.HandleFunc("POST /api/holds/{id}/void", handler.VoidHold)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/holds/5/void", nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"io"
+	"log"
+	"net/http"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// idempotent оборачивает обработчик поддержкой заголовка Idempotency-Key.
+// Первый запрос с ключом выполняется, а его ответ сохраняется; повторный запрос с тем же ключом и телом
+// получает сохраненный ответ, а запрос с тем же ключом, но другим телом отклоняется с кодом 422.
+// Ответы с кодом 5xx не сохраняются, чтобы клиент мог повторить запрос после сбоя.
+// Ключи действуют в пределах участника: одинаковые ключи разных клиентов не пересекаются.
+// Все обработчики с поддержкой ключа отвечают в формате JSON, поэтому сохраняется только тело ответа.
+func (h *Handler) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeError(w, r, domain.NewValidationError(idempotencyKeyHeader, "Idempotency key is too long"))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, err := h.services.ReserveIdempotencyKey(r.Context(), key, requestHash(r, body))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if record != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(idempotentReplayedHeader, "true")
+			w.WriteHeader(record.StatusCode)
+			w.Write(record.ResponseBody)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		// Ответ сохраняется и после отключения клиента, иначе ключ останется занятым до истечения срока действия.
+		ctx := context.WithoutCancel(r.Context())
+
+		if rec.status >= http.StatusInternalServerError {
+			if err := h.services.ReleaseIdempotencyKey(ctx, key); err != nil {
+				log.Printf("Failed to release idempotency key %q: %v", key, err)
+			}
+			return
+		}
+		if err := h.services.SaveIdempotentResponse(ctx, key, rec.status, rec.body.Bytes()); err != nil {
+			log.Printf("Failed to save response for idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// requestHash вычисляет хеш запроса, по которому определяется повторное использование ключа с другим телом.
+// В хеш входит идентификатор участника, поэтому чужой клиент с тем же ключом идемпотентности не получит сохраненный ответ.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	if principal := auth.FromContext(r.Context()); principal != nil {
+		io.WriteString(h, principal.Subject()+"\n")
+	}
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder передает ответ клиенту, одновременно запоминая его код и тело.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
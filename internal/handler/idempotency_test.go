@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	service_mocks "golangTestTask/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_Idempotent(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockIdempotency)
+
+	tests := []struct {
+		name                 string
+		key                  string
+		nextStatus           int
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedNextCalls    int
+		expectedReplayed     bool
+	}{
+		{
+			name:                 "Without Key",
+			key:                  "",
+			nextStatus:           http.StatusOK,
+			mockBehavior:         func(s *service_mocks.MockIdempotency) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "done",
+			expectedNextCalls:    1,
+		},
+		{
+			name:       "First Request Saves Response",
+			key:        "key1",
+			nextStatus: http.StatusOK,
+			mockBehavior: func(s *service_mocks.MockIdempotency) {
+				s.EXPECT().ReserveIdempotencyKey(gomock.Any(), "key1", gomock.Any()).Return(nil, nil)
+				s.EXPECT().SaveIdempotentResponse(gomock.Any(), "key1", http.StatusOK, []byte("done")).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "done",
+			expectedNextCalls:    1,
+		},
+		{
+			name:       "Client Error Is Saved",
+			key:        "key1",
+			nextStatus: http.StatusBadRequest,
+			mockBehavior: func(s *service_mocks.MockIdempotency) {
+				s.EXPECT().ReserveIdempotencyKey(gomock.Any(), "key1", gomock.Any()).Return(nil, nil)
+				s.EXPECT().SaveIdempotentResponse(gomock.Any(), "key1", http.StatusBadRequest, []byte("done")).Return(nil)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "done",
+			expectedNextCalls:    1,
+		},
+		{
+			name:       "Server Error Releases Key",
+			key:        "key1",
+			nextStatus: http.StatusInternalServerError,
+			mockBehavior: func(s *service_mocks.MockIdempotency) {
+				s.EXPECT().ReserveIdempotencyKey(gomock.Any(), "key1", gomock.Any()).Return(nil, nil)
+				s.EXPECT().ReleaseIdempotencyKey(gomock.Any(), "key1").Return(nil)
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: "done",
+			expectedNextCalls:    1,
+		},
+		{
+			name: "Replay",
+			key:  "key1",
+			mockBehavior: func(s *service_mocks.MockIdempotency) {
+				s.EXPECT().ReserveIdempotencyKey(gomock.Any(), "key1", gomock.Any()).Return(&models.IdempotencyRecord{
+					Key:          "key1",
+					StatusCode:   http.StatusOK,
+					ResponseBody: []byte("original"),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "original",
+			expectedReplayed:     true,
+		},
+		{
+			name: "Key Reused",
+			key:  "key1",
+			mockBehavior: func(s *service_mocks.MockIdempotency) {
+				s.EXPECT().ReserveIdempotencyKey(gomock.Any(), "key1", gomock.Any()).Return(nil, domain.ErrIdempotencyKeyReused)
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"code":"idempotency_key_reused","message":"idempotency key was already used with a different request"}` + "\n",
+		},
+		{
+			name: "In Progress",
+			key:  "key1",
+			mockBehavior: func(s *service_mocks.MockIdempotency) {
+				s.EXPECT().ReserveIdempotencyKey(gomock.Any(), "key1", gomock.Any()).Return(nil, domain.ErrIdempotencyRequestInProgress)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"idempotency_request_in_progress","message":"request with this idempotency key is still in progress"}` + "\n",
+		},
+		{
+			name: "Service Error",
+			key:  "key1",
+			mockBehavior: func(s *service_mocks.MockIdempotency) {
+				s.EXPECT().ReserveIdempotencyKey(gomock.Any(), "key1", gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			idempotencyMock := service_mocks.NewMockIdempotency(c)
+			tt.mockBehavior(idempotencyMock)
+
+			services := &service.Service{Idempotency: idempotencyMock}
+			handler := NewHandler(services, configs.Config{})
+
+			nextCalls := 0
+			next := func(w http.ResponseWriter, r *http.Request) {
+				nextCalls++
+				w.WriteHeader(tt.nextStatus)
+				w.Write([]byte("done"))
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/send", bytes.NewBufferString(`{"from":"`+addr1+`"}`))
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+
+			handler.idempotent(next)(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			assert.Equal(t, tt.expectedNextCalls, nextCalls)
+			if tt.expectedReplayed {
+				assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestRequestHash(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/send", nil)
+	assert.Equal(t, requestHash(req, []byte(`{"amount":"1.00"}`)), requestHash(req, []byte(`{"amount":"1.00"}`)))
+	assert.NotEqual(t, requestHash(req, []byte(`{"amount":"1.00"}`)), requestHash(req, []byte(`{"amount":"2.00"}`)))
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"log"
+	"net/http"
+)
+
+// Форматы отчета о сверке балансов, задаваемые параметром format.
+const (
+	reportFormatJSON = "json"
+	reportFormatCSV  = "csv"
+)
+
+// Reconcile сверяет балансы кошельков с журналом
+// @Summary Сверить балансы с журналом
+// @Description Сравнивает баланс каждого кошелька с суммой его проводок в журнале двойной записи, а ее — с балансом, восстановленным по начальным зачислениям и истории транзакций.
+// @Description Возвращает отчет о расхождениях балансов, несбалансированных записях журнала, записях, не совпадающих со своей транзакцией, и завершенных транзакциях без записи в журнале.
+// @Description Данные не изменяются. В формате CSV отчет содержит только расхождения балансов
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "Формат отчета: json (по умолчанию) или csv"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {object} models.ReconciliationReport
+// @Failure 400 {object} models.ErrorResponse "Invalid format"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/ledger/reconciliation [get]
+func (h *Handler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = reportFormatJSON
+	}
+	if format != reportFormatJSON && format != reportFormatCSV {
+		writeError(w, r, domain.NewValidationError("format", "Format must be json or csv"))
+		return
+	}
+
+	report, err := h.services.Reconcile(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if format == reportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="reconciliation.csv"`)
+		if err := report.WriteCSV(w); err != nil {
+			log.Printf("Failed to write reconciliation report: %v", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// RebuildBalances пересчитывает балансы кошельков по журналу
+// @Summary Пересчитать балансы по журналу
+// @Description Заменяет баланс каждого кошелька суммой его проводок в журнале двойной записи и возвращает количество исправленных кошельков. На время пересчета переводы блокируются
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {object} models.RebuildBalancesResponse
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/ledger/rebuild [post]
+func (h *Handler) RebuildBalances(w http.ResponseWriter, r *http.Request) {
+	corrected, err := h.services.RebuildBalances(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.RebuildBalancesResponse{Corrected: corrected})
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golangTestTask/configs"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_RebuildBalances(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockLedger)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *service_mocks.MockLedger) {
+				s.EXPECT().RebuildBalances(gomock.Any()).Return(int64(2), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"corrected":2}` + "\n",
+		},
+		{
+			name: "Server Error",
+			mockBehavior: func(s *service_mocks.MockLedger) {
+				s.EXPECT().RebuildBalances(gomock.Any()).Return(int64(0), errors.New("db error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			ledger := service_mocks.NewMockLedger(c)
+			tt.mockBehavior(ledger)
+
+			handler := NewHandler(&service.Service{Ledger: ledger}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/ledger/rebuild", nil)
+
+			handler.RebuildBalances(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_Reconcile(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockLedger)
+
+	generatedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	report := &models.ReconciliationReport{
+		GeneratedAt:    generatedAt,
+		WalletsChecked: 3,
+		Discrepancies: []models.BalanceDiscrepancy{
+			{Address: "addr2", Currency: "USD", Stored: money.MustParse("15.50"), Ledger: money.FromInt(10), Expected: money.FromInt(10), Difference: money.MustParse("5.50")},
+		},
+		UnbalancedEntries:      []int64{7},
+		UnrecordedTransactions: []int{12},
+		MismatchedEntries:      []int64{},
+	}
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name: "JSON",
+			mockBehavior: func(s *service_mocks.MockLedger) {
+				s.EXPECT().Reconcile(gomock.Any()).Return(report, nil)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+			expectedResponseBody: `{"generated_at":"2025-01-01T12:00:00Z","wallets_checked":3,` +
+				`"discrepancies":[{"address":"addr2","currency":"USD","stored":"15.50","ledger":"10.00","expected":"10.00","difference":"5.50"}],` +
+				`"unbalanced_entries":[7],"unrecorded_transactions":[12],"mismatched_entries":[]}` + "\n",
+		},
+		{
+			name:  "CSV",
+			query: "?format=csv",
+			mockBehavior: func(s *service_mocks.MockLedger) {
+				s.EXPECT().Reconcile(gomock.Any()).Return(report, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/csv",
+			expectedResponseBody: "address,currency,stored,ledger,expected,difference\naddr2,USD,15.50,10.00,10.00,5.50\n",
+		},
+		{
+			name:                 "Invalid Format",
+			query:                "?format=xml",
+			mockBehavior:         func(s *service_mocks.MockLedger) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json",
+			expectedResponseBody: `{"code":"invalid_request","message":"Format must be json or csv","details":{"field":"format"}}` + "\n",
+		},
+		{
+			name: "Server Error",
+			mockBehavior: func(s *service_mocks.MockLedger) {
+				s.EXPECT().Reconcile(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedContentType:  "application/json",
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			ledger := service_mocks.NewMockLedger(c)
+			tt.mockBehavior(ledger)
+
+			handler := NewHandler(&service.Service{Ledger: ledger}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/ledger/reconciliation"+tt.query, nil)
+
+			handler.Reconcile(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimiterIdleTTL — время, после которого корзина неактивного клиента удаляется.
+	// За это время корзина успевает заполниться, поэтому удаление не дает клиенту лишних запросов.
+	rateLimiterIdleTTL = 10 * time.Minute
+	// rateLimiterCleanupInterval — период удаления корзин неактивных клиентов.
+	rateLimiterCleanupInterval = time.Minute
+)
+
+// rateLimiter ограничивает частоту запросов каждого клиента алгоритмом token bucket:
+// корзина на burst запросов пополняется со скоростью limit запросов в секунду.
+type rateLimiter struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu          sync.Mutex
+	clients     map[string]*clientLimiter
+	lastCleanup time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter создает rateLimiter на rps запросов в секунду с запасом burst запросов.
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		limit:   rate.Limit(rps),
+		burst:   burst,
+		now:     time.Now,
+		clients: make(map[string]*clientLimiter),
+	}
+}
+
+// allow расходует один запрос клиента key. Если корзина пуста, возвращает false и время до появления в ней запроса.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastCleanup) >= rateLimiterCleanupInterval {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) >= rateLimiterIdleTTL {
+				delete(l.clients, k)
+			}
+		}
+		l.lastCleanup = now
+	}
+
+	client, ok := l.clients[key]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = client
+	}
+	client.lastSeen = now
+
+	reservation := client.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, rateLimiterIdleTTL
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// withRateLimit ограничивает частоту запросов каждого клиента, которого идентифицирует key. При превышении лимита
+// отвечает кодом 429 с заголовком Retry-After. Нулевое значение rps отключает ограничение.
+func withRateLimit(rps float64, burst int, key func(r *http.Request) string, next http.Handler) http.Handler {
+	if rps <= 0 {
+		return next
+	}
+	limiter := newRateLimiter(rps, burst)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := limiter.allow(key(r)); !ok {
+			writeError(w, r, domain.NewLimitError(domain.LimitRequests, retryAfter))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey возвращает идентификатор клиента, по которому ограничивается частота его запросов:
+// аутентифицированные клиенты различаются по ключу API или пользователю, остальные — по IP-адресу.
+func rateLimitKey(r *http.Request) string {
+	if principal := auth.FromContext(r.Context()); principal != nil {
+		return principal.Subject()
+	}
+	return clientIPKey(r)
+}
+
+// clientIPKey возвращает идентификатор IP-адреса клиента. Заголовки вроде X-Forwarded-For не учитываются,
+// так как клиент может подставить в них произвольный адрес.
+func clientIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
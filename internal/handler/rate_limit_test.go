@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, 2)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		ok, _ := limiter.allow("key:1")
+		assert.True(t, ok)
+	}
+	ok, retryAfter := limiter.allow("key:1")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Корзины клиентов независимы.
+	ok, _ = limiter.allow("key:2")
+	assert.True(t, ok)
+
+	// Отклоненный запрос не расходует токен: через полсекунды корзина пополняется на один запрос.
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = limiter.allow("key:1")
+	assert.True(t, ok)
+	ok, _ = limiter.allow("key:1")
+	assert.False(t, ok)
+
+	// Корзины неактивных клиентов удаляются.
+	now = now.Add(rateLimiterIdleTTL)
+	limiter.allow("key:2")
+	assert.Len(t, limiter.clients, 1)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		rps            float64
+		principal      *auth.Principal
+		expectedStatus []int
+	}{
+		{
+			name:           "Disabled",
+			rps:            0,
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:           "By IP",
+			rps:            1,
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "By Principal",
+			rps:            1,
+			principal:      &auth.Principal{KeyID: 1, Role: auth.RoleCustomer},
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := withRateLimit(tt.rps, 2, rateLimitKey, next)
+
+			for _, expected := range tt.expectedStatus {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest("GET", "/api/wallets", nil)
+				req.RemoteAddr = "192.0.2.1:1234"
+				if tt.principal != nil {
+					req = req.WithContext(auth.WithPrincipal(context.Background(), tt.principal))
+				}
+
+				handler.ServeHTTP(w, req)
+
+				assert.Equal(t, expected, w.Code)
+				if expected == http.StatusTooManyRequests {
+					assert.Equal(t, "1", w.Header().Get("Retry-After"))
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/wallets", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	assert.Equal(t, "ip:192.0.2.1", rateLimitKey(req))
+
+	req = req.WithContext(auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 7, Role: auth.RoleCustomer}))
+	assert.Equal(t, "user:7", rateLimitKey(req))
+	assert.Equal(t, "ip:192.0.2.1", clientIPKey(req))
+}
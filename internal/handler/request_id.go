@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	requestIDHeader     = "X-Request-ID"
+	maxRequestIDLength  = 128
+	requestIDContextKey = requestIDKey("request_id")
+)
+
+type requestIDKey string
+
+// withRequestID присваивает каждому запросу идентификатор: берет его из заголовка X-Request-ID
+// или генерирует новый. Идентификатор возвращается в заголовке ответа и в телах ошибок.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey, id)))
+	})
+}
+
+// requestIDFromContext возвращает идентификатор запроса или пустую строку, если он не задан.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
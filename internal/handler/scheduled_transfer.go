@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+	"net/http"
+	"strconv"
+)
+
+// CreateScheduledTransfer
+// @Summary Создать регулярный перевод
+// @Description Создает перевод, который сервис выполняет по расписанию от имени отправителя, например каждое 1-е число месяца.
+// @Description Расписание задается в формате cron из пяти полей (минута, час, день месяца, месяц, день недели) по UTC
+// @Description или сокращением @monthly, @weekly, @daily, @hourly, @every <интервал>; выполнять перевод чаще раза в минуту нельзя.
+// @Description Каждое выполнение проходит те же проверки, что и POST /api/send; его итог доступен в GET /api/scheduled-transfers/{id}/runs.
+// @Description Выполнение, не удавшееся из-за временной ошибки или лимита переводов, повторяется с растущей задержкой.
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param transfer body models.CreateScheduledTransferRequest true "Данные регулярного перевода"
+// @Success 201 {object} models.ScheduledTransfer
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload, wallet address or schedule, same wallet or amount too precise for the currency"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Wallet not found"
+// @Failure 409 {object} models.ErrorResponse "Wallet is closed"
+// @Failure 422 {object} models.ErrorResponse "Wallet currencies differ without conversion"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/scheduled-transfers [post]
+func (h *Handler) CreateScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateScheduledTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, money.ErrTooManyFractionDigits) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must have at most 2 fractional digits"))
+			return
+		}
+		if errors.Is(err, money.ErrAmountOverflow) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must not exceed "+money.MaxAmount.String()))
+			return
+		}
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if req.From == "" || req.To == "" || req.Schedule == "" || req.Amount <= 0 {
+		writeError(w, r, domain.NewValidationError("", "Missing required fields or invalid amount"))
+		return
+	}
+	if err := validateAddress("from", req.From); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := validateAddress("to", req.To); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if req.From == req.To {
+		writeError(w, r, domain.ErrSameWallet)
+		return
+	}
+
+	transfer, err := h.services.CreateScheduledTransfer(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// ListScheduledTransfers
+// @Summary Получить регулярные переводы
+// @Description Возвращает регулярные переводы с кошелька wallet от новых к старым, включая отмененные.
+// @Description Без параметра wallet возвращает переводы со всех кошельков вызывающего, а администратору — все регулярные переводы.
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param wallet query string false "Адрес кошелька отправителя"
+// @Success 200 {array} models.ScheduledTransfer
+// @Failure 400 {object} models.ErrorResponse "Invalid wallet address"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or wallet is not owned by the caller"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/scheduled-transfers [get]
+func (h *Handler) ListScheduledTransfers(w http.ResponseWriter, r *http.Request) {
+	wallet := r.URL.Query().Get("wallet")
+	if wallet != "" {
+		if err := validateAddress("wallet", wallet); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	transfers, err := h.services.ListScheduledTransfers(r.Context(), wallet)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfers)
+}
+
+// CancelScheduledTransfer
+// @Summary Отменить регулярный перевод
+// @Description Отменяет регулярный перевод: следующие выполнения не производятся. Уже выполненные переводы не отменяются.
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path int true "ID регулярного перевода"
+// @Success 200 {object} models.ScheduledTransfer
+// @Failure 400 {object} models.ErrorResponse "Invalid scheduled transfer ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Scheduled transfer not found"
+// @Failure 409 {object} models.ErrorResponse "Scheduled transfer is already cancelled"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/scheduled-transfers/{id} [delete]
+func (h *Handler) CancelScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	id, err := parseScheduledTransferID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	transfer, err := h.services.CancelScheduledTransfer(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// ListScheduledTransferRuns
+// @Summary Получить выполнения регулярного перевода
+// @Description Возвращает последние 50 попыток выполнить регулярный перевод от новых к старым: срок, номер попытки, итог
+// @Description (completed, retrying или failed), созданную транзакцию и причину неудачи.
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path int true "ID регулярного перевода"
+// @Success 200 {array} models.ScheduledTransferRun
+// @Failure 400 {object} models.ErrorResponse "Invalid scheduled transfer ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Scheduled transfer not found"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/scheduled-transfers/{id}/runs [get]
+func (h *Handler) ListScheduledTransferRuns(w http.ResponseWriter, r *http.Request) {
+	id, err := parseScheduledTransferID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	runs, err := h.services.ListScheduledTransferRuns(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// parseScheduledTransferID читает ID регулярного перевода из параметра маршрута {id}.
+func parseScheduledTransferID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, domain.NewValidationError("id", "Scheduled transfer ID must be a positive integer")
+	}
+	return id, nil
+}
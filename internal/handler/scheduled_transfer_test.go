@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_CreateScheduledTransfer(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockScheduledTransfer)
+
+	nextRunAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "50.00", "schedule": "0 9 1 * *"}`,
+			mockBehavior: func(s *service_mocks.MockScheduledTransfer) {
+				s.EXPECT().CreateScheduledTransfer(gomock.Any(), models.CreateScheduledTransferRequest{
+					From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "0 9 1 * *",
+				}).Return(&models.ScheduledTransfer{
+					ID: 3, From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "0 9 1 * *",
+					Status: models.ScheduledTransferStatusActive, NextRunAt: nextRunAt, CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{"id":3,"from":"` + addr1 + `","to":"` + addr2 + `","amount":"50.00","schedule":"0 9 1 * *","status":"active",` +
+				`"next_run_at":"2025-02-01T09:00:00Z","attempts":0,"created_at":"2025-01-15T12:00:00Z"}` + "\n",
+		},
+		{
+			name:                 "Missing Schedule",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "50.00"}`,
+			mockBehavior:         func(s *service_mocks.MockScheduledTransfer) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Missing required fields or invalid amount"}` + "\n",
+		},
+		{
+			name:                 "Same Wallet",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr1 + `", "amount": "50.00", "schedule": "@daily"}`,
+			mockBehavior:         func(s *service_mocks.MockScheduledTransfer) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"same_wallet","message":"sender and recipient wallets must differ"}` + "\n",
+		},
+		{
+			name:      "Invalid Schedule",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "50.00", "schedule": "@every 10s"}`,
+			mockBehavior: func(s *service_mocks.MockScheduledTransfer) {
+				s.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+					Return(nil, domain.NewValidationError("schedule", "schedule must not fire more than once a minute"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"schedule must not fire more than once a minute","details":{"field":"schedule"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			scheduledMock := service_mocks.NewMockScheduledTransfer(c)
+			tt.mockBehavior(scheduledMock)
+
+			services := &service.Service{ScheduledTransfer: scheduledMock}
+			handler := NewHandler(services, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/scheduled-transfers", bytes.NewBufferString(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			handler.CreateScheduledTransfer(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ListScheduledTransfers(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockScheduledTransfer)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			query: "?wallet=" + addr1,
+			mockBehavior: func(s *service_mocks.MockScheduledTransfer) {
+				s.EXPECT().ListScheduledTransfers(gomock.Any(), addr1).Return([]models.ScheduledTransfer{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: "[]\n",
+		},
+		{
+			name:                 "Invalid Wallet",
+			query:                "?wallet=abc",
+			mockBehavior:         func(s *service_mocks.MockScheduledTransfer) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"invalid wallet address: must be 64 characters long","details":{"field":"wallet"}}` + "\n",
+		},
+		{
+			name:  "Foreign Wallet",
+			query: "?wallet=" + addr2,
+			mockBehavior: func(s *service_mocks.MockScheduledTransfer) {
+				s.EXPECT().ListScheduledTransfers(gomock.Any(), addr2).Return(nil, domain.ErrWalletNotOwned)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"code":"wallet_not_owned","message":"wallet is not owned by the caller"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			scheduledMock := service_mocks.NewMockScheduledTransfer(c)
+			tt.mockBehavior(scheduledMock)
+
+			services := &service.Service{ScheduledTransfer: scheduledMock}
+			handler := NewHandler(services, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/scheduled-transfers"+tt.query, nil)
+
+			handler.ListScheduledTransfers(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_CancelScheduledTransfer(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockScheduledTransfer)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			id:   "3",
+			mockBehavior: func(s *service_mocks.MockScheduledTransfer) {
+				s.EXPECT().CancelScheduledTransfer(gomock.Any(), 3).Return(&models.ScheduledTransfer{ID: 3, Status: models.ScheduledTransferStatusCancelled}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"id":3,"from":"","to":"","amount":"0.00","schedule":"","status":"cancelled",` +
+				`"next_run_at":"0001-01-01T00:00:00Z","attempts":0,"created_at":"0001-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:                 "Invalid ID",
+			id:                   "0",
+			mockBehavior:         func(s *service_mocks.MockScheduledTransfer) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Scheduled transfer ID must be a positive integer","details":{"field":"id"}}` + "\n",
+		},
+		{
+			name: "Not Found",
+			id:   "3",
+			mockBehavior: func(s *service_mocks.MockScheduledTransfer) {
+				s.EXPECT().CancelScheduledTransfer(gomock.Any(), 3).Return(nil, domain.ErrScheduledTransferNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"code":"scheduled_transfer_not_found","message":"scheduled transfer not found"}` + "\n",
+		},
+		{
+			name: "Already Cancelled",
+			id:   "3",
+			mockBehavior: func(s *service_mocks.MockScheduledTransfer) {
+				s.EXPECT().CancelScheduledTransfer(gomock.Any(), 3).Return(nil, domain.ErrScheduledTransferCancelled)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"scheduled_transfer_cancelled","message":"scheduled transfer is cancelled"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			scheduledMock := service_mocks.NewMockScheduledTransfer(c)
+			tt.mockBehavior(scheduledMock)
+
+			services := &service.Service{ScheduledTransfer: scheduledMock}
+			handler := NewHandler(services, configs.Config{})
+
+			r := http.NewServeMux()
+			r.HandleFunc("DELETE /api/scheduled-transfers/{id}", handler.CancelScheduledTransfer)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/api/scheduled-transfers/"+tt.id, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ListScheduledTransferRuns(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	scheduledFor := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 2, 1, 9, 0, 5, 0, time.UTC)
+	transactionID := 42
+	scheduledMock := service_mocks.NewMockScheduledTransfer(c)
+	scheduledMock.EXPECT().ListScheduledTransferRuns(gomock.Any(), 3).Return([]models.ScheduledTransferRun{
+		{ID: 2, ScheduledTransferID: 3, ScheduledFor: scheduledFor, Attempt: 2, Status: models.ScheduledTransferRunCompleted, TransactionID: &transactionID, CreatedAt: createdAt},
+		{ID: 1, ScheduledTransferID: 3, ScheduledFor: scheduledFor, Attempt: 1, Status: models.ScheduledTransferRunRetrying, Error: "limit exceeded: daily_volume", CreatedAt: createdAt},
+	}, nil)
+
+	handler := NewHandler(&service.Service{ScheduledTransfer: scheduledMock}, configs.Config{})
+	r := http.NewServeMux()
+	r.HandleFunc("GET /api/scheduled-transfers/{id}/runs", handler.ListScheduledTransferRuns)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/scheduled-transfers/3/runs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `[{"id":2,"scheduled_transfer_id":3,"scheduled_for":"2025-02-01T09:00:00Z","attempt":2,"status":"completed","transaction_id":42,"created_at":"2025-02-01T09:00:05Z"},`+
+		`{"id":1,"scheduled_transfer_id":3,"scheduled_for":"2025-02-01T09:00:00Z","attempt":1,"status":"retrying","error":"limit exceeded: daily_volume","created_at":"2025-02-01T09:00:05Z"}]`+"\n", w.Body.String())
+}
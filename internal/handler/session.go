@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"net/http"
+)
+
+// Login выполняет вход пользователя
+// @Summary Войти в систему
+// @Description Проверяет имя и пароль пользователя и выдает токен доступа (JWT) и токен обновления
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "Имя и пароль"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload"
+// @Failure 401 {object} models.ErrorResponse "Invalid username or password"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/auth/login [post]
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+
+	tokens, err := h.services.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeTokens(w, tokens)
+}
+
+// Refresh обновляет сессию пользователя
+// @Summary Обновить токены
+// @Description Обменивает токен обновления на новую пару токенов; использованный токен обновления отзывается
+// @Accept json
+// @Produce json
+// @Param token body models.RefreshTokenRequest true "Токен обновления"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload"
+// @Failure 401 {object} models.ErrorResponse "Invalid or expired refresh token"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/auth/refresh [post]
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, r, domain.NewValidationError("refresh_token", "Invalid request body"))
+		return
+	}
+
+	tokens, err := h.services.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeTokens(w, tokens)
+}
+
+// Logout завершает сессию пользователя
+// @Summary Выйти из системы
+// @Description Отзывает токен обновления. Токен доступа остается действительным до истечения срока действия
+// @Accept json
+// @Param token body models.RefreshTokenRequest true "Токен обновления"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/auth/logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, r, domain.NewValidationError("refresh_token", "Invalid request body"))
+		return
+	}
+
+	if err := h.services.Logout(r.Context(), req.RefreshToken); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateUser создает пользователя
+// @Summary Создать пользователя
+// @Description Создает пользователя с ролью customer, operator, auditor или admin и передает ему во владение существующие кошельки. Требует роли admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user body models.CreateUserRequest true "Данные пользователя"
+// @Success 201 {object} models.User
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload, wallet address or role"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Admin role required"
+// @Failure 409 {object} models.ErrorResponse "User already exists"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/users [post]
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if err := validateAddresses("wallets", req.Wallets); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.services.CreateUser(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func writeTokens(w http.ResponseWriter, tokens *models.TokenPair) {
+	w.Header().Set("Content-Type", "application/json")
+	// Токены не должны оседать в кешах между клиентом и сервером.
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokens)
+}
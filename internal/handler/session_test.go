@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golangTestTask/configs"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	service_mocks "golangTestTask/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_Login(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         func(s *service_mocks.MockSession)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"username":"alice","password":"password1"}`,
+			mockBehavior: func(s *service_mocks.MockSession) {
+				s.EXPECT().Login(gomock.Any(), "alice", "password1").Return(&models.TokenPair{
+					AccessToken:  "access",
+					RefreshToken: "rt_refresh",
+					TokenType:    "Bearer",
+					ExpiresIn:    900,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"access_token":"access","refresh_token":"rt_refresh","token_type":"Bearer","expires_in":900}` + "\n",
+		},
+		{
+			name:      "Invalid Credentials",
+			inputBody: `{"username":"alice","password":"wrong"}`,
+			mockBehavior: func(s *service_mocks.MockSession) {
+				s.EXPECT().Login(gomock.Any(), "alice", "wrong").Return(nil, domain.ErrInvalidCredentials)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"code":"invalid_credentials","message":"invalid username or password"}` + "\n",
+		},
+		{
+			name:                 "Invalid JSON",
+			inputBody:            `{"username":`,
+			mockBehavior:         func(s *service_mocks.MockSession) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Invalid request body"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessionMock := service_mocks.NewMockSession(c)
+			tt.mockBehavior(sessionMock)
+
+			handler := NewHandler(&service.Service{Session: sessionMock}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/auth/login", bytes.NewBufferString(tt.inputBody))
+
+			handler.Login(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			if w.Code == http.StatusOK {
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         func(s *service_mocks.MockSession)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"refresh_token":"rt_old"}`,
+			mockBehavior: func(s *service_mocks.MockSession) {
+				s.EXPECT().Refresh(gomock.Any(), "rt_old").Return(&models.TokenPair{
+					AccessToken:  "access",
+					RefreshToken: "rt_new",
+					TokenType:    "Bearer",
+					ExpiresIn:    900,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"access_token":"access","refresh_token":"rt_new","token_type":"Bearer","expires_in":900}` + "\n",
+		},
+		{
+			name:      "Reused Token",
+			inputBody: `{"refresh_token":"rt_old"}`,
+			mockBehavior: func(s *service_mocks.MockSession) {
+				s.EXPECT().Refresh(gomock.Any(), "rt_old").Return(nil, domain.ErrInvalidToken)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"code":"invalid_token","message":"invalid or expired token"}` + "\n",
+		},
+		{
+			name:                 "Missing Token",
+			inputBody:            `{}`,
+			mockBehavior:         func(s *service_mocks.MockSession) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Invalid request body","details":{"field":"refresh_token"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessionMock := service_mocks.NewMockSession(c)
+			tt.mockBehavior(sessionMock)
+
+			handler := NewHandler(&service.Service{Session: sessionMock}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/auth/refresh", bytes.NewBufferString(tt.inputBody))
+
+			handler.Refresh(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_Logout(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	sessionMock := service_mocks.NewMockSession(c)
+	sessionMock.EXPECT().Logout(gomock.Any(), "rt_old").Return(nil)
+
+	handler := NewHandler(&service.Service{Session: sessionMock}, configs.Config{})
+
+	// Выход доступен без токена доступа: достаточно токена обновления.
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/auth/logout", bytes.NewBufferString(`{"refresh_token":"rt_old"}`))
+
+	handler.InitRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestHandler_CreateUser(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         func(s *service_mocks.MockSession)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"username":"bob","password":"password1","role":"auditor"}`,
+			mockBehavior: func(s *service_mocks.MockSession) {
+				s.EXPECT().CreateUser(gomock.Any(), models.CreateUserRequest{Username: "bob", Password: "password1", Role: auth.RoleAuditor}).
+					Return(&models.User{ID: 4, Username: "bob", Role: auth.RoleAuditor, Wallets: []string{}, PasswordHash: "secret"}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":4,"username":"bob","role":"auditor","wallets":[],"created_at":"0001-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:      "User Exists",
+			inputBody: `{"username":"bob","password":"password1"}`,
+			mockBehavior: func(s *service_mocks.MockSession) {
+				s.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil, domain.ErrUserAlreadyExists)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"user_already_exists","message":"user already exists"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessionMock := service_mocks.NewMockSession(c)
+			tt.mockBehavior(sessionMock)
+
+			handler := NewHandler(&service.Service{Session: sessionMock}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/users", bytes.NewBufferString(tt.inputBody))
+
+			handler.CreateUser(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"net/http"
+)
+
+// GetAllTiers возвращает все уровни кошельков
+// @Summary Получить уровни кошельков
+// @Description Возвращает все уровни кошельков с ограничениями на переводы. Нулевое ограничение, кроме min_transfer, означает его отсутствие
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} models.WalletTier
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/tiers [get]
+func (h *Handler) GetAllTiers(w http.ResponseWriter, r *http.Request) {
+	tiers, err := h.services.GetAllTiers(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if tiers == nil {
+		tiers = []models.WalletTier{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tiers)
+}
+
+// CreateTier создает уровень кошельков
+// @Summary Создать уровень кошельков
+// @Description Создает уровень кошельков с ограничениями на сумму одного перевода, суммы переводов за сутки и месяц и максимальный баланс
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param tier body models.WalletTier true "Уровень кошельков"
+// @Success 201 {object} models.WalletTier
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 409 {object} models.ErrorResponse "Tier already exists"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/tiers [post]
+func (h *Handler) CreateTier(w http.ResponseWriter, r *http.Request) {
+	var req models.WalletTier
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+
+	tier, err := h.services.CreateTier(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tier)
+}
+
+// UpdateTier изменяет ограничения уровня кошельков
+// @Summary Изменить уровень кошельков
+// @Description Заменяет ограничения уровня кошельков. Новые ограничения применяются ко всем кошелькам уровня
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param name path string true "Имя уровня"
+// @Param tier body models.WalletTier true "Ограничения уровня; поле name игнорируется"
+// @Success 200 {object} models.WalletTier
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 404 {object} models.ErrorResponse "Tier not found"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/tiers/{name} [put]
+func (h *Handler) UpdateTier(w http.ResponseWriter, r *http.Request) {
+	var req models.WalletTier
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	req.Name = r.PathValue("name")
+
+	tier, err := h.services.UpdateTier(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tier)
+}
+
+// UpdateWalletTier изменяет уровень кошелька
+// @Summary Изменить уровень кошелька
+// @Description Присваивает кошельку уровень, определяющий ограничения на переводы. Уровень закрытого кошелька изменить нельзя
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param address path string true "Адрес кошелька"
+// @Param tier body models.UpdateWalletTierRequest true "Новый уровень"
+// @Success 200 {object} models.Wallet
+// @Failure 400 {object} models.ErrorResponse "Invalid address or request payload"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 404 {object} models.ErrorResponse "Wallet or tier not found"
+// @Failure 409 {object} models.ErrorResponse "Wallet is closed"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/wallet/{address}/tier [put]
+func (h *Handler) UpdateWalletTier(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	if err := validateAddress("address", address); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req models.UpdateWalletTierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if req.Tier == "" {
+		writeError(w, r, domain.NewValidationError("tier", "tier is required"))
+		return
+	}
+
+	wallet, err := h.services.SetWalletTier(r.Context(), address, req.Tier)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wallet)
+}
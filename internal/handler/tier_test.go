@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_GetAllTiers(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	tierMock := service_mocks.NewMockTier(c)
+	tierMock.EXPECT().GetAllTiers(gomock.Any()).Return([]models.WalletTier{
+		{Name: "standard", MinTransfer: money.MustParse("0.01"), MaxBalance: money.FromInt(1000)},
+	}, nil)
+
+	handler := NewHandler(&service.Service{Tier: tierMock}, configs.Config{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/tiers", nil)
+
+	handler.GetAllTiers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `[{"name":"standard","min_transfer":"0.01","max_transfer":"0.00","daily_limit":"0.00","monthly_limit":"0.00","max_balance":"1000.00"}]`+"\n", w.Body.String())
+}
+
+func TestHandler_CreateTier(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockTier)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"verified","min_transfer":"1.00","daily_limit":"5000"}`,
+			mockBehavior: func(s *service_mocks.MockTier) {
+				s.EXPECT().CreateTier(gomock.Any(), models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), DailyLimit: money.FromInt(5000)}).
+					Return(&models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), DailyLimit: money.FromInt(5000)}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"name":"verified","min_transfer":"1.00","max_transfer":"0.00","daily_limit":"5000.00","monthly_limit":"0.00","max_balance":"0.00"}` + "\n",
+		},
+		{
+			name:                 "Invalid Body",
+			inputBody:            `{"name":"verified","min_transfer":"0.001"}`,
+			mockBehavior:         func(s *service_mocks.MockTier) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Invalid request body"}` + "\n",
+		},
+		{
+			name:      "Already Exists",
+			inputBody: `{"name":"standard","min_transfer":"0.01"}`,
+			mockBehavior: func(s *service_mocks.MockTier) {
+				s.EXPECT().CreateTier(gomock.Any(), gomock.Any()).Return(nil, domain.ErrTierAlreadyExists)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"tier_already_exists","message":"wallet tier already exists"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tierMock := service_mocks.NewMockTier(c)
+			tt.mockBehavior(tierMock)
+
+			handler := NewHandler(&service.Service{Tier: tierMock}, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/tiers", bytes.NewBufferString(tt.inputBody))
+
+			handler.CreateTier(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_UpdateTier(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	tierMock := service_mocks.NewMockTier(c)
+	tierMock.EXPECT().UpdateTier(gomock.Any(), models.WalletTier{Name: "standard", MinTransfer: money.FromInt(1)}).Return(nil, domain.ErrTierNotFound)
+
+	handler := NewHandler(&service.Service{Tier: tierMock}, configs.Config{})
+
+	r := http.NewServeMux()
+	r.HandleFunc("PUT /api/tiers/{name}", handler.UpdateTier)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", "/api/tiers/standard", bytes.NewBufferString(`{"name":"ignored","min_transfer":"1"}`))
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"code":"tier_not_found","message":"wallet tier not found"}`+"\n", w.Body.String())
+}
+
+func TestHandler_UpdateWalletTier(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockWallet)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"tier":"verified"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().SetWalletTier(gomock.Any(), addr1, "verified").Return(&models.Wallet{
+					Address: addr1,
+					Balance: money.MustParse("5.00"),
+					Status:  models.WalletStatusActive,
+					Tier:    "verified",
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"address":"` + addr1 + `","balance":"5.00","status":"active","tier":"verified"}` + "\n",
+		},
+		{
+			name:                 "Missing Tier",
+			inputBody:            `{}`,
+			mockBehavior:         func(s *service_mocks.MockWallet) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"tier is required","details":{"field":"tier"}}` + "\n",
+		},
+		{
+			name:      "Tier Not Found",
+			inputBody: `{"tier":"unknown"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().SetWalletTier(gomock.Any(), addr1, "unknown").Return(nil, domain.ErrTierNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"code":"tier_not_found","message":"wallet tier not found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			walletMock := service_mocks.NewMockWallet(c)
+			tt.mockBehavior(walletMock)
+
+			handler := NewHandler(&service.Service{Wallet: walletMock}, configs.Config{})
+
+			r := http.NewServeMux()
+			r.HandleFunc("PUT /api/wallet/{address}/tier", handler.UpdateWalletTier)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/api/wallet/"+addr1+"/tier", bytes.NewBufferString(tt.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// withTimeout ограничивает время обработки запроса значением timeout: по его истечении контекст запроса
+// отменяется, и выполняющиеся запросы к БД прерываются. Нулевое значение отключает ограничение.
+func withTimeout(timeout time.Duration, next http.Handler) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -2,86 +2,382 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
+	"golangTestTask/internal/service"
+	"golangTestTask/pkg/money"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 )
 
 // Send
 // @Summary Отправить денежные средства
-// @Description Переводит денежные средства с одного кошелька на другой
+// @Description Переводит денежные средства с одного кошелька на другой.
+// @Description Комиссия по тарифу сервиса списывается с отправителя сверх суммы перевода и возвращается в поле fee.
+// @Description Если передан quote_id из POST /api/send/quote, применяется рассчитанная в нем комиссия. Расчет одноразовый:
+// @Description повторный перевод с тем же quote_id отклоняется с кодом quote_used.
+// @Description Сумма указывается в валюте кошелька отправителя. Перевод между кошельками в разных валютах выполняется только с "convert": true:
+// @Description получателю зачисляется сумма по текущему курсу (или курсу из quote_id) за вычетом спреда, сведения о ней возвращаются в поле conversion.
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param transaction body models.CreateTransactionRequest true "Данные транзакции"
-// @Success 200 {object} models.StatusResponse "Status"
-// @Failure 400 {string} string "Invalid request payload"
-// @Failure 404 {string} string "Wallet not found"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повторный запрос того же клиента с тем же ключом вернет исходный ответ"
+// @Success 200 {object} models.TransferResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload, wallet address or quote, same wallet, insufficient funds, amount outside the sender tier limits or too precise for the currency"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Wallet not found"
+// @Failure 409 {object} models.ErrorResponse "Wallet is frozen or closed, quote has already been used, or request with this idempotency key is in progress"
+// @Failure 422 {object} models.ErrorResponse "Idempotency key reused with a different request, quote expired or issued for another transfer, wallet currencies differ without conversion or no exchange rate, no fee wallet in the sender currency, or daily, monthly or balance limit of the wallet tier exceeded"
+// @Failure 429 {object} models.ErrorResponse "Rate limit or wallet transfer limit exceeded"
+// @Failure 500 {object} models.ErrorResponse "Server error"
 // @Router /api/send [post]
 func (h *Handler) Send(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeMethodNotAllowed(w, r)
 		return
 	}
 
-	var req models.Transaction
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	req, err := decodeTransferRequest(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if req.From == "" || req.To == "" || req.Amount <= 0 {
-		http.Error(w, "Missing required fields or invalid amount", http.StatusBadRequest)
+	result, err := h.services.TransferFunds(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.TransferResponse{
+		Status:        "success",
+		Message:       "Transaction completed",
+		TransactionID: result.TransactionID,
+		Currency:      result.Currency,
+		Amount:        result.Amount,
+		Fee:           result.Fee,
+		Total:         result.Total,
+		Conversion:    result.Conversion,
+	})
+}
+
+// Quote
+// @Summary Рассчитать перевод
+// @Description Выполняет все проверки перевода, не изменяя кошельки, и возвращает комиссию, балансы после перевода
+// @Description и подписанный идентификатор расчета. Переданный в POST /api/send до истечения срока, он гарантирует рассчитанную комиссию.
+// @Description Баланс получателя возвращается, только если вызывающий может просматривать его кошелек. Для перевода с конвертацией
+// @Description расчет фиксирует курс, а баланс получателя указывается в его валюте.
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param transaction body models.CreateTransactionRequest true "Данные транзакции (quote_id не используется)"
+// @Success 200 {object} models.TransferQuote
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload or wallet address, same wallet, insufficient funds, amount outside the sender tier limits or too precise for the currency"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied or sender wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Wallet not found"
+// @Failure 409 {object} models.ErrorResponse "Wallet is frozen or closed"
+// @Failure 422 {object} models.ErrorResponse "Wallet currencies differ without conversion or no exchange rate, no fee wallet in the sender currency, or daily, monthly or balance limit of the wallet tier exceeded"
+// @Failure 429 {object} models.ErrorResponse "Rate limit or wallet transfer limit exceeded"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/send/quote [post]
+func (h *Handler) Quote(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeTransferRequest(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.services.TransferFunds(req.From, req.To, req.Amount); err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "insufficient funds" {
-			status = http.StatusBadRequest
-		} else if err.Error() == "sender wallet not found" || err.Error() == "recipient wallet not found" {
-			status = http.StatusNotFound
+	quote, err := h.services.QuoteTransfer(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quote)
+}
+
+// ReverseTransaction
+// @Summary Отменить перевод
+// @Description Отменяет завершенный перевод полностью или частично: создает компенсирующую транзакцию с получателя на отправителя,
+// @Description связанную с исходной через reversal_of, и переводит исходную транзакцию в статус reversed. Сумма возврата указывается
+// @Description в валюте отправителя; без тела запроса или без amount возвращается вся сумма перевода. Для перевода с конвертацией
+// @Description с получателя списывается сумма по курсу исходного перевода. Комиссия не возвращается. Перевод можно отменить только один раз.
+// @Description Доступно только администратору.
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path int true "ID транзакции"
+// @Param reversal body models.ReverseTransactionRequest false "Сумма возврата"
+// @Success 200 {object} models.TransactionReversal
+// @Failure 400 {object} models.ErrorResponse "Invalid transaction ID or amount, or recipient has insufficient available funds"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 404 {object} models.ErrorResponse "Transaction or wallet not found"
+// @Failure 409 {object} models.ErrorResponse "Transaction is not a completed transfer or has already been reversed, or sender wallet is closed"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/transactions/{id}/reverse [post]
+func (h *Handler) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, r, domain.NewValidationError("id", "Transaction ID must be a positive integer"))
+		return
+	}
+
+	var req models.ReverseTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		if errors.Is(err, money.ErrTooManyFractionDigits) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must have at most 2 fractional digits"))
+			return
 		}
-		http.Error(w, err.Error(), status)
+		if errors.Is(err, money.ErrAmountOverflow) {
+			writeError(w, r, domain.NewValidationError("amount", "Amount must not exceed "+money.MaxAmount.String()))
+			return
+		}
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if req.Amount != nil && *req.Amount <= 0 {
+		writeError(w, r, domain.NewValidationError("amount", "Amount must be positive"))
 		return
 	}
 
+	reversal, err := h.services.ReverseTransaction(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(models.StatusResponse{
-		Status:  "success",
-		Message: "Transaction completed",
-	})
+	json.NewEncoder(w).Encode(reversal)
 }
 
-// GetLastTransactions возвращает N последних транзакций
-// @Summary Получить последние транзакции
-// @Description Возвращает N последних по времени переводов средств
+// decodeTransferRequest читает и проверяет тело запроса на перевод.
+func decodeTransferRequest(r *http.Request) (models.CreateTransactionRequest, error) {
+	var req models.CreateTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, money.ErrTooManyFractionDigits) {
+			return req, domain.NewValidationError("amount", "Amount must have at most 2 fractional digits")
+		}
+		if errors.Is(err, money.ErrAmountOverflow) {
+			return req, domain.NewValidationError("amount", "Amount must not exceed "+money.MaxAmount.String())
+		}
+		return req, domain.NewValidationError("", "Invalid request body")
+	}
+	if req.From == "" || req.To == "" || req.Amount <= 0 {
+		return req, domain.NewValidationError("", "Missing required fields or invalid amount")
+	}
+	if err := validateAddress("from", req.From); err != nil {
+		return req, err
+	}
+	if err := validateAddress("to", req.To); err != nil {
+		return req, err
+	}
+	if req.From == req.To {
+		return req, domain.ErrSameWallet
+	}
+	return req, nil
+}
+
+// ListTransactions возвращает историю транзакций
+// @Summary Получить историю транзакций
+// @Description Возвращает страницу истории переводов от новых к старым с фильтрами и курсором следующей страницы.
+// @Description Если передан параметр count, возвращает массив из count последних транзакций без постраничной выборки (устаревший режим).
 // @Produce json
-// @Param count query int false "Количество транзакций"
-// @Success 200 {array} models.Transaction
-// @Failure 400 {string} string "Invalid request payload"
-// @Failure 500 {string} string "Server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param cursor query string false "Курсор страницы из next_cursor предыдущего ответа"
+// @Param limit query int false "Размер страницы (по умолчанию 20, не больше 100)"
+// @Param wallet query string false "Адрес кошелька"
+// @Param role query string false "Роль кошелька в транзакции" Enums(any, sender, recipient)
+// @Param status query string false "Статус транзакции" Enums(pending, completed, failed, reversed)
+// @Param min_amount query string false "Минимальная сумма"
+// @Param max_amount query string false "Максимальная сумма"
+// @Param from_time query string false "Начало периода (RFC 3339), включительно"
+// @Param to_time query string false "Конец периода (RFC 3339), не включительно"
+// @Param count query int false "Количество последних транзакций (устаревший режим)"
+// @Success 200 {object} models.TransactionPage
+// @Failure 400 {object} models.ErrorResponse "Invalid query parameters, wallet address or cursor"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 500 {object} models.ErrorResponse "Server error"
 // @Router /api/transactions [get]
+func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("count") {
+		h.GetLast(w, r)
+		return
+	}
+
+	filter, err := parseTransactionFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.writeTransactionPage(w, r, filter, r.URL.Query().Get("cursor"))
+}
+
+// GetWalletTransactions возвращает историю транзакций кошелька
+// @Summary Получить историю транзакций кошелька
+// @Description Возвращает страницу истории переводов, в которых участвовал кошелек, от новых к старым
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param address path string true "Адрес кошелька"
+// @Param cursor query string false "Курсор страницы из next_cursor предыдущего ответа"
+// @Param limit query int false "Размер страницы (по умолчанию 20, не больше 100)"
+// @Param role query string false "Роль кошелька в транзакции" Enums(any, sender, recipient)
+// @Param status query string false "Статус транзакции" Enums(pending, completed, failed, reversed)
+// @Param min_amount query string false "Минимальная сумма"
+// @Param max_amount query string false "Максимальная сумма"
+// @Param from_time query string false "Начало периода (RFC 3339), включительно"
+// @Param to_time query string false "Конец периода (RFC 3339), не включительно"
+// @Success 200 {object} models.TransactionPage
+// @Failure 400 {object} models.ErrorResponse "Invalid address, query parameters or cursor"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Wallet is not owned by the caller"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/wallet/{address}/transactions [get]
+func (h *Handler) GetWalletTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTransactionFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.Wallet = r.PathValue("address")
+	if err := validateAddress("address", filter.Wallet); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	h.writeTransactionPage(w, r, filter, r.URL.Query().Get("cursor"))
+}
+
+func (h *Handler) writeTransactionPage(w http.ResponseWriter, r *http.Request, filter models.TransactionFilter, cursor string) {
+	page, err := h.services.ListTransactions(r.Context(), filter, cursor)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseTransactionFilter разбирает параметры фильтрации истории транзакций из строки запроса.
+func parseTransactionFilter(query url.Values) (models.TransactionFilter, error) {
+	filter := models.TransactionFilter{
+		Wallet: query.Get("wallet"),
+		Role:   models.TransactionRoleAny,
+	}
+
+	if filter.Wallet != "" {
+		if err := validateAddress("wallet", filter.Wallet); err != nil {
+			return filter, err
+		}
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > service.MaxPageSize {
+			return filter, domain.NewValidationError("limit", fmt.Sprintf("limit must be an integer between 1 and %d", service.MaxPageSize))
+		}
+		filter.Limit = limit
+	}
+
+	if v := query.Get("role"); v != "" {
+		switch role := models.TransactionRole(v); role {
+		case models.TransactionRoleAny, models.TransactionRoleSender, models.TransactionRoleRecipient:
+			filter.Role = role
+		default:
+			return filter, domain.NewValidationError("role", "role must be one of: any, sender, recipient")
+		}
+	}
+
+	if v := query.Get("status"); v != "" {
+		status := models.TransactionStatus(v)
+		if !status.Valid() {
+			return filter, domain.NewValidationError("status", "status must be one of: pending, completed, failed, reversed")
+		}
+		filter.Status = status
+	}
+
+	var err error
+	if filter.MinAmount, err = parseAmountParam(query, "min_amount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = parseAmountParam(query, "max_amount"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = parseTimeParam(query, "from_time"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseTimeParam(query, "to_time"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func parseAmountParam(query url.Values, param string) (*money.Amount, error) {
+	v := query.Get(param)
+	if v == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(v)
+	if err != nil {
+		return nil, domain.NewValidationError(param, fmt.Sprintf("invalid %s: %v", param, err))
+	}
+	return &amount, nil
+}
+
+func parseTimeParam(query url.Values, param string) (*time.Time, error) {
+	v := query.Get(param)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, domain.NewValidationError(param, param+" must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
+
+// GetLast возвращает N последних транзакций; параметр count обязателен.
 func (h *Handler) GetLast(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeMethodNotAllowed(w, r)
 		return
 	}
 
 	countStr := r.URL.Query().Get("count")
 	if countStr == "" {
-		http.Error(w, "Count parameter is required", http.StatusBadRequest)
+		writeError(w, r, domain.NewValidationError("count", "Count parameter is required"))
 		return
 	}
 
 	count, err := strconv.Atoi(countStr)
 	if err != nil || count <= 0 {
-		http.Error(w, "Count must be a positive integer", http.StatusBadRequest)
+		writeError(w, r, domain.NewValidationError("count", "Count must be a positive integer"))
 		return
 	}
 
-	transactions, err := h.services.GetLastTransactions(count)
+	transactions, err := h.services.GetLastTransactions(r.Context(), count)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -6,83 +6,219 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
 	"golangTestTask/internal/service"
 	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
 func TestHandler_Send(t *testing.T) {
-	type mockBehavior func(s *service_mocks.MockTransaction, req models.Transaction)
+	type mockBehavior func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest)
 
 	tests := []struct {
 		name                 string
 		inputBody            string
-		inputRequest         models.Transaction
+		inputRequest         models.CreateTransactionRequest
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
 			name:      "Success",
-			inputBody: `{"from": "addr1", "to": "addr2", "amount": 10.5}`,
-			inputRequest: models.Transaction{
-				From:   "addr1",
-				To:     "addr2",
-				Amount: 10.5,
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": 10.5}`,
+			inputRequest: models.CreateTransactionRequest{
+				From:   addr1,
+				To:     addr2,
+				Amount: money.MustParse("10.50"),
 			},
-			mockBehavior: func(s *service_mocks.MockTransaction, req models.Transaction) {
-				s.EXPECT().TransferFunds(req.From, req.To, req.Amount).Return(nil)
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(&models.TransferResult{
+					TransactionID: 7,
+					Currency:      "USD",
+					Amount:        req.Amount,
+					Fee:           money.MustParse("0.30"),
+					Total:         req.Amount + money.MustParse("0.30"),
+				}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"status":"success","message":"Transaction completed"}` + "\n",
+			expectedResponseBody: `{"status":"success","message":"Transaction completed","transaction_id":7,"currency":"USD","amount":"10.50","fee":"0.30","total":"10.80"}` + "\n",
+		},
+		{
+			name:         "With Conversion",
+			inputBody:    `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "10.50", "convert": true}`,
+			inputRequest: models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Convert: true},
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(&models.TransferResult{
+					TransactionID: 10,
+					Currency:      "USD",
+					Amount:        req.Amount,
+					Total:         req.Amount,
+					Conversion:    &models.Conversion{Currency: "EUR", Amount: money.MustParse("9.61"), Rate: money.MustParseRate("0.9154"), SpreadBP: 50},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"status":"success","message":"Transaction completed","transaction_id":10,"currency":"USD","amount":"10.50","fee":"0.00","total":"10.50","conversion":{"currency":"EUR","amount":"9.61","rate":"0.91540000","spread_bp":50}}` + "\n",
 		},
 		{
 			name:                 "Invalid JSON",
-			inputBody:            `{"from": "addr1", "to": "addr2", "amount": "invalid"}`,
-			inputRequest:         models.Transaction{},
-			mockBehavior:         func(s *service_mocks.MockTransaction, req models.Transaction) {},
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "invalid"}`,
+			inputRequest:         models.CreateTransactionRequest{},
+			mockBehavior:         func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "Invalid request body\n",
+			expectedResponseBody: `{"code":"invalid_request","message":"Invalid request body"}` + "\n",
 		},
 		{
 			name:                 "Missing Fields",
-			inputBody:            `{"from": "", "to": "addr2", "amount": 10.5}`,
-			inputRequest:         models.Transaction{},
-			mockBehavior:         func(s *service_mocks.MockTransaction, req models.Transaction) {},
+			inputBody:            `{"from": "", "to": "` + addr2 + `", "amount": 10.5}`,
+			inputRequest:         models.CreateTransactionRequest{},
+			mockBehavior:         func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Missing required fields or invalid amount"}` + "\n",
+		},
+		{
+			name:                 "Same Wallet",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr1 + `", "amount": 10.5}`,
+			inputRequest:         models.CreateTransactionRequest{},
+			mockBehavior:         func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"same_wallet","message":"sender and recipient wallets must differ"}` + "\n",
+		},
+		{
+			name:         "Mistyped Recipient",
+			inputBody:    `{"from": "` + addr1 + `", "to": "` + addr2[:63] + `7", "amount": 10.5}`,
+			inputRequest: models.CreateTransactionRequest{From: addr1, To: addr2[:63] + "7", Amount: money.MustParse("10.50")},
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(nil, domain.NewValidationError("to", "invalid wallet address: checksum mismatch"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"invalid wallet address: checksum mismatch","details":{"field":"to"}}` + "\n",
+		},
+		{
+			name:                 "Invalid Sender",
+			inputBody:            `{"from": "addr1", "to": "` + addr2 + `", "amount": 10.5}`,
+			inputRequest:         models.CreateTransactionRequest{},
+			mockBehavior:         func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"invalid wallet address: must be 64 characters long","details":{"field":"from"}}` + "\n",
+		},
+		{
+			name:         "Amount As String",
+			inputBody:    `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "0.30"}`,
+			inputRequest: models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("0.30")},
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(&models.TransferResult{TransactionID: 8, Currency: "USD", Amount: req.Amount, Total: req.Amount}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"status":"success","message":"Transaction completed","transaction_id":8,"currency":"USD","amount":"0.30","fee":"0.00","total":"0.30"}` + "\n",
+		},
+		{
+			name:                 "Too Many Fractional Digits",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": 10.555}`,
+			inputRequest:         models.CreateTransactionRequest{},
+			mockBehavior:         func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Amount must have at most 2 fractional digits","details":{"field":"amount"}}` + "\n",
+		},
+		{
+			name:                 "Amount Too Large",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "10000000000000.00"}`,
+			inputRequest:         models.CreateTransactionRequest{},
+			mockBehavior:         func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Amount must not exceed 9999999999999.99","details":{"field":"amount"}}` + "\n",
+		},
+		{
+			name:                 "Negative Amount",
+			inputBody:            `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "-1.00"}`,
+			inputRequest:         models.CreateTransactionRequest{},
+			mockBehavior:         func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "Missing required fields or invalid amount\n",
+			expectedResponseBody: `{"code":"invalid_request","message":"Missing required fields or invalid amount"}` + "\n",
 		},
 		{
 			name:      "Insufficient Funds",
-			inputBody: `{"from": "addr1", "to": "addr2", "amount": 10.5}`,
-			inputRequest: models.Transaction{
-				From:   "addr1",
-				To:     "addr2",
-				Amount: 10.5,
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": 10.5}`,
+			inputRequest: models.CreateTransactionRequest{
+				From:   addr1,
+				To:     addr2,
+				Amount: money.MustParse("10.50"),
 			},
-			mockBehavior: func(s *service_mocks.MockTransaction, req models.Transaction) {
-				s.EXPECT().TransferFunds(req.From, req.To, req.Amount).Return(errors.New("insufficient funds"))
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(nil, domain.ErrInsufficientFunds)
 			},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "insufficient funds\n",
+			expectedResponseBody: `{"code":"insufficient_funds","message":"insufficient funds"}` + "\n",
+		},
+		{
+			name:      "Wallet Frozen",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": 10.5}`,
+			inputRequest: models.CreateTransactionRequest{
+				From:   addr1,
+				To:     addr2,
+				Amount: money.MustParse("10.50"),
+			},
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(nil, domain.NewWalletError(models.TransactionRoleSender, addr1, domain.ErrWalletFrozen))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"wallet_frozen","message":"sender wallet is frozen","details":{"address":"` + addr1 + `","role":"sender"}}` + "\n",
 		},
 		{
 			name:      "Wallet Not Found",
-			inputBody: `{"from": "addr1", "to": "addr2", "amount": 10.5}`,
-			inputRequest: models.Transaction{
-				From:   "addr1",
-				To:     "addr2",
-				Amount: 10.5,
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": 10.5}`,
+			inputRequest: models.CreateTransactionRequest{
+				From:   addr1,
+				To:     addr2,
+				Amount: money.MustParse("10.50"),
 			},
-			mockBehavior: func(s *service_mocks.MockTransaction, req models.Transaction) {
-				s.EXPECT().TransferFunds(req.From, req.To, req.Amount).Return(errors.New("sender wallet not found"))
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(nil, domain.NewWalletError(models.TransactionRoleSender, addr1, domain.ErrWalletNotFound))
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: "sender wallet not found\n",
+			expectedResponseBody: `{"code":"wallet_not_found","message":"sender wallet not found","details":{"address":"` + addr1 + `","role":"sender"}}` + "\n",
+		},
+		{
+			name:      "With Quote",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "10.50", "quote_id": "q1"}`,
+			inputRequest: models.CreateTransactionRequest{
+				From:    addr1,
+				To:      addr2,
+				Amount:  money.MustParse("10.50"),
+				QuoteID: "q1",
+			},
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(&models.TransferResult{
+					TransactionID: 9,
+					Currency:      "USD",
+					Amount:        req.Amount,
+					Fee:           money.MustParse("0.20"),
+					Total:         req.Amount + money.MustParse("0.20"),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"status":"success","message":"Transaction completed","transaction_id":9,"currency":"USD","amount":"10.50","fee":"0.20","total":"10.70"}` + "\n",
+		},
+		{
+			name:      "Quote Expired",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "10.50", "quote_id": "q1"}`,
+			inputRequest: models.CreateTransactionRequest{
+				From:    addr1,
+				To:      addr2,
+				Amount:  money.MustParse("10.50"),
+				QuoteID: "q1",
+			},
+			mockBehavior: func(s *service_mocks.MockTransaction, req models.CreateTransactionRequest) {
+				s.EXPECT().TransferFunds(gomock.Any(), req).Return(nil, domain.ErrQuoteExpired)
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"code":"quote_expired","message":"quote has expired"}` + "\n",
 		},
 	}
 
@@ -95,7 +231,7 @@ func TestHandler_Send(t *testing.T) {
 			tt.mockBehavior(transactionMock, tt.inputRequest)
 
 			services := &service.Service{Transaction: transactionMock}
-			handler := NewHandler(services)
+			handler := NewHandler(services, configs.Config{})
 
 			r := http.NewServeMux()
 			r.HandleFunc("/api/send", handler.Send)
@@ -112,6 +248,206 @@ func TestHandler_Send(t *testing.T) {
 	}
 }
 
+func TestHandler_Quote(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockTransaction)
+
+	recipientBalance := money.MustParse("60.50")
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Success",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "10.50"}`,
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().QuoteTransfer(gomock.Any(), models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50")}).Return(&models.TransferQuote{
+					QuoteID:               "q1",
+					From:                  addr1,
+					To:                    addr2,
+					Currency:              "USD",
+					Amount:                money.MustParse("10.50"),
+					Fee:                   money.MustParse("0.30"),
+					Total:                 money.MustParse("10.80"),
+					SenderBalanceAfter:    money.MustParse("89.20"),
+					RecipientBalanceAfter: &recipientBalance,
+					ExpiresAt:             time.Date(2025, 1, 1, 12, 1, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"quote_id":"q1","from":"` + addr1 + `","to":"` + addr2 + `","currency":"USD","amount":"10.50","fee":"0.30","total":"10.80","sender_balance_after":"89.20","recipient_balance_after":"60.50","expires_at":"2025-01-01T12:01:00Z"}` + "\n",
+		},
+		{
+			name:                 "Missing Fields",
+			inputBody:            `{"from": "` + addr1 + `", "amount": "10.50"}`,
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Missing required fields or invalid amount"}` + "\n",
+		},
+		{
+			name:      "Insufficient Funds",
+			inputBody: `{"from": "` + addr1 + `", "to": "` + addr2 + `", "amount": "1000"}`,
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().QuoteTransfer(gomock.Any(), models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(1000)}).Return(nil, domain.ErrInsufficientFunds)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"insufficient_funds","message":"insufficient funds"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			transactionMock := service_mocks.NewMockTransaction(c)
+			tt.mockBehavior(transactionMock)
+
+			services := &service.Service{Transaction: transactionMock}
+			handler := NewHandler(services, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/send/quote", bytes.NewBufferString(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			handler.Quote(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ReverseTransaction(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockTransaction)
+
+	partial := money.MustParse("5.00")
+	tests := []struct {
+		name                 string
+		id                   string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Full Reversal",
+			id:   "7",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ReverseTransaction(gomock.Any(), 7, models.ReverseTransactionRequest{}).Return(&models.TransactionReversal{
+					TransactionID:  8,
+					OriginalID:     7,
+					From:           addr2,
+					To:             addr1,
+					Currency:       "EUR",
+					Amount:         money.MustParse("9.61"),
+					RefundCurrency: "USD",
+					Refund:         money.MustParse("10.50"),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"transaction_id":8,"original_id":7,"from":"` + addr2 + `","to":"` + addr1 + `","currency":"EUR","amount":"9.61","refund_currency":"USD","refund":"10.50"}` + "\n",
+		},
+		{
+			name:      "Partial Reversal",
+			id:        "7",
+			inputBody: `{"amount": "5.00"}`,
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ReverseTransaction(gomock.Any(), 7, models.ReverseTransactionRequest{Amount: &partial}).Return(&models.TransactionReversal{
+					TransactionID:  8,
+					OriginalID:     7,
+					From:           addr2,
+					To:             addr1,
+					Currency:       "USD",
+					Amount:         partial,
+					RefundCurrency: "USD",
+					Refund:         partial,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"transaction_id":8,"original_id":7,"from":"` + addr2 + `","to":"` + addr1 + `","currency":"USD","amount":"5.00","refund_currency":"USD","refund":"5.00"}` + "\n",
+		},
+		{
+			name:                 "Invalid ID",
+			id:                   "abc",
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Transaction ID must be a positive integer","details":{"field":"id"}}` + "\n",
+		},
+		{
+			name:                 "Negative Amount",
+			id:                   "7",
+			inputBody:            `{"amount": "-5.00"}`,
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Amount must be positive","details":{"field":"amount"}}` + "\n",
+		},
+		{
+			name:                 "Invalid JSON",
+			id:                   "7",
+			inputBody:            `{"amount": `,
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Invalid request body"}` + "\n",
+		},
+		{
+			name: "Not Found",
+			id:   "7",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ReverseTransaction(gomock.Any(), 7, models.ReverseTransactionRequest{}).Return(nil, domain.ErrTransactionNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"code":"transaction_not_found","message":"transaction not found"}` + "\n",
+		},
+		{
+			name: "Already Reversed",
+			id:   "7",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ReverseTransaction(gomock.Any(), 7, models.ReverseTransactionRequest{}).Return(nil, domain.ErrTransactionAlreadyReversed)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"transaction_already_reversed","message":"transaction has already been reversed"}` + "\n",
+		},
+		{
+			name: "Recipient Insufficient Funds",
+			id:   "7",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ReverseTransaction(gomock.Any(), 7, models.ReverseTransactionRequest{}).
+					Return(nil, domain.NewWalletError(models.TransactionRoleRecipient, addr2, domain.ErrInsufficientFunds))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"insufficient_funds","message":"recipient insufficient funds","details":{"address":"` + addr2 + `","role":"recipient"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			transactionMock := service_mocks.NewMockTransaction(c)
+			tt.mockBehavior(transactionMock)
+
+			services := &service.Service{Transaction: transactionMock}
+			handler := NewHandler(services, configs.Config{})
+
+			r := http.NewServeMux()
+			r.HandleFunc("POST /api/transactions/{id}/reverse", handler.ReverseTransaction)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/transactions/"+tt.id+"/reverse", bytes.NewBufferString(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func TestHandler_GetLastTransactions(t *testing.T) {
 	type mockBehavior func(s *service_mocks.MockTransaction, count int)
 
@@ -128,12 +464,12 @@ func TestHandler_GetLastTransactions(t *testing.T) {
 			queryParam: "5",
 			inputCount: 5,
 			mockBehavior: func(s *service_mocks.MockTransaction, count int) {
-				s.EXPECT().GetLastTransactions(count).Return([]models.Transaction{
-					{ID: 1, From: "addr1", To: "addr2", Amount: 10.5},
+				s.EXPECT().GetLastTransactions(gomock.Any(), count).Return([]models.Transaction{
+					{ID: 1, From: addr1, To: addr2, Amount: money.MustParse("10.50"), Status: models.TransactionStatusCompleted},
 				}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `[{"id":1,"from":"addr1","to":"addr2","amount":10.5}]` + "\n",
+			expectedResponseBody: `[{"id":1,"from":"` + addr1 + `","to":"` + addr2 + `","amount":"10.50","status":"completed","created_at":"0001-01-01T00:00:00Z"}]` + "\n",
 		},
 		{
 			name:                 "Missing Count",
@@ -141,7 +477,7 @@ func TestHandler_GetLastTransactions(t *testing.T) {
 			inputCount:           0,
 			mockBehavior:         func(s *service_mocks.MockTransaction, count int) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "Count parameter is required\n",
+			expectedResponseBody: `{"code":"invalid_request","message":"Count parameter is required","details":{"field":"count"}}` + "\n",
 		},
 		{
 			name:                 "Invalid Count",
@@ -149,14 +485,14 @@ func TestHandler_GetLastTransactions(t *testing.T) {
 			inputCount:           0,
 			mockBehavior:         func(s *service_mocks.MockTransaction, count int) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "Count must be a positive integer\n",
+			expectedResponseBody: `{"code":"invalid_request","message":"Count must be a positive integer","details":{"field":"count"}}` + "\n",
 		},
 		{
 			name:       "Empty Result",
 			queryParam: "5",
 			inputCount: 5,
 			mockBehavior: func(s *service_mocks.MockTransaction, count int) {
-				s.EXPECT().GetLastTransactions(count).Return([]models.Transaction{}, nil)
+				s.EXPECT().GetLastTransactions(gomock.Any(), count).Return([]models.Transaction{}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `[]` + "\n",
@@ -166,10 +502,10 @@ func TestHandler_GetLastTransactions(t *testing.T) {
 			queryParam: "5",
 			inputCount: 5,
 			mockBehavior: func(s *service_mocks.MockTransaction, count int) {
-				s.EXPECT().GetLastTransactions(count).Return(nil, errors.New("database error"))
+				s.EXPECT().GetLastTransactions(gomock.Any(), count).Return(nil, errors.New("database error"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: "database error\n",
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}` + "\n",
 		},
 	}
 
@@ -182,7 +518,7 @@ func TestHandler_GetLastTransactions(t *testing.T) {
 			tt.mockBehavior(transactionMock, tt.inputCount)
 
 			services := &service.Service{Transaction: transactionMock}
-			handler := NewHandler(services)
+			handler := NewHandler(services, configs.Config{})
 
 			r := http.NewServeMux()
 			r.HandleFunc("/api/transactions", handler.GetLast)
@@ -197,3 +533,140 @@ func TestHandler_GetLastTransactions(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ListTransactions(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockTransaction)
+
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	minAmount := money.MustParse("5.00")
+
+	tests := []struct {
+		name                 string
+		url                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "First Page",
+			url:  "/api/transactions?limit=1",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ListTransactions(gomock.Any(), models.TransactionFilter{Role: models.TransactionRoleAny, Limit: 1}, "").Return(&models.TransactionPage{
+					Transactions: []models.Transaction{
+						{ID: 7, From: addr1, To: addr2, Amount: money.MustParse("10.50"), Status: models.TransactionStatusFailed, FailureReason: "insufficient funds", CreatedAt: createdAt, CompletedAt: &createdAt},
+					},
+					NextCursor: "next",
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"transactions":[{"id":7,"from":"` + addr1 + `","to":"` + addr2 + `","amount":"10.50","status":"failed","failure_reason":"insufficient funds","created_at":"2025-01-01T12:00:00Z","completed_at":"2025-01-01T12:00:00Z"}],"next_cursor":"next"}` + "\n",
+		},
+		{
+			name: "Filters",
+			url:  "/api/transactions?wallet=" + addr1 + "&role=sender&status=failed&min_amount=5&from_time=2025-01-01T12:00:00Z&cursor=abc",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ListTransactions(gomock.Any(), models.TransactionFilter{
+					Wallet:      addr1,
+					Role:        models.TransactionRoleSender,
+					Status:      models.TransactionStatusFailed,
+					MinAmount:   &minAmount,
+					CreatedFrom: &createdAt,
+				}, "abc").Return(&models.TransactionPage{Transactions: []models.Transaction{}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"transactions":[]}` + "\n",
+		},
+		{
+			name: "Legacy Count",
+			url:  "/api/transactions?count=1",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().GetLastTransactions(gomock.Any(), 1).Return([]models.Transaction{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[]` + "\n",
+		},
+		{
+			name:                 "Invalid Limit",
+			url:                  "/api/transactions?limit=500",
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"limit must be an integer between 1 and 100","details":{"field":"limit"}}` + "\n",
+		},
+		{
+			name:                 "Invalid Role",
+			url:                  "/api/transactions?role=owner",
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"role must be one of: any, sender, recipient","details":{"field":"role"}}` + "\n",
+		},
+		{
+			name:                 "Invalid Status",
+			url:                  "/api/transactions?status=done",
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"status must be one of: pending, completed, failed, reversed","details":{"field":"status"}}` + "\n",
+		},
+		{
+			name:                 "Invalid Time",
+			url:                  "/api/transactions?to_time=yesterday",
+			mockBehavior:         func(s *service_mocks.MockTransaction) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"to_time must be an RFC 3339 timestamp","details":{"field":"to_time"}}` + "\n",
+		},
+		{
+			name: "Invalid Cursor",
+			url:  "/api/transactions?cursor=bad",
+			mockBehavior: func(s *service_mocks.MockTransaction) {
+				s.EXPECT().ListTransactions(gomock.Any(), gomock.Any(), "bad").Return(nil, domain.ErrInvalidCursor)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_cursor","message":"invalid cursor"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			transactionMock := service_mocks.NewMockTransaction(c)
+			tt.mockBehavior(transactionMock)
+
+			services := &service.Service{Transaction: transactionMock}
+			handler := NewHandler(services, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+
+			handler.ListTransactions(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetWalletTransactions(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	transactionMock := service_mocks.NewMockTransaction(c)
+	transactionMock.EXPECT().ListTransactions(gomock.Any(), models.TransactionFilter{
+		Wallet: addr1,
+		Role:   models.TransactionRoleRecipient,
+		Limit:  10,
+	}, "").Return(&models.TransactionPage{Transactions: []models.Transaction{}}, nil)
+
+	handler := NewHandler(&service.Service{Transaction: transactionMock}, configs.Config{})
+
+	r := http.NewServeMux()
+	r.HandleFunc("GET /api/wallet/{address}/transactions", handler.GetWalletTransactions)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/wallet/"+addr1+"/transactions?role=recipient&limit=10", nil)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"transactions":[]}`+"\n", w.Body.String())
+}
@@ -2,67 +2,181 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
+	"golangTestTask/pkg/address"
+	"io"
 	"net/http"
 )
 
+// CreateWallet создает новый кошелек
+// @Summary Создать кошелек
+// @Description Создает кошелек с нулевым балансом. Если адрес не указан, он генерируется сервером; адрес, указанный клиентом, должен быть в формате с контрольной суммой. Если валюта не указана — используется USD
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param wallet body models.CreateWalletRequest false "Данные кошелька"
+// @Success 201 {object} models.Wallet
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload or address, or unsupported currency"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 409 {object} models.ErrorResponse "Wallet already exists"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/wallets [post]
+func (h *Handler) CreateWallet(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+	if req.Address != "" {
+		if err := validateAddress("address", req.Address); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	wallet, err := h.services.CreateWallet(r.Context(), models.Wallet{Address: req.Address, Currency: req.Currency})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(wallet)
+}
+
+// GetWallet возвращает кошелек
+// @Summary Получить кошелек
+// @Description Возвращает адрес, баланс и статус кошелька
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param address path string true "Адрес кошелька"
+// @Success 200 {object} models.Wallet
+// @Failure 400 {object} models.ErrorResponse "Invalid address"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Wallet not found"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/wallet/{address} [get]
+func (h *Handler) GetWallet(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	if err := validateAddress("address", address); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	wallet, err := h.services.GetWallet(r.Context(), address)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wallet)
+}
+
+// UpdateWalletStatus меняет статус кошелька
+// @Summary Изменить статус кошелька
+// @Description Замораживает, размораживает или закрывает кошелек. Закрыть можно только кошелек с нулевым балансом, закрытый кошелек изменить нельзя
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param address path string true "Адрес кошелька"
+// @Param status body models.UpdateWalletStatusRequest true "Новый статус"
+// @Success 200 {object} models.Wallet
+// @Failure 400 {object} models.ErrorResponse "Invalid address or status"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 404 {object} models.ErrorResponse "Wallet not found"
+// @Failure 409 {object} models.ErrorResponse "Wallet is closed or not empty"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/wallet/{address}/status [put]
+func (h *Handler) UpdateWalletStatus(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	if err := validateAddress("address", address); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req models.UpdateWalletStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, domain.NewValidationError("", "Invalid request body"))
+		return
+	}
+
+	wallet, err := h.services.SetWalletStatus(r.Context(), address, req.Status)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wallet)
+}
+
 // GetBalance возвращает баланс кошелька
 // @Summary Получить баланс кошелька
-// @Description Возвращает баланс по адресу кошелька
+// @Description Возвращает баланс по адресу кошелька: общий баланс, сумму активных блокировок средств (held)
+// @Description и доступные для переводов средства (available).
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param address path string true "Адрес кошелька"
-// @Success 200 {object} models.Wallet
-// @Failure 400 {string} string "Invalid address"
-// @Failure 404 {string} string "Wallet not found"
-// @Failure 500 {string} string "Server error"
+// @Success 200 {object} models.WalletBalance
+// @Failure 400 {object} models.ErrorResponse "Invalid address"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Wallet is not owned by the caller"
+// @Failure 404 {object} models.ErrorResponse "Wallet not found"
+// @Failure 500 {object} models.ErrorResponse "Server error"
 // @Router /api/wallet/{address}/balance [get]
 func (h *Handler) GetBalance(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeMethodNotAllowed(w, r)
 		return
 	}
 
 	address := r.PathValue("address")
-	if len(address) >= 64 {
-		http.Error(w, "too long address", http.StatusBadRequest)
+	if err := validateAddress("address", address); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	balance, err := h.services.GetWalletBalance(address)
+	balance, err := h.services.GetWalletBalance(r.Context(), address)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "wallet not found" {
-			status = http.StatusNotFound
-		}
-		http.Error(w, err.Error(), status)
+		writeError(w, r, err)
 		return
 	}
 
-	response := models.Wallet{
-		Address: address,
-		Balance: balance,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(balance)
 }
 
 // GetAllwallets Получение списка всех кошельков в БД
 // @Summary Получить список всех кошельков (для удобства проверки работоспособности API проверяющими)
 // @Description Возвращает все кошельки из БД
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Success 200 {array} models.Wallet
-// @Failure 500 {string} string "Server error"
+// @Failure 401 {object} models.ErrorResponse "Unauthenticated"
+// @Failure 403 {object} models.ErrorResponse "Permission denied"
+// @Failure 500 {object} models.ErrorResponse "Server error"
 // @Router /api/wallets [get]
 func (h *Handler) GetAllWallets(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeMethodNotAllowed(w, r)
 		return
 	}
 
-	wallets, err := h.services.GetAllWallets()
+	wallets, err := h.services.GetAllWallets(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -73,3 +187,22 @@ func (h *Handler) GetAllWallets(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(wallets)
 }
+
+// validateAddress проверяет, что значение value поля или параметра field имеет вид адреса кошелька пакета address.
+// Контрольную сумму проверяют сервисы: адрес с неверной контрольной суммой может быть устаревшим адресом существующего кошелька.
+func validateAddress(field string, value string) error {
+	if err := address.ValidateFormat(value); err != nil {
+		return domain.NewValidationError(field, err.Error())
+	}
+	return nil
+}
+
+// validateAddresses проверяет каждый адрес кошелька из списка values поля field.
+func validateAddresses(field string, values []string) error {
+	for _, value := range values {
+		if err := validateAddress(field, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,21 +1,35 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
 	"golangTestTask/internal/service"
 	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+// Адреса кошельков в формате pkg/address, используемые в тестах обработчиков, и устаревший адрес legacyAddr,
+// созданный до введения формата.
+const (
+	addr1      = "011111111111111111111111111111111111111111111111111111119b3556e5"
+	addr2      = "01222222222222222222222222222222222222222222222222222222b42432d6"
+	addr3      = "01333333333333333333333333333333333333333333333333333333aed4eec7"
+	legacyAddr = "e240d825e240d825e240d825e240d825e240d825e240d825e240d825e240d825"
+)
+
 func TestHandler_GetBalance(t *testing.T) {
-	type mockBehavior func(s *service_mocks.MockWallet, address string, balance float64, err error)
+	type mockBehavior func(s *service_mocks.MockWallet, address string, balance money.Amount, err error)
 
 	tests := []struct {
 		name                 string
@@ -23,47 +37,58 @@ func TestHandler_GetBalance(t *testing.T) {
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedLocation     string
 	}{
 		{
 			name:    "Success",
-			address: "addr1",
-			mockBehavior: func(s *service_mocks.MockWallet, address string, balance float64, err error) {
-				s.EXPECT().GetWalletBalance(address).Return(balance, nil)
+			address: addr1,
+			mockBehavior: func(s *service_mocks.MockWallet, address string, balance money.Amount, err error) {
+				s.EXPECT().GetWalletBalance(gomock.Any(), address).Return(&models.WalletBalance{
+					Address: address, Balance: balance, Held: money.FromInt(30), Available: balance - money.FromInt(30), Currency: "USD",
+				}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"address":"addr1","balance":100.5}` + "\n",
+			expectedResponseBody: `{"address":"` + addr1 + `","balance":"100.50","held":"30.00","available":"70.50","currency":"USD"}` + "\n",
 		},
 		{
 			name:    "Wallet Not Found",
-			address: "unknown",
-			mockBehavior: func(s *service_mocks.MockWallet, address string, balance float64, err error) {
-				s.EXPECT().GetWalletBalance(address).Return(0.0, errors.New("wallet not found"))
+			address: addr3,
+			mockBehavior: func(s *service_mocks.MockWallet, address string, balance money.Amount, err error) {
+				s.EXPECT().GetWalletBalance(gomock.Any(), address).Return(nil, domain.ErrWalletNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: "wallet not found\n",
+			expectedResponseBody: `{"code":"wallet_not_found","message":"wallet not found"}` + "\n",
 		},
 		{
-			name:                 "Empty Address",
-			address:              "",
-			mockBehavior:         nil,
-			expectedStatusCode:   http.StatusMovedPermanently,
-			expectedResponseBody: "<a href=\"/api/wallet/balance\">Moved Permanently</a>.\n\n",
+			name:             "Empty Address",
+			address:          "",
+			mockBehavior:     nil,
+			expectedLocation: "/api/wallet/balance",
 		},
 		{
 			name:                 "Long Address",
 			address:              "this_is_a_very_long_wallet_address_that_exceeds_the_maximum_allowed_length_of_64_characters",
 			mockBehavior:         nil,
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: "too long address\n",
+			expectedResponseBody: `{"code":"invalid_request","message":"invalid wallet address: must be 64 characters long","details":{"field":"address"}}` + "\n",
+		},
+		{
+			name:    "Mistyped Address",
+			address: addr1[:63] + "6",
+			mockBehavior: func(s *service_mocks.MockWallet, address string, balance money.Amount, err error) {
+				s.EXPECT().GetWalletBalance(gomock.Any(), address).Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"code":"wallet_not_found","message":"wallet not found"}` + "\n",
 		},
 		{
 			name:    "Service Error",
-			address: "addr1",
-			mockBehavior: func(s *service_mocks.MockWallet, address string, balance float64, err error) {
-				s.EXPECT().GetWalletBalance(address).Return(0.0, errors.New("database error"))
+			address: addr1,
+			mockBehavior: func(s *service_mocks.MockWallet, address string, balance money.Amount, err error) {
+				s.EXPECT().GetWalletBalance(gomock.Any(), address).Return(nil, errors.New("database error"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: "database error\n",
+			expectedResponseBody: `{"code":"internal_error","message":"internal server error"}` + "\n",
 		},
 	}
 
@@ -74,11 +99,11 @@ func TestHandler_GetBalance(t *testing.T) {
 
 			walletMock := service_mocks.NewMockWallet(c)
 			if tt.mockBehavior != nil {
-				tt.mockBehavior(walletMock, tt.address, 100.5, nil)
+				tt.mockBehavior(walletMock, tt.address, money.MustParse("100.50"), nil)
 			}
 
 			services := &service.Service{Wallet: walletMock}
-			handler := NewHandler(services)
+			handler := NewHandler(services, configs.Config{})
 
 			r := http.NewServeMux()
 			r.HandleFunc("GET /api/wallet/{address}/balance", handler.GetBalance)
@@ -92,6 +117,13 @@ func TestHandler_GetBalance(t *testing.T) {
 
 			r.ServeHTTP(w, req)
 
+			if tt.expectedLocation != "" {
+				// Код редиректа при очистке пути (301 или 307) зависит от версии net/http.
+				assert.GreaterOrEqual(t, w.Code, http.StatusMultipleChoices)
+				assert.Less(t, w.Code, http.StatusBadRequest)
+				assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+				return
+			}
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
 		})
@@ -103,3 +135,236 @@ func withPathValue(parent context.Context, key, value string) context.Context {
 }
 
 type contextKey string
+
+func TestHandler_CreateWallet(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockWallet)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Client Address",
+			inputBody: `{"address": "` + addr1 + `"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().CreateWallet(gomock.Any(), models.Wallet{Address: addr1}).Return(&models.Wallet{
+					Address: addr1,
+					Status:  models.WalletStatusActive,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"address":"` + addr1 + `","balance":"0.00","status":"active"}` + "\n",
+		},
+		{
+			name:      "Generated Address",
+			inputBody: "",
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().CreateWallet(gomock.Any(), models.Wallet{}).Return(&models.Wallet{
+					Address: "generated",
+					Status:  models.WalletStatusActive,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"address":"generated","balance":"0.00","status":"active"}` + "\n",
+		},
+		{
+			name:      "Already Exists",
+			inputBody: `{"address": "` + addr1 + `"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().CreateWallet(gomock.Any(), models.Wallet{Address: addr1}).Return(nil, domain.ErrWalletAlreadyExists)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"wallet_already_exists","message":"wallet already exists"}` + "\n",
+		},
+		{
+			name:      "Invalid Address",
+			inputBody: `{"address": "` + addr1[:63] + `6"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().CreateWallet(gomock.Any(), models.Wallet{Address: addr1[:63] + "6"}).
+					Return(nil, domain.NewValidationError("address", "invalid wallet address: checksum mismatch"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"invalid wallet address: checksum mismatch","details":{"field":"address"}}` + "\n",
+		},
+		{
+			name:                 "Invalid JSON",
+			inputBody:            `{"address": 1}`,
+			mockBehavior:         func(s *service_mocks.MockWallet) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_request","message":"Invalid request body"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			walletMock := service_mocks.NewMockWallet(c)
+			tt.mockBehavior(walletMock)
+
+			services := &service.Service{Wallet: walletMock}
+			handler := NewHandler(services, configs.Config{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/wallets", bytes.NewBufferString(tt.inputBody))
+
+			handler.CreateWallet(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetWallet(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockWallet)
+
+	tests := []struct {
+		name                 string
+		address              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "Success",
+			address: addr1,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().GetWallet(gomock.Any(), addr1).Return(&models.Wallet{
+					Address: addr1,
+					Balance: money.MustParse("5.00"),
+					Status:  models.WalletStatusFrozen,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"address":"` + addr1 + `","balance":"5.00","status":"frozen"}` + "\n",
+		},
+		{
+			name:    "Legacy Address",
+			address: legacyAddr,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().GetWallet(gomock.Any(), legacyAddr).Return(&models.Wallet{
+					Address: legacyAddr,
+					Balance: money.MustParse("5.00"),
+					Status:  models.WalletStatusActive,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"address":"` + legacyAddr + `","balance":"5.00","status":"active"}` + "\n",
+		},
+		{
+			name:    "Not Found",
+			address: addr3,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().GetWallet(gomock.Any(), addr3).Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"code":"wallet_not_found","message":"wallet not found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			walletMock := service_mocks.NewMockWallet(c)
+			tt.mockBehavior(walletMock)
+
+			services := &service.Service{Wallet: walletMock}
+			handler := NewHandler(services, configs.Config{})
+
+			r := http.NewServeMux()
+			r.HandleFunc("GET /api/wallet/{address}", handler.GetWallet)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/wallet/"+tt.address, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_UpdateWalletStatus(t *testing.T) {
+	type mockBehavior func(s *service_mocks.MockWallet)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Freeze",
+			inputBody: `{"status": "frozen"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().SetWalletStatus(gomock.Any(), addr1, models.WalletStatusFrozen).Return(&models.Wallet{
+					Address: addr1,
+					Balance: money.MustParse("5.00"),
+					Status:  models.WalletStatusFrozen,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"address":"` + addr1 + `","balance":"5.00","status":"frozen"}` + "\n",
+		},
+		{
+			name:      "Invalid Status",
+			inputBody: `{"status": "deleted"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().SetWalletStatus(gomock.Any(), addr1, models.WalletStatus("deleted")).Return(nil, domain.ErrInvalidWalletStatus)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"code":"invalid_wallet_status","message":"invalid wallet status"}` + "\n",
+		},
+		{
+			name:      "Close Non Empty Wallet",
+			inputBody: `{"status": "closed"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().SetWalletStatus(gomock.Any(), addr1, models.WalletStatusClosed).Return(nil, domain.ErrWalletNotEmpty)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"code":"wallet_not_empty","message":"wallet balance is not zero"}` + "\n",
+		},
+		{
+			name:      "Not Found",
+			inputBody: `{"status": "frozen"}`,
+			mockBehavior: func(s *service_mocks.MockWallet) {
+				s.EXPECT().SetWalletStatus(gomock.Any(), addr1, models.WalletStatusFrozen).Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"code":"wallet_not_found","message":"wallet not found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			walletMock := service_mocks.NewMockWallet(c)
+			tt.mockBehavior(walletMock)
+
+			services := &service.Service{Wallet: walletMock}
+			handler := NewHandler(services, configs.Config{})
+
+			r := http.NewServeMux()
+			r.HandleFunc("PUT /api/wallet/{address}/status", handler.UpdateWalletStatus)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/api/wallet/"+addr1+"/status", bytes.NewBufferString(tt.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
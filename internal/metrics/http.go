@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute — значение метки route для запросов, не подошедших ни к одному маршруту.
+// Путь таких запросов в метку не попадает, чтобы число временных рядов не зависело от клиентов.
+const unmatchedRoute = "unmatched"
+
+// otherMethod — значение метки method для запросов с нестандартным методом. Метод запроса задает клиент,
+// поэтому в метку попадают только известные методы, чтобы число временных рядов не зависело от клиентов.
+const otherMethod = "other"
+
+// Middleware измеряет длительность и код ответа запросов, обрабатываемых next. Маршрут берется из шаблона mux,
+// подходящего запросу, до вызова next, поэтому next может быть цепочкой промежуточных обработчиков перед mux:
+// ответы, которые они дают сами (401, 429, истечение времени обработки), учитываются под маршрутом запроса.
+func Middleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = unmatchedRoute
+		}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		httpRequestDuration.
+			WithLabelValues(methodLabel(r.Method), route, strconv.Itoa(rec.status)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// methodLabel возвращает значение метки method для метода запроса method.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return method
+	}
+	return otherMethod
+}
+
+// statusRecorder запоминает код ответа, переданный клиенту.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
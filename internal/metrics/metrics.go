@@ -0,0 +1,88 @@
+// Package metrics содержит метрики Prometheus сервиса и обработчик /metrics для их выдачи.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"golangTestTask/internal/domain"
+	"golangTestTask/pkg/money"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "payment"
+
+// Исходы перевода, используемые как значение метки outcome.
+const (
+	OutcomeSuccess           = "success"
+	OutcomeInsufficientFunds = "insufficient_funds"
+	OutcomeNotFound          = "not_found"
+	OutcomeRejected          = "rejected"
+	OutcomeLimitExceeded     = "limit_exceeded"
+	OutcomeError             = "error"
+)
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Длительность обработки HTTP-запросов по маршрутам и кодам ответа.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	transfersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Количество попыток перевода по исходам.",
+	}, []string{"outcome"})
+
+	transferVolumeTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_volume_total",
+		Help:      "Сумма попыток перевода в единицах валюты отправителя по исходам и валютам.",
+	}, []string{"outcome", "currency"})
+)
+
+// Handler возвращает обработчик, отдающий метрики в формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveTransfer учитывает попытку перевода суммы amount в валюте currency, завершившуюся ошибкой err (nil при успехе).
+// Валюта пуста, если перевод отклонен до того, как стал известен кошелек отправителя.
+func ObserveTransfer(amount money.Amount, currency string, err error) {
+	outcome := TransferOutcome(err)
+	transfersTotal.WithLabelValues(outcome).Inc()
+	transferVolumeTotal.WithLabelValues(outcome, currency).Add(amount.Float64())
+}
+
+// TransferOutcome определяет исход перевода по ошибке err.
+func TransferOutcome(err error) string {
+	var walletErr *domain.WalletError
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, domain.ErrInsufficientFunds):
+		return OutcomeInsufficientFunds
+	case errors.Is(err, domain.ErrWalletNotFound):
+		return OutcomeNotFound
+	case errors.Is(err, domain.ErrLimitExceeded), errors.Is(err, domain.ErrAmountBelowMinimum), errors.Is(err, domain.ErrAmountAboveMaximum),
+		errors.Is(err, domain.ErrDailyLimitExceeded), errors.Is(err, domain.ErrMonthlyLimitExceeded), errors.Is(err, domain.ErrMaxBalanceExceeded):
+		return OutcomeLimitExceeded
+	case errors.As(err, &walletErr), errors.Is(err, domain.ErrSameWallet), errors.Is(err, domain.ErrUnauthenticated),
+		errors.Is(err, domain.ErrCurrencyMismatch), errors.Is(err, domain.ErrConversionUnavailable), errors.Is(err, domain.ErrInvalidAmountPrecision),
+		errors.Is(err, domain.ErrFeeUnavailable):
+		return OutcomeRejected
+	}
+	return OutcomeError
+}
+
+// RegisterDBStats регистрирует метрики пула соединений db (открытые, занятые, ожидания и т.д.).
+func RegisterDBStats(db *sql.DB, dbName string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
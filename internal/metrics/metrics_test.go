@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "success", err: nil, want: OutcomeSuccess},
+		{name: "insufficient funds", err: domain.ErrInsufficientFunds, want: OutcomeInsufficientFunds},
+		{name: "wallet not found", err: domain.NewWalletError(models.TransactionRoleSender, "addr1", domain.ErrWalletNotFound), want: OutcomeNotFound},
+		{name: "wallet frozen", err: domain.NewWalletError(models.TransactionRoleRecipient, "addr2", domain.ErrWalletFrozen), want: OutcomeRejected},
+		{name: "same wallet", err: domain.ErrSameWallet, want: OutcomeRejected},
+		{name: "limit exceeded", err: domain.NewLimitError(domain.LimitDailyVolume, time.Hour), want: OutcomeLimitExceeded},
+		{name: "tier limit exceeded", err: domain.NewWalletError(models.TransactionRoleSender, "addr1", domain.ErrDailyLimitExceeded), want: OutcomeLimitExceeded},
+		{name: "database error", err: fmt.Errorf("failed to commit transaction: %w", errors.New("conn reset")), want: OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TransferOutcome(tt.err))
+		})
+	}
+}
+
+func TestObserveTransfer(t *testing.T) {
+	count := testutil.ToFloat64(transfersTotal.WithLabelValues(OutcomeInsufficientFunds))
+	volume := testutil.ToFloat64(transferVolumeTotal.WithLabelValues(OutcomeInsufficientFunds, "EUR"))
+
+	ObserveTransfer(money.MustParse("10.50"), "EUR", domain.ErrInsufficientFunds)
+
+	assert.Equal(t, count+1, testutil.ToFloat64(transfersTotal.WithLabelValues(OutcomeInsufficientFunds)))
+	assert.InDelta(t, volume+10.5, testutil.ToFloat64(transferVolumeTotal.WithLabelValues(OutcomeInsufficientFunds, "EUR")), 1e-9)
+}
+
+func TestObserveReconciliation(t *testing.T) {
+	generatedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ObserveReconciliation(&models.ReconciliationReport{
+		GeneratedAt:            generatedAt,
+		Discrepancies:          []models.BalanceDiscrepancy{{Address: "addr1"}, {Address: "addr2"}},
+		UnbalancedEntries:      []int64{7},
+		UnrecordedTransactions: []int{12, 13, 14},
+		MismatchedEntries:      []int64{9},
+	}, nil)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(reconciliationDiscrepancies))
+	assert.Equal(t, float64(1), testutil.ToFloat64(reconciliationUnbalancedEntries))
+	assert.Equal(t, float64(3), testutil.ToFloat64(reconciliationUnrecordedTransactions))
+	assert.Equal(t, float64(1), testutil.ToFloat64(reconciliationMismatchedEntries))
+	assert.Equal(t, float64(generatedAt.Unix()), testutil.ToFloat64(reconciliationLastRun))
+
+	failures := testutil.ToFloat64(reconciliationFailuresTotal)
+	ObserveReconciliation(nil, errors.New("db error"))
+
+	assert.Equal(t, failures+1, testutil.ToFloat64(reconciliationFailuresTotal))
+	assert.Equal(t, float64(2), testutil.ToFloat64(reconciliationDiscrepancies))
+}
+
+func TestObserveScheduledTransferRun(t *testing.T) {
+	completed := testutil.ToFloat64(scheduledTransferRunsTotal.WithLabelValues("completed"))
+	retrying := testutil.ToFloat64(scheduledTransferRunsTotal.WithLabelValues("retrying"))
+
+	ObserveScheduledTransferRun(models.ScheduledTransferRunCompleted)
+
+	assert.Equal(t, completed+1, testutil.ToFloat64(scheduledTransferRunsTotal.WithLabelValues("completed")))
+	assert.Equal(t, retrying, testutil.ToFloat64(scheduledTransferRunsTotal.WithLabelValues("retrying")))
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/wallet/{address}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	// Промежуточный обработчик перед mux отклоняет запросы без учетных данных, не вызывая mux.
+	authenticate := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+	handler := Middleware(mux, authenticate)
+
+	before := testutil.CollectAndCount(httpRequestDuration)
+	for _, path := range []string{"/api/wallet/addr1", "/api/wallet/addr2", "/unknown"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-API-Key", "pk_test")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/wallet/addr1", nil))
+
+	// Запросы к одному маршруту попадают в один ряд независимо от адреса кошелька.
+	assert.Equal(t, before+3, testutil.CollectAndCount(httpRequestDuration))
+	expected := `payment_http_request_duration_seconds_count{method="GET",route="GET /api/wallet/{address}",status="404"} 2`
+	assert.Contains(t, gatherText(t), expected)
+	assert.Contains(t, gatherText(t), `payment_http_request_duration_seconds_count{method="GET",route="GET /api/wallet/{address}",status="401"} 1`)
+	assert.Contains(t, gatherText(t), `route="unmatched",status="404"`)
+
+	// Нестандартные методы попадают в один ряд, а не в отдельный ряд для каждого метода.
+	for _, method := range []string{"PROPFIND", "X-RANDOM-1", "X-RANDOM-2"} {
+		req := httptest.NewRequest(method, "/unknown", nil)
+		req.Header.Set("X-API-Key", "pk_test")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.Contains(t, gatherText(t), `payment_http_request_duration_seconds_count{method="other",route="unmatched",status="404"} 3`)
+	assert.NotContains(t, gatherText(t), `method="PROPFIND"`)
+}
+
+func TestWalletCollector(t *testing.T) {
+	collector := walletCollector{stats: func(ctx context.Context) ([]models.WalletStats, error) {
+		return []models.WalletStats{
+			{Status: models.WalletStatusActive, Currency: "EUR", Count: 2, Balance: money.MustParse("40.25")},
+			{Status: models.WalletStatusActive, Currency: "USD", Count: 3, Balance: money.MustParse("250.75")},
+			{Status: models.WalletStatusFrozen, Currency: "USD", Count: 1, Balance: money.MustParse("10.00")},
+		}, nil
+	}}
+
+	expected := `
+# HELP payment_wallet_balance Сумма балансов кошельков в единицах валюты по статусам и валютам.
+# TYPE payment_wallet_balance gauge
+payment_wallet_balance{currency="EUR",status="active"} 40.25
+payment_wallet_balance{currency="USD",status="active"} 250.75
+payment_wallet_balance{currency="USD",status="frozen"} 10
+# HELP payment_wallets Количество кошельков по статусам и валютам.
+# TYPE payment_wallets gauge
+payment_wallets{currency="EUR",status="active"} 2
+payment_wallets{currency="USD",status="active"} 3
+payment_wallets{currency="USD",status="frozen"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestWalletCollector_Error(t *testing.T) {
+	collector := walletCollector{stats: func(ctx context.Context) ([]models.WalletStats, error) {
+		return nil, errors.New("db error")
+	}}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	_, err := registry.Gather()
+	assert.Error(t, err)
+}
+
+func gatherText(t *testing.T) string {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	var b strings.Builder
+	for _, family := range families {
+		if family.GetName() != "payment_http_request_duration_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make([]string, 0, len(m.GetLabel()))
+			for _, l := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%q", l.GetName(), l.GetValue()))
+			}
+			fmt.Fprintf(&b, "%s_count{%s} %d\n", family.GetName(), strings.Join(labels, ","), m.GetHistogram().GetSampleCount())
+		}
+	}
+	return b.String()
+}
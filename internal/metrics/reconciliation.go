@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"golangTestTask/internal/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	reconciliationDiscrepancies = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciliation",
+		Name:      "discrepancies",
+		Help:      "Количество кошельков, баланс которых расходился с журналом или историей транзакций при последней сверке.",
+	})
+
+	reconciliationUnbalancedEntries = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciliation",
+		Name:      "unbalanced_entries",
+		Help:      "Количество несбалансированных записей журнала, найденных при последней сверке.",
+	})
+
+	reconciliationUnrecordedTransactions = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciliation",
+		Name:      "unrecorded_transactions",
+		Help:      "Количество завершенных транзакций без записи в журнале, найденных при последней сверке.",
+	})
+
+	reconciliationMismatchedEntries = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciliation",
+		Name:      "mismatched_entries",
+		Help:      "Количество записей журнала, расходящихся со своей транзакцией, найденных при последней сверке.",
+	})
+
+	reconciliationLastRun = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "reconciliation",
+		Name:      "last_run_timestamp_seconds",
+		Help:      "Время последней успешной сверки балансов с журналом в секундах Unix.",
+	})
+
+	reconciliationFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "reconciliation",
+		Name:      "failures_total",
+		Help:      "Количество сверок балансов, завершившихся ошибкой.",
+	})
+)
+
+// ObserveReconciliation учитывает итог сверки балансов: отчет report при успехе или ошибку err.
+func ObserveReconciliation(report *models.ReconciliationReport, err error) {
+	if err != nil {
+		reconciliationFailuresTotal.Inc()
+		return
+	}
+	reconciliationDiscrepancies.Set(float64(len(report.Discrepancies)))
+	reconciliationUnbalancedEntries.Set(float64(len(report.UnbalancedEntries)))
+	reconciliationUnrecordedTransactions.Set(float64(len(report.UnrecordedTransactions)))
+	reconciliationMismatchedEntries.Set(float64(len(report.MismatchedEntries)))
+	reconciliationLastRun.Set(float64(report.GeneratedAt.Unix()))
+}
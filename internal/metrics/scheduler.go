@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"golangTestTask/internal/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var scheduledTransferRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "scheduler",
+	Name:      "runs_total",
+	Help:      "Количество попыток выполнить регулярные переводы по итогу попытки.",
+}, []string{"status"})
+
+// ObserveScheduledTransferRun учитывает попытку выполнить регулярный перевод, завершившуюся со статусом status.
+func ObserveScheduledTransferRun(status models.ScheduledTransferRunStatus) {
+	scheduledTransferRunsTotal.WithLabelValues(string(status)).Inc()
+}
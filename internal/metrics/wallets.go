@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"golangTestTask/internal/models"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// walletStatsTimeout ограничивает время запроса статистики кошельков при одном сборе метрик.
+const walletStatsTimeout = 5 * time.Second
+
+// WalletStatsFunc возвращает количество кошельков и сумму их балансов по статусам и валютам.
+type WalletStatsFunc func(ctx context.Context) ([]models.WalletStats, error)
+
+var (
+	walletsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "wallets"),
+		"Количество кошельков по статусам и валютам.",
+		[]string{"status", "currency"}, nil,
+	)
+	walletBalanceDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "wallet_balance"),
+		"Сумма балансов кошельков в единицах валюты по статусам и валютам.",
+		[]string{"status", "currency"}, nil,
+	)
+)
+
+// RegisterWalletStats регистрирует метрики кошельков, которые при каждом сборе запрашиваются через stats.
+func RegisterWalletStats(stats WalletStatsFunc) {
+	prometheus.MustRegister(walletCollector{stats: stats})
+}
+
+type walletCollector struct {
+	stats WalletStatsFunc
+}
+
+func (c walletCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- walletsDesc
+	ch <- walletBalanceDesc
+}
+
+func (c walletCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), walletStatsTimeout)
+	defer cancel()
+
+	stats, err := c.stats(ctx)
+	if err != nil {
+		log.Printf("Failed to collect wallet stats: %v", err)
+		ch <- prometheus.NewInvalidMetric(walletsDesc, err)
+		return
+	}
+	for _, s := range stats {
+		ch <- prometheus.MustNewConstMetric(walletsDesc, prometheus.GaugeValue, float64(s.Count), string(s.Status), s.Currency)
+		ch <- prometheus.MustNewConstMetric(walletBalanceDesc, prometheus.GaugeValue, s.Balance.Float64(), string(s.Status), s.Currency)
+	}
+}
@@ -1,24 +1,595 @@
 package models
 
+import (
+	"encoding/csv"
+	"golangTestTask/internal/auth"
+	"golangTestTask/pkg/money"
+	"io"
+	"time"
+)
+
+type WalletStatus string
+
+const (
+	WalletStatusActive WalletStatus = "active"
+	WalletStatusFrozen WalletStatus = "frozen"
+	WalletStatusClosed WalletStatus = "closed"
+)
+
+// Valid сообщает, является ли s одним из известных статусов кошелька.
+func (s WalletStatus) Valid() bool {
+	switch s {
+	case WalletStatusActive, WalletStatusFrozen, WalletStatusClosed:
+		return true
+	}
+	return false
+}
+
 type Wallet struct {
-	Address string  `json:"address"`
-	Balance float64 `json:"balance"`
+	Address string       `json:"address"`
+	Balance money.Amount `json:"balance" swaggertype:"string" example:"100.00"`
+	Status  WalletStatus `json:"status,omitempty" enums:"active,frozen,closed" example:"active"`
+	// Tier — имя уровня кошелька, определяющего ограничения на переводы с него и на него.
+	Tier string `json:"tier,omitempty" example:"standard"`
+	// Currency — код валюты кошелька по ISO 4217. Баланс и все суммы переводов с кошелька выражены в этой валюте.
+	Currency string `json:"currency,omitempty" example:"USD"`
+}
+
+// WalletBalance — баланс кошелька. Balance — общий баланс, Held — сумма активных блокировок средств кошелька,
+// Available — доступные для переводов и новых блокировок средства (Balance - Held).
+type WalletBalance struct {
+	Address   string       `json:"address"`
+	Balance   money.Amount `json:"balance" swaggertype:"string" example:"100.00"`
+	Held      money.Amount `json:"held" swaggertype:"string" example:"30.00"`
+	Available money.Amount `json:"available" swaggertype:"string" example:"70.00"`
+	Currency  string       `json:"currency,omitempty" example:"USD"`
+}
+
+// DefaultWalletTier — уровень, который присваивается новым кошелькам.
+const DefaultWalletTier = "standard"
+
+// WalletTier — уровень кошелька с ограничениями на переводы. Нулевое значение ограничения, кроме MinTransfer, означает его отсутствие.
+// Ограничения задаются в USD и применяются к суммам в других валютах после пересчета по курсу.
+type WalletTier struct {
+	Name string `json:"name" example:"standard"`
+	// MinTransfer и MaxTransfer ограничивают сумму одного перевода с кошелька.
+	MinTransfer money.Amount `json:"min_transfer" swaggertype:"string" example:"0.01"`
+	MaxTransfer money.Amount `json:"max_transfer" swaggertype:"string" example:"1000.00"`
+	// DailyLimit и MonthlyLimit ограничивают сумму переводов с кошелька за календарные сутки и месяц по UTC.
+	DailyLimit   money.Amount `json:"daily_limit" swaggertype:"string" example:"5000.00"`
+	MonthlyLimit money.Amount `json:"monthly_limit" swaggertype:"string" example:"50000.00"`
+	// MaxBalance — максимальный баланс, до которого можно пополнить кошелек переводом.
+	MaxBalance money.Amount `json:"max_balance" swaggertype:"string" example:"100000.00"`
+}
+
+type UpdateWalletTierRequest struct {
+	Tier string `json:"tier" example:"verified"`
+}
+
+// WalletStats — количество кошельков в статусе Status и валюте Currency и сумма их балансов.
+type WalletStats struct {
+	Status   WalletStatus
+	Currency string
+	Count    int
+	Balance  money.Amount
+}
+
+type TransactionStatus string
+
+const (
+	TransactionStatusPending   TransactionStatus = "pending"
+	TransactionStatusCompleted TransactionStatus = "completed"
+	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusReversed  TransactionStatus = "reversed"
+)
+
+// Valid сообщает, является ли s одним из известных статусов транзакции.
+func (s TransactionStatus) Valid() bool {
+	switch s {
+	case TransactionStatusPending, TransactionStatusCompleted, TransactionStatusFailed, TransactionStatusReversed:
+		return true
+	}
+	return false
 }
 
 type Transaction struct {
-	ID     int     `json:"id"`
-	From   string  `json:"from"`
-	To     string  `json:"to"`
-	Amount float64 `json:"amount"`
+	ID     int               `json:"id"`
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Amount money.Amount      `json:"amount" swaggertype:"string" example:"10.50"`
+	Status TransactionStatus `json:"status" enums:"pending,completed,failed,reversed" example:"completed"`
+	// FailureReason — причина отказа в переводе; заполняется только для транзакций в статусе failed.
+	FailureReason string     `json:"failure_reason,omitempty" example:"insufficient funds"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	// Currency — валюта суммы перевода (валюта кошелька отправителя); пуста, если отправитель не найден.
+	Currency string `json:"currency,omitempty" example:"USD"`
+	// ReversalOf — ID перевода, который отменяет эта компенсирующая транзакция; nil для обычных переводов.
+	ReversalOf *int `json:"reversal_of,omitempty" example:"42"`
+	// ScheduledTransferID и ScheduledFor — регулярный перевод, по которому выполнена транзакция, и срок, за который
+	// она выполнена; nil для переводов, запрошенных через API.
+	ScheduledTransferID *int       `json:"scheduled_transfer_id,omitempty" example:"3"`
+	ScheduledFor        *time.Time `json:"scheduled_for,omitempty"`
+}
+
+type TransactionRole string
+
+const (
+	TransactionRoleAny       TransactionRole = "any"
+	TransactionRoleSender    TransactionRole = "sender"
+	TransactionRoleRecipient TransactionRole = "recipient"
+)
+
+// TransactionFilter задает условия выборки истории транзакций. Пустые поля не ограничивают выборку.
+type TransactionFilter struct {
+	// Wallet — адрес кошелька, участвующего в транзакции в роли Role. Неудачные переводы выбираются только
+	// для отправителя: средства получателя они не затрагивают, а причина отказа касается только отправителя.
+	Wallet string
+	Role   TransactionRole
+	Status TransactionStatus
+	// MinAmount и MaxAmount ограничивают сумму транзакции включительно.
+	MinAmount *money.Amount
+	MaxAmount *money.Amount
+	// CreatedFrom (включительно) и CreatedTo (не включительно) ограничивают время создания транзакции.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// BeforeID выбирает транзакции с ID меньше указанного; используется для постраничной выборки.
+	BeforeID int
+	Limit    int
+}
+
+// TransactionFee — строка комиссии, списанной с отправителя в пользу кошелька комиссий Wallet при переводе TransactionID.
+type TransactionFee struct {
+	ID            int
+	TransactionID int
+	Wallet        string
+	Amount        money.Amount
+	CreatedAt     time.Time
+}
+
+// TransactionConversion — обе части перевода TransactionID с конвертацией: списание DebitAmount в валюте DebitCurrency
+// и зачисление CreditAmount в валюте CreditCurrency по курсу Rate, равному рыночному курсу MidRate за вычетом спреда SpreadBP.
+type TransactionConversion struct {
+	ID             int
+	TransactionID  int
+	DebitCurrency  string
+	DebitAmount    money.Amount
+	CreditCurrency string
+	CreditAmount   money.Amount
+	MidRate        money.Rate
+	Rate           money.Rate
+	SpreadBP       int64
+	CreatedAt      time.Time
+}
+
+// TransactionQuote — использование предварительного расчета с одноразовым значением Nonce переводом TransactionID.
+type TransactionQuote struct {
+	TransactionID int
+	Nonce         string
+	CreatedAt     time.Time
+}
+
+type JournalEntryKind string
+
+const (
+	// JournalEntryKindOpening — зачисление начального баланса кошелька со счета LedgerAccountEquity.
+	JournalEntryKindOpening JournalEntryKind = "opening"
+	// JournalEntryKindTransfer — перевод между кошельками вместе с комиссией и конвертацией.
+	JournalEntryKindTransfer JournalEntryKind = "transfer"
+	// JournalEntryKindReversal — возврат средств отправителю по отмененному переводу.
+	JournalEntryKindReversal JournalEntryKind = "reversal"
+)
+
+const (
+	// LedgerAccountEquity — системный счет, с которого зачисляются начальные балансы кошельков.
+	LedgerAccountEquity = "equity"
+	// LedgerAccountExchange — системный счет обмена валют: при конвертации он принимает сумму в валюте отправителя
+	// и выдает сумму зачисления в валюте получателя.
+	LedgerAccountExchange = "exchange"
+)
+
+// JournalEntry — запись журнала двойной записи: набор проводок Postings, сумма которых в каждой валюте равна нулю.
+// TransactionID — транзакция, по которой сделана запись, или 0, если запись не связана с переводом.
+type JournalEntry struct {
+	ID            int64
+	Kind          JournalEntryKind
+	TransactionID int
+	Postings      []Posting
+	CreatedAt     time.Time
+}
+
+// Posting — проводка: изменение баланса кошелька Wallet либо системного счета Account на сумму Amount в валюте Currency.
+// Положительная сумма зачисляется на счет, отрицательная списывается с него. Заполняется ровно одно из полей Wallet и Account.
+type Posting struct {
+	Wallet   string
+	Account  string
+	Currency string
+	Amount   money.Amount
+}
+
+// Conversion — зачисление получателю при переводе с конвертацией: сумма Amount в валюте Currency по курсу Rate
+// с учетом спреда SpreadBP в базисных пунктах.
+type Conversion struct {
+	Currency string       `json:"currency" example:"EUR"`
+	Amount   money.Amount `json:"amount" swaggertype:"string" example:"9.61"`
+	Rate     money.Rate   `json:"rate" swaggertype:"string" example:"0.91540000"`
+	SpreadBP int64        `json:"spread_bp" example:"50"`
+}
+
+// TransferResult — итог выполненного перевода: с отправителя списано Total = Amount + Fee в валюте Currency.
+// Для перевода с конвертацией Conversion содержит сумму, зачисленную получателю в его валюте.
+type TransferResult struct {
+	TransactionID int
+	Currency      string
+	Amount        money.Amount
+	Fee           money.Amount
+	Total         money.Amount
+	Conversion    *Conversion
+}
+
+// TransferQuote — предварительный расчет перевода: комиссия, итоговая сумма списания и балансы кошельков после перевода.
+// Баланс получателя возвращается, только если участник может просматривать его кошелек.
+// QuoteID можно передать в POST /api/send до ExpiresAt, чтобы перевод был выполнен с рассчитанной комиссией
+// и, для перевода с конвертацией, по зафиксированному в Conversion курсу. Баланс получателя указан в его валюте.
+type TransferQuote struct {
+	QuoteID               string        `json:"quote_id"`
+	From                  string        `json:"from" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	To                    string        `json:"to" example:"01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"`
+	Currency              string        `json:"currency" example:"USD"`
+	Amount                money.Amount  `json:"amount" swaggertype:"string" example:"10.50"`
+	Fee                   money.Amount  `json:"fee" swaggertype:"string" example:"0.30"`
+	Total                 money.Amount  `json:"total" swaggertype:"string" example:"10.80"`
+	SenderBalanceAfter    money.Amount  `json:"sender_balance_after" swaggertype:"string" example:"89.20"`
+	RecipientBalanceAfter *money.Amount `json:"recipient_balance_after,omitempty" swaggertype:"string" example:"60.50"`
+	Conversion            *Conversion   `json:"conversion,omitempty"`
+	ExpiresAt             time.Time     `json:"expires_at"`
+}
+
+// TransferActivity — сводка исходящих переводов кошелька за период: их количество,
+// общая сумма и время самого раннего из них (нулевое, если переводов не было).
+type TransferActivity struct {
+	Count  int
+	Volume money.Amount
+	First  time.Time
+}
+
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	// NextCursor — курсор следующей страницы; пуст, если страница последняя.
+	NextCursor string `json:"next_cursor,omitempty" example:"djE6MTA0"`
 }
 
 type CreateTransactionRequest struct {
-	From   string  `json:"from" example:"e240d825d255af751f5f55af8d9671be"`
-	To     string  `json:"to" example:"abdf2236c0a3b4e2639b3e182d994c88e"`
-	Amount float64 `json:"amount" example:"10" minimum:"0.01"`
+	From   string       `json:"from" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	To     string       `json:"to" example:"01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"`
+	Amount money.Amount `json:"amount" swaggertype:"string" example:"10.50"`
+	// QuoteID — идентификатор предварительного расчета из POST /api/send/quote, гарантирующий рассчитанную комиссию.
+	QuoteID string `json:"quote_id,omitempty"`
+	// Convert разрешает перевод между кошельками в разных валютах с конвертацией суммы по текущему курсу.
+	Convert bool `json:"convert,omitempty"`
+	// ScheduledTransferID и ScheduledFor задают планировщик при выполнении регулярного перевода за срок ScheduledFor.
+	// Из тела запроса API они не читаются.
+	ScheduledTransferID int       `json:"-"`
+	ScheduledFor        time.Time `json:"-"`
+}
+
+// ReverseTransactionRequest — запрос на отмену перевода. Amount указывается в валюте отправителя исходного перевода;
+// если он не задан, отменяется вся сумма перевода.
+type ReverseTransactionRequest struct {
+	Amount *money.Amount `json:"amount,omitempty" swaggertype:"string" example:"5.00"`
+}
+
+// TransactionReversal — итог отмены перевода OriginalID компенсирующей транзакцией TransactionID:
+// с получателя исходного перевода списано Amount в валюте Currency, а отправителю возвращено Refund в валюте RefundCurrency.
+// Для перевода без конвертации суммы и валюты совпадают.
+type TransactionReversal struct {
+	TransactionID  int          `json:"transaction_id" example:"43"`
+	OriginalID     int          `json:"original_id" example:"42"`
+	From           string       `json:"from" example:"01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"`
+	To             string       `json:"to" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	Currency       string       `json:"currency" example:"USD"`
+	Amount         money.Amount `json:"amount" swaggertype:"string" example:"5.00"`
+	RefundCurrency string       `json:"refund_currency" example:"USD"`
+	Refund         money.Amount `json:"refund" swaggertype:"string" example:"5.00"`
+}
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled"
+)
+
+// ScheduledTransfer — регулярный перевод Amount с кошелька From на кошелек To по расписанию Schedule в формате cron.
+// NextRunAt — ближайший срок выполнения. Если перевод за этот срок не удался из-за временной ошибки, Attempts содержит
+// число неудачных попыток, а RetryAt — время следующей.
+type ScheduledTransfer struct {
+	ID          int                     `json:"id" example:"3"`
+	From        string                  `json:"from" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	To          string                  `json:"to" example:"01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"`
+	Amount      money.Amount            `json:"amount" swaggertype:"string" example:"50.00"`
+	Convert     bool                    `json:"convert,omitempty"`
+	Schedule    string                  `json:"schedule" example:"0 9 1 * *"`
+	Status      ScheduledTransferStatus `json:"status" enums:"active,cancelled" example:"active"`
+	NextRunAt   time.Time               `json:"next_run_at"`
+	RetryAt     *time.Time              `json:"retry_at,omitempty"`
+	Attempts    int                     `json:"attempts"`
+	CreatedAt   time.Time               `json:"created_at"`
+	CancelledAt *time.Time              `json:"cancelled_at,omitempty"`
+	// CreatedByKeyID и CreatedByUserID — ключ API или пользователь, создавший перевод, от имени которого он выполняется;
+	// оба nil, если перевод создан самим сервисом.
+	CreatedByKeyID  *int `json:"-"`
+	CreatedByUserID *int `json:"-"`
+}
+
+// DueAt возвращает время, когда перевод нужно выполнить: время повторной попытки, если она назначена, иначе NextRunAt.
+func (t ScheduledTransfer) DueAt() time.Time {
+	if t.RetryAt != nil {
+		return *t.RetryAt
+	}
+	return t.NextRunAt
+}
+
+type CreateScheduledTransferRequest struct {
+	From   string       `json:"from" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	To     string       `json:"to" example:"01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"`
+	Amount money.Amount `json:"amount" swaggertype:"string" example:"50.00"`
+	// Convert разрешает перевод между кошельками в разных валютах с конвертацией по курсу на момент выполнения.
+	Convert bool `json:"convert,omitempty"`
+	// Schedule — расписание в формате cron из пяти полей (минута, час, день месяца, месяц, день недели) по UTC
+	// или одно из сокращений @monthly, @weekly, @daily, @hourly, @every <интервал>.
+	Schedule string `json:"schedule" example:"0 9 1 * *"`
+}
+
+type ScheduledTransferRunStatus string
+
+const (
+	// ScheduledTransferRunCompleted — перевод выполнен.
+	ScheduledTransferRunCompleted ScheduledTransferRunStatus = "completed"
+	// ScheduledTransferRunRetrying — перевод не удался из-за временной ошибки и будет повторен.
+	ScheduledTransferRunRetrying ScheduledTransferRunStatus = "retrying"
+	// ScheduledTransferRunFailed — перевод за этот срок отклонен или исчерпаны попытки его выполнить.
+	ScheduledTransferRunFailed ScheduledTransferRunStatus = "failed"
+)
+
+// ScheduledTransferRun — попытка Attempt выполнить регулярный перевод ScheduledTransferID за срок ScheduledFor.
+// TransactionID — транзакция, созданная попыткой; nil, если транзакция не записана.
+type ScheduledTransferRun struct {
+	ID                  int                        `json:"id" example:"12"`
+	ScheduledTransferID int                        `json:"scheduled_transfer_id" example:"3"`
+	ScheduledFor        time.Time                  `json:"scheduled_for"`
+	Attempt             int                        `json:"attempt" example:"1"`
+	Status              ScheduledTransferRunStatus `json:"status" enums:"completed,retrying,failed" example:"completed"`
+	TransactionID       *int                       `json:"transaction_id,omitempty" example:"42"`
+	Error               string                     `json:"error,omitempty" example:"insufficient funds"`
+	CreatedAt           time.Time                  `json:"created_at"`
+}
+
+type HoldStatus string
+
+const (
+	// HoldStatusActive — средства заблокированы до списания, отмены или истечения срока блокировки.
+	HoldStatusActive HoldStatus = "active"
+	// HoldStatusCaptured — заблокированные средства полностью или частично переведены получателю, остаток разблокирован.
+	HoldStatusCaptured HoldStatus = "captured"
+	// HoldStatusVoided — блокировка отменена, средства разблокированы.
+	HoldStatusVoided HoldStatus = "voided"
+	// HoldStatusExpired — срок блокировки истек, средства разблокированы.
+	HoldStatusExpired HoldStatus = "expired"
+)
+
+// Hold — блокировка суммы Amount на кошельке From для перевода на кошелек To. Пока блокировка активна и не истекла,
+// заблокированные средства недоступны для других переводов. При списании получателю переводится CapturedAmount
+// транзакцией TransactionID.
+type Hold struct {
+	ID             int           `json:"id" example:"5"`
+	From           string        `json:"from" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	To             string        `json:"to" example:"01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"`
+	Amount         money.Amount  `json:"amount" swaggertype:"string" example:"30.00"`
+	Currency       string        `json:"currency" example:"USD"`
+	Convert        bool          `json:"convert,omitempty"`
+	Status         HoldStatus    `json:"status" enums:"active,captured,voided,expired" example:"active"`
+	CapturedAmount *money.Amount `json:"captured_amount,omitempty" swaggertype:"string" example:"25.00"`
+	TransactionID  *int          `json:"transaction_id,omitempty" example:"42"`
+	ExpiresAt      time.Time     `json:"expires_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+	// FinalizedAt — время списания, отмены или истечения блокировки.
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+}
+
+// Expired сообщает, истек ли к моменту now срок активной блокировки.
+func (h Hold) Expired(now time.Time) bool {
+	return h.Status == HoldStatusActive && !now.Before(h.ExpiresAt)
+}
+
+type CreateHoldRequest struct {
+	From   string       `json:"from" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	To     string       `json:"to" example:"01abdf2236c0a3b4e2639b3e182d994c88e240d825d255af751f5f55d69664a8"`
+	Amount money.Amount `json:"amount" swaggertype:"string" example:"30.00"`
+	// Convert разрешает списание на кошелек в другой валюте с конвертацией по курсу на момент списания.
+	Convert bool `json:"convert,omitempty"`
+	// ExpiresIn — срок действия блокировки в секундах; если не указан, применяется срок по умолчанию.
+	ExpiresIn int `json:"expires_in,omitempty" example:"3600"`
+}
+
+// CaptureHoldRequest — запрос на списание заблокированных средств. Amount не может превышать заблокированную сумму;
+// если он не указан, списывается вся сумма блокировки.
+type CaptureHoldRequest struct {
+	Amount *money.Amount `json:"amount,omitempty" swaggertype:"string" example:"25.00"`
+}
+
+type CreateWalletRequest struct {
+	// Address — адрес нового кошелька; если не указан, генерируется сервером.
+	Address string `json:"address,omitempty" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	// Currency — код валюты кошелька по ISO 4217; по умолчанию USD.
+	Currency string `json:"currency,omitempty" example:"EUR"`
+}
+
+type UpdateWalletStatusRequest struct {
+	Status WalletStatus `json:"status" enums:"active,frozen,closed" example:"frozen"`
 }
 
 type StatusResponse struct {
 	Status  string `json:"status" example:"success"`
 	Message string `json:"message" example:"Transaction completed"`
 }
+
+// TransferResponse — ответ на успешный перевод с суммой комиссии и итоговой суммой списания с отправителя.
+// Для перевода с конвертацией Conversion содержит сумму, зачисленную получателю, и примененный курс.
+type TransferResponse struct {
+	Status        string       `json:"status" example:"success"`
+	Message       string       `json:"message" example:"Transaction completed"`
+	TransactionID int          `json:"transaction_id" example:"42"`
+	Currency      string       `json:"currency" example:"USD"`
+	Amount        money.Amount `json:"amount" swaggertype:"string" example:"10.50"`
+	Fee           money.Amount `json:"fee" swaggertype:"string" example:"0.30"`
+	Total         money.Amount `json:"total" swaggertype:"string" example:"10.80"`
+	Conversion    *Conversion  `json:"conversion,omitempty"`
+}
+
+// RebuildBalancesResponse — итог пересчета балансов по журналу: Corrected — количество кошельков, баланс которых был исправлен.
+type RebuildBalancesResponse struct {
+	Corrected int64 `json:"corrected" example:"0"`
+}
+
+// LedgerBalance — кэшированный баланс кошелька Stored, баланс Ledger, полученный суммированием его проводок в журнале,
+// и баланс Expected, восстановленный без журнала по начальным зачислениям и транзакциям кошелька.
+type LedgerBalance struct {
+	Address  string
+	Currency string
+	Stored   money.Amount
+	Ledger   money.Amount
+	Expected money.Amount
+}
+
+// BalanceDiscrepancy — расхождение кэшированного баланса кошелька с журналом или журнала с историей транзакций:
+// Difference = Stored - Ledger, а Expected — баланс, восстановленный по начальным зачислениям и транзакциям.
+type BalanceDiscrepancy struct {
+	Address    string       `json:"address" example:"01e240d825d255af751f5f55af8d9671beabdf2236c0a3b4e2639b3ef711397f"`
+	Currency   string       `json:"currency" example:"USD"`
+	Stored     money.Amount `json:"stored" swaggertype:"string" example:"100.00"`
+	Ledger     money.Amount `json:"ledger" swaggertype:"string" example:"90.00"`
+	Expected   money.Amount `json:"expected" swaggertype:"string" example:"90.00"`
+	Difference money.Amount `json:"difference" swaggertype:"string" example:"10.00"`
+}
+
+// ReconciliationReport — итог сверки балансов кошельков с журналом двойной записи и историей транзакций.
+// UnbalancedEntries содержит ID записей журнала, проводки которых в какой-либо валюте не сходятся в ноль,
+// UnrecordedTransactions — ID завершенных транзакций без записи в журнале, а MismatchedEntries — ID записей журнала,
+// проводки которых не совпадают с суммой, комиссией или конвертацией их транзакции.
+type ReconciliationReport struct {
+	GeneratedAt            time.Time            `json:"generated_at"`
+	WalletsChecked         int                  `json:"wallets_checked" example:"10"`
+	Discrepancies          []BalanceDiscrepancy `json:"discrepancies"`
+	UnbalancedEntries      []int64              `json:"unbalanced_entries"`
+	UnrecordedTransactions []int                `json:"unrecorded_transactions"`
+	MismatchedEntries      []int64              `json:"mismatched_entries"`
+}
+
+// Consistent сообщает, что сверка не нашла ни расхождений балансов, ни нарушений в журнале.
+func (r *ReconciliationReport) Consistent() bool {
+	return len(r.Discrepancies) == 0 && len(r.UnbalancedEntries) == 0 &&
+		len(r.UnrecordedTransactions) == 0 && len(r.MismatchedEntries) == 0
+}
+
+// WriteCSV записывает расхождения балансов отчета в w в формате CSV с заголовком.
+// Нарушения в журнале в CSV не попадают: они есть только в JSON-представлении отчета.
+func (r *ReconciliationReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"address", "currency", "stored", "ledger", "expected", "difference"}); err != nil {
+		return err
+	}
+	for _, d := range r.Discrepancies {
+		if err := cw.Write([]string{d.Address, d.Currency, d.Stored.String(), d.Ledger.String(), d.Expected.String(), d.Difference.String()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ErrorResponse — тело ответа с ошибкой, общее для всех эндпоинтов.
+type ErrorResponse struct {
+	// Code — машиночитаемый код ошибки, на который может опираться клиент.
+	Code    string `json:"code" example:"insufficient_funds"`
+	Message string `json:"message" example:"insufficient funds"`
+	// Details — дополнительные сведения об ошибке, например роль и адрес кошелька или имя некорректного поля.
+	Details   map[string]string `json:"details,omitempty"`
+	RequestID string            `json:"request_id,omitempty" example:"3f2a9c4e1b7d4a6f8e0c5b2d9a1f7e3c"`
+}
+
+// APIKey — ключ доступа к API. Сам ключ не хранится, в БД сохраняется только его хеш.
+type APIKey struct {
+	ID     int      `json:"id" example:"1"`
+	Name   string   `json:"name" example:"merchant-42"`
+	Scopes []string `json:"scopes" example:"admin"`
+	// Wallets — адреса кошельков, принадлежащих владельцу ключа; списывать средства можно только с них.
+	Wallets   []string   `json:"wallets"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" example:"merchant-42"`
+	Scopes []string `json:"scopes,omitempty" example:"admin"`
+	// Wallets — адреса существующих кошельков, которые передаются во владение ключу.
+	Wallets []string `json:"wallets,omitempty"`
+}
+
+// CreateAPIKeyResponse содержит созданный ключ. Значение Key возвращается только один раз и больше нигде не хранится.
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key" example:"pk_6f1c0e4b2a9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f"`
+}
+
+// User — пользователь, входящий в систему по имени и паролю.
+type User struct {
+	ID       int       `json:"id" example:"1"`
+	Username string    `json:"username" example:"alice"`
+	Role     auth.Role `json:"role" swaggertype:"string" enums:"customer,operator,auditor,admin,metrics" example:"customer"`
+	// Wallets — адреса кошельков, принадлежащих пользователю.
+	Wallets      []string  `json:"wallets"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username" example:"alice"`
+	Password string `json:"password" example:"correct horse battery staple"`
+	// Role — роль пользователя; по умолчанию customer.
+	Role auth.Role `json:"role,omitempty" swaggertype:"string" enums:"customer,operator,auditor,admin,metrics" example:"customer"`
+	// Wallets — адреса существующих кошельков, которые передаются во владение пользователю.
+	Wallets []string `json:"wallets,omitempty"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" example:"alice"`
+	Password string `json:"password" example:"correct horse battery staple"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" example:"rt_9b1f0c2e4d6a8b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c"`
+}
+
+// TokenPair — токен доступа и токен обновления, выданные при входе или обновлении сессии.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	// ExpiresIn — срок действия токена доступа в секундах.
+	ExpiresIn int `json:"expires_in" example:"900"`
+}
+
+type IdempotencyRecord struct {
+	Subject      string
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	ExpiresAt    time.Time
+}
+
+// Completed сообщает, сохранен ли уже ответ на запрос с этим ключом.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
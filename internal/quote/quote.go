@@ -0,0 +1,125 @@
+// Package quote выпускает и проверяет подписанные идентификаторы предварительных расчетов перевода.
+// Идентификатор содержит условия перевода и подпись HMAC-SHA256, поэтому выданные расчеты не хранятся на сервере;
+// сохраняются только одноразовые значения использованных расчетов.
+package quote
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"golangTestTask/configs"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/fx"
+	"golangTestTask/pkg/money"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// secretBytes — минимальная длина секрета подписи.
+	secretBytes = 32
+	// nonceBytes — длина одноразового значения расчета.
+	nonceBytes = 16
+)
+
+// Terms — условия перевода, которые гарантирует расчет до момента ExpiresAt.
+// Rate — зафиксированный курс перевода с конвертацией; nil для перевода в одной валюте.
+// Nonce — одноразовое значение, по которому перевод отмечает расчет использованным.
+type Terms struct {
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Amount    money.Amount `json:"amount"`
+	Fee       money.Amount `json:"fee"`
+	Rate      *fx.Rate     `json:"rate,omitempty"`
+	ExpiresAt time.Time    `json:"exp"`
+	Nonce     string       `json:"nonce"`
+}
+
+// Signer подписывает условия расчетов и проверяет подписанные идентификаторы.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner создает Signer, подписывающий расчеты секретом secret. Расчеты действуют в течение ttl.
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{secret: secret, ttl: ttl}
+}
+
+// LoadSigner создает Signer по настройкам config, читая секрет подписи из QuoteSecretFile.
+// Если файл секрета не указан, генерируется случайный секрет: выданные расчеты перестанут действовать после перезапуска.
+func LoadSigner(config configs.Config) (*Signer, error) {
+	var secret []byte
+	if config.QuoteSecretFile == "" {
+		log.Println("Warning: QUOTE_SECRET_FILE is not set, transfer quotes will be signed with a random secret")
+		secret = make([]byte, secretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if secret, err = os.ReadFile(config.QuoteSecretFile); err != nil {
+			return nil, fmt.Errorf("failed to read quote secret: %w", err)
+		}
+		if len(secret) < secretBytes {
+			return nil, fmt.Errorf("quote secret must be at least %d bytes long", secretBytes)
+		}
+	}
+	return NewSigner(secret, config.QuoteTTL), nil
+}
+
+// TTL возвращает срок действия выпускаемых расчетов.
+func (s *Signer) TTL() time.Duration {
+	return s.ttl
+}
+
+// Sign возвращает идентификатор расчета с условиями terms. Если terms.Nonce не задан, он генерируется из crypto/rand.
+func (s *Signer) Sign(terms Terms) (string, error) {
+	if terms.Nonce == "" {
+		nonce := make([]byte, nonceBytes)
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		terms.Nonce = base64.RawURLEncoding.EncodeToString(nonce)
+	}
+	payload, err := json.Marshal(terms)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// Verify проверяет подпись идентификатора расчета id и возвращает его условия.
+// Срок действия не проверяется: его сравнивает с текущим временем вызывающий код.
+// Любая ошибка проверки, в том числе отсутствие одноразового значения, возвращается как domain.ErrInvalidQuote.
+func (s *Signer) Verify(id string) (Terms, error) {
+	var terms Terms
+	encoded, signature, ok := strings.Cut(id, ".")
+	if !ok {
+		return terms, domain.ErrInvalidQuote
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.sign(encoded)) {
+		return terms, domain.ErrInvalidQuote
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return terms, domain.ErrInvalidQuote
+	}
+	if err := json.Unmarshal(payload, &terms); err != nil || terms.Nonce == "" {
+		return terms, domain.ErrInvalidQuote
+	}
+	return terms, nil
+}
+
+func (s *Signer) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
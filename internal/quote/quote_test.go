@@ -0,0 +1,88 @@
+package quote
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner([]byte(strings.Repeat("s", secretBytes)), time.Minute)
+	terms := Terms{
+		From:      "addr1",
+		To:        "addr2",
+		Amount:    money.MustParse("10.50"),
+		Fee:       money.MustParse("0.30"),
+		ExpiresAt: time.Date(2025, 1, 1, 12, 1, 0, 0, time.UTC),
+		Nonce:     "n1",
+	}
+	id, err := signer.Sign(terms)
+	require.NoError(t, err)
+
+	payload, signature, _ := strings.Cut(id, ".")
+	tampered, err := signer.Sign(Terms{
+		From:      "addr1",
+		To:        "addr2",
+		Amount:    money.MustParse("10.50"),
+		ExpiresAt: terms.ExpiresAt,
+		Nonce:     terms.Nonce,
+	})
+	require.NoError(t, err)
+	tamperedPayload, _, _ := strings.Cut(tampered, ".")
+
+	// Расчет, выданный до появления одноразовых значений, подписан верно, но использовать его нельзя.
+	legacyPayload := base64.RawURLEncoding.EncodeToString([]byte(`{"from":"addr1","to":"addr2","amount":"10.50","fee":"0.30","exp":"2025-01-01T12:01:00Z"}`))
+	legacy := legacyPayload + "." + base64.RawURLEncoding.EncodeToString(signer.sign(legacyPayload))
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		id      string
+		want    Terms
+		wantErr error
+	}{
+		{name: "valid", signer: signer, id: id, want: terms},
+		{name: "other secret", signer: NewSigner([]byte(strings.Repeat("x", secretBytes)), time.Minute), id: id, wantErr: domain.ErrInvalidQuote},
+		{name: "tampered terms", signer: signer, id: tamperedPayload + "." + signature, wantErr: domain.ErrInvalidQuote},
+		{name: "missing signature", signer: signer, id: payload, wantErr: domain.ErrInvalidQuote},
+		{name: "malformed signature", signer: signer, id: payload + ".!!", wantErr: domain.ErrInvalidQuote},
+		{name: "empty", signer: signer, id: "", wantErr: domain.ErrInvalidQuote},
+		{name: "without nonce", signer: signer, id: legacy, wantErr: domain.ErrInvalidQuote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.signer.Verify(tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSigner_Sign_Nonce(t *testing.T) {
+	signer := NewSigner([]byte(strings.Repeat("s", secretBytes)), time.Minute)
+	terms := Terms{From: "addr1", To: "addr2", Amount: money.MustParse("10.50"), ExpiresAt: time.Date(2025, 1, 1, 12, 1, 0, 0, time.UTC)}
+
+	first, err := signer.Sign(terms)
+	require.NoError(t, err)
+	second, err := signer.Sign(terms)
+	require.NoError(t, err)
+
+	firstTerms, err := signer.Verify(first)
+	require.NoError(t, err)
+	secondTerms, err := signer.Verify(second)
+	require.NoError(t, err)
+	assert.NotEmpty(t, firstTerms.Nonce)
+	assert.NotEqual(t, firstTerms.Nonce, secondTerms.Nonce)
+}
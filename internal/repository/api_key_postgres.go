@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// foreignKeyViolation — код ошибки PostgreSQL при нарушении ограничения внешнего ключа.
+const foreignKeyViolation = "23503"
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// apiKeyColumns — столбцы ключа API и адреса его кошельков в порядке, который ожидает get.
+const apiKeyColumns = `k.id, k.name, k.scopes, k.created_at,
+	ARRAY(SELECT w.wallet_address FROM api_key_wallets w WHERE w.api_key_id = k.id ORDER BY w.wallet_address)`
+
+type APIKeyPostgres struct {
+	db DBTX
+}
+
+// NewAPIKeyPostgres создает новый экземпляр APIKeyPostgres.
+func NewAPIKeyPostgres(db DBTX) *APIKeyPostgres {
+	return &APIKeyPostgres{db: db}
+}
+
+// Create сохраняет новый ключ API с хешем keyHash в БД PostgreSQL и заполняет его ID и время создания.
+func (r *APIKeyPostgres) Create(ctx context.Context, key *models.APIKey, keyHash string) error {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	query := `INSERT INTO api_keys (name, key_hash, scopes) VALUES ($1, $2, $3) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, key.Name, keyHash, pq.Array(scopes)).Scan(&key.ID, &key.CreatedAt)
+}
+
+// GetByHash возвращает действующий ключ API с хешем keyHash вместе с адресами принадлежащих ему кошельков.
+// Отозванные ключи не возвращаются.
+func (r *APIKeyPostgres) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys k WHERE k.key_hash = $1 AND k.revoked_at IS NULL`
+	return r.get(ctx, query, keyHash)
+}
+
+// GetByID возвращает действующий ключ API с ID id вместе с адресами принадлежащих ему кошельков.
+// Отозванные ключи не возвращаются.
+func (r *APIKeyPostgres) GetByID(ctx context.Context, id int) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys k WHERE k.id = $1 AND k.revoked_at IS NULL`
+	return r.get(ctx, query, id)
+}
+
+func (r *APIKeyPostgres) get(ctx context.Context, query string, arg interface{}) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.QueryRowContext(ctx, query, arg).
+		Scan(&key.ID, &key.Name, pq.Array(&key.Scopes), &key.CreatedAt, pq.Array(&key.Wallets))
+	if err == sql.ErrNoRows {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// AddWallet передает кошелек address во владение ключу keyID. Повторная передача не считается ошибкой.
+func (r *APIKeyPostgres) AddWallet(ctx context.Context, keyID int, address string) error {
+	query := `INSERT INTO api_key_wallets (api_key_id, wallet_address) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, keyID, address)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrWalletNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAPIKeyPostgres(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("INSERT INTO api_keys \\(name, key_hash, scopes\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id, created_at").
+		WithArgs("merchant", "hash1", "{}").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, createdAt))
+
+	key := &models.APIKey{Name: "merchant"}
+	err = repo.Create(context.Background(), key, "hash1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, key.ID)
+	assert.Equal(t, createdAt, key.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyPostgres_GetByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAPIKeyPostgres(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.APIKey
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "scopes", "created_at", "wallets"}).
+					AddRow(1, "admin", "{admin}", createdAt, "{addr1,addr2}")
+				mock.ExpectQuery("SELECT k.id, k.name, k.scopes, k.created_at, .+ FROM api_keys k WHERE k.key_hash = \\$1 AND k.revoked_at IS NULL").
+					WithArgs("hash1").
+					WillReturnRows(rows)
+			},
+			want: &models.APIKey{
+				ID:        1,
+				Name:      "admin",
+				Scopes:    []string{"admin"},
+				Wallets:   []string{"addr1", "addr2"},
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "Key Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT k.id").
+					WithArgs("hash1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: ErrAPIKeyNotFound,
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT k.id").
+					WithArgs("hash1").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetByHash(context.Background(), "hash1")
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyPostgres_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAPIKeyPostgres(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.APIKey
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "scopes", "created_at", "wallets"}).
+					AddRow(1, "admin", "{admin}", createdAt, "{addr1,addr2}")
+				mock.ExpectQuery("SELECT k.id, k.name, k.scopes, k.created_at, .+ FROM api_keys k WHERE k.id = \\$1 AND k.revoked_at IS NULL").
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+			want: &models.APIKey{
+				ID:        1,
+				Name:      "admin",
+				Scopes:    []string{"admin"},
+				Wallets:   []string{"addr1", "addr2"},
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "Key Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT k.id").
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: ErrAPIKeyNotFound,
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT k.id").
+					WithArgs(1).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetByID(context.Background(), 1)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyPostgres_AddWallet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAPIKeyPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO api_key_wallets \\(api_key_id, wallet_address\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT DO NOTHING").
+					WithArgs(1, "addr1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Wallet Not Found",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO api_key_wallets").
+					WithArgs(1, "addr1").
+					WillReturnError(&pq.Error{Code: "23503"})
+			},
+			wantErr: domain.ErrWalletNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.AddWallet(context.Background(), 1, "addr1")
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
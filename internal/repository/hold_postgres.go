@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+	"time"
+)
+
+// holdColumns — столбцы блокировки средств в порядке, который ожидает scanHold.
+const holdColumns = `id, from_address, to_address, amount, currency, convert_currency, status, captured_amount, transaction_id,
+	expires_at, created_at, finalized_at`
+
+type HoldPostgres struct {
+	db DBTX
+}
+
+// NewHoldPostgres создает новый экземпляр HoldPostgres.
+func NewHoldPostgres(db DBTX) *HoldPostgres {
+	return &HoldPostgres{db: db}
+}
+
+// Create сохраняет новую блокировку средств в БД PostgreSQL и заполняет ее ID, статус и время создания.
+func (r *HoldPostgres) Create(ctx context.Context, hold *models.Hold) error {
+	query := `INSERT INTO holds (from_address, to_address, amount, currency, convert_currency, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, status, created_at`
+	err := r.db.QueryRowContext(ctx, query, hold.From, hold.To, hold.Amount, hold.Currency, hold.Convert, hold.ExpiresAt).
+		Scan(&hold.ID, &hold.Status, &hold.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get возвращает блокировку средств по ID из БД PostgreSQL.
+func (r *HoldPostgres) Get(ctx context.Context, id int) (*models.Hold, error) {
+	query := `SELECT ` + holdColumns + ` FROM holds WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+// GetForUpdate возвращает блокировку средств по ID из БД PostgreSQL, блокируя ее строку (SELECT ... FOR UPDATE).
+// Блокировка строки действует до конца транзакции, поэтому метод имеет смысл вызывать только внутри UnitOfWork.WithTx.
+func (r *HoldPostgres) GetForUpdate(ctx context.Context, id int) (*models.Hold, error) {
+	query := `SELECT ` + holdColumns + ` FROM holds WHERE id = $1 FOR UPDATE`
+	return r.get(ctx, query, id)
+}
+
+// Held возвращает из БД PostgreSQL сумму активных и не истекших к now блокировок средств на кошельке address.
+func (r *HoldPostgres) Held(ctx context.Context, address string, now time.Time) (money.Amount, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM holds WHERE from_address = $1 AND status = $2 AND expires_at > $3`
+	var held money.Amount
+	if err := r.db.QueryRowContext(ctx, query, address, models.HoldStatusActive, now).Scan(&held); err != nil {
+		return 0, err
+	}
+	return held, nil
+}
+
+// Capture отмечает в БД PostgreSQL активную блокировку id как списанную: сумма amount переведена транзакцией transactionID.
+func (r *HoldPostgres) Capture(ctx context.Context, id int, amount money.Amount, transactionID int) error {
+	query := `UPDATE holds SET status = $1, captured_amount = $2, transaction_id = $3, finalized_at = now()
+		WHERE id = $4 AND status = $5`
+	return r.finalize(ctx, query, models.HoldStatusCaptured, amount, transactionID, id, models.HoldStatusActive)
+}
+
+// Void отмечает в БД PostgreSQL активную блокировку id как отмененную.
+func (r *HoldPostgres) Void(ctx context.Context, id int) error {
+	query := `UPDATE holds SET status = $1, finalized_at = now() WHERE id = $2 AND status = $3`
+	return r.finalize(ctx, query, models.HoldStatusVoided, id, models.HoldStatusActive)
+}
+
+// ExpireStale отмечает в БД PostgreSQL активные блокировки, срок которых истек к now, как истекшие
+// и возвращает их количество. Временем завершения истекшей блокировки считается ее срок действия.
+func (r *HoldPostgres) ExpireStale(ctx context.Context, now time.Time) (int64, error) {
+	query := `UPDATE holds SET status = $1, finalized_at = expires_at WHERE status = $2 AND expires_at <= $3`
+	result, err := r.db.ExecContext(ctx, query, models.HoldStatusExpired, models.HoldStatusActive, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// finalize выполняет запрос query, завершающий активную блокировку. Если блокировка не изменена,
+// возвращает domain.ErrHoldNotActive.
+func (r *HoldPostgres) finalize(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrHoldNotActive
+	}
+	return nil
+}
+
+func (r *HoldPostgres) get(ctx context.Context, query string, id int) (*models.Hold, error) {
+	hold, err := scanHold(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func scanHold(row rowScanner) (models.Hold, error) {
+	var h models.Hold
+	err := row.Scan(&h.ID, &h.From, &h.To, &h.Amount, &h.Currency, &h.Convert, &h.Status, &h.CapturedAmount, &h.TransactionID,
+		&h.ExpiresAt, &h.CreatedAt, &h.FinalizedAt)
+	return h, err
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var holdTestColumns = []string{"id", "from_address", "to_address", "amount", "currency", "convert_currency", "status", "captured_amount",
+	"transaction_id", "expires_at", "created_at", "finalized_at"}
+
+func TestHoldPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewHoldPostgres(db)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2025, 1, 16, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("INSERT INTO holds (.+) RETURNING id, status, created_at").
+		WithArgs("from1", "to1", "30.00", "USD", false, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(5, "active", createdAt))
+
+	hold := &models.Hold{From: "from1", To: "to1", Amount: money.FromInt(30), Currency: "USD", ExpiresAt: expiresAt}
+	err = repo.Create(context.Background(), hold)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, hold.ID)
+	assert.Equal(t, models.HoldStatusActive, hold.Status)
+	assert.Equal(t, createdAt, hold.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHoldPostgres_GetForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewHoldPostgres(db)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2025, 1, 16, 12, 0, 0, 0, time.UTC)
+	finalizedAt := time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)
+	captured := money.FromInt(25)
+	transactionID := 42
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.Hold
+		wantErr error
+	}{
+		{
+			name: "Captured",
+			mock: func() {
+				rows := sqlmock.NewRows(holdTestColumns).
+					AddRow(5, "from1", "to1", "30.00", "USD", false, "captured", "25.00", 42, expiresAt, createdAt, finalizedAt)
+				mock.ExpectQuery("SELECT (.+) FROM holds WHERE id = \\$1 FOR UPDATE").
+					WithArgs(5).
+					WillReturnRows(rows)
+			},
+			want: &models.Hold{ID: 5, From: "from1", To: "to1", Amount: money.FromInt(30), Currency: "USD", Status: models.HoldStatusCaptured,
+				CapturedAmount: &captured, TransactionID: &transactionID, ExpiresAt: expiresAt, CreatedAt: createdAt, FinalizedAt: &finalizedAt},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM holds WHERE id = \\$1 FOR UPDATE").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(holdTestColumns))
+			},
+			wantErr: domain.ErrHoldNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetForUpdate(context.Background(), 5)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestHoldPostgres_Held(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewHoldPostgres(db)
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    money.Amount
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM holds WHERE from_address = \\$1 AND status = \\$2 AND expires_at > \\$3").
+					WithArgs("from1", models.HoldStatusActive, now).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("55.50"))
+			},
+			want: money.MustParse("55.50"),
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT COALESCE").
+					WithArgs("from1", models.HoldStatusActive, now).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Held(context.Background(), "from1", now)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestHoldPostgres_Finalize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewHoldPostgres(db)
+
+	mock.ExpectExec("UPDATE holds SET status = \\$1, captured_amount = \\$2, transaction_id = \\$3, finalized_at = now\\(\\)\\s+WHERE id = \\$4 AND status = \\$5").
+		WithArgs(models.HoldStatusCaptured, "25.00", 42, 5, models.HoldStatusActive).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE holds SET status = \\$1, finalized_at = now\\(\\) WHERE id = \\$2 AND status = \\$3").
+		WithArgs(models.HoldStatusVoided, 5, models.HoldStatusActive).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.Capture(context.Background(), 5, money.FromInt(25), 42))
+	assert.ErrorIs(t, repo.Void(context.Background(), 5), domain.ErrHoldNotActive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHoldPostgres_ExpireStale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewHoldPostgres(db)
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE holds SET status = \\$1, finalized_at = expires_at WHERE status = \\$2 AND expires_at <= \\$3").
+		WithArgs(models.HoldStatusExpired, models.HoldStatusActive, now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	expired, err := repo.ExpireStale(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golangTestTask/internal/models"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+)
+
+type IdempotencyPostgres struct {
+	db DBTX
+}
+
+// NewIdempotencyPostgres создает новый экземпляр IdempotencyPostgres.
+func NewIdempotencyPostgres(db DBTX) *IdempotencyPostgres {
+	return &IdempotencyPostgres{db: db}
+}
+
+// Reserve резервирует ключ идемпотентности участника subject в БД PostgreSQL.
+// Ключ с истекшим сроком действия, который еще не удалил фоновый процесс, резервируется заново.
+func (r *IdempotencyPostgres) Reserve(ctx context.Context, subject string, key string, requestHash string, expiresAt time.Time) (bool, error) {
+	query := `INSERT INTO idempotency_keys (subject, key, request_hash, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subject, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = NULL, response_body = NULL,
+			created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < now()`
+	result, err := r.db.ExecContext(ctx, query, subject, key, requestHash, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return affected == 1, nil
+}
+
+// Get возвращает запись по ключу идемпотентности участника subject из БД PostgreSQL.
+func (r *IdempotencyPostgres) Get(ctx context.Context, subject string, key string) (*models.IdempotencyRecord, error) {
+	query := `SELECT subject, key, request_hash, status_code, response_body, expires_at FROM idempotency_keys
+		WHERE subject = $1 AND key = $2`
+
+	var record models.IdempotencyRecord
+	var statusCode sql.NullInt32
+	err := r.db.QueryRowContext(ctx, query, subject, key).
+		Scan(&record.Subject, &record.Key, &record.RequestHash, &statusCode, &record.ResponseBody, &record.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	record.StatusCode = int(statusCode.Int32)
+	return &record, nil
+}
+
+// Complete сохраняет итоговый ответ на запрос участника subject с ключом key в БД PostgreSQL.
+func (r *IdempotencyPostgres) Complete(ctx context.Context, subject string, key string, statusCode int, responseBody []byte) error {
+	query := `UPDATE idempotency_keys SET status_code = $1, response_body = $2 WHERE subject = $3 AND key = $4`
+	_, err := r.db.ExecContext(ctx, query, statusCode, responseBody, subject, key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Release удаляет из БД PostgreSQL резервирование ключа участника subject, ответ на который еще не сохранен.
+func (r *IdempotencyPostgres) Release(ctx context.Context, subject string, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE subject = $1 AND key = $2 AND status_code IS NULL`
+	_, err := r.db.ExecContext(ctx, query, subject, key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired удаляет ключи с истекшим сроком действия из БД PostgreSQL.
+func (r *IdempotencyPostgres) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at < now()`
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
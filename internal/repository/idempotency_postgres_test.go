@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyPostgres_Reserve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewIdempotencyPostgres(db)
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "Reserved",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO idempotency_keys").
+					WithArgs("key:1", "key1", "hash1", expiresAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "Already Reserved",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO idempotency_keys").
+					WithArgs("key:1", "key1", "hash1", expiresAt).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: false,
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO idempotency_keys").
+					WithArgs("key:1", "key1", "hash1", expiresAt).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Reserve(context.Background(), "key:1", "key1", "hash1", expiresAt)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIdempotencyPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewIdempotencyPostgres(db)
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"subject", "key", "request_hash", "status_code", "response_body", "expires_at"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.IdempotencyRecord
+		wantErr error
+	}{
+		{
+			name: "Completed",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow("key:1", "key1", "hash1", 200, []byte(`{"status":"success"}`), expiresAt)
+				mock.ExpectQuery("SELECT subject, key, request_hash, status_code, response_body, expires_at FROM idempotency_keys\\s+WHERE subject = \\$1 AND key = \\$2").
+					WithArgs("key:1", "key1").
+					WillReturnRows(rows)
+			},
+			want: &models.IdempotencyRecord{
+				Subject:      "key:1",
+				Key:          "key1",
+				RequestHash:  "hash1",
+				StatusCode:   200,
+				ResponseBody: []byte(`{"status":"success"}`),
+				ExpiresAt:    expiresAt,
+			},
+		},
+		{
+			name: "In Progress",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow("key:1", "key1", "hash1", nil, nil, expiresAt)
+				mock.ExpectQuery("SELECT subject, key, request_hash, status_code, response_body, expires_at FROM idempotency_keys\\s+WHERE subject = \\$1 AND key = \\$2").
+					WithArgs("key:1", "key1").
+					WillReturnRows(rows)
+			},
+			want: &models.IdempotencyRecord{
+				Subject:     "key:1",
+				Key:         "key1",
+				RequestHash: "hash1",
+				ExpiresAt:   expiresAt,
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT subject, key, request_hash, status_code, response_body, expires_at FROM idempotency_keys\\s+WHERE subject = \\$1 AND key = \\$2").
+					WithArgs("key:1", "key1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: ErrIdempotencyKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Get(context.Background(), "key:1", "key1")
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIdempotencyPostgres_Complete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewIdempotencyPostgres(db)
+
+	mock.ExpectExec("UPDATE idempotency_keys SET status_code = \\$1, response_body = \\$2 WHERE subject = \\$3 AND key = \\$4").
+		WithArgs(200, []byte("ok"), "key:1", "key1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Complete(context.Background(), "key:1", "key1", 200, []byte("ok")))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyPostgres_Release(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewIdempotencyPostgres(db)
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE subject = \\$1 AND key = \\$2 AND status_code IS NULL").
+		WithArgs("key:1", "key1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Release(context.Background(), "key:1", "key1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyPostgres_DeleteExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewIdempotencyPostgres(db)
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE expires_at < now\\(\\)").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpired(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
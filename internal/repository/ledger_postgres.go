@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+)
+
+type LedgerPostgres struct {
+	db DBTX
+}
+
+// NewLedgerPostgres создает новый экземпляр LedgerPostgres.
+func NewLedgerPostgres(db DBTX) *LedgerPostgres {
+	return &LedgerPostgres{db: db}
+}
+
+// Post сохраняет запись журнала и ее проводки в БД PostgreSQL и применяет проводки по кошелькам к столбцу wallets.balance.
+// Проводка по кошельку, которого нет или валюта которого отличается от валюты проводки, возвращает domain.ErrWalletNotFound.
+// Вызывается внутри транзакции: запись и изменения балансов должны фиксироваться вместе.
+func (r *LedgerPostgres) Post(ctx context.Context, entry *models.JournalEntry) error {
+	query := `INSERT INTO journal_entries (kind, transaction_id) VALUES ($1, NULLIF($2, 0)) RETURNING id, created_at`
+	if err := r.db.QueryRowContext(ctx, query, entry.Kind, entry.TransactionID).Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, posting := range entry.Postings {
+		query := `INSERT INTO postings (entry_id, wallet_address, account, currency, amount)
+			VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5)`
+		if _, err := r.db.ExecContext(ctx, query, entry.ID, posting.Wallet, posting.Account, posting.Currency, posting.Amount); err != nil {
+			return err
+		}
+		if posting.Wallet == "" {
+			continue
+		}
+
+		query = `UPDATE wallets SET balance = balance + $1 WHERE address = $2 AND currency = $3`
+		result, err := r.db.ExecContext(ctx, query, posting.Amount, posting.Wallet, posting.Currency)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("%w: %s in %s", domain.ErrWalletNotFound, posting.Wallet, posting.Currency)
+		}
+	}
+	return nil
+}
+
+// RebuildBalances заменяет баланс каждого кошелька в БД PostgreSQL суммой его проводок.
+// Перед пересчетом блокируются строки всех кошельков, поэтому метод нужно вызывать внутри транзакции:
+// так параллельные переводы не изменят балансы между чтением проводок и записью результата.
+func (r *LedgerPostgres) RebuildBalances(ctx context.Context) (int64, error) {
+	if _, err := r.db.ExecContext(ctx, `SELECT address FROM wallets ORDER BY address FOR UPDATE`); err != nil {
+		return 0, err
+	}
+
+	query := `UPDATE wallets SET balance = ledger.balance
+		FROM (SELECT w.address, COALESCE(SUM(p.amount), 0) AS balance
+			FROM wallets w LEFT JOIN postings p ON p.wallet_address = w.address
+			GROUP BY w.address) AS ledger
+		WHERE wallets.address = ledger.address AND wallets.balance <> ledger.balance`
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// transactionMovements — подзапрос с изменениями балансов кошельков по каждой транзакции, восстановленными без журнала:
+// по сумме транзакции, ее комиссии из transaction_fees и сумме зачисления из transaction_conversions.
+const transactionMovements = `SELECT t.id AS transaction_id, t.status, t.created_at, t.from_address AS address, -(t.amount + COALESCE(f.amount, 0)) AS amount
+		FROM transactions t LEFT JOIN transaction_fees f ON f.transaction_id = t.id
+	UNION ALL
+	SELECT t.id, t.status, t.created_at, t.to_address, COALESCE(c.credit_amount, t.amount)
+		FROM transactions t LEFT JOIN transaction_conversions c ON c.transaction_id = t.id
+	UNION ALL
+	SELECT t.id, t.status, t.created_at, f.wallet_address, f.amount
+		FROM transaction_fees f JOIN transactions t ON t.id = f.transaction_id`
+
+// ledgerStart — подзапрос со временем появления журнала. Транзакции до него учтены в начальной записи журнала,
+// перенесшей балансы кошельков, поэтому при восстановлении балансов по транзакциям не учитываются.
+const ledgerStart = `(SELECT COALESCE(MIN(created_at), '-infinity') FROM journal_entries)`
+
+// Balances возвращает для каждого кошелька из БД PostgreSQL, упорядоченных по адресу, кэшированный баланс, сумму его проводок
+// в журнале и баланс, восстановленный по начальным зачислениям журнала и завершенным или отмененным транзакциям после появления журнала.
+// Все балансы читаются одним запросом, поэтому перевод, зафиксированный во время чтения, не дает ложного расхождения.
+func (r *LedgerPostgres) Balances(ctx context.Context) ([]models.LedgerBalance, error) {
+	query := `WITH movements AS (` + transactionMovements + `)
+		SELECT w.address, w.currency, w.balance,
+			COALESCE((SELECT SUM(p.amount) FROM postings p WHERE p.wallet_address = w.address), 0),
+			COALESCE((SELECT SUM(p.amount) FROM postings p JOIN journal_entries e ON e.id = p.entry_id
+				WHERE p.wallet_address = w.address AND e.kind = 'opening'), 0) +
+			COALESCE((SELECT SUM(m.amount) FROM movements m
+				WHERE m.address = w.address AND m.status IN ('completed', 'reversed') AND m.created_at >= ` + ledgerStart + `), 0)
+		FROM wallets w
+		ORDER BY w.address`
+	balances := make([]models.LedgerBalance, 0)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b models.LedgerBalance
+		if err := rows.Scan(&b.Address, &b.Currency, &b.Stored, &b.Ledger, &b.Expected); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		balances = append(balances, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return balances, nil
+}
+
+// UnrecordedTransactions возвращает упорядоченные ID завершенных или отмененных транзакций из БД PostgreSQL,
+// выполненных после появления журнала, но не имеющих записи в нем.
+func (r *LedgerPostgres) UnrecordedTransactions(ctx context.Context) ([]int, error) {
+	query := `SELECT t.id FROM transactions t
+		WHERE t.status IN ('completed', 'reversed') AND t.created_at >= ` + ledgerStart + `
+			AND NOT EXISTS (SELECT 1 FROM journal_entries e WHERE e.transaction_id = t.id)
+		ORDER BY t.id`
+	ids := make([]int, 0)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return ids, nil
+}
+
+// MismatchedEntries возвращает упорядоченные ID записей журнала из БД PostgreSQL, проводки которых по кошелькам
+// не совпадают с суммой, комиссией или конвертацией их транзакции.
+func (r *LedgerPostgres) MismatchedEntries(ctx context.Context) ([]int64, error) {
+	query := `WITH movements AS (` + transactionMovements + `),
+		expected AS (SELECT transaction_id, address, SUM(amount) AS amount FROM movements
+			GROUP BY transaction_id, address HAVING SUM(amount) <> 0),
+		actual AS (SELECT p.entry_id, p.wallet_address AS address, SUM(p.amount) AS amount FROM postings p
+			WHERE p.wallet_address IS NOT NULL GROUP BY p.entry_id, p.wallet_address HAVING SUM(p.amount) <> 0)
+		SELECT e.id FROM journal_entries e
+		WHERE e.transaction_id IS NOT NULL AND EXISTS (
+			(SELECT x.address, x.amount FROM expected x WHERE x.transaction_id = e.transaction_id
+			EXCEPT SELECT a.address, a.amount FROM actual a WHERE a.entry_id = e.id)
+			UNION ALL
+			(SELECT a.address, a.amount FROM actual a WHERE a.entry_id = e.id
+			EXCEPT SELECT x.address, x.amount FROM expected x WHERE x.transaction_id = e.transaction_id))
+		ORDER BY e.id`
+	ids := make([]int64, 0)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return ids, nil
+}
+
+// UnbalancedEntries возвращает упорядоченные ID записей журнала из БД PostgreSQL, сумма проводок которых в какой-либо валюте не равна нулю.
+func (r *LedgerPostgres) UnbalancedEntries(ctx context.Context) ([]int64, error) {
+	query := `SELECT DISTINCT entry_id FROM postings GROUP BY entry_id, currency HAVING SUM(amount) <> 0 ORDER BY entry_id`
+	ids := make([]int64, 0)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerPostgres_Post(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLedgerPostgres(db)
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newEntry := func() *models.JournalEntry {
+		return &models.JournalEntry{
+			Kind:          models.JournalEntryKindTransfer,
+			TransactionID: 7,
+			Postings: []models.Posting{
+				{Wallet: "addr1", Currency: "USD", Amount: money.MustParse("-10.50")},
+				{Account: models.LedgerAccountExchange, Currency: "USD", Amount: money.MustParse("10.50")},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO journal_entries (.+) RETURNING id, created_at").
+					WithArgs(models.JournalEntryKindTransfer, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+				mock.ExpectExec("INSERT INTO postings").
+					WithArgs(3, "addr1", "", "USD", "-10.50").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE wallets SET balance = balance \\+ \\$1 WHERE address = \\$2 AND currency = \\$3").
+					WithArgs("-10.50", "addr1", "USD").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO postings").
+					WithArgs(3, "", models.LedgerAccountExchange, "USD", "10.50").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+			},
+		},
+		{
+			name: "Wallet Not Found",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO journal_entries").
+					WithArgs(models.JournalEntryKindTransfer, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+				mock.ExpectExec("INSERT INTO postings").
+					WithArgs(3, "addr1", "", "USD", "-10.50").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE wallets").
+					WithArgs("-10.50", "addr1", "USD").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: domain.ErrWalletNotFound,
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO journal_entries").
+					WithArgs(models.JournalEntryKindTransfer, 7).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			entry := newEntry()
+			err := repo.Post(context.Background(), entry)
+			if tt.wantErr != nil {
+				if errors.Is(tt.wantErr, domain.ErrWalletNotFound) {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.EqualError(t, err, tt.wantErr.Error())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), entry.ID)
+				assert.Equal(t, createdAt, entry.CreatedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLedgerPostgres_RebuildBalances(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLedgerPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("SELECT address FROM wallets ORDER BY address FOR UPDATE").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("(?s)UPDATE wallets SET balance = ledger.balance .+ LEFT JOIN postings").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			want: 2,
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectExec("SELECT address FROM wallets").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.RebuildBalances(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLedgerPostgres_Balances(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLedgerPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []models.LedgerBalance
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"address", "currency", "balance", "ledger", "expected"}).
+					AddRow("addr1", "USD", "100.00", "100.00", "100.00").
+					AddRow("addr2", "EUR", "15.50", "10.00", "12.00")
+				mock.ExpectQuery("(?s)WITH movements AS \\(.+transaction_fees.+transaction_conversions.+\\)\\s+SELECT w.address, w.currency, w.balance, .+e.kind = 'opening'.+" +
+					"m.status IN \\('completed', 'reversed'\\) AND m.created_at >= \\(SELECT COALESCE\\(MIN\\(created_at\\), '-infinity'\\) FROM journal_entries\\)").
+					WillReturnRows(rows)
+			},
+			want: []models.LedgerBalance{
+				{Address: "addr1", Currency: "USD", Stored: money.MustParse("100.00"), Ledger: money.MustParse("100.00"), Expected: money.MustParse("100.00")},
+				{Address: "addr2", Currency: "EUR", Stored: money.MustParse("15.50"), Ledger: money.MustParse("10.00"), Expected: money.MustParse("12.00")},
+			},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("WITH movements AS").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Balances(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLedgerPostgres_UnbalancedEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLedgerPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("SELECT DISTINCT entry_id FROM postings GROUP BY entry_id, currency HAVING SUM\\(amount\\) <> 0").
+					WillReturnRows(sqlmock.NewRows([]string{"entry_id"}).AddRow(4).AddRow(9))
+			},
+			want: []int64{4, 9},
+		},
+		{
+			name: "No Unbalanced Entries",
+			mock: func() {
+				mock.ExpectQuery("SELECT DISTINCT entry_id FROM postings").
+					WillReturnRows(sqlmock.NewRows([]string{"entry_id"}))
+			},
+			want: []int64{},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT DISTINCT entry_id FROM postings").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.UnbalancedEntries(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLedgerPostgres_UnrecordedTransactions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLedgerPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("(?s)SELECT t.id FROM transactions t .+status IN \\('completed', 'reversed'\\).+" +
+					"NOT EXISTS \\(SELECT 1 FROM journal_entries e WHERE e.transaction_id = t.id\\)").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12).AddRow(15))
+			},
+			want: []int{12, 15},
+		},
+		{
+			name: "No Unrecorded Transactions",
+			mock: func() {
+				mock.ExpectQuery("SELECT t.id FROM transactions t").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			want: []int{},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT t.id FROM transactions t").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.UnrecordedTransactions(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLedgerPostgres_MismatchedEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLedgerPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("(?s)WITH movements AS .+expected AS .+actual AS .+SELECT e.id FROM journal_entries e .+EXCEPT.+EXCEPT").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+			},
+			want: []int64{6},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("WITH movements AS").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.MismatchedEntries(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
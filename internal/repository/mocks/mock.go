@@ -1,12 +1,21 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository.go -destination=mocks/mock.go
+//
 
 // Package mock_repository is a generated GoMock package.
 package mock_repository
 
 import (
+	context "context"
 	models "golangTestTask/internal/models"
+	repository "golangTestTask/internal/repository"
+	money "golangTestTask/pkg/money"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -15,6 +24,7 @@ import (
 type MockWallet struct {
 	ctrl     *gomock.Controller
 	recorder *MockWalletMockRecorder
+	isgomock struct{}
 }
 
 // MockWalletMockRecorder is the mock recorder for MockWallet.
@@ -35,81 +45,126 @@ func (m *MockWallet) EXPECT() *MockWalletMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockWallet) Create(wallet *models.Wallet) error {
+func (m *MockWallet) Create(ctx context.Context, wallet *models.Wallet) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", wallet)
+	ret := m.ctrl.Call(m, "Create", ctx, wallet)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockWalletMockRecorder) Create(wallet interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) Create(ctx, wallet any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWallet)(nil).Create), wallet)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWallet)(nil).Create), ctx, wallet)
 }
 
 // Existence mocks base method.
-func (m *MockWallet) Existence() bool {
+func (m *MockWallet) Existence(ctx context.Context) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Existence")
+	ret := m.ctrl.Call(m, "Existence", ctx)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // Existence indicates an expected call of Existence.
-func (mr *MockWalletMockRecorder) Existence() *gomock.Call {
+func (mr *MockWalletMockRecorder) Existence(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Existence", reflect.TypeOf((*MockWallet)(nil).Existence))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Existence", reflect.TypeOf((*MockWallet)(nil).Existence), ctx)
 }
 
 // Get mocks base method.
-func (m *MockWallet) Get(address string) (*models.Wallet, error) {
+func (m *MockWallet) Get(ctx context.Context, address string) (*models.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", address)
+	ret := m.ctrl.Call(m, "Get", ctx, address)
 	ret0, _ := ret[0].(*models.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockWalletMockRecorder) Get(address interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) Get(ctx, address any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWallet)(nil).Get), address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWallet)(nil).Get), ctx, address)
 }
 
 // GetAll mocks base method.
-func (m *MockWallet) GetAll() ([]models.Wallet, error) {
+func (m *MockWallet) GetAll(ctx context.Context) ([]models.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx)
 	ret0, _ := ret[0].([]models.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockWalletMockRecorder) GetAll() *gomock.Call {
+func (mr *MockWalletMockRecorder) GetAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWallet)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWallet)(nil).GetAll), ctx)
 }
 
-// Update mocks base method.
-func (m *MockWallet) Update(wallet *models.Wallet) error {
+// GetForUpdate mocks base method.
+func (m *MockWallet) GetForUpdate(ctx context.Context, address string) (*models.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, address)
+	ret0, _ := ret[0].(*models.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockWalletMockRecorder) GetForUpdate(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockWallet)(nil).GetForUpdate), ctx, address)
+}
+
+// Stats mocks base method.
+func (m *MockWallet) Stats(ctx context.Context) ([]models.WalletStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].([]models.WalletStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockWalletMockRecorder) Stats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockWallet)(nil).Stats), ctx)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockWallet) UpdateStatus(ctx context.Context, address string, status models.WalletStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", wallet)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, address, status)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockWalletMockRecorder) Update(wallet interface{}) *gomock.Call {
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockWalletMockRecorder) UpdateStatus(ctx, address, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockWallet)(nil).UpdateStatus), ctx, address, status)
+}
+
+// UpdateTier mocks base method.
+func (m *MockWallet) UpdateTier(ctx context.Context, address, tier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTier", ctx, address, tier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTier indicates an expected call of UpdateTier.
+func (mr *MockWalletMockRecorder) UpdateTier(ctx, address, tier any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWallet)(nil).Update), wallet)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTier", reflect.TypeOf((*MockWallet)(nil).UpdateTier), ctx, address, tier)
 }
 
 // MockTransaction is a mock of Transaction interface.
 type MockTransaction struct {
 	ctrl     *gomock.Controller
 	recorder *MockTransactionMockRecorder
+	isgomock struct{}
 }
 
 // MockTransactionMockRecorder is the mock recorder for MockTransaction.
@@ -130,30 +185,961 @@ func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockTransaction) Create(transaction models.Transaction) error {
+func (m *MockTransaction) Create(ctx context.Context, transaction models.Transaction) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transaction)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionMockRecorder) Create(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransaction)(nil).Create), ctx, transaction)
+}
+
+// CreateConversion mocks base method.
+func (m *MockTransaction) CreateConversion(ctx context.Context, conversion models.TransactionConversion) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", transaction)
+	ret := m.ctrl.Call(m, "CreateConversion", ctx, conversion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockTransactionMockRecorder) Create(transaction interface{}) *gomock.Call {
+// CreateConversion indicates an expected call of CreateConversion.
+func (mr *MockTransactionMockRecorder) CreateConversion(ctx, conversion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConversion", reflect.TypeOf((*MockTransaction)(nil).CreateConversion), ctx, conversion)
+}
+
+// CreateFee mocks base method.
+func (m *MockTransaction) CreateFee(ctx context.Context, fee models.TransactionFee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFee", ctx, fee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFee indicates an expected call of CreateFee.
+func (mr *MockTransactionMockRecorder) CreateFee(ctx, fee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFee", reflect.TypeOf((*MockTransaction)(nil).CreateFee), ctx, fee)
+}
+
+// CreateQuote mocks base method.
+func (m *MockTransaction) CreateQuote(ctx context.Context, quote models.TransactionQuote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQuote", ctx, quote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateQuote indicates an expected call of CreateQuote.
+func (mr *MockTransactionMockRecorder) CreateQuote(ctx, quote any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuote", reflect.TypeOf((*MockTransaction)(nil).CreateQuote), ctx, quote)
+}
+
+// GetConversion mocks base method.
+func (m *MockTransaction) GetConversion(ctx context.Context, transactionID int) (*models.TransactionConversion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversion", ctx, transactionID)
+	ret0, _ := ret[0].(*models.TransactionConversion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversion indicates an expected call of GetConversion.
+func (mr *MockTransactionMockRecorder) GetConversion(ctx, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversion", reflect.TypeOf((*MockTransaction)(nil).GetConversion), ctx, transactionID)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockTransaction) GetForUpdate(ctx context.Context, id int) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockTransactionMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransaction)(nil).Create), transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockTransaction)(nil).GetForUpdate), ctx, id)
 }
 
 // Getlast mocks base method.
-func (m *MockTransaction) Getlast(count int) ([]models.Transaction, error) {
+func (m *MockTransaction) Getlast(ctx context.Context, count int) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Getlast", count)
+	ret := m.ctrl.Call(m, "Getlast", ctx, count)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Getlast indicates an expected call of Getlast.
-func (mr *MockTransactionMockRecorder) Getlast(count interface{}) *gomock.Call {
+func (mr *MockTransactionMockRecorder) Getlast(ctx, count any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getlast", reflect.TypeOf((*MockTransaction)(nil).Getlast), ctx, count)
+}
+
+// List mocks base method.
+func (m *MockTransaction) List(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransaction)(nil).List), ctx, filter)
+}
+
+// OutgoingSince mocks base method.
+func (m *MockTransaction) OutgoingSince(ctx context.Context, address string, since time.Time) (models.TransferActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutgoingSince", ctx, address, since)
+	ret0, _ := ret[0].(models.TransferActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OutgoingSince indicates an expected call of OutgoingSince.
+func (mr *MockTransactionMockRecorder) OutgoingSince(ctx, address, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutgoingSince", reflect.TypeOf((*MockTransaction)(nil).OutgoingSince), ctx, address, since)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockTransaction) UpdateStatus(ctx context.Context, id int, status models.TransactionStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockTransactionMockRecorder) UpdateStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockTransaction)(nil).UpdateStatus), ctx, id, status)
+}
+
+// MockLedger is a mock of Ledger interface.
+type MockLedger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerMockRecorder
+	isgomock struct{}
+}
+
+// MockLedgerMockRecorder is the mock recorder for MockLedger.
+type MockLedgerMockRecorder struct {
+	mock *MockLedger
+}
+
+// NewMockLedger creates a new mock instance.
+func NewMockLedger(ctrl *gomock.Controller) *MockLedger {
+	mock := &MockLedger{ctrl: ctrl}
+	mock.recorder = &MockLedgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedger) EXPECT() *MockLedgerMockRecorder {
+	return m.recorder
+}
+
+// Balances mocks base method.
+func (m *MockLedger) Balances(ctx context.Context) ([]models.LedgerBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Balances", ctx)
+	ret0, _ := ret[0].([]models.LedgerBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Balances indicates an expected call of Balances.
+func (mr *MockLedgerMockRecorder) Balances(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balances", reflect.TypeOf((*MockLedger)(nil).Balances), ctx)
+}
+
+// MismatchedEntries mocks base method.
+func (m *MockLedger) MismatchedEntries(ctx context.Context) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MismatchedEntries", ctx)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MismatchedEntries indicates an expected call of MismatchedEntries.
+func (mr *MockLedgerMockRecorder) MismatchedEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MismatchedEntries", reflect.TypeOf((*MockLedger)(nil).MismatchedEntries), ctx)
+}
+
+// Post mocks base method.
+func (m *MockLedger) Post(ctx context.Context, entry *models.JournalEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockLedgerMockRecorder) Post(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockLedger)(nil).Post), ctx, entry)
+}
+
+// RebuildBalances mocks base method.
+func (m *MockLedger) RebuildBalances(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildBalances", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildBalances indicates an expected call of RebuildBalances.
+func (mr *MockLedgerMockRecorder) RebuildBalances(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildBalances", reflect.TypeOf((*MockLedger)(nil).RebuildBalances), ctx)
+}
+
+// UnbalancedEntries mocks base method.
+func (m *MockLedger) UnbalancedEntries(ctx context.Context) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbalancedEntries", ctx)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnbalancedEntries indicates an expected call of UnbalancedEntries.
+func (mr *MockLedgerMockRecorder) UnbalancedEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbalancedEntries", reflect.TypeOf((*MockLedger)(nil).UnbalancedEntries), ctx)
+}
+
+// UnrecordedTransactions mocks base method.
+func (m *MockLedger) UnrecordedTransactions(ctx context.Context) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnrecordedTransactions", ctx)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnrecordedTransactions indicates an expected call of UnrecordedTransactions.
+func (mr *MockLedgerMockRecorder) UnrecordedTransactions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnrecordedTransactions", reflect.TypeOf((*MockLedger)(nil).UnrecordedTransactions), ctx)
+}
+
+// MockScheduledTransfer is a mock of ScheduledTransfer interface.
+type MockScheduledTransfer struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledTransferMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduledTransferMockRecorder is the mock recorder for MockScheduledTransfer.
+type MockScheduledTransferMockRecorder struct {
+	mock *MockScheduledTransfer
+}
+
+// NewMockScheduledTransfer creates a new mock instance.
+func NewMockScheduledTransfer(ctrl *gomock.Controller) *MockScheduledTransfer {
+	mock := &MockScheduledTransfer{ctrl: ctrl}
+	mock.recorder = &MockScheduledTransferMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledTransfer) EXPECT() *MockScheduledTransferMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockScheduledTransfer) Cancel(ctx context.Context, id int) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockScheduledTransferMockRecorder) Cancel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockScheduledTransfer)(nil).Cancel), ctx, id)
+}
+
+// ClaimDue mocks base method.
+func (m *MockScheduledTransfer) ClaimDue(ctx context.Context, now time.Time) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", ctx, now)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockScheduledTransferMockRecorder) ClaimDue(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockScheduledTransfer)(nil).ClaimDue), ctx, now)
+}
+
+// Create mocks base method.
+func (m *MockScheduledTransfer) Create(ctx context.Context, transfer *models.ScheduledTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScheduledTransferMockRecorder) Create(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduledTransfer)(nil).Create), ctx, transfer)
+}
+
+// CreateRun mocks base method.
+func (m *MockScheduledTransfer) CreateRun(ctx context.Context, run models.ScheduledTransferRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRun indicates an expected call of CreateRun.
+func (mr *MockScheduledTransferMockRecorder) CreateRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRun", reflect.TypeOf((*MockScheduledTransfer)(nil).CreateRun), ctx, run)
+}
+
+// Get mocks base method.
+func (m *MockScheduledTransfer) Get(ctx context.Context, id int) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockScheduledTransferMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockScheduledTransfer)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockScheduledTransfer) List(ctx context.Context, wallets []string) ([]models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, wallets)
+	ret0, _ := ret[0].([]models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockScheduledTransferMockRecorder) List(ctx, wallets any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScheduledTransfer)(nil).List), ctx, wallets)
+}
+
+// ListRuns mocks base method.
+func (m *MockScheduledTransfer) ListRuns(ctx context.Context, id, limit int) ([]models.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", ctx, id, limit)
+	ret0, _ := ret[0].([]models.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockScheduledTransferMockRecorder) ListRuns(ctx, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockScheduledTransfer)(nil).ListRuns), ctx, id, limit)
+}
+
+// Reschedule mocks base method.
+func (m *MockScheduledTransfer) Reschedule(ctx context.Context, transfer models.ScheduledTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockScheduledTransferMockRecorder) Reschedule(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockScheduledTransfer)(nil).Reschedule), ctx, transfer)
+}
+
+// MockHold is a mock of Hold interface.
+type MockHold struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldMockRecorder
+	isgomock struct{}
+}
+
+// MockHoldMockRecorder is the mock recorder for MockHold.
+type MockHoldMockRecorder struct {
+	mock *MockHold
+}
+
+// NewMockHold creates a new mock instance.
+func NewMockHold(ctrl *gomock.Controller) *MockHold {
+	mock := &MockHold{ctrl: ctrl}
+	mock.recorder = &MockHoldMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHold) EXPECT() *MockHoldMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockHold) Capture(ctx context.Context, id int, amount money.Amount, transactionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, id, amount, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockHoldMockRecorder) Capture(ctx, id, amount, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockHold)(nil).Capture), ctx, id, amount, transactionID)
+}
+
+// Create mocks base method.
+func (m *MockHold) Create(ctx context.Context, hold *models.Hold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHoldMockRecorder) Create(ctx, hold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHold)(nil).Create), ctx, hold)
+}
+
+// ExpireStale mocks base method.
+func (m *MockHold) ExpireStale(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireStale", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireStale indicates an expected call of ExpireStale.
+func (mr *MockHoldMockRecorder) ExpireStale(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireStale", reflect.TypeOf((*MockHold)(nil).ExpireStale), ctx, now)
+}
+
+// Get mocks base method.
+func (m *MockHold) Get(ctx context.Context, id int) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockHoldMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockHold)(nil).Get), ctx, id)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockHold) GetForUpdate(ctx context.Context, id int) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockHoldMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockHold)(nil).GetForUpdate), ctx, id)
+}
+
+// Held mocks base method.
+func (m *MockHold) Held(ctx context.Context, address string, now time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Held", ctx, address, now)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Held indicates an expected call of Held.
+func (mr *MockHoldMockRecorder) Held(ctx, address, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Held", reflect.TypeOf((*MockHold)(nil).Held), ctx, address, now)
+}
+
+// Void mocks base method.
+func (m *MockHold) Void(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockHoldMockRecorder) Void(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockHold)(nil).Void), ctx, id)
+}
+
+// MockWalletTier is a mock of WalletTier interface.
+type MockWalletTier struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletTierMockRecorder
+	isgomock struct{}
+}
+
+// MockWalletTierMockRecorder is the mock recorder for MockWalletTier.
+type MockWalletTierMockRecorder struct {
+	mock *MockWalletTier
+}
+
+// NewMockWalletTier creates a new mock instance.
+func NewMockWalletTier(ctrl *gomock.Controller) *MockWalletTier {
+	mock := &MockWalletTier{ctrl: ctrl}
+	mock.recorder = &MockWalletTierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletTier) EXPECT() *MockWalletTierMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWalletTier) Create(ctx context.Context, tier models.WalletTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletTierMockRecorder) Create(ctx, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletTier)(nil).Create), ctx, tier)
+}
+
+// Get mocks base method.
+func (m *MockWalletTier) Get(ctx context.Context, name string) (*models.WalletTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name)
+	ret0, _ := ret[0].(*models.WalletTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWalletTierMockRecorder) Get(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletTier)(nil).Get), ctx, name)
+}
+
+// GetAll mocks base method.
+func (m *MockWalletTier) GetAll(ctx context.Context) ([]models.WalletTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.WalletTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWalletTierMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWalletTier)(nil).GetAll), ctx)
+}
+
+// Update mocks base method.
+func (m *MockWalletTier) Update(ctx context.Context, tier models.WalletTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWalletTierMockRecorder) Update(ctx, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWalletTier)(nil).Update), ctx, tier)
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotency) Complete(ctx context.Context, subject, key string, statusCode int, responseBody []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, subject, key, statusCode, responseBody)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyMockRecorder) Complete(ctx, subject, key, statusCode, responseBody any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotency)(nil).Complete), ctx, subject, key, statusCode, responseBody)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotency) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotency)(nil).DeleteExpired), ctx)
+}
+
+// Get mocks base method.
+func (m *MockIdempotency) Get(ctx context.Context, subject, key string) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, subject, key)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIdempotencyMockRecorder) Get(ctx, subject, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIdempotency)(nil).Get), ctx, subject, key)
+}
+
+// Release mocks base method.
+func (m *MockIdempotency) Release(ctx context.Context, subject, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, subject, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyMockRecorder) Release(ctx, subject, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotency)(nil).Release), ctx, subject, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotency) Reserve(ctx context.Context, subject, key, requestHash string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, subject, key, requestHash, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyMockRecorder) Reserve(ctx, subject, key, requestHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotency)(nil).Reserve), ctx, subject, key, requestHash, expiresAt)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// AddWallet mocks base method.
+func (m *MockAPIKey) AddWallet(ctx context.Context, keyID int, address string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWallet", ctx, keyID, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWallet indicates an expected call of AddWallet.
+func (mr *MockAPIKeyMockRecorder) AddWallet(ctx, keyID, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWallet", reflect.TypeOf((*MockAPIKey)(nil).AddWallet), ctx, keyID, address)
+}
+
+// Create mocks base method.
+func (m *MockAPIKey) Create(ctx context.Context, key *models.APIKey, keyHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key, keyHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeyMockRecorder) Create(ctx, key, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKey)(nil).Create), ctx, key, keyHash)
+}
+
+// GetByHash mocks base method.
+func (m *MockAPIKey) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, keyHash)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockAPIKeyMockRecorder) GetByHash(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockAPIKey)(nil).GetByHash), ctx, keyHash)
+}
+
+// GetByID mocks base method.
+func (m *MockAPIKey) GetByID(ctx context.Context, id int) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAPIKeyMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAPIKey)(nil).GetByID), ctx, id)
+}
+
+// MockUser is a mock of User interface.
+type MockUser struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMockRecorder
+	isgomock struct{}
+}
+
+// MockUserMockRecorder is the mock recorder for MockUser.
+type MockUserMockRecorder struct {
+	mock *MockUser
+}
+
+// NewMockUser creates a new mock instance.
+func NewMockUser(ctrl *gomock.Controller) *MockUser {
+	mock := &MockUser{ctrl: ctrl}
+	mock.recorder = &MockUserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUser) EXPECT() *MockUserMockRecorder {
+	return m.recorder
+}
+
+// AddWallet mocks base method.
+func (m *MockUser) AddWallet(ctx context.Context, userID int, address string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWallet", ctx, userID, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWallet indicates an expected call of AddWallet.
+func (mr *MockUserMockRecorder) AddWallet(ctx, userID, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWallet", reflect.TypeOf((*MockUser)(nil).AddWallet), ctx, userID, address)
+}
+
+// Create mocks base method.
+func (m *MockUser) Create(ctx context.Context, user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserMockRecorder) Create(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), ctx, user)
+}
+
+// GetByID mocks base method.
+func (m *MockUser) GetByID(ctx context.Context, id int) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUser)(nil).GetByID), ctx, id)
+}
+
+// GetByUsername mocks base method.
+func (m *MockUser) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsername", ctx, username)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsername indicates an expected call of GetByUsername.
+func (mr *MockUserMockRecorder) GetByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsername", reflect.TypeOf((*MockUser)(nil).GetByUsername), ctx, username)
+}
+
+// MockRefreshToken is a mock of RefreshToken interface.
+type MockRefreshToken struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenMockRecorder
+	isgomock struct{}
+}
+
+// MockRefreshTokenMockRecorder is the mock recorder for MockRefreshToken.
+type MockRefreshTokenMockRecorder struct {
+	mock *MockRefreshToken
+}
+
+// NewMockRefreshToken creates a new mock instance.
+func NewMockRefreshToken(ctrl *gomock.Controller) *MockRefreshToken {
+	mock := &MockRefreshToken{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshToken) EXPECT() *MockRefreshTokenMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockRefreshToken) Consume(ctx context.Context, tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockRefreshTokenMockRecorder) Consume(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockRefreshToken)(nil).Consume), ctx, tokenHash)
+}
+
+// Create mocks base method.
+func (m *MockRefreshToken) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRefreshTokenMockRecorder) Create(ctx, userID, tokenHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefreshToken)(nil).Create), ctx, userID, tokenHash, expiresAt)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+	isgomock struct{}
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// WithTx mocks base method.
+func (m *MockUnitOfWork) WithTx(ctx context.Context, fn func(*repository.Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockUnitOfWorkMockRecorder) WithTx(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Getlast", reflect.TypeOf((*MockTransaction)(nil).Getlast), count)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockUnitOfWork)(nil).WithTx), ctx, fn)
 }
@@ -2,13 +2,17 @@ package repository
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"golangTestTask/configs"
+	"io/fs"
+	"net"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/lib/pq"
 )
 
 // NewPostgresDB создает новое подключение к PostgreSQL.
@@ -28,23 +32,102 @@ func NewPostgresDB(cfg configs.Config) (*sql.DB, error) {
 	return db, nil
 }
 
+// migrationsSource — папка с миграциями схемы БД.
+const migrationsSource = "file://migrations"
+
+// ErrSchemaOutdated возвращается CheckSchema, если к БД применены не все миграции.
+var ErrSchemaOutdated = errors.New("database schema is outdated")
+
 // Migrate применяет все миграции из папки migrations.
 func Migrate(db *sql.DB) error {
+	m, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	return nil
+}
+
+// CheckSchema проверяет, что к БД применены все миграции из папки migrations, не изменяя схему.
+// Если версия схемы меньше последней миграции или последняя миграция не завершилась, возвращает ошибку,
+// оборачивающую ErrSchemaOutdated: такую БД нужно сначала обновить запуском сервера.
+func CheckSchema(db *sql.DB) error {
+	m, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("%w: migration %d did not complete", ErrSchemaOutdated, version)
+	}
+
+	latest, err := latestMigration()
+	if err != nil {
+		return err
+	}
+	if version < latest {
+		return fmt.Errorf("%w: version %d, latest migration %d", ErrSchemaOutdated, version, latest)
+	}
+	return nil
+}
+
+// newMigrate создает экземпляр migrate для миграций из папки migrations и БД db.
+func newMigrate(db *sql.DB) (*migrate.Migrate, error) {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
-		return fmt.Errorf("failed to create migrate driver: %w", err)
+		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations",
-		"postgres", driver)
+	m, err := migrate.NewWithDatabaseInstance(migrationsSource, "postgres", driver)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
 	}
+	return m, nil
+}
 
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("failed to apply migrations: %w", err)
+// latestMigration возвращает номер последней миграции из папки migrations.
+func latestMigration() (uint, error) {
+	source, err := (&file.File{}).Open(migrationsSource)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open migrations: %w", err)
 	}
+	defer source.Close()
 
-	return nil
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
+
+// IsTransient сообщает, вызвана ли ошибка err временной недоступностью PostgreSQL: обрывом соединения, конфликтом
+// сериализации или взаимной блокировкой, нехваткой ресурсов либо остановкой сервера. Операцию, завершившуюся
+// такой ошибкой, можно повторить.
+func IsTransient(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", "40", "53", "57":
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr)
 }
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+)
+
+type RefreshTokenPostgres struct {
+	db DBTX
+}
+
+// NewRefreshTokenPostgres создает новый экземпляр RefreshTokenPostgres.
+func NewRefreshTokenPostgres(db DBTX) *RefreshTokenPostgres {
+	return &RefreshTokenPostgres{db: db}
+}
+
+// Create сохраняет в БД PostgreSQL токен обновления с хешем tokenHash пользователя userID, действующий до expiresAt.
+func (r *RefreshTokenPostgres) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO refresh_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	_, err := r.db.ExecContext(ctx, query, userID, tokenHash, expiresAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Consume отзывает действующий токен обновления с хешем tokenHash и возвращает ID его пользователя.
+// Отзыв выполняется одним запросом, поэтому один и тот же токен нельзя использовать дважды даже параллельно.
+func (r *RefreshTokenPostgres) Consume(ctx context.Context, tokenHash string) (int, error) {
+	query := `UPDATE refresh_tokens SET revoked_at = now()
+		WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > now()
+		RETURNING user_id`
+	var userID int
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewRefreshTokenPostgres(db)
+	expiresAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("INSERT INTO refresh_tokens \\(user_id, token_hash, expires_at\\) VALUES \\(\\$1, \\$2, \\$3\\)").
+		WithArgs(3, "hash1", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Create(context.Background(), 3, "hash1", expiresAt)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenPostgres_Consume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewRefreshTokenPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("UPDATE refresh_tokens SET revoked_at = now\\(\\) WHERE token_hash = \\$1 AND revoked_at IS NULL AND expires_at > now\\(\\) RETURNING user_id").
+					WithArgs("hash1").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(3))
+			},
+			want: 3,
+		},
+		{
+			name: "Revoked Or Expired",
+			mock: func() {
+				mock.ExpectQuery("UPDATE refresh_tokens").
+					WithArgs("hash1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: ErrRefreshTokenNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Consume(context.Background(), "hash1")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
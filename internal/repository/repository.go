@@ -1,41 +1,202 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+	"time"
 )
 
 //go:generate mockgen -source=repository.go -destination=mocks/mock.go
 
 type Wallet interface {
-	// Create сохраняет новый кошелек в БД.
-	Create(wallet *models.Wallet) error
-	// Update обновляет баланс кошелька по адресу.
-	Update(wallet *models.Wallet) error
+	// Create сохраняет новый кошелек в БД. Если адрес кошелька не задан, генерирует уникальный адрес и записывает его в wallet.
+	Create(ctx context.Context, wallet *models.Wallet) error
+	// UpdateStatus обновляет статус кошелька по адресу.
+	UpdateStatus(ctx context.Context, address string, status models.WalletStatus) error
+	// UpdateTier присваивает кошельку по адресу уровень tier.
+	UpdateTier(ctx context.Context, address string, tier string) error
 	// Get возвращает кошелек по адресу.
-	Get(address string) (*models.Wallet, error)
+	Get(ctx context.Context, address string) (*models.Wallet, error)
+	// GetForUpdate возвращает кошелек по адресу и блокирует его строку до конца транзакции.
+	GetForUpdate(ctx context.Context, address string) (*models.Wallet, error)
 	// GetAll возвращает все кошельки в БД.
-	GetAll() ([]models.Wallet, error)
+	GetAll(ctx context.Context) ([]models.Wallet, error)
+	// Stats возвращает количество кошельков и сумму их балансов по статусам.
+	Stats(ctx context.Context) ([]models.WalletStats, error)
 	// Existence проверяет существуют ли какие-либо кошельки в БД.
-	Existence() bool
+	Existence(ctx context.Context) bool
 }
 
 type Transaction interface {
-	// Create сохраняет новую транзакцию в БД.
-	Create(transaction models.Transaction) error
+	// Create сохраняет новую транзакцию в БД и возвращает ее ID.
+	Create(ctx context.Context, transaction models.Transaction) (int, error)
+	// GetForUpdate возвращает транзакцию id, блокируя ее до конца транзакции БД.
+	// Если транзакция не найдена, возвращает domain.ErrTransactionNotFound.
+	GetForUpdate(ctx context.Context, id int) (*models.Transaction, error)
+	// UpdateStatus изменяет статус транзакции id. Если транзакция не найдена, возвращает domain.ErrTransactionNotFound.
+	UpdateStatus(ctx context.Context, id int, status models.TransactionStatus) error
+	// CreateFee сохраняет строку комиссии, связанную с транзакцией fee.TransactionID.
+	CreateFee(ctx context.Context, fee models.TransactionFee) error
+	// CreateConversion сохраняет части перевода с конвертацией, связанного с транзакцией conversion.TransactionID.
+	CreateConversion(ctx context.Context, conversion models.TransactionConversion) error
+	// CreateQuote сохраняет использование предварительного расчета переводом quote.TransactionID.
+	// Если расчет с тем же quote.Nonce уже использован, возвращает domain.ErrQuoteUsed.
+	CreateQuote(ctx context.Context, quote models.TransactionQuote) error
+	// GetConversion возвращает части перевода с конвертацией transactionID.
+	GetConversion(ctx context.Context, transactionID int) (*models.TransactionConversion, error)
 	// Getlast возвращает count последних транзакций из БД.
-	Getlast(count int) ([]models.Transaction, error)
+	Getlast(ctx context.Context, count int) ([]models.Transaction, error)
+	// List возвращает транзакции, подходящие под filter, в порядке убывания ID.
+	List(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error)
+	// OutgoingSince возвращает сводку завершенных и отмененных переводов с кошелька address, созданных не раньше since,
+	// без компенсирующих транзакций отмены.
+	OutgoingSince(ctx context.Context, address string, since time.Time) (models.TransferActivity, error)
+}
+
+type Ledger interface {
+	// Post сохраняет запись журнала entry с ее проводками, заполняет ее ID и применяет проводки по кошелькам
+	// к их кэшированным балансам.
+	Post(ctx context.Context, entry *models.JournalEntry) error
+	// RebuildBalances пересчитывает кэшированные балансы всех кошельков по проводкам журнала
+	// и возвращает количество кошельков, баланс которых был исправлен.
+	RebuildBalances(ctx context.Context) (int64, error)
+	// Balances возвращает для каждого кошелька его кэшированный баланс, сумму его проводок в журнале
+	// и баланс, восстановленный по начальным зачислениям и транзакциям.
+	Balances(ctx context.Context) ([]models.LedgerBalance, error)
+	// UnbalancedEntries возвращает ID записей журнала, проводки которых в какой-либо валюте не сходятся в ноль.
+	UnbalancedEntries(ctx context.Context) ([]int64, error)
+	// UnrecordedTransactions возвращает ID завершенных транзакций, выполненных после появления журнала, но не записанных в него.
+	UnrecordedTransactions(ctx context.Context) ([]int, error)
+	// MismatchedEntries возвращает ID записей журнала, проводки которых не совпадают с их транзакцией.
+	MismatchedEntries(ctx context.Context) ([]int64, error)
+}
+
+type ScheduledTransfer interface {
+	// Create сохраняет новый регулярный перевод и заполняет его ID, статус и время создания.
+	Create(ctx context.Context, transfer *models.ScheduledTransfer) error
+	// Get возвращает регулярный перевод по ID. Если он не найден, возвращает domain.ErrScheduledTransferNotFound.
+	Get(ctx context.Context, id int) (*models.ScheduledTransfer, error)
+	// List возвращает регулярные переводы с кошельков wallets в порядке убывания ID; при wallets, равном nil, — все регулярные переводы.
+	List(ctx context.Context, wallets []string) ([]models.ScheduledTransfer, error)
+	// Cancel отменяет активный регулярный перевод id и возвращает его.
+	Cancel(ctx context.Context, id int) (*models.ScheduledTransfer, error)
+	// ClaimDue возвращает активный регулярный перевод, срок выполнения которого наступил к now, и блокирует его до конца
+	// транзакции БД, пропуская переводы, заблокированные другими транзакциями. Если таких переводов нет, возвращает nil.
+	ClaimDue(ctx context.Context, now time.Time) (*models.ScheduledTransfer, error)
+	// Reschedule сохраняет срок следующего выполнения, время повторной попытки и число попыток регулярного перевода.
+	Reschedule(ctx context.Context, transfer models.ScheduledTransfer) error
+	// CreateRun сохраняет итог попытки выполнить регулярный перевод.
+	CreateRun(ctx context.Context, run models.ScheduledTransferRun) error
+	// ListRuns возвращает limit последних попыток выполнить регулярный перевод id в порядке убывания ID.
+	ListRuns(ctx context.Context, id int, limit int) ([]models.ScheduledTransferRun, error)
+}
+
+type Hold interface {
+	// Create сохраняет новую блокировку средств и заполняет ее ID, статус и время создания.
+	Create(ctx context.Context, hold *models.Hold) error
+	// Get возвращает блокировку средств по ID. Если она не найдена, возвращает domain.ErrHoldNotFound.
+	Get(ctx context.Context, id int) (*models.Hold, error)
+	// GetForUpdate возвращает блокировку средств по ID и блокирует ее строку до конца транзакции БД.
+	GetForUpdate(ctx context.Context, id int) (*models.Hold, error)
+	// Held возвращает сумму активных и не истекших к now блокировок средств на кошельке address.
+	Held(ctx context.Context, address string, now time.Time) (money.Amount, error)
+	// Capture отмечает активную блокировку id как списанную в размере amount транзакцией transactionID.
+	// Если блокировка не активна, возвращает domain.ErrHoldNotActive.
+	Capture(ctx context.Context, id int, amount money.Amount, transactionID int) error
+	// Void отмечает активную блокировку id как отмененную. Если блокировка не активна, возвращает domain.ErrHoldNotActive.
+	Void(ctx context.Context, id int) error
+	// ExpireStale отмечает активные блокировки, срок которых истек к now, как истекшие и возвращает их количество.
+	ExpireStale(ctx context.Context, now time.Time) (int64, error)
+}
+
+type WalletTier interface {
+	// Create сохраняет новый уровень кошельков.
+	Create(ctx context.Context, tier models.WalletTier) error
+	// Update обновляет ограничения уровня кошельков по имени.
+	Update(ctx context.Context, tier models.WalletTier) error
+	// Get возвращает уровень кошельков по имени.
+	Get(ctx context.Context, name string) (*models.WalletTier, error)
+	// GetAll возвращает все уровни кошельков.
+	GetAll(ctx context.Context) ([]models.WalletTier, error)
+}
+
+type Idempotency interface {
+	// Reserve резервирует ключ идемпотентности участника subject за запросом с хешем requestHash до expiresAt.
+	// Возвращает false, если ключ уже занят и его срок действия не истек.
+	Reserve(ctx context.Context, subject string, key string, requestHash string, expiresAt time.Time) (bool, error)
+	// Get возвращает запись по ключу идемпотентности участника subject.
+	Get(ctx context.Context, subject string, key string) (*models.IdempotencyRecord, error)
+	// Complete сохраняет итоговый ответ на запрос участника subject с ключом key.
+	Complete(ctx context.Context, subject string, key string, statusCode int, responseBody []byte) error
+	// Release снимает резервирование ключа участника subject, ответ на который еще не сохранен.
+	Release(ctx context.Context, subject string, key string) error
+	// DeleteExpired удаляет ключи с истекшим сроком действия и возвращает их количество.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type APIKey interface {
+	// Create сохраняет новый ключ API с хешем keyHash и заполняет его ID и время создания.
+	Create(ctx context.Context, key *models.APIKey, keyHash string) error
+	// GetByHash возвращает действующий ключ API по хешу вместе с адресами принадлежащих ему кошельков.
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	// GetByID возвращает действующий ключ API по ID вместе с адресами принадлежащих ему кошельков.
+	GetByID(ctx context.Context, id int) (*models.APIKey, error)
+	// AddWallet передает кошелек address во владение ключу keyID.
+	AddWallet(ctx context.Context, keyID int, address string) error
+}
+
+type User interface {
+	// Create сохраняет нового пользователя и заполняет его ID и время создания.
+	Create(ctx context.Context, user *models.User) error
+	// GetByID возвращает пользователя по ID вместе с адресами принадлежащих ему кошельков.
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	// GetByUsername возвращает пользователя по имени вместе с адресами принадлежащих ему кошельков.
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	// AddWallet передает кошелек address во владение пользователю userID.
+	AddWallet(ctx context.Context, userID int, address string) error
+}
+
+type RefreshToken interface {
+	// Create сохраняет токен обновления с хешем tokenHash пользователя userID, действующий до expiresAt.
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	// Consume отзывает действующий токен обновления с хешем tokenHash и возвращает ID его пользователя.
+	Consume(ctx context.Context, tokenHash string) (int, error)
+}
+
+type UnitOfWork interface {
+	// WithTx выполняет fn в одной транзакции БД: при ошибке все изменения откатываются, иначе фиксируются.
+	WithTx(ctx context.Context, fn func(repos *Repository) error) error
 }
 
 type Repository struct {
 	Wallet
+	WalletTier
 	Transaction
+	Ledger
+	ScheduledTransfer
+	Hold
+	Idempotency
+	APIKey
+	User
+	RefreshToken
+	UnitOfWork
 }
 
 // NewRepository создает новый экземпляр Repository.
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		Wallet:      NewWalletPostgres(db),
-		Transaction: NewTransactionPostgres(db),
+		Wallet:            NewWalletPostgres(db),
+		WalletTier:        NewWalletTierPostgres(db),
+		Transaction:       NewTransactionPostgres(db),
+		Ledger:            NewLedgerPostgres(db),
+		ScheduledTransfer: NewScheduledTransferPostgres(db),
+		Hold:              NewHoldPostgres(db),
+		Idempotency:       NewIdempotencyPostgres(db),
+		APIKey:            NewAPIKeyPostgres(db),
+		User:              NewUserPostgres(db),
+		RefreshToken:      NewRefreshTokenPostgres(db),
+		UnitOfWork:        NewUnitOfWorkPostgres(db),
 	}
 }
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// scheduledTransferColumns — столбцы регулярного перевода в порядке, который ожидает scanScheduledTransfer.
+const scheduledTransferColumns = `id, from_address, to_address, amount, convert_currency, schedule, status, next_run_at, retry_at, attempts,
+	created_at, cancelled_at, created_by_key_id, created_by_user_id`
+
+// scheduledTransferRunColumns — столбцы попытки выполнить регулярный перевод в порядке, который ожидает ListRuns.
+const scheduledTransferRunColumns = `id, scheduled_transfer_id, scheduled_for, attempt, status, transaction_id, COALESCE(error, ''), created_at`
+
+type ScheduledTransferPostgres struct {
+	db DBTX
+}
+
+// NewScheduledTransferPostgres создает новый экземпляр ScheduledTransferPostgres.
+func NewScheduledTransferPostgres(db DBTX) *ScheduledTransferPostgres {
+	return &ScheduledTransferPostgres{db: db}
+}
+
+// Create сохраняет новый регулярный перевод в БД PostgreSQL и заполняет его ID, статус и время создания.
+// Если кошелек отправителя или получателя не существует, возвращает domain.ErrWalletNotFound.
+func (r *ScheduledTransferPostgres) Create(ctx context.Context, transfer *models.ScheduledTransfer) error {
+	query := `INSERT INTO scheduled_transfers (from_address, to_address, amount, convert_currency, schedule, next_run_at,
+			created_by_key_id, created_by_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, status, created_at`
+	err := r.db.QueryRowContext(ctx, query, transfer.From, transfer.To, transfer.Amount, transfer.Convert, transfer.Schedule, transfer.NextRunAt,
+		transfer.CreatedByKeyID, transfer.CreatedByUserID).
+		Scan(&transfer.ID, &transfer.Status, &transfer.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrWalletNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get возвращает регулярный перевод по ID из БД PostgreSQL.
+func (r *ScheduledTransferPostgres) Get(ctx context.Context, id int) (*models.ScheduledTransfer, error) {
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+// List возвращает из БД PostgreSQL регулярные переводы с кошельков wallets, отсортированные по ID в порядке убывания.
+// Если wallets равен nil, возвращаются все регулярные переводы.
+func (r *ScheduledTransferPostgres) List(ctx context.Context, wallets []string) ([]models.ScheduledTransfer, error) {
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers ORDER BY id DESC`
+	var args []interface{}
+	if wallets != nil {
+		query = `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers WHERE from_address = ANY($1) ORDER BY id DESC`
+		args = append(args, pq.Array(wallets))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := make([]models.ScheduledTransfer, 0)
+	for rows.Next() {
+		transfer, err := scanScheduledTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return transfers, nil
+}
+
+// Cancel отменяет активный регулярный перевод id в БД PostgreSQL и возвращает его.
+// Если перевод не найден, возвращает domain.ErrScheduledTransferNotFound, а если он уже отменен — domain.ErrScheduledTransferCancelled.
+func (r *ScheduledTransferPostgres) Cancel(ctx context.Context, id int) (*models.ScheduledTransfer, error) {
+	query := `UPDATE scheduled_transfers SET status = $1, cancelled_at = now(), retry_at = NULL
+		WHERE id = $2 AND status = $3 RETURNING ` + scheduledTransferColumns
+	transfer, err := r.get(ctx, query, models.ScheduledTransferStatusCancelled, id, models.ScheduledTransferStatusActive)
+	if errors.Is(err, domain.ErrScheduledTransferNotFound) {
+		// Перевод не изменен: либо его нет, либо он уже отменен.
+		if _, err := r.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrScheduledTransferCancelled
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// ClaimDue возвращает из БД PostgreSQL активный регулярный перевод с самым ранним наступившим к now сроком выполнения
+// и блокирует его строку до конца транзакции (SELECT ... FOR NO KEY UPDATE SKIP LOCKED). Переводы, заблокированные
+// другими транзакциями, пропускаются, поэтому несколько планировщиков не выполняют один перевод одновременно.
+// Блокировка не мешает вставке транзакций, ссылающихся на перевод. Если наступивших переводов нет, возвращает nil.
+func (r *ScheduledTransferPostgres) ClaimDue(ctx context.Context, now time.Time) (*models.ScheduledTransfer, error) {
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers
+		WHERE status = $1 AND COALESCE(retry_at, next_run_at) <= $2
+		ORDER BY COALESCE(retry_at, next_run_at), id LIMIT 1 FOR NO KEY UPDATE SKIP LOCKED`
+	transfer, err := r.get(ctx, query, models.ScheduledTransferStatusActive, now)
+	if errors.Is(err, domain.ErrScheduledTransferNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// Reschedule сохраняет в БД PostgreSQL срок следующего выполнения, время повторной попытки и число попыток регулярного перевода.
+func (r *ScheduledTransferPostgres) Reschedule(ctx context.Context, transfer models.ScheduledTransfer) error {
+	query := `UPDATE scheduled_transfers SET next_run_at = $1, retry_at = $2, attempts = $3 WHERE id = $4`
+	result, err := r.db.ExecContext(ctx, query, transfer.NextRunAt, transfer.RetryAt, transfer.Attempts, transfer.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrScheduledTransferNotFound
+	}
+	return nil
+}
+
+// CreateRun сохраняет итог попытки выполнить регулярный перевод в БД PostgreSQL.
+func (r *ScheduledTransferPostgres) CreateRun(ctx context.Context, run models.ScheduledTransferRun) error {
+	query := `INSERT INTO scheduled_transfer_runs (scheduled_transfer_id, scheduled_for, attempt, status, transaction_id, error)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))`
+	_, err := r.db.ExecContext(ctx, query, run.ScheduledTransferID, run.ScheduledFor, run.Attempt, run.Status, run.TransactionID, run.Error)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListRuns возвращает из БД PostgreSQL limit последних попыток выполнить регулярный перевод id, отсортированных по ID в порядке убывания.
+func (r *ScheduledTransferPostgres) ListRuns(ctx context.Context, id int, limit int) ([]models.ScheduledTransferRun, error) {
+	query := `SELECT ` + scheduledTransferRunColumns + ` FROM scheduled_transfer_runs
+		WHERE scheduled_transfer_id = $1 ORDER BY id DESC LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]models.ScheduledTransferRun, 0)
+	for rows.Next() {
+		var run models.ScheduledTransferRun
+		if err := rows.Scan(&run.ID, &run.ScheduledTransferID, &run.ScheduledFor, &run.Attempt, &run.Status, &run.TransactionID,
+			&run.Error, &run.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *ScheduledTransferPostgres) get(ctx context.Context, query string, args ...interface{}) (*models.ScheduledTransfer, error) {
+	transfer, err := scanScheduledTransfer(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrScheduledTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func scanScheduledTransfer(row rowScanner) (models.ScheduledTransfer, error) {
+	var t models.ScheduledTransfer
+	err := row.Scan(&t.ID, &t.From, &t.To, &t.Amount, &t.Convert, &t.Schedule, &t.Status, &t.NextRunAt, &t.RetryAt, &t.Attempts,
+		&t.CreatedAt, &t.CancelledAt, &t.CreatedByKeyID, &t.CreatedByUserID)
+	return t, err
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var scheduledTransferTestColumns = []string{"id", "from_address", "to_address", "amount", "convert_currency", "schedule", "status",
+	"next_run_at", "retry_at", "attempts", "created_at", "cancelled_at", "created_by_key_id", "created_by_user_id"}
+
+func TestScheduledTransferPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewScheduledTransferPostgres(db)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	nextRunAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	keyID := 7
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO scheduled_transfers (.+) RETURNING id, status, created_at").
+					WithArgs("from1", "to1", "50.00", false, "0 9 1 * *", nextRunAt, 7, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(3, "active", createdAt))
+			},
+		},
+		{
+			name: "Wallet Not Found",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO scheduled_transfers (.+) RETURNING id, status, created_at").
+					WithArgs("from1", "to1", "50.00", false, "0 9 1 * *", nextRunAt, 7, nil).
+					WillReturnError(&pq.Error{Code: "23503"})
+			},
+			wantErr: domain.ErrWalletNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			transfer := &models.ScheduledTransfer{From: "from1", To: "to1", Amount: money.FromInt(50), Schedule: "0 9 1 * *", NextRunAt: nextRunAt,
+				CreatedByKeyID: &keyID}
+			err := repo.Create(context.Background(), transfer)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 3, transfer.ID)
+				assert.Equal(t, models.ScheduledTransferStatusActive, transfer.Status)
+				assert.Equal(t, createdAt, transfer.CreatedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduledTransferPostgres_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewScheduledTransferPostgres(db)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	nextRunAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wallets []string
+		want    []models.ScheduledTransfer
+		wantErr bool
+	}{
+		{
+			name: "All",
+			mock: func() {
+				rows := sqlmock.NewRows(scheduledTransferTestColumns).
+					AddRow(3, "from1", "to1", "50.00", false, "0 9 1 * *", "active", nextRunAt, nil, 0, createdAt, nil, nil, nil)
+				mock.ExpectQuery("SELECT (.+) FROM scheduled_transfers ORDER BY id DESC").
+					WithoutArgs().
+					WillReturnRows(rows)
+			},
+			want: []models.ScheduledTransfer{
+				{ID: 3, From: "from1", To: "to1", Amount: money.FromInt(50), Schedule: "0 9 1 * *", Status: models.ScheduledTransferStatusActive,
+					NextRunAt: nextRunAt, CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "By Wallets",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM scheduled_transfers WHERE from_address = ANY\\(\\$1\\) ORDER BY id DESC").
+					WithArgs(pq.Array([]string{"from2"})).
+					WillReturnRows(sqlmock.NewRows(scheduledTransferTestColumns))
+			},
+			wallets: []string{"from2"},
+			want:    []models.ScheduledTransfer{},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM scheduled_transfers ORDER BY id DESC").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.List(context.Background(), tt.wallets)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduledTransferPostgres_Cancel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewScheduledTransferPostgres(db)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	nextRunAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	cancelledAt := time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.ScheduledTransfer
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(scheduledTransferTestColumns).
+					AddRow(3, "from1", "to1", "50.00", false, "0 9 1 * *", "cancelled", nextRunAt, nil, 0, createdAt, cancelledAt, nil, nil)
+				mock.ExpectQuery("UPDATE scheduled_transfers SET status = \\$1, cancelled_at = now\\(\\), retry_at = NULL WHERE id = \\$2 AND status = \\$3 RETURNING").
+					WithArgs(models.ScheduledTransferStatusCancelled, 3, models.ScheduledTransferStatusActive).
+					WillReturnRows(rows)
+			},
+			want: &models.ScheduledTransfer{ID: 3, From: "from1", To: "to1", Amount: money.FromInt(50), Schedule: "0 9 1 * *",
+				Status: models.ScheduledTransferStatusCancelled, NextRunAt: nextRunAt, CreatedAt: createdAt, CancelledAt: &cancelledAt},
+		},
+		{
+			name: "Already Cancelled",
+			mock: func() {
+				mock.ExpectQuery("UPDATE scheduled_transfers").
+					WithArgs(models.ScheduledTransferStatusCancelled, 3, models.ScheduledTransferStatusActive).
+					WillReturnRows(sqlmock.NewRows(scheduledTransferTestColumns))
+				rows := sqlmock.NewRows(scheduledTransferTestColumns).
+					AddRow(3, "from1", "to1", "50.00", false, "0 9 1 * *", "cancelled", nextRunAt, nil, 0, createdAt, cancelledAt, nil, nil)
+				mock.ExpectQuery("SELECT (.+) FROM scheduled_transfers WHERE id = \\$1").
+					WithArgs(3).
+					WillReturnRows(rows)
+			},
+			wantErr: domain.ErrScheduledTransferCancelled,
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("UPDATE scheduled_transfers").
+					WithArgs(models.ScheduledTransferStatusCancelled, 3, models.ScheduledTransferStatusActive).
+					WillReturnRows(sqlmock.NewRows(scheduledTransferTestColumns))
+				mock.ExpectQuery("SELECT (.+) FROM scheduled_transfers WHERE id = \\$1").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows(scheduledTransferTestColumns))
+			},
+			wantErr: domain.ErrScheduledTransferNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Cancel(context.Background(), 3)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduledTransferPostgres_ClaimDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewScheduledTransferPostgres(db)
+	now := time.Date(2025, 2, 1, 9, 0, 30, 0, time.UTC)
+	createdAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	nextRunAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	retryAt := time.Date(2025, 2, 1, 9, 0, 15, 0, time.UTC)
+	userID := 4
+	query := "(?s)SELECT (.+) FROM scheduled_transfers\\s+WHERE status = \\$1 AND COALESCE\\(retry_at, next_run_at\\) <= \\$2" +
+		".+ LIMIT 1 FOR NO KEY UPDATE SKIP LOCKED"
+
+	tests := []struct {
+		name string
+		mock func()
+		want *models.ScheduledTransfer
+	}{
+		{
+			name: "Due",
+			mock: func() {
+				rows := sqlmock.NewRows(scheduledTransferTestColumns).
+					AddRow(3, "from1", "to1", "50.00", true, "0 9 1 * *", "active", nextRunAt, retryAt, 1, createdAt, nil, nil, 4)
+				mock.ExpectQuery(query).
+					WithArgs(models.ScheduledTransferStatusActive, now).
+					WillReturnRows(rows)
+			},
+			want: &models.ScheduledTransfer{ID: 3, From: "from1", To: "to1", Amount: money.FromInt(50), Convert: true, Schedule: "0 9 1 * *",
+				Status: models.ScheduledTransferStatusActive, NextRunAt: nextRunAt, RetryAt: &retryAt, Attempts: 1, CreatedAt: createdAt,
+				CreatedByUserID: &userID},
+		},
+		{
+			name: "Nothing Due",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs(models.ScheduledTransferStatusActive, now).
+					WillReturnRows(sqlmock.NewRows(scheduledTransferTestColumns))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.ClaimDue(context.Background(), now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduledTransferPostgres_Reschedule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewScheduledTransferPostgres(db)
+	nextRunAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE scheduled_transfers SET next_run_at = \\$1, retry_at = \\$2, attempts = \\$3 WHERE id = \\$4").
+		WithArgs(nextRunAt, nil, 0, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE scheduled_transfers SET next_run_at").
+		WithArgs(nextRunAt, nil, 0, 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.Reschedule(context.Background(), models.ScheduledTransfer{ID: 3, NextRunAt: nextRunAt}))
+	assert.ErrorIs(t, repo.Reschedule(context.Background(), models.ScheduledTransfer{ID: 4, NextRunAt: nextRunAt}), domain.ErrScheduledTransferNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduledTransferPostgres_Runs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewScheduledTransferPostgres(db)
+	scheduledFor := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 2, 1, 9, 0, 5, 0, time.UTC)
+	transactionID := 42
+
+	mock.ExpectExec("INSERT INTO scheduled_transfer_runs").
+		WithArgs(3, scheduledFor, 1, models.ScheduledTransferRunCompleted, 42, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	rows := sqlmock.NewRows([]string{"id", "scheduled_transfer_id", "scheduled_for", "attempt", "status", "transaction_id", "error", "created_at"}).
+		AddRow(2, 3, scheduledFor, 2, "completed", 42, "", createdAt).
+		AddRow(1, 3, scheduledFor, 1, "retrying", nil, "db error", createdAt)
+	mock.ExpectQuery("SELECT (.+) FROM scheduled_transfer_runs\\s+WHERE scheduled_transfer_id = \\$1 ORDER BY id DESC LIMIT \\$2").
+		WithArgs(3, 20).
+		WillReturnRows(rows)
+
+	err = repo.CreateRun(context.Background(), models.ScheduledTransferRun{
+		ScheduledTransferID: 3,
+		ScheduledFor:        scheduledFor,
+		Attempt:             1,
+		Status:              models.ScheduledTransferRunCompleted,
+		TransactionID:       &transactionID,
+	})
+	assert.NoError(t, err)
+
+	runs, err := repo.ListRuns(context.Background(), 3, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ScheduledTransferRun{
+		{ID: 2, ScheduledTransferID: 3, ScheduledFor: scheduledFor, Attempt: 2, Status: models.ScheduledTransferRunCompleted, TransactionID: &transactionID, CreatedAt: createdAt},
+		{ID: 1, ScheduledTransferID: 3, ScheduledFor: scheduledFor, Attempt: 1, Status: models.ScheduledTransferRunRetrying, Error: "db error", CreatedAt: createdAt},
+	}, runs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,44 +1,229 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const transactionColumns = `id, from_address, to_address, amount, COALESCE(currency, ''), status, COALESCE(failure_reason, ''), created_at, completed_at, reversal_of, scheduled_transfer_id, scheduled_for`
+
+// Уникальные индексы транзакций, нарушение которых означает повтор уже выполненной операции.
+const (
+	transactionReversalIndex     = "idx_transactions_reversal_of"
+	transactionScheduledRunIndex = "idx_transactions_scheduled_run"
 )
 
 type TransactionPostgres struct {
-	db *sql.DB
+	db DBTX
 }
 
 // NewTransactionPostgres создает новый экземпляр TransactionPostgres.
-func NewTransactionPostgres(db *sql.DB) *TransactionPostgres {
+func NewTransactionPostgres(db DBTX) *TransactionPostgres {
 	return &TransactionPostgres{db: db}
 }
 
-// Create сохраняет новую транзакцию в БД PostgreSQL.
-func (r *TransactionPostgres) Create(transaction models.Transaction) error {
-	query := `INSERT INTO transactions (from_address, to_address, amount) VALUES ($1, $2, $3)`
-	_, err := r.db.Exec(query, transaction.From, transaction.To, transaction.Amount)
+// Create сохраняет новую транзакцию в БД PostgreSQL и возвращает ее ID.
+// Время завершения проставляется для всех статусов, кроме pending.
+// Повторная отмена перевода возвращается как domain.ErrTransactionAlreadyReversed, а повторное выполнение
+// регулярного перевода за тот же срок — как domain.ErrScheduledTransferExecuted.
+func (r *TransactionPostgres) Create(ctx context.Context, transaction models.Transaction) (int, error) {
+	query := `INSERT INTO transactions (from_address, to_address, amount, currency, status, failure_reason, completed_at, reversal_of,
+		scheduled_transfer_id, scheduled_for)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), CASE WHEN $7 THEN now() END, $8, $9, $10) RETURNING id`
+	var id int
+	err := r.db.QueryRowContext(ctx, query, transaction.From, transaction.To, transaction.Amount, transaction.Currency, transaction.Status,
+		transaction.FailureReason, transaction.Status != models.TransactionStatusPending, transaction.ReversalOf,
+		transaction.ScheduledTransferID, transaction.ScheduledFor).Scan(&id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		switch pqErr.Constraint {
+		case transactionReversalIndex:
+			return 0, domain.ErrTransactionAlreadyReversed
+		case transactionScheduledRunIndex:
+			return 0, domain.ErrScheduledTransferExecuted
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetForUpdate возвращает транзакцию id из БД PostgreSQL, блокируя ее строку (SELECT ... FOR UPDATE).
+// Блокировка действует до конца транзакции, поэтому метод имеет смысл вызывать только внутри UnitOfWork.WithTx.
+func (r *TransactionPostgres) GetForUpdate(ctx context.Context, id int) (*models.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1 FOR UPDATE`
+	t, err := scanTransaction(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpdateStatus изменяет статус транзакции id в БД PostgreSQL.
+func (r *TransactionPostgres) UpdateStatus(ctx context.Context, id int, status models.TransactionStatus) error {
+	query := `UPDATE transactions SET status = $1 WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// CreateFee сохраняет строку комиссии в БД PostgreSQL.
+func (r *TransactionPostgres) CreateFee(ctx context.Context, fee models.TransactionFee) error {
+	query := `INSERT INTO transaction_fees (transaction_id, wallet_address, amount) VALUES ($1, $2, $3)`
+	_, err := r.db.ExecContext(ctx, query, fee.TransactionID, fee.Wallet, fee.Amount)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// Getlast возвращает count последних транзакций из БД PostgreSQL, отсортированных по ID в порядке убывания.
-func (r *TransactionPostgres) Getlast(count int) ([]models.Transaction, error) {
-	query := `SELECT id, from_address, to_address, amount FROM transactions ORDER BY id DESC LIMIT $1`
+// CreateConversion сохраняет части перевода с конвертацией в БД PostgreSQL.
+func (r *TransactionPostgres) CreateConversion(ctx context.Context, conversion models.TransactionConversion) error {
+	query := `INSERT INTO transaction_conversions
+		(transaction_id, debit_currency, debit_amount, credit_currency, credit_amount, mid_rate, rate, spread_bp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := r.db.ExecContext(ctx, query, conversion.TransactionID, conversion.DebitCurrency, conversion.DebitAmount,
+		conversion.CreditCurrency, conversion.CreditAmount, conversion.MidRate, conversion.Rate, conversion.SpreadBP)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateQuote сохраняет использование предварительного расчета в БД PostgreSQL. Одноразовое значение расчета —
+// первичный ключ, поэтому повторное использование расчета, в том числе параллельным переводом, возвращается
+// как domain.ErrQuoteUsed.
+func (r *TransactionPostgres) CreateQuote(ctx context.Context, quote models.TransactionQuote) error {
+	query := `INSERT INTO transaction_quotes (nonce, transaction_id) VALUES ($1, $2)`
+	_, err := r.db.ExecContext(ctx, query, quote.Nonce, quote.TransactionID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrQuoteUsed
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetConversion возвращает из БД PostgreSQL части перевода с конвертацией transactionID.
+func (r *TransactionPostgres) GetConversion(ctx context.Context, transactionID int) (*models.TransactionConversion, error) {
+	query := `SELECT id, transaction_id, debit_currency, debit_amount, credit_currency, credit_amount, mid_rate, rate, spread_bp, created_at
+		FROM transaction_conversions WHERE transaction_id = $1`
+	var c models.TransactionConversion
+	err := r.db.QueryRowContext(ctx, query, transactionID).Scan(&c.ID, &c.TransactionID, &c.DebitCurrency, &c.DebitAmount,
+		&c.CreditCurrency, &c.CreditAmount, &c.MidRate, &c.Rate, &c.SpreadBP, &c.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversion of transaction %d: %w", transactionID, err)
+	}
+	return &c, nil
+}
+
+// Getlast возвращает count последних транзакций из БД PostgreSQL, отсортированных по времени создания в порядке убывания.
+func (r *TransactionPostgres) Getlast(ctx context.Context, count int) ([]models.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions ORDER BY created_at DESC, id DESC LIMIT $1`
+	return r.query(ctx, query, count)
+}
+
+// List возвращает из БД PostgreSQL транзакции, подходящие под filter, отсортированные по ID в порядке убывания.
+func (r *TransactionPostgres) List(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Wallet != "" {
+		switch filter.Role {
+		case models.TransactionRoleSender:
+			conditions = append(conditions, "from_address = "+arg(filter.Wallet))
+		case models.TransactionRoleRecipient:
+			conditions = append(conditions, "to_address = "+arg(filter.Wallet)+" AND status <> "+arg(models.TransactionStatusFailed))
+		default:
+			placeholder := arg(filter.Wallet)
+			conditions = append(conditions, "(from_address = "+placeholder+" OR (to_address = "+placeholder+" AND status <> "+arg(models.TransactionStatusFailed)+"))")
+		}
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+	if filter.MinAmount != nil {
+		conditions = append(conditions, "amount >= "+arg(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		conditions = append(conditions, "amount <= "+arg(*filter.MaxAmount))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < "+arg(*filter.CreatedTo))
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "id < "+arg(filter.BeforeID))
+	}
+
+	query := `SELECT ` + transactionColumns + ` FROM transactions`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT " + arg(filter.Limit)
+
+	return r.query(ctx, query, args...)
+}
+
+// OutgoingSince возвращает сводку переводов с кошелька address, созданных не раньше since: завершенных и отмененных.
+// Отмененный перевод учитывается, чтобы перевод с последующей отменой не освобождал лимит повторно,
+// а компенсирующие транзакции отмены не учитываются, так как их инициирует не владелец кошелька.
+// Неудачные переводы не учитываются, так как не списывают средства.
+func (r *TransactionPostgres) OutgoingSince(ctx context.Context, address string, since time.Time) (models.TransferActivity, error) {
+	query := `SELECT count(*), COALESCE(sum(amount), 0), min(created_at) FROM transactions
+		WHERE from_address = $1 AND status IN ($2, $3) AND reversal_of IS NULL AND created_at >= $4`
+	var activity models.TransferActivity
+	var first sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, address, models.TransactionStatusCompleted, models.TransactionStatusReversed, since).
+		Scan(&activity.Count, &activity.Volume, &first)
+	if err != nil {
+		return models.TransferActivity{}, fmt.Errorf("failed to get outgoing transfers: %w", err)
+	}
+	activity.First = first.Time
+	return activity, nil
+}
+
+func (r *TransactionPostgres) query(ctx context.Context, query string, args ...interface{}) ([]models.Transaction, error) {
 	transactions := make([]models.Transaction, 0)
 
-	rows, err := r.db.Query(query, count)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var t models.Transaction
-		if err := rows.Scan(&t.ID, &t.From, &t.To, &t.Amount); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		transactions = append(transactions, t)
@@ -49,3 +234,11 @@ func (r *TransactionPostgres) Getlast(count int) ([]models.Transaction, error) {
 	}
 	return transactions, nil
 }
+
+// scanTransaction читает строку, выбранную по столбцам transactionColumns.
+func scanTransaction(row rowScanner) (models.Transaction, error) {
+	var t models.Transaction
+	err := row.Scan(&t.ID, &t.From, &t.To, &t.Amount, &t.Currency, &t.Status, &t.FailureReason, &t.CreatedAt, &t.CompletedAt, &t.ReversalOf,
+		&t.ScheduledTransferID, &t.ScheduledFor)
+	return t, err
+}
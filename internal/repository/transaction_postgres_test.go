@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,49 +23,297 @@ func TestTransactionPostgres_Create(t *testing.T) {
 	defer db.Close()
 
 	repo := NewTransactionPostgres(db)
+	reversalOf := 3
+	scheduledTransferID := 3
+	scheduledFor := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
 
 	tests := []struct {
-		name    string
-		mock    func()
-		input   models.Transaction
-		wantErr bool
+		name        string
+		mock        func()
+		input       models.Transaction
+		wantErr     bool
+		expectedErr error
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				mock.ExpectExec("INSERT INTO transactions").
-					WithArgs("from1", "to1", 10.5).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("INSERT INTO transactions (.+) RETURNING id").
+					WithArgs("from1", "to1", "10.50", "USD", models.TransactionStatusCompleted, "", true, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+			},
+			input: models.Transaction{
+				From:     "from1",
+				To:       "to1",
+				Amount:   money.MustParse("10.50"),
+				Currency: "USD",
+				Status:   models.TransactionStatusCompleted,
+			},
+		},
+		{
+			name: "Failed Attempt",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO transactions (.+) RETURNING id").
+					WithArgs("from1", "to1", "10.50", "", models.TransactionStatusFailed, "insufficient funds", true, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+			},
+			input: models.Transaction{
+				From:          "from1",
+				To:            "to1",
+				Amount:        money.MustParse("10.50"),
+				Status:        models.TransactionStatusFailed,
+				FailureReason: "insufficient funds",
+			},
+		},
+		{
+			name: "Pending",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO transactions (.+) RETURNING id").
+					WithArgs("from1", "to1", "10.50", "", models.TransactionStatusPending, "", false, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
 			},
 			input: models.Transaction{
 				From:   "from1",
 				To:     "to1",
-				Amount: 10.5,
+				Amount: money.MustParse("10.50"),
+				Status: models.TransactionStatusPending,
+			},
+		},
+		{
+			name: "Reversal",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO transactions (.+) RETURNING id").
+					WithArgs("to1", "from1", "5.00", "USD", models.TransactionStatusCompleted, "", true, 3, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+			},
+			input: models.Transaction{
+				From:       "to1",
+				To:         "from1",
+				Amount:     money.MustParse("5.00"),
+				Currency:   "USD",
+				Status:     models.TransactionStatusCompleted,
+				ReversalOf: &reversalOf,
 			},
 		},
 		{
 			name: "Empty Fields",
 			mock: func() {
-				mock.ExpectExec("INSERT INTO transactions").
-					WithArgs("", "to1", 10.5).
+				mock.ExpectQuery("INSERT INTO transactions (.+) RETURNING id").
+					WithArgs("", "to1", "10.50", "", models.TransactionStatusCompleted, "", true, nil, nil, nil).
 					WillReturnError(errors.New("empty from address"))
 			},
 			input: models.Transaction{
 				From:   "",
 				To:     "to1",
-				Amount: 10.5,
+				Amount: money.MustParse("10.50"),
+				Status: models.TransactionStatusCompleted,
 			},
 			wantErr: true,
 		},
+		{
+			name: "Scheduled Run Already Executed",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO transactions (.+) RETURNING id").
+					WithArgs("from1", "to1", "50.00", "USD", models.TransactionStatusCompleted, "", true, nil, 3, scheduledFor).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_transactions_scheduled_run"})
+			},
+			input: models.Transaction{
+				From:                "from1",
+				To:                  "to1",
+				Amount:              money.MustParse("50.00"),
+				Currency:            "USD",
+				Status:              models.TransactionStatusCompleted,
+				ScheduledTransferID: &scheduledTransferID,
+				ScheduledFor:        &scheduledFor,
+			},
+			wantErr:     true,
+			expectedErr: domain.ErrScheduledTransferExecuted,
+		},
+		{
+			name: "Already Reversed",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO transactions (.+) RETURNING id").
+					WithArgs("to1", "from1", "5.00", "USD", models.TransactionStatusCompleted, "", true, 3, nil, nil).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_transactions_reversal_of"})
+			},
+			input: models.Transaction{
+				From:       "to1",
+				To:         "from1",
+				Amount:     money.MustParse("5.00"),
+				Currency:   "USD",
+				Status:     models.TransactionStatusCompleted,
+				ReversalOf: &reversalOf,
+			},
+			wantErr:     true,
+			expectedErr: domain.ErrTransactionAlreadyReversed,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := repo.Create(tt.input)
+			id, err := repo.Create(context.Background(), tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 7, id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPostgres_CreateFee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+	fee := models.TransactionFee{TransactionID: 7, Wallet: "fees", Amount: money.MustParse("0.30")}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO transaction_fees").
+					WithArgs(7, "fees", "0.30").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO transaction_fees").
+					WithArgs(7, "fees", "0.30").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.CreateFee(context.Background(), fee)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPostgres_CreateConversion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+	conversion := models.TransactionConversion{
+		TransactionID:  7,
+		DebitCurrency:  "USD",
+		DebitAmount:    money.MustParse("10.50"),
+		CreditCurrency: "EUR",
+		CreditAmount:   money.MustParse("9.61"),
+		MidRate:        money.MustParseRate("0.92"),
+		Rate:           money.MustParseRate("0.9154"),
+		SpreadBP:       50,
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO transaction_conversions").
+					WithArgs(7, "USD", "10.50", "EUR", "9.61", "0.92000000", "0.91540000", 50).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO transaction_conversions").
+					WithArgs(7, "USD", "10.50", "EUR", "9.61", "0.92000000", "0.91540000", 50).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.CreateConversion(context.Background(), conversion)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPostgres_CreateQuote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO transaction_quotes \\(nonce, transaction_id\\) VALUES \\(\\$1, \\$2\\)").
+					WithArgs("q1", 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Already Used",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO transaction_quotes").
+					WithArgs("q1", 7).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "transaction_quotes_pkey"})
+			},
+			wantErr: domain.ErrQuoteUsed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.CreateQuote(context.Background(), models.TransactionQuote{TransactionID: 7, Nonce: "q1"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -77,6 +330,7 @@ func TestTransactionPostgres_Getlast(t *testing.T) {
 	defer db.Close()
 
 	repo := NewTransactionPostgres(db)
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name    string
@@ -88,26 +342,26 @@ func TestTransactionPostgres_Getlast(t *testing.T) {
 		{
 			name: "OK",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "from_address", "to_address", "amount"}).
-					AddRow(1, "from1", "to1", 10.5).
-					AddRow(2, "from2", "to2", 20.0)
+				rows := sqlmock.NewRows([]string{"id", "from_address", "to_address", "amount", "currency", "status", "failure_reason", "created_at", "completed_at", "reversal_of", "scheduled_transfer_id", "scheduled_for"}).
+					AddRow(1, "from1", "to1", "10.50", "USD", "completed", "", createdAt, createdAt, nil, nil, nil).
+					AddRow(2, "from2", "to2", "20.00", "", "failed", "insufficient funds", createdAt, createdAt, nil, nil, nil)
 
-				mock.ExpectQuery("SELECT id, from_address, to_address, amount FROM transactions ORDER BY id DESC LIMIT \\$1").
+				mock.ExpectQuery("SELECT (.+) FROM transactions ORDER BY created_at DESC, id DESC LIMIT \\$1").
 					WithArgs(2).
 					WillReturnRows(rows)
 			},
 			input: 2,
 			want: []models.Transaction{
-				{ID: 1, From: "from1", To: "to1", Amount: 10.5},
-				{ID: 2, From: "from2", To: "to2", Amount: 20.0},
+				{ID: 1, From: "from1", To: "to1", Amount: money.MustParse("10.50"), Currency: "USD", Status: models.TransactionStatusCompleted, CreatedAt: createdAt, CompletedAt: &createdAt},
+				{ID: 2, From: "from2", To: "to2", Amount: money.MustParse("20.00"), Status: models.TransactionStatusFailed, FailureReason: "insufficient funds", CreatedAt: createdAt, CompletedAt: &createdAt},
 			},
 		},
 		{
 			name: "Empty Result",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "from_address", "to_address", "amount"})
+				rows := sqlmock.NewRows([]string{"id", "from_address", "to_address", "amount", "currency", "status", "failure_reason", "created_at", "completed_at", "reversal_of", "scheduled_transfer_id", "scheduled_for"})
 
-				mock.ExpectQuery("SELECT id, from_address, to_address, amount FROM transactions ORDER BY id DESC LIMIT \\$1").
+				mock.ExpectQuery("SELECT (.+) FROM transactions ORDER BY created_at DESC, id DESC LIMIT \\$1").
 					WithArgs(2).
 					WillReturnRows(rows)
 			},
@@ -117,7 +371,7 @@ func TestTransactionPostgres_Getlast(t *testing.T) {
 		{
 			name: "Database Error",
 			mock: func() {
-				mock.ExpectQuery("SELECT id, from_address, to_address, amount FROM transactions ORDER BY id DESC LIMIT \\$1").
+				mock.ExpectQuery("SELECT (.+) FROM transactions ORDER BY created_at DESC, id DESC LIMIT \\$1").
 					WithArgs(2).
 					WillReturnError(errors.New("db error"))
 			},
@@ -130,7 +384,7 @@ func TestTransactionPostgres_Getlast(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repo.Getlast(tt.input)
+			got, err := repo.Getlast(context.Background(), tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -144,3 +398,342 @@ func TestTransactionPostgres_Getlast(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionPostgres_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	minAmount := money.MustParse("1.00")
+	maxAmount := money.MustParse("100.00")
+	columns := []string{"id", "from_address", "to_address", "amount", "currency", "status", "failure_reason", "created_at", "completed_at", "reversal_of", "scheduled_transfer_id", "scheduled_for"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		input   models.TransactionFilter
+		want    []models.Transaction
+		wantErr bool
+	}{
+		{
+			name: "No Filters",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "from2", "to2", "20.00", "", "failed", "insufficient funds", createdAt, createdAt, nil, nil, nil)
+				mock.ExpectQuery("SELECT (.+) FROM transactions ORDER BY id DESC LIMIT \\$1").
+					WithArgs(10).
+					WillReturnRows(rows)
+			},
+			input: models.TransactionFilter{Limit: 10},
+			want: []models.Transaction{
+				{ID: 2, From: "from2", To: "to2", Amount: money.MustParse("20.00"), Status: models.TransactionStatusFailed, FailureReason: "insufficient funds", CreatedAt: createdAt, CompletedAt: &createdAt},
+			},
+		},
+		{
+			name: "Wallet As Either Party",
+			mock: func() {
+				rows := sqlmock.NewRows(columns)
+				mock.ExpectQuery("WHERE \\(from_address = \\$1 OR \\(to_address = \\$1 AND status <> \\$2\\)\\) AND id < \\$3 ORDER BY id DESC LIMIT \\$4").
+					WithArgs("addr1", models.TransactionStatusFailed, 50, 10).
+					WillReturnRows(rows)
+			},
+			input: models.TransactionFilter{Wallet: "addr1", Role: models.TransactionRoleAny, BeforeID: 50, Limit: 10},
+			want:  []models.Transaction{},
+		},
+		{
+			name: "All Filters",
+			mock: func() {
+				rows := sqlmock.NewRows(columns)
+				mock.ExpectQuery("WHERE to_address = \\$1 AND status <> \\$2 AND status = \\$3 AND amount >= \\$4 AND amount <= \\$5 AND created_at >= \\$6 AND created_at < \\$7 ORDER BY id DESC LIMIT \\$8").
+					WithArgs("addr1", models.TransactionStatusFailed, models.TransactionStatusCompleted, "1.00", "100.00", createdAt, createdAt.Add(time.Hour), 5).
+					WillReturnRows(rows)
+			},
+			input: models.TransactionFilter{
+				Wallet:      "addr1",
+				Role:        models.TransactionRoleRecipient,
+				Status:      models.TransactionStatusCompleted,
+				MinAmount:   &minAmount,
+				MaxAmount:   &maxAmount,
+				CreatedFrom: &createdAt,
+				CreatedTo:   func() *time.Time { t := createdAt.Add(time.Hour); return &t }(),
+				Limit:       5,
+			},
+			want: []models.Transaction{},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("WHERE from_address = \\$1").
+					WithArgs("addr1", 10).
+					WillReturnError(errors.New("db error"))
+			},
+			input:   models.TransactionFilter{Wallet: "addr1", Role: models.TransactionRoleSender, Limit: 10},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.List(context.Background(), tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPostgres_OutgoingSince(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    models.TransferActivity
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"count", "sum", "min"}).AddRow(3, "150.25", first)
+				mock.ExpectQuery("SELECT count\\(\\*\\), COALESCE\\(sum\\(amount\\), 0\\), min\\(created_at\\) FROM transactions").
+					WithArgs("from", models.TransactionStatusCompleted, models.TransactionStatusReversed, since).
+					WillReturnRows(rows)
+			},
+			want: models.TransferActivity{Count: 3, Volume: money.MustParse("150.25"), First: first},
+		},
+		{
+			// Отмененные переводы учитываются, а компенсирующие транзакции отмены — нет.
+			name: "Reversed Transfers",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"count", "sum", "min"}).AddRow(2, "80.00", first)
+				mock.ExpectQuery("WHERE from_address = \\$1 AND status IN \\(\\$2, \\$3\\) AND reversal_of IS NULL AND created_at >= \\$4").
+					WithArgs("from", models.TransactionStatusCompleted, models.TransactionStatusReversed, since).
+					WillReturnRows(rows)
+			},
+			want: models.TransferActivity{Count: 2, Volume: money.MustParse("80.00"), First: first},
+		},
+		{
+			name: "No Transfers",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"count", "sum", "min"}).AddRow(0, "0", nil)
+				mock.ExpectQuery("SELECT count\\(\\*\\), COALESCE\\(sum\\(amount\\), 0\\), min\\(created_at\\) FROM transactions").
+					WithArgs("from", models.TransactionStatusCompleted, models.TransactionStatusReversed, since).
+					WillReturnRows(rows)
+			},
+			want: models.TransferActivity{},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT count\\(\\*\\), COALESCE\\(sum\\(amount\\), 0\\), min\\(created_at\\) FROM transactions").
+					WithArgs("from", models.TransactionStatusCompleted, models.TransactionStatusReversed, since).
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.OutgoingSince(context.Background(), "from", since)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPostgres_GetForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "from_address", "to_address", "amount", "currency", "status", "failure_reason", "created_at", "completed_at", "reversal_of", "scheduled_transfer_id", "scheduled_for"}
+	reversalOf := 3
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.Transaction
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(7, "from1", "to1", "5.00", "USD", "completed", "", createdAt, createdAt, 3, nil, nil)
+				mock.ExpectQuery("SELECT (.+) FROM transactions WHERE id = \\$1 FOR UPDATE").
+					WithArgs(7).
+					WillReturnRows(rows)
+			},
+			want: &models.Transaction{ID: 7, From: "from1", To: "to1", Amount: money.MustParse("5.00"), Currency: "USD",
+				Status: models.TransactionStatusCompleted, CreatedAt: createdAt, CompletedAt: &createdAt, ReversalOf: &reversalOf},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM transactions WHERE id = \\$1 FOR UPDATE").
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetForUpdate(context.Background(), 7)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPostgres_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE transactions SET status = \\$1 WHERE id = \\$2").
+					WithArgs(models.TransactionStatusReversed, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE transactions SET status = \\$1 WHERE id = \\$2").
+					WithArgs(models.TransactionStatusReversed, 7).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.UpdateStatus(context.Background(), 7, models.TransactionStatusReversed)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPostgres_GetConversion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTransactionPostgres(db)
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "transaction_id", "debit_currency", "debit_amount", "credit_currency", "credit_amount", "mid_rate", "rate", "spread_bp", "created_at"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.TransactionConversion
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 7, "USD", "10.50", "EUR", "9.61", "0.92000000", "0.91540000", 50, createdAt)
+				mock.ExpectQuery("SELECT (.+) FROM transaction_conversions WHERE transaction_id = \\$1").
+					WithArgs(7).
+					WillReturnRows(rows)
+			},
+			want: &models.TransactionConversion{
+				ID:             1,
+				TransactionID:  7,
+				DebitCurrency:  "USD",
+				DebitAmount:    money.MustParse("10.50"),
+				CreditCurrency: "EUR",
+				CreditAmount:   money.MustParse("9.61"),
+				MidRate:        money.MustParseRate("0.92"),
+				Rate:           money.MustParseRate("0.9154"),
+				SpreadBP:       50,
+				CreatedAt:      createdAt,
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM transaction_conversions WHERE transaction_id = \\$1").
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetConversion(context.Background(), 7)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
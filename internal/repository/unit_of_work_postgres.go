@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX — общий интерфейс *sql.DB и *sql.Tx, позволяющий репозиториям работать как вне транзакции, так и внутри нее.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type UnitOfWorkPostgres struct {
+	db *sql.DB
+}
+
+// NewUnitOfWorkPostgres создает новый экземпляр UnitOfWorkPostgres.
+func NewUnitOfWorkPostgres(db *sql.DB) *UnitOfWorkPostgres {
+	return &UnitOfWorkPostgres{db: db}
+}
+
+// WithTx выполняет fn в транзакции PostgreSQL. Репозитории, переданные в fn, работают внутри этой транзакции.
+// Если fn возвращает ошибку или паникует, транзакция откатывается, иначе фиксируется.
+// Отмена ctx прерывает выполняющиеся запросы и откатывает транзакцию.
+func (u *UnitOfWorkPostgres) WithTx(ctx context.Context, fn func(repos *Repository) error) (err error) {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	repos := newTxRepository(tx)
+	if err := fn(repos); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// newTxRepository создает Repository, все репозитории которого работают внутри транзакции tx.
+func newTxRepository(tx *sql.Tx) *Repository {
+	repos := &Repository{
+		Wallet:            NewWalletPostgres(tx),
+		WalletTier:        NewWalletTierPostgres(tx),
+		Transaction:       NewTransactionPostgres(tx),
+		Ledger:            NewLedgerPostgres(tx),
+		ScheduledTransfer: NewScheduledTransferPostgres(tx),
+		Hold:              NewHoldPostgres(tx),
+		Idempotency:       NewIdempotencyPostgres(tx),
+		APIKey:            NewAPIKeyPostgres(tx),
+		User:              NewUserPostgres(tx),
+		RefreshToken:      NewRefreshTokenPostgres(tx),
+	}
+	repos.UnitOfWork = nestedUnitOfWork{repos: repos}
+	return repos
+}
+
+// nestedUnitOfWork присоединяет вложенный вызов WithTx к уже открытой транзакции.
+// Контекст вложенного вызова не используется: транзакция остается привязанной к контексту внешнего вызова.
+type nestedUnitOfWork struct {
+	repos *Repository
+}
+
+func (n nestedUnitOfWork) WithTx(_ context.Context, fn func(repos *Repository) error) error {
+	return fn(n.repos)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWorkPostgres_WithTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	uow := NewUnitOfWorkPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		fn      func(repos *Repository) error
+		wantErr bool
+	}{
+		{
+			name: "Commit",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE wallets").
+					WithArgs(models.WalletStatusFrozen, "addr1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("INSERT INTO transactions").
+					WithArgs("addr1", "addr2", "50.00", "USD", models.TransactionStatusCompleted, "", true, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
+			},
+			fn: func(repos *Repository) error {
+				if err := repos.Wallet.UpdateStatus(context.Background(), "addr1", models.WalletStatusFrozen); err != nil {
+					return err
+				}
+				_, err := repos.Transaction.Create(context.Background(), models.Transaction{From: "addr1", To: "addr2", Amount: money.MustParse("50.00"), Currency: "USD", Status: models.TransactionStatusCompleted})
+				return err
+			},
+		},
+		{
+			name: "Rollback On Error",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE wallets").
+					WithArgs(models.WalletStatusFrozen, "addr1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("INSERT INTO transactions").
+					WithArgs("addr1", "addr2", "50.00", "USD", models.TransactionStatusCompleted, "", true, nil, nil, nil).
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+			},
+			fn: func(repos *Repository) error {
+				if err := repos.Wallet.UpdateStatus(context.Background(), "addr1", models.WalletStatusFrozen); err != nil {
+					return err
+				}
+				_, err := repos.Transaction.Create(context.Background(), models.Transaction{From: "addr1", To: "addr2", Amount: money.MustParse("50.00"), Currency: "USD", Status: models.TransactionStatusCompleted})
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "Nested WithTx Joins Outer Transaction",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE wallets").
+					WithArgs(models.WalletStatusFrozen, "addr1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(repos *Repository) error {
+				return repos.UnitOfWork.WithTx(context.Background(), func(inner *Repository) error {
+					return inner.Wallet.UpdateStatus(context.Background(), "addr1", models.WalletStatusFrozen)
+				})
+			},
+		},
+		{
+			name: "Begin Failed",
+			mock: func() {
+				mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+			},
+			fn: func(repos *Repository) error {
+				return nil
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := uow.WithTx(context.Background(), tt.fn)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// userColumns — столбцы пользователя в порядке, который ожидает scanUser.
+const userColumns = `u.id, u.username, u.role, u.password_hash, u.created_at,
+	ARRAY(SELECT w.wallet_address FROM user_wallets w WHERE w.user_id = u.id ORDER BY w.wallet_address)`
+
+type UserPostgres struct {
+	db DBTX
+}
+
+// NewUserPostgres создает новый экземпляр UserPostgres.
+func NewUserPostgres(db DBTX) *UserPostgres {
+	return &UserPostgres{db: db}
+}
+
+// Create сохраняет нового пользователя в БД PostgreSQL и заполняет его ID и время создания.
+func (r *UserPostgres) Create(ctx context.Context, user *models.User) error {
+	query := `INSERT INTO users (username, password_hash, role) VALUES ($1, $2, $3) RETURNING id, created_at`
+	err := r.db.QueryRowContext(ctx, query, user.Username, user.PasswordHash, user.Role).Scan(&user.ID, &user.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrUserAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByID возвращает пользователя по ID вместе с адресами принадлежащих ему кошельков.
+func (r *UserPostgres) GetByID(ctx context.Context, id int) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users u WHERE u.id = $1`
+	return scanUser(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByUsername возвращает пользователя по имени вместе с адресами принадлежащих ему кошельков.
+func (r *UserPostgres) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users u WHERE u.username = $1`
+	return scanUser(r.db.QueryRowContext(ctx, query, username))
+}
+
+// AddWallet передает кошелек address во владение пользователю userID. Повторная передача не считается ошибкой.
+func (r *UserPostgres) AddWallet(ctx context.Context, userID int, address string) error {
+	query := `INSERT INTO user_wallets (user_id, wallet_address) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, userID, address)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrWalletNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func scanUser(row *sql.Row) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.Role, &user.PasswordHash, &user.CreatedAt, pq.Array(&user.Wallets))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewUserPostgres(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO users \\(username, password_hash, role\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id, created_at").
+					WithArgs("alice", "hash", auth.RoleCustomer).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+			},
+		},
+		{
+			name: "Duplicate Username",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO users").
+					WithArgs("alice", "hash", auth.RoleCustomer).
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			wantErr: domain.ErrUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			user := &models.User{Username: "alice", PasswordHash: "hash", Role: auth.RoleCustomer}
+			err := repo.Create(context.Background(), user)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, 3, user.ID)
+				assert.Equal(t, createdAt, user.CreatedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserPostgres_GetByUsername(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewUserPostgres(db)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.User
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "username", "role", "password_hash", "created_at", "wallets"}).
+					AddRow(3, "alice", "operator", "hash", createdAt, "{addr1}")
+				mock.ExpectQuery("SELECT u.id, u.username, u.role, u.password_hash, u.created_at, .+ FROM users u WHERE u.username = \\$1").
+					WithArgs("alice").
+					WillReturnRows(rows)
+			},
+			want: &models.User{
+				ID:           3,
+				Username:     "alice",
+				Role:         auth.RoleOperator,
+				Wallets:      []string{"addr1"},
+				PasswordHash: "hash",
+				CreatedAt:    createdAt,
+			},
+		},
+		{
+			name: "User Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT u.id").
+					WithArgs("alice").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetByUsername(context.Background(), "alice")
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserPostgres_AddWallet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewUserPostgres(db)
+
+	mock.ExpectExec("INSERT INTO user_wallets \\(user_id, wallet_address\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT DO NOTHING").
+		WithArgs(3, "unknown").
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err = repo.AddWallet(context.Background(), 3, "unknown")
+	assert.Equal(t, domain.ErrWalletNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,54 +1,136 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
-)
+	"golangTestTask/pkg/address"
 
-var (
-	ErrWalletNotFound = errors.New("wallet not found")
+	"github.com/lib/pq"
 )
 
+// uniqueViolation — код ошибки PostgreSQL при нарушении ограничения уникальности.
+const uniqueViolation = "23505"
+
+// maxAddressAttempts ограничивает число сгенерированных адресов, которые Create пробует сохранить,
+// прежде чем сдаться. Совпадение случайных адресов практически невозможно, так что повтор нужен лишь для надежности.
+const maxAddressAttempts = 5
+
 type WalletPostgres struct {
-	db *sql.DB
+	db       DBTX
+	generate func() (string, error)
 }
 
 // NewWalletPostgres создает новый экземпляр WalletPostgres.
-func NewWalletPostgres(db *sql.DB) *WalletPostgres {
-	return &WalletPostgres{db: db}
+func NewWalletPostgres(db DBTX) *WalletPostgres {
+	return &WalletPostgres{db: db, generate: address.Generate}
 }
 
-// Create сохраняет новый кошелек в БД PostgreSQL.
-func (r *WalletPostgres) Create(wallet *models.Wallet) error {
-	query := `INSERT INTO wallets (address, balance) VALUES ($1, $2)`
-	_, err := r.db.Exec(query, wallet.Address, wallet.Balance)
+// Create сохраняет новый кошелек в БД PostgreSQL. Если адрес кошелька не задан, он генерируется и записывается в wallet;
+// при совпадении с адресом существующего кошелька генерируется новый адрес, не более maxAddressAttempts раз.
+// Совпадение заданного адреса возвращается как domain.ErrWalletAlreadyExists.
+func (r *WalletPostgres) Create(ctx context.Context, wallet *models.Wallet) error {
+	if wallet.Address != "" {
+		return r.insert(ctx, wallet)
+	}
+
+	for attempt := 0; attempt < maxAddressAttempts; attempt++ {
+		generated, err := r.generate()
+		if err != nil {
+			return err
+		}
+		wallet.Address = generated
+		if err := r.insert(ctx, wallet); !errors.Is(err, domain.ErrWalletAlreadyExists) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to generate a unique wallet address in %d attempts", maxAddressAttempts)
+}
+
+// insert сохраняет кошелек wallet с заданным адресом. Занятый адрес не вызывает ошибку БД, а возвращается
+// как domain.ErrWalletAlreadyExists, поэтому транзакция, в которой выполняется вставка, остается пригодной для повтора.
+func (r *WalletPostgres) insert(ctx context.Context, wallet *models.Wallet) error {
+	query := `INSERT INTO wallets (address, balance, status, tier, currency) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (address) DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query, wallet.Address, wallet.Balance, wallet.Status, wallet.Tier, wallet.Currency)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrTierNotFound
+	}
 	if err != nil {
 		return err
 	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrWalletAlreadyExists
+	}
+	return nil
+}
+
+// UpdateStatus обновляет статус кошелька по адресу в БД PostgreSQL.
+func (r *WalletPostgres) UpdateStatus(ctx context.Context, address string, status models.WalletStatus) error {
+	query := `UPDATE wallets SET status = $1 WHERE address = $2`
+	result, err := r.db.ExecContext(ctx, query, status, address)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrWalletNotFound
+	}
 	return nil
 }
 
-// Update обновляет баланс кошелька по адресу в БД PostgreSQL.
-func (r *WalletPostgres) Update(wallet *models.Wallet) error {
-	query := `UPDATE wallets SET balance = $1 WHERE address = $2`
-	_, err := r.db.Exec(query, wallet.Balance, wallet.Address)
+// UpdateTier присваивает кошельку по адресу в БД PostgreSQL уровень tier.
+func (r *WalletPostgres) UpdateTier(ctx context.Context, address string, tier string) error {
+	query := `UPDATE wallets SET tier = $1 WHERE address = $2`
+	result, err := r.db.ExecContext(ctx, query, tier, address)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrTierNotFound
+	}
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return domain.ErrWalletNotFound
+	}
 	return nil
 }
 
 // Get возвращает кошелек по адресу в БД PostgreSQL.
-func (r *WalletPostgres) Get(address string) (*models.Wallet, error) {
-	query := `SELECT address, balance FROM wallets WHERE address = $1`
-	row := r.db.QueryRow(query, address)
+func (r *WalletPostgres) Get(ctx context.Context, address string) (*models.Wallet, error) {
+	query := `SELECT address, balance, status, tier, currency FROM wallets WHERE address = $1`
+	return r.get(ctx, query, address)
+}
+
+// GetForUpdate возвращает кошелек по адресу в БД PostgreSQL, блокируя его строку (SELECT ... FOR UPDATE).
+// Блокировка действует до конца транзакции, поэтому метод имеет смысл вызывать только внутри UnitOfWork.WithTx.
+func (r *WalletPostgres) GetForUpdate(ctx context.Context, address string) (*models.Wallet, error) {
+	query := `SELECT address, balance, status, tier, currency FROM wallets WHERE address = $1 FOR UPDATE`
+	return r.get(ctx, query, address)
+}
+
+func (r *WalletPostgres) get(ctx context.Context, query string, address string) (*models.Wallet, error) {
+	row := r.db.QueryRowContext(ctx, query, address)
 
 	var wallet models.Wallet
-	err := row.Scan(&wallet.Address, &wallet.Balance)
+	err := row.Scan(&wallet.Address, &wallet.Balance, &wallet.Status, &wallet.Tier, &wallet.Currency)
 	if err == sql.ErrNoRows {
-		return nil, ErrWalletNotFound
+		return nil, domain.ErrWalletNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -57,11 +139,11 @@ func (r *WalletPostgres) Get(address string) (*models.Wallet, error) {
 }
 
 // Get возвращает все кошельки в БД PostgreSQL.
-func (r *WalletPostgres) GetAll() ([]models.Wallet, error) {
-	query := `SELECT address, balance FROM wallets`
+func (r *WalletPostgres) GetAll(ctx context.Context) ([]models.Wallet, error) {
+	query := `SELECT address, balance, status, tier, currency FROM wallets`
 	wallets := make([]models.Wallet, 0)
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -69,7 +151,7 @@ func (r *WalletPostgres) GetAll() ([]models.Wallet, error) {
 
 	for rows.Next() {
 		var w models.Wallet
-		if err := rows.Scan(&w.Address, &w.Balance); err != nil {
+		if err := rows.Scan(&w.Address, &w.Balance, &w.Status, &w.Tier, &w.Currency); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		wallets = append(wallets, w)
@@ -81,11 +163,36 @@ func (r *WalletPostgres) GetAll() ([]models.Wallet, error) {
 	return wallets, nil
 }
 
+// Stats возвращает количество кошельков и сумму их балансов по статусам и валютам из БД PostgreSQL.
+func (r *WalletPostgres) Stats(ctx context.Context) ([]models.WalletStats, error) {
+	query := `SELECT status, currency, count(*), COALESCE(sum(balance), 0) FROM wallets GROUP BY status, currency ORDER BY status, currency`
+	stats := make([]models.WalletStats, 0)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s models.WalletStats
+		if err := rows.Scan(&s.Status, &s.Currency, &s.Count, &s.Balance); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return stats, nil
+}
+
 // Existence проверяет существуют ли какие-либо кошельки в БД PostgreSQL.
-func (r *WalletPostgres) Existence() bool {
+func (r *WalletPostgres) Existence(ctx context.Context) bool {
 	query := `SELECT EXISTS (SELECT 1 FROM wallets)`
 	var exists bool
-	err := r.db.QueryRow(query).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query).Scan(&exists)
 	if err != nil {
 		return false
 	}
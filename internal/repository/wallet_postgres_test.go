@@ -1,13 +1,17 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
 
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,71 +22,165 @@ func TestWalletPostgres_Create(t *testing.T) {
 	}
 	defer db.Close()
 
-	repo := NewWalletPostgres(db)
+	// sequence возвращает генератор, выдающий адреса addresses по очереди.
+	sequence := func(addresses ...string) func() (string, error) {
+		return func() (string, error) {
+			address := addresses[0]
+			addresses = addresses[1:]
+			return address, nil
+		}
+	}
 
 	tests := []struct {
-		name    string
-		mock    func()
-		input   *models.Wallet
-		wantErr bool
+		name            string
+		mock            func()
+		generate        func() (string, error)
+		input           *models.Wallet
+		wantErr         bool
+		expectedErr     error
+		expectedAddress string
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				mock.ExpectExec("INSERT INTO wallets").
-					WithArgs("addr1", 100.0).
+				mock.ExpectExec("INSERT INTO wallets (.+) ON CONFLICT \\(address\\) DO NOTHING").
+					WithArgs("addr1", "100.00", models.WalletStatusActive, models.DefaultWalletTier, "USD").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			input: &models.Wallet{
-				Address: "addr1",
-				Balance: 100.0,
+				Address:  "addr1",
+				Balance:  money.MustParse("100.00"),
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
 			},
-			wantErr: false,
+			wantErr:         false,
+			expectedAddress: "addr1",
 		},
 		{
 			name: "Duplicate Address",
 			mock: func() {
 				mock.ExpectExec("INSERT INTO wallets").
-					WithArgs("addr1", 100.0).
-					WillReturnError(errors.New("duplicate key"))
+					WithArgs("addr1", "100.00", models.WalletStatusActive, models.DefaultWalletTier, "USD").
+					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 			input: &models.Wallet{
-				Address: "addr1",
-				Balance: 100.0,
+				Address:  "addr1",
+				Balance:  money.MustParse("100.00"),
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
 			},
-			wantErr: true,
+			wantErr:     true,
+			expectedErr: domain.ErrWalletAlreadyExists,
+		},
+		{
+			name: "Unknown Tier",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wallets").
+					WithArgs("addr1", "100.00", models.WalletStatusActive, "unknown", "USD").
+					WillReturnError(&pq.Error{Code: "23503"})
+			},
+			input: &models.Wallet{
+				Address:  "addr1",
+				Balance:  money.MustParse("100.00"),
+				Status:   models.WalletStatusActive,
+				Tier:     "unknown",
+				Currency: "USD",
+			},
+			wantErr:     true,
+			expectedErr: domain.ErrTierNotFound,
 		},
 		{
-			name: "Empty Address",
+			name: "Generated Address",
 			mock: func() {
 				mock.ExpectExec("INSERT INTO wallets").
-					WithArgs("", 100.0).
-					WillReturnError(errors.New("empty address"))
+					WithArgs("generated1", "0.00", models.WalletStatusActive, models.DefaultWalletTier, "USD").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			generate: sequence("generated1"),
+			input: &models.Wallet{
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
+			},
+			wantErr:         false,
+			expectedAddress: "generated1",
+		},
+		{
+			name: "Generated Address Collision",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wallets").
+					WithArgs("generated1", "0.00", models.WalletStatusActive, models.DefaultWalletTier, "USD").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO wallets").
+					WithArgs("generated2", "0.00", models.WalletStatusActive, models.DefaultWalletTier, "USD").
+					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
+			generate: sequence("generated1", "generated2"),
 			input: &models.Wallet{
-				Address: "",
-				Balance: 100.0,
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
+			},
+			wantErr:         false,
+			expectedAddress: "generated2",
+		},
+		{
+			name: "Generated Addresses Exhausted",
+			mock: func() {
+				for i := 0; i < maxAddressAttempts; i++ {
+					mock.ExpectExec("INSERT INTO wallets").
+						WithArgs("generated1", "0.00", models.WalletStatusActive, models.DefaultWalletTier, "USD").
+						WillReturnResult(sqlmock.NewResult(0, 0))
+				}
+			},
+			generate: func() (string, error) { return "generated1", nil },
+			input: &models.Wallet{
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
 			},
 			wantErr: true,
 		},
+		{
+			name:     "Generator Error",
+			mock:     func() {},
+			generate: func() (string, error) { return "", errors.New("entropy exhausted") },
+			input: &models.Wallet{
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
+			},
+			wantErr:     true,
+			expectedErr: errors.New("entropy exhausted"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := repo.Create(tt.input)
+			repo := NewWalletPostgres(db)
+			if tt.generate != nil {
+				repo.generate = tt.generate
+			}
+			err := repo.Create(context.Background(), tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.Equal(t, tt.expectedErr, err)
+				}
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAddress, tt.input.Address)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
 
-func TestWalletPostgres_Update(t *testing.T) {
+func TestWalletPostgres_UpdateStatus(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -94,47 +192,77 @@ func TestWalletPostgres_Update(t *testing.T) {
 	tests := []struct {
 		name    string
 		mock    func()
-		input   *models.Wallet
-		wantErr bool
+		wantErr error
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				mock.ExpectExec("UPDATE wallets").
-					WithArgs(150.0, "addr1").
+				mock.ExpectExec("UPDATE wallets SET status = \\$1 WHERE address = \\$2").
+					WithArgs(models.WalletStatusFrozen, "addr1").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
-			input: &models.Wallet{
-				Address: "addr1",
-				Balance: 150.0,
-			},
-			wantErr: false,
 		},
 		{
 			name: "Wallet Not Found",
 			mock: func() {
-				mock.ExpectExec("UPDATE wallets").
-					WithArgs(150.0, "unknown").
-					WillReturnError(errors.New("wallet not found"))
+				mock.ExpectExec("UPDATE wallets SET status = \\$1 WHERE address = \\$2").
+					WithArgs(models.WalletStatusFrozen, "addr1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
-			input: &models.Wallet{
-				Address: "unknown",
-				Balance: 150.0,
+			wantErr: domain.ErrWalletNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.UpdateStatus(context.Background(), "addr1", models.WalletStatusFrozen)
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWalletPostgres_UpdateTier(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWalletPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE wallets SET tier = \\$1 WHERE address = \\$2").
+					WithArgs("verified", "addr1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
-			wantErr: true,
 		},
 		{
-			name: "Database Error",
+			name: "Wallet Not Found",
 			mock: func() {
-				mock.ExpectExec("UPDATE wallets").
-					WithArgs(150.0, "addr1").
-					WillReturnError(errors.New("db error"))
+				mock.ExpectExec("UPDATE wallets SET tier = \\$1 WHERE address = \\$2").
+					WithArgs("verified", "addr1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
-			input: &models.Wallet{
-				Address: "addr1",
-				Balance: 150.0,
+			wantErr: domain.ErrWalletNotFound,
+		},
+		{
+			name: "Tier Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE wallets SET tier = \\$1 WHERE address = \\$2").
+					WithArgs("verified", "addr1").
+					WillReturnError(&pq.Error{Code: "23503"})
 			},
-			wantErr: true,
+			wantErr: domain.ErrTierNotFound,
 		},
 	}
 
@@ -142,12 +270,8 @@ func TestWalletPostgres_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := repo.Update(tt.input)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
+			err := repo.UpdateTier(context.Background(), "addr1", "verified")
+			assert.Equal(t, tt.wantErr, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
@@ -172,34 +296,37 @@ func TestWalletPostgres_Get(t *testing.T) {
 		{
 			name: "OK",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"address", "balance"}).
-					AddRow("addr1", 100.0)
-				mock.ExpectQuery("SELECT address, balance FROM wallets").
+				rows := sqlmock.NewRows([]string{"address", "balance", "status", "tier", "currency"}).
+					AddRow("addr1", "100.00", "active", "standard", "USD")
+				mock.ExpectQuery("SELECT address, balance, status, tier, currency FROM wallets").
 					WithArgs("addr1").
 					WillReturnRows(rows)
 			},
 			input: "addr1",
 			want: &models.Wallet{
-				Address: "addr1",
-				Balance: 100.0,
+				Address:  "addr1",
+				Balance:  money.MustParse("100.00"),
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
 			},
 			wantErr: nil,
 		},
 		{
 			name: "Wallet Not Found",
 			mock: func() {
-				mock.ExpectQuery("SELECT address, balance FROM wallets").
+				mock.ExpectQuery("SELECT address, balance, status, tier, currency FROM wallets").
 					WithArgs("unknown").
 					WillReturnError(sql.ErrNoRows)
 			},
 			input:   "unknown",
 			want:    nil,
-			wantErr: ErrWalletNotFound,
+			wantErr: domain.ErrWalletNotFound,
 		},
 		{
 			name: "Database Error",
 			mock: func() {
-				mock.ExpectQuery("SELECT address, balance FROM wallets").
+				mock.ExpectQuery("SELECT address, balance, status, tier, currency FROM wallets").
 					WithArgs("addr1").
 					WillReturnError(errors.New("db error"))
 			},
@@ -213,7 +340,7 @@ func TestWalletPostgres_Get(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repo.Get(tt.input)
+			got, err := repo.Get(context.Background(), tt.input)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.wantErr, err)
@@ -226,6 +353,68 @@ func TestWalletPostgres_Get(t *testing.T) {
 	}
 }
 
+func TestWalletPostgres_GetForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWalletPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		input   string
+		want    *models.Wallet
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"address", "balance", "status", "tier", "currency"}).
+					AddRow("addr1", "100.00", "active", "standard", "USD")
+				mock.ExpectQuery("SELECT address, balance, status, tier, currency FROM wallets WHERE address = \\$1 FOR UPDATE").
+					WithArgs("addr1").
+					WillReturnRows(rows)
+			},
+			input: "addr1",
+			want: &models.Wallet{
+				Address:  "addr1",
+				Balance:  money.MustParse("100.00"),
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
+			},
+		},
+		{
+			name: "Wallet Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT address, balance, status, tier, currency FROM wallets WHERE address = \\$1 FOR UPDATE").
+					WithArgs("unknown").
+					WillReturnError(sql.ErrNoRows)
+			},
+			input:   "unknown",
+			wantErr: domain.ErrWalletNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetForUpdate(context.Background(), tt.input)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestWalletPostgres_Existence(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -274,9 +463,66 @@ func TestWalletPostgres_Existence(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			result := repo.Existence()
+			result := repo.Existence(context.Background())
 			assert.Equal(t, tt.expected, result)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
+
+func TestWalletPostgres_Stats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWalletPostgres(db)
+
+	tests := []struct {
+		name     string
+		mock     func()
+		expected []models.WalletStats
+		wantErr  bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"status", "currency", "count", "sum"}).
+					AddRow("active", "EUR", 1, "50.00").
+					AddRow("active", "USD", 2, "200.75").
+					AddRow("closed", "USD", 1, "0")
+				mock.ExpectQuery("SELECT status, currency, count\\(\\*\\), COALESCE\\(sum\\(balance\\), 0\\) FROM wallets GROUP BY status, currency").
+					WillReturnRows(rows)
+			},
+			expected: []models.WalletStats{
+				{Status: models.WalletStatusActive, Currency: "EUR", Count: 1, Balance: money.MustParse("50.00")},
+				{Status: models.WalletStatusActive, Currency: "USD", Count: 2, Balance: money.MustParse("200.75")},
+				{Status: models.WalletStatusClosed, Currency: "USD", Count: 1, Balance: 0},
+			},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT status").
+					WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			result, err := repo.Stats(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// walletTierColumns — столбцы уровня кошельков в порядке, который ожидает scanWalletTier.
+const walletTierColumns = `name, min_transfer, max_transfer, daily_limit, monthly_limit, max_balance`
+
+type WalletTierPostgres struct {
+	db DBTX
+}
+
+// NewWalletTierPostgres создает новый экземпляр WalletTierPostgres.
+func NewWalletTierPostgres(db DBTX) *WalletTierPostgres {
+	return &WalletTierPostgres{db: db}
+}
+
+// Create сохраняет новый уровень кошельков в БД PostgreSQL.
+func (r *WalletTierPostgres) Create(ctx context.Context, tier models.WalletTier) error {
+	query := `INSERT INTO wallet_tiers (` + walletTierColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.db.ExecContext(ctx, query, tier.Name, tier.MinTransfer, tier.MaxTransfer, tier.DailyLimit, tier.MonthlyLimit, tier.MaxBalance)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrTierAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update обновляет ограничения уровня кошельков по имени в БД PostgreSQL.
+func (r *WalletTierPostgres) Update(ctx context.Context, tier models.WalletTier) error {
+	query := `UPDATE wallet_tiers SET min_transfer = $1, max_transfer = $2, daily_limit = $3, monthly_limit = $4, max_balance = $5
+		WHERE name = $6`
+	result, err := r.db.ExecContext(ctx, query, tier.MinTransfer, tier.MaxTransfer, tier.DailyLimit, tier.MonthlyLimit, tier.MaxBalance, tier.Name)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrTierNotFound
+	}
+	return nil
+}
+
+// Get возвращает уровень кошельков по имени из БД PostgreSQL.
+func (r *WalletTierPostgres) Get(ctx context.Context, name string) (*models.WalletTier, error) {
+	query := `SELECT ` + walletTierColumns + ` FROM wallet_tiers WHERE name = $1`
+	tier, err := scanWalletTier(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// GetAll возвращает все уровни кошельков из БД PostgreSQL, отсортированные по имени.
+func (r *WalletTierPostgres) GetAll(ctx context.Context) ([]models.WalletTier, error) {
+	query := `SELECT ` + walletTierColumns + ` FROM wallet_tiers ORDER BY name`
+	tiers := make([]models.WalletTier, 0)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tier, err := scanWalletTier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tiers = append(tiers, tier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return tiers, nil
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWalletTier(row rowScanner) (models.WalletTier, error) {
+	var tier models.WalletTier
+	err := row.Scan(&tier.Name, &tier.MinTransfer, &tier.MaxTransfer, &tier.DailyLimit, &tier.MonthlyLimit, &tier.MaxBalance)
+	return tier, err
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var walletTierRowColumns = []string{"name", "min_transfer", "max_transfer", "daily_limit", "monthly_limit", "max_balance"}
+
+func TestWalletTierPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWalletTierPostgres(db)
+	tier := models.WalletTier{
+		Name:         "verified",
+		MinTransfer:  money.MustParse("0.01"),
+		MaxTransfer:  money.FromInt(1000),
+		DailyLimit:   money.FromInt(5000),
+		MonthlyLimit: money.FromInt(50000),
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wallet_tiers").
+					WithArgs("verified", "0.01", "1000.00", "5000.00", "50000.00", "0.00").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Duplicate Name",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO wallet_tiers").
+					WithArgs("verified", "0.01", "1000.00", "5000.00", "50000.00", "0.00").
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+			wantErr: domain.ErrTierAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.Create(context.Background(), tier)
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWalletTierPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWalletTierPostgres(db)
+	tier := models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), MaxBalance: money.FromInt(10000)}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE wallet_tiers SET min_transfer = \\$1").
+					WithArgs("1.00", "0.00", "0.00", "0.00", "10000.00", "verified").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Tier Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE wallet_tiers SET min_transfer = \\$1").
+					WithArgs("1.00", "0.00", "0.00", "0.00", "10000.00", "verified").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: domain.ErrTierNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := repo.Update(context.Background(), tier)
+			assert.Equal(t, tt.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWalletTierPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWalletTierPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *models.WalletTier
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(walletTierRowColumns).AddRow("standard", "0.01", "0", "0", "0", "0")
+				mock.ExpectQuery("SELECT (.+) FROM wallet_tiers WHERE name = \\$1").
+					WithArgs("standard").
+					WillReturnRows(rows)
+			},
+			want: &models.WalletTier{Name: "standard", MinTransfer: money.MustParse("0.01")},
+		},
+		{
+			name: "Tier Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM wallet_tiers WHERE name = \\$1").
+					WithArgs("standard").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: domain.ErrTierNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.Get(context.Background(), "standard")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWalletTierPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewWalletTierPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []models.WalletTier
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(walletTierRowColumns).
+					AddRow("standard", "0.01", "0", "0", "0", "0").
+					AddRow("verified", "0.01", "1000.00", "5000.00", "50000.00", "100000.00")
+				mock.ExpectQuery("SELECT (.+) FROM wallet_tiers ORDER BY name").WillReturnRows(rows)
+			},
+			want: []models.WalletTier{
+				{Name: "standard", MinTransfer: money.MustParse("0.01")},
+				{
+					Name:         "verified",
+					MinTransfer:  money.MustParse("0.01"),
+					MaxTransfer:  money.FromInt(1000),
+					DailyLimit:   money.FromInt(5000),
+					MonthlyLimit: money.FromInt(50000),
+					MaxBalance:   money.FromInt(100000),
+				},
+			},
+		},
+		{
+			name: "Database Error",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM wallet_tiers ORDER BY name").WillReturnError(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.GetAll(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"golangTestTask/configs"
+	"net"
+	"net/http"
+)
+
+type Server struct {
+	httpServer *http.Server
+}
+
+// NewServer создает HTTP-сервер с адресом, таймаутами и ограничением размера заголовков из config.
+func NewServer(config configs.Config, handler http.Handler) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              config.HTTPAddr,
+			Handler:           handler,
+			ReadTimeout:       config.HTTPReadTimeout,
+			ReadHeaderTimeout: config.HTTPReadHeaderTimeout,
+			WriteTimeout:      config.HTTPWriteTimeout,
+			IdleTimeout:       config.HTTPIdleTimeout,
+			MaxHeaderBytes:    config.HTTPMaxHeaderBytes,
+		},
+	}
+}
+
+// Run начинает принимать соединения и блокируется до остановки сервера.
+// После вызова Shutdown возвращает nil.
+func (s *Server) Run() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve обслуживает соединения, принимаемые listener. После вызова Shutdown возвращает nil.
+func (s *Server) Serve(listener net.Listener) error {
+	if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown перестает принимать новые соединения и ждет завершения обрабатываемых запросов.
+// Если ctx отменяется раньше, оставшиеся соединения закрываются принудительно и возвращается ошибка ctx.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		s.httpServer.Close()
+		return err
+	}
+	return nil
+}
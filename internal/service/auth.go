@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"slices"
+	"strings"
+)
+
+// knownScopes — области доступа, которые можно выдать ключу API.
+var knownScopes = []string{auth.ScopeAdmin, auth.ScopeMetrics}
+
+type AuthService struct {
+	repo repository.APIKey
+	uow  repository.UnitOfWork
+}
+
+// NewAuthService создает новый экземпляр AuthService.
+func NewAuthService(repo *repository.Repository) *AuthService {
+	return &AuthService{
+		repo: repo.APIKey,
+		uow:  repo.UnitOfWork,
+	}
+}
+
+// Authenticate возвращает участника, которому выдан ключ API key.
+// Неизвестный или отозванный ключ возвращается как domain.ErrInvalidAPIKey.
+func (s *AuthService) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	apiKey, err := s.repo.GetByHash(ctx, auth.HashKey(key))
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return apiKeyPrincipal(apiKey), nil
+}
+
+// apiKeyPrincipal возвращает участника, которому выдан ключ API apiKey.
+func apiKeyPrincipal(apiKey *models.APIKey) *auth.Principal {
+	return &auth.Principal{
+		KeyID:   apiKey.ID,
+		Name:    apiKey.Name,
+		Role:    auth.RoleForScopes(apiKey.Scopes),
+		Wallets: apiKey.Wallets,
+	}
+}
+
+// CreateAPIKey создает ключ API с областями доступа req.Scopes и передает ему во владение кошельки req.Wallets.
+// Значение ключа возвращается только в ответе и в БД не сохраняется.
+func (s *AuthService) CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", "name is required")
+	}
+	if len(name) > 128 {
+		return nil, domain.NewValidationError("name", "too long name")
+	}
+	scopes := []string{}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(knownScopes, scope) {
+			return nil, domain.ErrInvalidScope
+		}
+		scopes = append(scopes, scope)
+	}
+
+	key, err := auth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	apiKey := models.APIKey{Name: name, Scopes: scopes, Wallets: []string{}}
+	err = s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		if err := repos.APIKey.Create(ctx, &apiKey, auth.HashKey(key)); err != nil {
+			return err
+		}
+		for _, address := range req.Wallets {
+			if err := repos.APIKey.AddWallet(ctx, apiKey.ID, address); err != nil {
+				if errors.Is(err, domain.ErrWalletNotFound) {
+					return domain.NewValidationError("wallets", "wallet "+address+" not found")
+				}
+				return err
+			}
+			apiKey.Wallets = append(apiKey.Wallets, address)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreateAPIKeyResponse{APIKey: apiKey, Key: key}, nil
+}
+
+// EnsureAPIKey сохраняет заранее известный ключ key с именем name и областями доступа scopes, если его еще нет в БД.
+// Используется для первичной настройки, когда в системе еще нет ни одного административного ключа.
+func (s *AuthService) EnsureAPIKey(ctx context.Context, name string, key string, scopes []string) error {
+	hash := auth.HashKey(key)
+	_, err := s.repo.GetByHash(ctx, hash)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return err
+	}
+	return s.repo.Create(ctx, &models.APIKey{Name: name, Scopes: scopes}, hash)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	repository_mocks "golangTestTask/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthService_Authenticate(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        func(r *repository_mocks.MockAPIKey)
+		want        *auth.Principal
+		expectedErr error
+	}{
+		{
+			name: "valid key",
+			mock: func(r *repository_mocks.MockAPIKey) {
+				r.EXPECT().GetByHash(gomock.Any(), auth.HashKey("pk_key")).Return(&models.APIKey{
+					ID:      1,
+					Name:    "merchant",
+					Scopes:  []string{},
+					Wallets: []string{"addr1"},
+				}, nil)
+			},
+			want: &auth.Principal{KeyID: 1, Name: "merchant", Role: auth.RoleCustomer, Wallets: []string{"addr1"}},
+		},
+		{
+			name: "unknown key",
+			mock: func(r *repository_mocks.MockAPIKey) {
+				r.EXPECT().GetByHash(gomock.Any(), gomock.Any()).Return(nil, repository.ErrAPIKeyNotFound)
+			},
+			expectedErr: domain.ErrInvalidAPIKey,
+		},
+		{
+			name: "repository error",
+			mock: func(r *repository_mocks.MockAPIKey) {
+				r.EXPECT().GetByHash(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository_mocks.NewMockAPIKey(ctrl)
+			tt.mock(repo)
+
+			service := NewAuthService(&repository.Repository{APIKey: repo})
+			got, err := service.Authenticate(context.Background(), "pk_key")
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAuthService_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         models.CreateAPIKeyRequest
+		mock        func(r *repository_mocks.MockAPIKey, uow *repository_mocks.MockUnitOfWork)
+		wantWallets []string
+		wantScopes  []string
+		expectedErr error
+	}{
+		{
+			name: "success",
+			req:  models.CreateAPIKeyRequest{Name: "merchant", Wallets: []string{"addr1", "addr2"}},
+			mock: func(r *repository_mocks.MockAPIKey, uow *repository_mocks.MockUnitOfWork) {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{APIKey: r})
+				})
+				r.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key *models.APIKey, keyHash string) error {
+					key.ID = 7
+					return nil
+				})
+				r.EXPECT().AddWallet(gomock.Any(), 7, "addr1").Return(nil)
+				r.EXPECT().AddWallet(gomock.Any(), 7, "addr2").Return(nil)
+			},
+			wantWallets: []string{"addr1", "addr2"},
+			wantScopes:  []string{},
+		},
+		{
+			name: "metrics scope",
+			req:  models.CreateAPIKeyRequest{Name: "prometheus", Scopes: []string{auth.ScopeMetrics}},
+			mock: func(r *repository_mocks.MockAPIKey, uow *repository_mocks.MockUnitOfWork) {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{APIKey: r})
+				})
+				r.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key *models.APIKey, keyHash string) error {
+					key.ID = 7
+					return nil
+				})
+			},
+			wantWallets: []string{},
+			wantScopes:  []string{auth.ScopeMetrics},
+		},
+		{
+			name:        "empty name",
+			req:         models.CreateAPIKeyRequest{Name: "  "},
+			mock:        func(r *repository_mocks.MockAPIKey, uow *repository_mocks.MockUnitOfWork) {},
+			expectedErr: domain.NewValidationError("name", "name is required"),
+		},
+		{
+			name:        "unknown scope",
+			req:         models.CreateAPIKeyRequest{Name: "merchant", Scopes: []string{"root"}},
+			mock:        func(r *repository_mocks.MockAPIKey, uow *repository_mocks.MockUnitOfWork) {},
+			expectedErr: domain.ErrInvalidScope,
+		},
+		{
+			name: "wallet not found",
+			req:  models.CreateAPIKeyRequest{Name: "merchant", Wallets: []string{"unknown"}},
+			mock: func(r *repository_mocks.MockAPIKey, uow *repository_mocks.MockUnitOfWork) {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{APIKey: r})
+				})
+				r.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().AddWallet(gomock.Any(), gomock.Any(), "unknown").Return(domain.ErrWalletNotFound)
+			},
+			expectedErr: domain.NewValidationError("wallets", "wallet unknown not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository_mocks.NewMockAPIKey(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			tt.mock(repo, uow)
+
+			service := NewAuthService(&repository.Repository{APIKey: repo, UnitOfWork: uow})
+			got, err := service.CreateAPIKey(context.Background(), tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 7, got.ID)
+			assert.Equal(t, tt.wantWallets, got.Wallets)
+			assert.Equal(t, tt.wantScopes, got.Scopes)
+			assert.Regexp(t, "^pk_[0-9a-f]{64}$", got.Key)
+		})
+	}
+}
+
+func TestAuthService_EnsureAPIKey(t *testing.T) {
+	tests := []struct {
+		name string
+		mock func(r *repository_mocks.MockAPIKey)
+	}{
+		{
+			name: "key exists",
+			mock: func(r *repository_mocks.MockAPIKey) {
+				r.EXPECT().GetByHash(gomock.Any(), auth.HashKey("pk_admin")).Return(&models.APIKey{ID: 1}, nil)
+			},
+		},
+		{
+			name: "key created",
+			mock: func(r *repository_mocks.MockAPIKey) {
+				r.EXPECT().GetByHash(gomock.Any(), auth.HashKey("pk_admin")).Return(nil, repository.ErrAPIKeyNotFound)
+				r.EXPECT().Create(gomock.Any(), &models.APIKey{Name: "admin", Scopes: []string{auth.ScopeAdmin}}, auth.HashKey("pk_admin")).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository_mocks.NewMockAPIKey(ctrl)
+			tt.mock(repo)
+
+			service := NewAuthService(&repository.Repository{APIKey: repo})
+			err := service.EnsureAPIKey(context.Background(), "admin", "pk_admin", []string{auth.ScopeAdmin})
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/metrics"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"golangTestTask/pkg/money"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultHoldTTL — срок действия блокировки средств, если он не указан в запросе.
+	DefaultHoldTTL = 24 * time.Hour
+	// MaxHoldTTL — максимальный срок действия блокировки средств.
+	MaxHoldTTL = 30 * 24 * time.Hour
+)
+
+// AuthorizeHold блокирует req.Amount средств на кошельке req.From для последующего перевода на кошелек req.To
+// и возвращает блокировку. Заблокированные средства остаются на кошельке, но недоступны для других переводов и блокировок,
+// пока блокировка не будет списана (CaptureHold), отменена (VoidHold) или не истечет ее срок req.ExpiresIn
+// (по умолчанию DefaultHoldTTL, не более MaxHoldTTL).
+// Блокировать средства можно только на кошельке, с которого участнику из ctx разрешено списывать средства.
+// Адреса и кошельки, валюта и точность суммы проверяются так же, как при переводе. Ограничения на переводы и комиссия
+// применяются при списании, поэтому комиссия в блокировку не входит и списывается из доступных средств.
+func (s *TransactionService) AuthorizeHold(ctx context.Context, req models.CreateHoldRequest) (*models.Hold, error) {
+	if err := checkAddresses(ctx, s.wallet_repo, req.From, req.To); err != nil {
+		return nil, err
+	}
+	if err := checkCanDebit(ctx, req.From); err != nil {
+		return nil, err
+	}
+	ttl := DefaultHoldTTL
+	if req.ExpiresIn != 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if req.ExpiresIn < 0 || ttl > MaxHoldTTL {
+		return nil, domain.NewValidationError("expires_in", fmt.Sprintf("expires_in must be between 1 and %d seconds", int(MaxHoldTTL/time.Second)))
+	}
+
+	hold := &models.Hold{From: req.From, To: req.To, Amount: req.Amount, Convert: req.Convert}
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		plan, err := lockTransfer(ctx, repos.Wallet, req.From, req.To, "")
+		if err != nil {
+			return err
+		}
+		if err := checkWalletActive(plan.wallet_from, models.TransactionRoleSender); err != nil {
+			return err
+		}
+		if err := checkWalletActive(plan.wallet_to, models.TransactionRoleRecipient); err != nil {
+			return err
+		}
+		if plan.wallet_from.Currency != plan.wallet_to.Currency {
+			if !req.Convert {
+				return domain.ErrCurrencyMismatch
+			}
+			if s.rates == nil {
+				return domain.ErrConversionUnavailable
+			}
+		}
+		currency, ok := money.LookupCurrency(plan.wallet_from.Currency)
+		if !ok {
+			return fmt.Errorf("%w: %q", domain.ErrUnsupportedCurrency, plan.wallet_from.Currency)
+		}
+		if !currency.Fits(req.Amount) {
+			return domain.ErrInvalidAmountPrecision
+		}
+
+		// Кошелек отправителя заблокирован, поэтому параллельные переводы и блокировки не могут одновременно
+		// израсходовать одни и те же средства.
+		now := s.now().UTC()
+		held, err := repos.Hold.Held(ctx, req.From, now)
+		if err != nil {
+			return err
+		}
+		if plan.wallet_from.Balance-held < req.Amount {
+			return domain.ErrInsufficientFunds
+		}
+		hold.Currency = plan.wallet_from.Currency
+		hold.ExpiresAt = now.Add(ttl)
+		return repos.Hold.Create(ctx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// GetHold возвращает блокировку средств id. Блокировка доступна тем, кому разрешено просматривать кошелек
+// отправителя или получателя.
+func (s *TransactionService) GetHold(ctx context.Context, id int) (*models.Hold, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return nil, domain.ErrUnauthenticated
+	}
+	hold, err := s.hold_repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.CanReadWallet(hold.From) && !principal.CanReadWallet(hold.To) {
+		return nil, domain.ErrWalletNotOwned
+	}
+	return hold, nil
+}
+
+// CaptureHold переводит получателю блокировки id сумму req.Amount, а если она не задана — всю заблокированную сумму,
+// и возвращает блокировку. Остаток заблокированной суммы разблокируется. Списать блокировку можно только один раз
+// и только до истечения ее срока; в остальном перевод выполняется так же, как TransferFunds, включая комиссию,
+// ограничения на переводы и конвертацию по текущему курсу. Перевод, смена статуса блокировки и запись транзакции
+// выполняются атомарно в одной транзакции БД; неудачное списание в историю не записывается, а блокировка остается активной.
+// Списывать блокировку может тот, кому разрешено списывать средства с кошелька отправителя.
+func (s *TransactionService) CaptureHold(ctx context.Context, id int, req models.CaptureHoldRequest) (*models.Hold, error) {
+	var hold *models.Hold
+	var amount money.Amount
+	// currency — валюта кошелька отправителя; остается пустой, пока перевод не начат.
+	var currency string
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		var err error
+		hold, err = s.lockHold(ctx, repos.Hold, id)
+		if err != nil {
+			return err
+		}
+		amount = hold.Amount
+		if req.Amount != nil {
+			if *req.Amount > hold.Amount {
+				return domain.NewValidationError("amount", "amount must not exceed the held amount")
+			}
+			amount = *req.Amount
+		}
+
+		fee_address, err := s.feeWallet(ctx, repos.Wallet, hold.From, nil)
+		if err != nil {
+			return err
+		}
+		plan, err := lockTransfer(ctx, repos.Wallet, hold.From, hold.To, fee_address)
+		if err != nil {
+			return err
+		}
+		currency = plan.wallet_from.Currency
+		plan.hold = hold
+		transfer := models.CreateTransactionRequest{From: hold.From, To: hold.To, Amount: amount, Convert: hold.Convert}
+		if err := s.checkTransfer(ctx, repos, plan, transfer, nil); err != nil {
+			return err
+		}
+		transactionID, err := recordTransfer(ctx, repos, plan, models.Transaction{
+			From:     hold.From,
+			To:       hold.To,
+			Amount:   amount,
+			Currency: currency,
+			Status:   models.TransactionStatusCompleted,
+		})
+		if err != nil {
+			return err
+		}
+		if err := repos.Hold.Capture(ctx, id, amount, transactionID); err != nil {
+			return err
+		}
+
+		finalizedAt := s.now().UTC()
+		hold.Status = models.HoldStatusCaptured
+		hold.CapturedAmount = &amount
+		hold.TransactionID = &transactionID
+		hold.FinalizedAt = &finalizedAt
+		return nil
+	})
+	if currency != "" {
+		metrics.ObserveTransfer(amount, currency, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// VoidHold отменяет активную блокировку средств id и возвращает ее; заблокированные средства становятся доступны.
+// Отменить блокировку может тот, кому разрешено списывать средства с кошелька отправителя.
+func (s *TransactionService) VoidHold(ctx context.Context, id int) (*models.Hold, error) {
+	var hold *models.Hold
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		var err error
+		hold, err = s.lockHold(ctx, repos.Hold, id)
+		if err != nil {
+			return err
+		}
+		if err := repos.Hold.Void(ctx, id); err != nil {
+			return err
+		}
+
+		finalizedAt := s.now().UTC()
+		hold.Status = models.HoldStatusVoided
+		hold.FinalizedAt = &finalizedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// lockHold блокирует строку блокировки средств id и проверяет, что участник из ctx может списывать средства
+// с кошелька ее отправителя, а сама блокировка активна и не истекла.
+func (s *TransactionService) lockHold(ctx context.Context, repo repository.Hold, id int) (*models.Hold, error) {
+	hold, err := repo.GetForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCanDebit(ctx, hold.From); err != nil {
+		return nil, err
+	}
+	if hold.Status != models.HoldStatusActive {
+		return nil, domain.ErrHoldNotActive
+	}
+	if hold.Expired(s.now()) {
+		return nil, domain.ErrHoldExpired
+	}
+	return hold, nil
+}
+
+// ExpireHolds отмечает активные блокировки средств с истекшим сроком как истекшие и возвращает их количество.
+// Истекшие блокировки перестают учитываться в заблокированных средствах сразу по истечении срока, поэтому смена
+// статуса нужна только для истории блокировок.
+func (s *TransactionService) ExpireHolds(ctx context.Context) (int64, error) {
+	return s.hold_repo.ExpireStale(ctx, s.now().UTC())
+}
+
+// RunHoldExpirer раз в interval отмечает блокировки средств с истекшим сроком как истекшие, пока не отменен ctx.
+func (s *TransactionService) RunHoldExpirer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.ExpireHolds(ctx)
+			if err != nil {
+				log.Printf("Failed to expire holds: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Expired %d holds", expired)
+			}
+		}
+	}
+}
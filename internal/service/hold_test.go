@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	repository_mocks "golangTestTask/internal/repository/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransactionService_AuthorizeHold(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	type mockBehavior func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold)
+
+	tests := []struct {
+		name         string
+		req          models.CreateHoldRequest
+		wallets      []string
+		mockBehavior mockBehavior
+		withTx       bool
+		expected     *models.Hold
+		expectedErr  string
+	}{
+		{
+			name:    "success",
+			req:     models.CreateHoldRequest{From: addr1, To: addr2, Amount: money.FromInt(30), ExpiresIn: 3600},
+			wallets: []string{addr1},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold) {
+				w.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+				w.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD"}, nil)
+				h.EXPECT().Held(gomock.Any(), addr1, now).Return(money.FromInt(70), nil)
+				h.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, hold *models.Hold) error {
+					hold.ID = 5
+					hold.Status = models.HoldStatusActive
+					return nil
+				})
+			},
+			withTx: true,
+			expected: &models.Hold{ID: 5, From: addr1, To: addr2, Amount: money.FromInt(30), Currency: "USD",
+				Status: models.HoldStatusActive, ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "insufficient available funds",
+			req:     models.CreateHoldRequest{From: addr1, To: addr2, Amount: money.FromInt(30)},
+			wallets: []string{addr1},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold) {
+				w.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+				w.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD"}, nil)
+				h.EXPECT().Held(gomock.Any(), addr1, now).Return(money.FromInt(80), nil)
+			},
+			withTx:      true,
+			expectedErr: "insufficient funds",
+		},
+		{
+			name:    "currency mismatch",
+			req:     models.CreateHoldRequest{From: addr1, To: addr2, Amount: money.FromInt(30)},
+			wallets: []string{addr1},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold) {
+				w.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+				w.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "EUR"}, nil)
+			},
+			withTx:      true,
+			expectedErr: "sender and recipient wallets have different currencies",
+		},
+		{
+			name:         "expiry too long",
+			req:          models.CreateHoldRequest{From: addr1, To: addr2, Amount: money.FromInt(30), ExpiresIn: int(MaxHoldTTL/time.Second) + 1},
+			wallets:      []string{addr1},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold) {},
+			expectedErr:  "expires_in must be between 1 and 2592000 seconds",
+		},
+		{
+			name:         "foreign wallet",
+			req:          models.CreateHoldRequest{From: addr1, To: addr2, Amount: money.FromInt(30)},
+			wallets:      []string{addr2},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold) {},
+			expectedErr:  "wallet is not owned by the caller",
+		},
+		{
+			name:    "invalid recipient address",
+			req:     models.CreateHoldRequest{From: addr1, To: addr2[:10] + "3" + addr2[11:], Amount: money.FromInt(30)},
+			wallets: []string{addr1},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold) {
+				w.EXPECT().Get(gomock.Any(), addr2[:10]+"3"+addr2[11:]).Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedErr: "invalid wallet address: checksum mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			holdRepo := repository_mocks.NewMockHold(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			if tt.withTx {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{Wallet: walletRepo, Hold: holdRepo})
+				})
+			}
+			tt.mockBehavior(walletRepo, holdRepo)
+
+			service := NewTransactionService(&repository.Repository{Wallet: walletRepo, Hold: holdRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			service.now = func() time.Time { return now }
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: tt.wallets})
+			hold, err := service.AuthorizeHold(ctx, tt.req)
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, hold)
+		})
+	}
+}
+
+func TestTransactionService_CaptureHold(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	activeHold := func() *models.Hold {
+		return &models.Hold{ID: 5, From: addr1, To: addr2, Amount: money.FromInt(30), Currency: "USD",
+			Status: models.HoldStatusActive, ExpiresAt: now.Add(time.Hour)}
+	}
+	partial := money.FromInt(25)
+	tooMuch := money.FromInt(31)
+	transactionID := 42
+
+	tests := []struct {
+		name         string
+		req          models.CaptureHoldRequest
+		mockBehavior func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold, tx *repository_mocks.MockTransaction, l *repository_mocks.MockLedger)
+		expected     *models.Hold
+		expectedErr  string
+	}{
+		{
+			name: "partial capture",
+			req:  models.CaptureHoldRequest{Amount: &partial},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold, tx *repository_mocks.MockTransaction, l *repository_mocks.MockLedger) {
+				h.EXPECT().GetForUpdate(gomock.Any(), 5).Return(activeHold(), nil)
+				w.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+				w.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD"}, nil)
+				// Все средства кошелька заблокированы, но списываемая блокировка в проверке баланса не учитывается.
+				h.EXPECT().Held(gomock.Any(), addr1, now).Return(money.FromInt(100), nil)
+				tx.EXPECT().Create(gomock.Any(), models.Transaction{
+					From: addr1, To: addr2, Amount: partial, Currency: "USD", Status: models.TransactionStatusCompleted,
+				}).Return(transactionID, nil)
+				l.EXPECT().Post(gomock.Any(), &models.JournalEntry{
+					Kind:          models.JournalEntryKindTransfer,
+					TransactionID: transactionID,
+					Postings: []models.Posting{
+						{Wallet: addr1, Currency: "USD", Amount: -partial},
+						{Wallet: addr2, Currency: "USD", Amount: partial},
+					},
+				}).Return(nil)
+				h.EXPECT().Capture(gomock.Any(), 5, partial, transactionID).Return(nil)
+			},
+			expected: &models.Hold{ID: 5, From: addr1, To: addr2, Amount: money.FromInt(30), Currency: "USD",
+				Status: models.HoldStatusCaptured, CapturedAmount: &partial, TransactionID: &transactionID,
+				ExpiresAt: now.Add(time.Hour), FinalizedAt: &now},
+		},
+		{
+			name: "amount exceeds hold",
+			req:  models.CaptureHoldRequest{Amount: &tooMuch},
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold, tx *repository_mocks.MockTransaction, l *repository_mocks.MockLedger) {
+				h.EXPECT().GetForUpdate(gomock.Any(), 5).Return(activeHold(), nil)
+			},
+			expectedErr: "amount must not exceed the held amount",
+		},
+		{
+			name: "expired",
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold, tx *repository_mocks.MockTransaction, l *repository_mocks.MockLedger) {
+				hold := activeHold()
+				hold.ExpiresAt = now
+				h.EXPECT().GetForUpdate(gomock.Any(), 5).Return(hold, nil)
+			},
+			expectedErr: "hold has expired",
+		},
+		{
+			name: "already voided",
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold, tx *repository_mocks.MockTransaction, l *repository_mocks.MockLedger) {
+				hold := activeHold()
+				hold.Status = models.HoldStatusVoided
+				h.EXPECT().GetForUpdate(gomock.Any(), 5).Return(hold, nil)
+			},
+			expectedErr: "hold has already been captured, voided or expired",
+		},
+		{
+			name: "not found",
+			mockBehavior: func(w *repository_mocks.MockWallet, h *repository_mocks.MockHold, tx *repository_mocks.MockTransaction, l *repository_mocks.MockLedger) {
+				h.EXPECT().GetForUpdate(gomock.Any(), 5).Return(nil, domain.ErrHoldNotFound)
+			},
+			expectedErr: "hold not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			holdRepo := repository_mocks.NewMockHold(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Hold: holdRepo, Transaction: txRepo, Ledger: ledgerRepo})
+			})
+			tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+			tt.mockBehavior(walletRepo, holdRepo, txRepo, ledgerRepo)
+
+			service := NewTransactionService(&repository.Repository{Hold: holdRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			service.now = func() time.Time { return now }
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}})
+			hold, err := service.CaptureHold(ctx, 5, tt.req)
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, hold)
+		})
+	}
+}
+
+func TestTransactionService_VoidHold(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		wallets      []string
+		mockBehavior func(h *repository_mocks.MockHold)
+		expectedErr  string
+	}{
+		{
+			name:    "success",
+			wallets: []string{addr1},
+			mockBehavior: func(h *repository_mocks.MockHold) {
+				h.EXPECT().Void(gomock.Any(), 5).Return(nil)
+			},
+		},
+		{
+			name:         "foreign wallet",
+			wallets:      []string{addr2},
+			mockBehavior: func(h *repository_mocks.MockHold) {},
+			expectedErr:  "wallet is not owned by the caller",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			holdRepo := repository_mocks.NewMockHold(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Hold: holdRepo})
+			})
+			holdRepo.EXPECT().GetForUpdate(gomock.Any(), 5).Return(&models.Hold{ID: 5, From: addr1, To: addr2, Amount: money.FromInt(30),
+				Status: models.HoldStatusActive, ExpiresAt: now.Add(time.Hour)}, nil)
+			tt.mockBehavior(holdRepo)
+
+			service := NewTransactionService(&repository.Repository{Hold: holdRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			service.now = func() time.Time { return now }
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: tt.wallets})
+			hold, err := service.VoidHold(ctx, 5)
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.HoldStatusVoided, hold.Status)
+			assert.Equal(t, &now, hold.FinalizedAt)
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_Holds(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	walletRepo := repository_mocks.NewMockWallet(ctrl)
+	tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+	holdRepo := repository_mocks.NewMockHold(ctrl)
+	txRepo := repository_mocks.NewMockTransaction(ctrl)
+	uow := repository_mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+		return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Hold: holdRepo, Transaction: txRepo})
+	})
+	tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+	walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+	walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD"}, nil)
+	// Баланса хватает на перевод, но 60.00 из 100.00 заблокированы.
+	holdRepo.EXPECT().Held(gomock.Any(), addr1, now).Return(money.FromInt(60), nil)
+	txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+		From: addr1, To: addr2, Amount: money.FromInt(50), Currency: "USD",
+		Status: models.TransactionStatusFailed, FailureReason: domain.ErrInsufficientFunds.Error(),
+	}).Return(1, nil)
+
+	service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+	service.now = func() time.Time { return now }
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}})
+	_, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(50)})
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"log"
+	"time"
+)
+
+type IdempotencyService struct {
+	repo repository.Idempotency
+	ttl  time.Duration
+}
+
+// NewIdempotencyService создает новый экземпляр IdempotencyService. Ключи хранятся в течение ttl.
+func NewIdempotencyService(repo repository.Idempotency, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// ReserveIdempotencyKey резервирует ключ key участника из ctx за запросом с хешем requestHash.
+// Возвращает nil, если ключ свободен и запрос нужно выполнить, или сохраненную запись с ответом,
+// если запрос с этим ключом уже был выполнен. Ключи разных участников независимы.
+func (s *IdempotencyService) ReserveIdempotencyKey(ctx context.Context, key string, requestHash string) (*models.IdempotencyRecord, error) {
+	subject, err := idempotencySubject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.repo.Reserve(ctx, subject, key, requestHash, time.Now().Add(s.ttl))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	record, err := s.repo.Get(ctx, subject, key)
+	if err != nil {
+		return nil, err
+	}
+	if record.RequestHash != requestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if !record.Completed() {
+		return nil, domain.ErrIdempotencyRequestInProgress
+	}
+	return record, nil
+}
+
+// SaveIdempotentResponse сохраняет ответ на запрос участника из ctx с ключом key для повторной выдачи.
+func (s *IdempotencyService) SaveIdempotentResponse(ctx context.Context, key string, statusCode int, responseBody []byte) error {
+	subject, err := idempotencySubject(ctx)
+	if err != nil {
+		return err
+	}
+	return s.repo.Complete(ctx, subject, key, statusCode, responseBody)
+}
+
+// ReleaseIdempotencyKey освобождает ключ key участника из ctx, чтобы запрос с ним можно было повторить.
+func (s *IdempotencyService) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	subject, err := idempotencySubject(ctx)
+	if err != nil {
+		return err
+	}
+	return s.repo.Release(ctx, subject, key)
+}
+
+// idempotencySubject возвращает идентификатор участника из ctx, в пределах которого действуют его ключи идемпотентности.
+func idempotencySubject(ctx context.Context) (string, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return "", domain.ErrUnauthenticated
+	}
+	return principal.Subject(), nil
+}
+
+// DeleteExpiredIdempotencyKeys удаляет ключи идемпотентности с истекшим сроком действия.
+func (s *IdempotencyService) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx)
+}
+
+// RunIdempotencySweeper раз в interval удаляет истекшие ключи идемпотентности, пока не отменен ctx.
+func (s *IdempotencyService) RunIdempotencySweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.DeleteExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				log.Printf("Failed to delete expired idempotency keys: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d expired idempotency keys", deleted)
+			}
+		}
+	}
+}
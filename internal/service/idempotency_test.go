@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	repository_mocks "golangTestTask/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdempotencyService_ReserveIdempotencyKey(t *testing.T) {
+	completed := &models.IdempotencyRecord{
+		Key:          "key1",
+		RequestHash:  "hash1",
+		StatusCode:   200,
+		ResponseBody: []byte("ok"),
+	}
+
+	tests := []struct {
+		name        string
+		mock        func(r *repository_mocks.MockIdempotency)
+		want        *models.IdempotencyRecord
+		expectedErr error
+	}{
+		{
+			name: "key reserved",
+			mock: func(r *repository_mocks.MockIdempotency) {
+				r.EXPECT().Reserve(gomock.Any(), "key:1", "key1", "hash1", gomock.Any()).Return(true, nil)
+			},
+			want: nil,
+		},
+		{
+			name: "completed request replayed",
+			mock: func(r *repository_mocks.MockIdempotency) {
+				r.EXPECT().Reserve(gomock.Any(), "key:1", "key1", "hash1", gomock.Any()).Return(false, nil)
+				r.EXPECT().Get(gomock.Any(), "key:1", "key1").Return(completed, nil)
+			},
+			want: completed,
+		},
+		{
+			name: "key reused with different request",
+			mock: func(r *repository_mocks.MockIdempotency) {
+				r.EXPECT().Reserve(gomock.Any(), "key:1", "key1", "hash1", gomock.Any()).Return(false, nil)
+				r.EXPECT().Get(gomock.Any(), "key:1", "key1").Return(&models.IdempotencyRecord{Key: "key1", RequestHash: "hash2", StatusCode: 200}, nil)
+			},
+			expectedErr: domain.ErrIdempotencyKeyReused,
+		},
+		{
+			name: "request in progress",
+			mock: func(r *repository_mocks.MockIdempotency) {
+				r.EXPECT().Reserve(gomock.Any(), "key:1", "key1", "hash1", gomock.Any()).Return(false, nil)
+				r.EXPECT().Get(gomock.Any(), "key:1", "key1").Return(&models.IdempotencyRecord{Key: "key1", RequestHash: "hash1"}, nil)
+			},
+			expectedErr: domain.ErrIdempotencyRequestInProgress,
+		},
+		{
+			name: "repository error",
+			mock: func(r *repository_mocks.MockIdempotency) {
+				r.EXPECT().Reserve(gomock.Any(), "key:1", "key1", "hash1", gomock.Any()).Return(false, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository_mocks.NewMockIdempotency(ctrl)
+			tt.mock(repo)
+
+			service := NewIdempotencyService(repo, time.Hour)
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1})
+			got, err := service.ReserveIdempotencyKey(ctx, "key1", "hash1")
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_ReserveIdempotencyKey_TTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository_mocks.NewMockIdempotency(ctrl)
+	repo.EXPECT().Reserve(gomock.Any(), "key:1", "key1", "hash1", gomock.Any()).DoAndReturn(func(ctx context.Context, subject, key, hash string, expiresAt time.Time) (bool, error) {
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), expiresAt, time.Minute)
+		return true, nil
+	})
+
+	service := NewIdempotencyService(repo, 2*time.Hour)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1})
+	_, err := service.ReserveIdempotencyKey(ctx, "key1", "hash1")
+	assert.NoError(t, err)
+}
+
+func TestIdempotencyService_ScopedBySubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repository_mocks.NewMockIdempotency(ctrl)
+	repo.EXPECT().Complete(gomock.Any(), "user:2", "key1", 201, []byte("ok")).Return(nil)
+	repo.EXPECT().Release(gomock.Any(), "key:1", "key1").Return(nil)
+
+	service := NewIdempotencyService(repo, time.Hour)
+	assert.NoError(t, service.SaveIdempotentResponse(auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 2}), "key1", 201, []byte("ok")))
+	assert.NoError(t, service.ReleaseIdempotencyKey(auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1}), "key1"))
+
+	_, err := service.ReserveIdempotencyKey(context.Background(), "key1", "hash1")
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
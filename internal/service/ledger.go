@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/metrics"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"golangTestTask/pkg/money"
+	"log"
+	"slices"
+	"time"
+)
+
+type LedgerService struct {
+	repo repository.Ledger
+	uow  repository.UnitOfWork
+}
+
+// NewLedgerService создает новый экземпляр LedgerService.
+func NewLedgerService(repo *repository.Repository) *LedgerService {
+	return &LedgerService{repo: repo.Ledger, uow: repo.UnitOfWork}
+}
+
+// RebuildBalances пересчитывает кэшированные балансы всех кошельков по проводкам журнала
+// и возвращает количество кошельков, баланс которых расходился с журналом и был исправлен.
+func (s *LedgerService) RebuildBalances(ctx context.Context) (int64, error) {
+	var corrected int64
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		var err error
+		corrected, err = repos.Ledger.RebuildBalances(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return corrected, nil
+}
+
+// Reconcile сверяет кэшированный баланс каждого кошелька с суммой его проводок в журнале, а ее — с балансом, восстановленным
+// без журнала по начальным зачислениям и истории транзакций. Кроме того, ищет записи журнала, не сходящиеся в ноль
+// или расходящиеся со своей транзакцией, и завершенные транзакции без записи в журнале.
+// Сверка только читает данные: расхождения кэшированных балансов с журналом исправляет RebuildBalances.
+func (s *LedgerService) Reconcile(ctx context.Context) (*models.ReconciliationReport, error) {
+	report := &models.ReconciliationReport{
+		GeneratedAt:            time.Now().UTC(),
+		Discrepancies:          make([]models.BalanceDiscrepancy, 0),
+		UnbalancedEntries:      make([]int64, 0),
+		UnrecordedTransactions: make([]int, 0),
+		MismatchedEntries:      make([]int64, 0),
+	}
+
+	balances, err := s.repo.Balances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balances: %w", err)
+	}
+	report.WalletsChecked = len(balances)
+	for _, b := range balances {
+		if b.Stored != b.Ledger || b.Ledger != b.Expected {
+			report.Discrepancies = append(report.Discrepancies, models.BalanceDiscrepancy{
+				Address:    b.Address,
+				Currency:   b.Currency,
+				Stored:     b.Stored,
+				Ledger:     b.Ledger,
+				Expected:   b.Expected,
+				Difference: b.Stored - b.Ledger,
+			})
+		}
+	}
+
+	unbalanced, err := s.repo.UnbalancedEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load unbalanced entries: %w", err)
+	}
+	report.UnbalancedEntries = append(report.UnbalancedEntries, unbalanced...)
+
+	unrecorded, err := s.repo.UnrecordedTransactions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load unrecorded transactions: %w", err)
+	}
+	report.UnrecordedTransactions = append(report.UnrecordedTransactions, unrecorded...)
+
+	mismatched, err := s.repo.MismatchedEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mismatched entries: %w", err)
+	}
+	report.MismatchedEntries = append(report.MismatchedEntries, mismatched...)
+	return report, nil
+}
+
+// RunReconciler раз в interval сверяет балансы с журналом, пока не отменен ctx. Итог каждой сверки
+// записывается в метрики, а найденные расхождения — в журнал приложения.
+func (s *LedgerService) RunReconciler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Reconcile(ctx)
+			metrics.ObserveReconciliation(report, err)
+			if err != nil {
+				log.Printf("Failed to reconcile balances: %v", err)
+				continue
+			}
+			for _, d := range report.Discrepancies {
+				log.Printf("Balance discrepancy: wallet %s stores %s %s, ledger has %s, transactions give %s",
+					d.Address, d.Stored, d.Currency, d.Ledger, d.Expected)
+			}
+			if len(report.UnbalancedEntries) > 0 {
+				log.Printf("Unbalanced journal entries: %v", report.UnbalancedEntries)
+			}
+			if len(report.UnrecordedTransactions) > 0 {
+				log.Printf("Transactions missing from the journal: %v", report.UnrecordedTransactions)
+			}
+			if len(report.MismatchedEntries) > 0 {
+				log.Printf("Journal entries mismatching their transactions: %v", report.MismatchedEntries)
+			}
+		}
+	}
+}
+
+// postEntry проверяет запись журнала entry и сохраняет ее через repo.
+// Только эта функция записывает проводки, поэтому несбалансированная запись не может попасть в журнал.
+func postEntry(ctx context.Context, repo repository.Ledger, entry *models.JournalEntry) error {
+	if err := checkBalanced(entry); err != nil {
+		return err
+	}
+	return repo.Post(ctx, entry)
+}
+
+// checkBalanced проверяет инвариант двойной записи: каждая проводка изменяет баланс ровно одного счета
+// на ненулевую сумму, а сумма проводок записи в каждой валюте равна нулю.
+// Нарушение инварианта возвращается как ошибка, оборачивающая domain.ErrUnbalancedEntry.
+func checkBalanced(entry *models.JournalEntry) error {
+	if len(entry.Postings) < 2 {
+		return fmt.Errorf("%w: %s entry has %d postings", domain.ErrUnbalancedEntry, entry.Kind, len(entry.Postings))
+	}
+	totals := make(map[string]money.Amount)
+	for _, posting := range entry.Postings {
+		if (posting.Wallet == "") == (posting.Account == "") {
+			return fmt.Errorf("%w: posting must have either a wallet or an account", domain.ErrUnbalancedEntry)
+		}
+		if posting.Amount == 0 || posting.Currency == "" {
+			return fmt.Errorf("%w: posting to %s%s has no amount or currency", domain.ErrUnbalancedEntry, posting.Wallet, posting.Account)
+		}
+		totals[posting.Currency] += posting.Amount
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+	for _, currency := range currencies {
+		if totals[currency] != 0 {
+			return fmt.Errorf("%w: %s postings sum to %s %s", domain.ErrUnbalancedEntry, entry.Kind, totals[currency], currency)
+		}
+	}
+	return nil
+}
+
+// openingEntry возвращает запись журнала, зачисляющую начальный баланс balance на кошелек wallet со счета models.LedgerAccountEquity.
+func openingEntry(wallet *models.Wallet, balance money.Amount) *models.JournalEntry {
+	return &models.JournalEntry{
+		Kind: models.JournalEntryKindOpening,
+		Postings: []models.Posting{
+			{Account: models.LedgerAccountEquity, Currency: wallet.Currency, Amount: -balance},
+			{Wallet: wallet.Address, Currency: wallet.Currency, Amount: balance},
+		},
+	}
+}
+
+// transferEntry возвращает запись журнала для перевода amount по плану plan, сохраненного как транзакция transactionID.
+// Сумма перевода списывается с отправителя; при конвертации она поступает на счет models.LedgerAccountExchange,
+// с которого получателю зачисляется plan.credit в его валюте. Комиссия списывается с отправителя на кошелек комиссий.
+func transferEntry(plan *transferPlan, amount money.Amount, transactionID int) *models.JournalEntry {
+	from, to := plan.wallet_from, plan.wallet_to
+	entry := &models.JournalEntry{Kind: models.JournalEntryKindTransfer, TransactionID: transactionID}
+	if plan.rate == nil {
+		entry.Postings = append(entry.Postings,
+			models.Posting{Wallet: from.Address, Currency: from.Currency, Amount: -amount},
+			models.Posting{Wallet: to.Address, Currency: to.Currency, Amount: plan.credit},
+		)
+	} else {
+		entry.Postings = append(entry.Postings,
+			models.Posting{Wallet: from.Address, Currency: from.Currency, Amount: -amount},
+			models.Posting{Account: models.LedgerAccountExchange, Currency: from.Currency, Amount: amount},
+			models.Posting{Account: models.LedgerAccountExchange, Currency: to.Currency, Amount: -plan.credit},
+			models.Posting{Wallet: to.Address, Currency: to.Currency, Amount: plan.credit},
+		)
+	}
+	if plan.wallet_fee != nil {
+		entry.Postings = append(entry.Postings,
+			models.Posting{Wallet: from.Address, Currency: from.Currency, Amount: -plan.fee},
+			models.Posting{Wallet: plan.wallet_fee.Address, Currency: plan.wallet_fee.Currency, Amount: plan.fee},
+		)
+	}
+	return entry
+}
+
+// reversalEntry возвращает запись журнала для отмены перевода компенсирующей транзакцией transactionID:
+// с получателя исходного перевода to списывается debit в его валюте, а отправителю from возвращается refund в его валюте.
+// Для кошельков в разных валютах обмен проходит через счет models.LedgerAccountExchange.
+func reversalEntry(from *models.Wallet, to *models.Wallet, refund money.Amount, debit money.Amount, transactionID int) *models.JournalEntry {
+	entry := &models.JournalEntry{Kind: models.JournalEntryKindReversal, TransactionID: transactionID}
+	if from.Currency == to.Currency {
+		entry.Postings = append(entry.Postings,
+			models.Posting{Wallet: to.Address, Currency: to.Currency, Amount: -debit},
+			models.Posting{Wallet: from.Address, Currency: from.Currency, Amount: refund},
+		)
+		return entry
+	}
+	entry.Postings = append(entry.Postings,
+		models.Posting{Wallet: to.Address, Currency: to.Currency, Amount: -debit},
+		models.Posting{Account: models.LedgerAccountExchange, Currency: to.Currency, Amount: debit},
+		models.Posting{Account: models.LedgerAccountExchange, Currency: from.Currency, Amount: -refund},
+		models.Posting{Wallet: from.Address, Currency: from.Currency, Amount: refund},
+	)
+	return entry
+}
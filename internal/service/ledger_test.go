@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	repository_mocks "golangTestTask/internal/repository/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckBalanced(t *testing.T) {
+	tests := []struct {
+		name     string
+		postings []models.Posting
+		wantErr  bool
+	}{
+		{
+			name: "balanced",
+			postings: []models.Posting{
+				{Wallet: "addr1", Currency: "USD", Amount: money.MustParse("-10.40")},
+				{Wallet: "addr2", Currency: "USD", Amount: money.FromInt(10)},
+				{Wallet: "fees", Currency: "USD", Amount: money.MustParse("0.40")},
+			},
+		},
+		{
+			name: "balanced in each currency",
+			postings: []models.Posting{
+				{Wallet: "addr1", Currency: "USD", Amount: money.MustParse("-10.50")},
+				{Account: models.LedgerAccountExchange, Currency: "USD", Amount: money.MustParse("10.50")},
+				{Account: models.LedgerAccountExchange, Currency: "EUR", Amount: money.MustParse("-9.61")},
+				{Wallet: "addr2", Currency: "EUR", Amount: money.MustParse("9.61")},
+			},
+		},
+		{
+			name: "sum is not zero",
+			postings: []models.Posting{
+				{Wallet: "addr1", Currency: "USD", Amount: money.FromInt(-10)},
+				{Wallet: "addr2", Currency: "USD", Amount: money.MustParse("10.01")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "balanced only across currencies",
+			postings: []models.Posting{
+				{Wallet: "addr1", Currency: "USD", Amount: money.FromInt(-10)},
+				{Wallet: "addr2", Currency: "EUR", Amount: money.FromInt(10)},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "single posting",
+			postings: []models.Posting{{Wallet: "addr1", Currency: "USD", Amount: money.FromInt(10)}},
+			wantErr:  true,
+		},
+		{
+			name: "zero amount",
+			postings: []models.Posting{
+				{Wallet: "addr1", Currency: "USD", Amount: 0},
+				{Wallet: "addr2", Currency: "USD", Amount: 0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "both wallet and account",
+			postings: []models.Posting{
+				{Wallet: "addr1", Account: models.LedgerAccountEquity, Currency: "USD", Amount: money.FromInt(-10)},
+				{Wallet: "addr2", Currency: "USD", Amount: money.FromInt(10)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBalanced(&models.JournalEntry{Kind: models.JournalEntryKindTransfer, Postings: tt.postings})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrUnbalancedEntry)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPostEntry_RejectsUnbalanced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Несбалансированная запись не должна доходить до репозитория.
+	ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+	err := postEntry(context.Background(), ledgerRepo, &models.JournalEntry{
+		Kind: models.JournalEntryKindTransfer,
+		Postings: []models.Posting{
+			{Wallet: "addr1", Currency: "USD", Amount: money.FromInt(-10)},
+			{Wallet: "addr2", Currency: "USD", Amount: money.FromInt(11)},
+		},
+	})
+
+	assert.ErrorIs(t, err, domain.ErrUnbalancedEntry)
+}
+
+func TestLedgerService_RebuildBalances(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        func(r *repository_mocks.MockLedger)
+		expected    int64
+		expectedErr error
+	}{
+		{
+			name: "success",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().RebuildBalances(gomock.Any()).Return(int64(2), nil)
+			},
+			expected: 2,
+		},
+		{
+			name: "repository error",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().RebuildBalances(gomock.Any()).Return(int64(0), errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Ledger: ledgerRepo})
+			})
+			tt.mock(ledgerRepo)
+
+			service := NewLedgerService(&repository.Repository{UnitOfWork: uow})
+			corrected, err := service.RebuildBalances(context.Background())
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, corrected)
+			}
+		})
+	}
+}
+
+func TestLedgerService_Reconcile(t *testing.T) {
+	tests := []struct {
+		name               string
+		mock               func(r *repository_mocks.MockLedger)
+		expectedChecked    int
+		expectedDiffs      []models.BalanceDiscrepancy
+		expectedUnbalanced []int64
+		expectedUnrecorded []int
+		expectedMismatched []int64
+		expectedErr        bool
+	}{
+		{
+			name: "consistent",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().Balances(gomock.Any()).Return([]models.LedgerBalance{
+					{Address: "addr1", Currency: "USD", Stored: money.FromInt(100), Ledger: money.FromInt(100), Expected: money.FromInt(100)},
+					{Address: "addr2", Currency: "EUR", Stored: 0, Ledger: 0, Expected: 0},
+				}, nil)
+				r.EXPECT().UnbalancedEntries(gomock.Any()).Return([]int64{}, nil)
+				r.EXPECT().UnrecordedTransactions(gomock.Any()).Return([]int{}, nil)
+				r.EXPECT().MismatchedEntries(gomock.Any()).Return([]int64{}, nil)
+			},
+			expectedChecked:    2,
+			expectedDiffs:      []models.BalanceDiscrepancy{},
+			expectedUnbalanced: []int64{},
+			expectedUnrecorded: []int{},
+			expectedMismatched: []int64{},
+		},
+		{
+			name: "discrepancies",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().Balances(gomock.Any()).Return([]models.LedgerBalance{
+					{Address: "addr1", Currency: "USD", Stored: money.FromInt(100), Ledger: money.FromInt(100), Expected: money.FromInt(100)},
+					{Address: "addr2", Currency: "USD", Stored: money.MustParse("15.50"), Ledger: money.FromInt(10), Expected: money.FromInt(10)},
+					{Address: "addr3", Currency: "EUR", Stored: 0, Ledger: money.FromInt(5), Expected: money.FromInt(5)},
+				}, nil)
+				r.EXPECT().UnbalancedEntries(gomock.Any()).Return([]int64{7}, nil)
+				r.EXPECT().UnrecordedTransactions(gomock.Any()).Return([]int{}, nil)
+				r.EXPECT().MismatchedEntries(gomock.Any()).Return([]int64{}, nil)
+			},
+			expectedChecked: 3,
+			expectedDiffs: []models.BalanceDiscrepancy{
+				{Address: "addr2", Currency: "USD", Stored: money.MustParse("15.50"), Ledger: money.FromInt(10), Expected: money.FromInt(10), Difference: money.MustParse("5.50")},
+				{Address: "addr3", Currency: "EUR", Stored: 0, Ledger: money.FromInt(5), Expected: money.FromInt(5), Difference: money.FromInt(-5)},
+			},
+			expectedUnbalanced: []int64{7},
+			expectedUnrecorded: []int{},
+			expectedMismatched: []int64{},
+		},
+		{
+			// Перевод 12 изменил балансы, но не попал в журнал, а запись 9 зачислила получателю не ту сумму:
+			// кэшированные балансы сходятся с журналом, но журнал расходится с историей транзакций.
+			name: "journal diverges from transactions",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().Balances(gomock.Any()).Return([]models.LedgerBalance{
+					{Address: "addr1", Currency: "USD", Stored: money.FromInt(90), Ledger: money.FromInt(90), Expected: money.FromInt(80)},
+					{Address: "addr2", Currency: "USD", Stored: money.FromInt(110), Ledger: money.FromInt(110), Expected: money.FromInt(120)},
+				}, nil)
+				r.EXPECT().UnbalancedEntries(gomock.Any()).Return([]int64{}, nil)
+				r.EXPECT().UnrecordedTransactions(gomock.Any()).Return([]int{12}, nil)
+				r.EXPECT().MismatchedEntries(gomock.Any()).Return([]int64{9}, nil)
+			},
+			expectedChecked: 2,
+			expectedDiffs: []models.BalanceDiscrepancy{
+				{Address: "addr1", Currency: "USD", Stored: money.FromInt(90), Ledger: money.FromInt(90), Expected: money.FromInt(80), Difference: 0},
+				{Address: "addr2", Currency: "USD", Stored: money.FromInt(110), Ledger: money.FromInt(110), Expected: money.FromInt(120), Difference: 0},
+			},
+			expectedUnbalanced: []int64{},
+			expectedUnrecorded: []int{12},
+			expectedMismatched: []int64{9},
+		},
+		{
+			name: "balances error",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().Balances(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unbalanced entries error",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().Balances(gomock.Any()).Return([]models.LedgerBalance{}, nil)
+				r.EXPECT().UnbalancedEntries(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unrecorded transactions error",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().Balances(gomock.Any()).Return([]models.LedgerBalance{}, nil)
+				r.EXPECT().UnbalancedEntries(gomock.Any()).Return([]int64{}, nil)
+				r.EXPECT().UnrecordedTransactions(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "mismatched entries error",
+			mock: func(r *repository_mocks.MockLedger) {
+				r.EXPECT().Balances(gomock.Any()).Return([]models.LedgerBalance{}, nil)
+				r.EXPECT().UnbalancedEntries(gomock.Any()).Return([]int64{}, nil)
+				r.EXPECT().UnrecordedTransactions(gomock.Any()).Return([]int{}, nil)
+				r.EXPECT().MismatchedEntries(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			tt.mock(ledgerRepo)
+
+			service := NewLedgerService(&repository.Repository{Ledger: ledgerRepo})
+			report, err := service.Reconcile(context.Background())
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedChecked, report.WalletsChecked)
+			assert.Equal(t, tt.expectedDiffs, report.Discrepancies)
+			assert.Equal(t, tt.expectedUnbalanced, report.UnbalancedEntries)
+			assert.Equal(t, tt.expectedUnrecorded, report.UnrecordedTransactions)
+			assert.Equal(t, tt.expectedMismatched, report.MismatchedEntries)
+			assert.Equal(t, len(tt.expectedDiffs) == 0 && len(tt.expectedUnbalanced) == 0 &&
+				len(tt.expectedUnrecorded) == 0 && len(tt.expectedMismatched) == 0, report.Consistent())
+			assert.False(t, report.GeneratedAt.IsZero())
+		})
+	}
+}
@@ -1,12 +1,21 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
 
 // Package mock_service is a generated GoMock package.
 package mock_service
 
 import (
+	context "context"
+	auth "golangTestTask/internal/auth"
 	models "golangTestTask/internal/models"
+	money "golangTestTask/pkg/money"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -15,6 +24,7 @@ import (
 type MockWallet struct {
 	ctrl     *gomock.Controller
 	recorder *MockWalletMockRecorder
+	isgomock struct{}
 }
 
 // MockWalletMockRecorder is the mock recorder for MockWallet.
@@ -35,81 +45,212 @@ func (m *MockWallet) EXPECT() *MockWalletMockRecorder {
 }
 
 // BaseWallets mocks base method.
-func (m *MockWallet) BaseWallets(count int, balance float64) error {
+func (m *MockWallet) BaseWallets(ctx context.Context, count int, balance money.Amount, currencies []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BaseWallets", count, balance)
+	ret := m.ctrl.Call(m, "BaseWallets", ctx, count, balance, currencies)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BaseWallets indicates an expected call of BaseWallets.
-func (mr *MockWalletMockRecorder) BaseWallets(count, balance interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) BaseWallets(ctx, count, balance, currencies any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseWallets", reflect.TypeOf((*MockWallet)(nil).BaseWallets), count, balance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseWallets", reflect.TypeOf((*MockWallet)(nil).BaseWallets), ctx, count, balance, currencies)
 }
 
 // CreateRandomWallets mocks base method.
-func (m *MockWallet) CreateRandomWallets(count int, balance float64) error {
+func (m *MockWallet) CreateRandomWallets(ctx context.Context, count int, balance money.Amount, currency string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRandomWallets", count, balance)
+	ret := m.ctrl.Call(m, "CreateRandomWallets", ctx, count, balance, currency)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateRandomWallets indicates an expected call of CreateRandomWallets.
-func (mr *MockWalletMockRecorder) CreateRandomWallets(count, balance interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) CreateRandomWallets(ctx, count, balance, currency any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRandomWallets", reflect.TypeOf((*MockWallet)(nil).CreateRandomWallets), count, balance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRandomWallets", reflect.TypeOf((*MockWallet)(nil).CreateRandomWallets), ctx, count, balance, currency)
 }
 
 // CreateWallet mocks base method.
-func (m *MockWallet) CreateWallet(arg0 models.Wallet) error {
+func (m *MockWallet) CreateWallet(ctx context.Context, wallet models.Wallet) (*models.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWallet", arg0)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateWallet", ctx, wallet)
+	ret0, _ := ret[0].(*models.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateWallet indicates an expected call of CreateWallet.
-func (mr *MockWalletMockRecorder) CreateWallet(arg0 interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) CreateWallet(ctx, wallet any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockWallet)(nil).CreateWallet), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockWallet)(nil).CreateWallet), ctx, wallet)
 }
 
 // GetAllWallets mocks base method.
-func (m *MockWallet) GetAllWallets() ([]models.Wallet, error) {
+func (m *MockWallet) GetAllWallets(ctx context.Context) ([]models.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllWallets")
+	ret := m.ctrl.Call(m, "GetAllWallets", ctx)
 	ret0, _ := ret[0].([]models.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllWallets indicates an expected call of GetAllWallets.
-func (mr *MockWalletMockRecorder) GetAllWallets() *gomock.Call {
+func (mr *MockWalletMockRecorder) GetAllWallets(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWallets", reflect.TypeOf((*MockWallet)(nil).GetAllWallets), ctx)
+}
+
+// GetWallet mocks base method.
+func (m *MockWallet) GetWallet(ctx context.Context, address string) (*models.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWallet", ctx, address)
+	ret0, _ := ret[0].(*models.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWallet indicates an expected call of GetWallet.
+func (mr *MockWalletMockRecorder) GetWallet(ctx, address any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWallets", reflect.TypeOf((*MockWallet)(nil).GetAllWallets))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallet", reflect.TypeOf((*MockWallet)(nil).GetWallet), ctx, address)
 }
 
 // GetWalletBalance mocks base method.
-func (m *MockWallet) GetWalletBalance(address string) (float64, error) {
+func (m *MockWallet) GetWalletBalance(ctx context.Context, address string) (*models.WalletBalance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWalletBalance", address)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "GetWalletBalance", ctx, address)
+	ret0, _ := ret[0].(*models.WalletBalance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetWalletBalance indicates an expected call of GetWalletBalance.
-func (mr *MockWalletMockRecorder) GetWalletBalance(address interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) GetWalletBalance(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletBalance", reflect.TypeOf((*MockWallet)(nil).GetWalletBalance), ctx, address)
+}
+
+// GetWalletStats mocks base method.
+func (m *MockWallet) GetWalletStats(ctx context.Context) ([]models.WalletStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletStats", ctx)
+	ret0, _ := ret[0].([]models.WalletStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletStats indicates an expected call of GetWalletStats.
+func (mr *MockWalletMockRecorder) GetWalletStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletStats", reflect.TypeOf((*MockWallet)(nil).GetWalletStats), ctx)
+}
+
+// SetWalletStatus mocks base method.
+func (m *MockWallet) SetWalletStatus(ctx context.Context, address string, status models.WalletStatus) (*models.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWalletStatus", ctx, address, status)
+	ret0, _ := ret[0].(*models.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWalletStatus indicates an expected call of SetWalletStatus.
+func (mr *MockWalletMockRecorder) SetWalletStatus(ctx, address, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWalletStatus", reflect.TypeOf((*MockWallet)(nil).SetWalletStatus), ctx, address, status)
+}
+
+// SetWalletTier mocks base method.
+func (m *MockWallet) SetWalletTier(ctx context.Context, address, tier string) (*models.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWalletTier", ctx, address, tier)
+	ret0, _ := ret[0].(*models.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWalletTier indicates an expected call of SetWalletTier.
+func (mr *MockWalletMockRecorder) SetWalletTier(ctx, address, tier any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletBalance", reflect.TypeOf((*MockWallet)(nil).GetWalletBalance), address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWalletTier", reflect.TypeOf((*MockWallet)(nil).SetWalletTier), ctx, address, tier)
+}
+
+// MockTier is a mock of Tier interface.
+type MockTier struct {
+	ctrl     *gomock.Controller
+	recorder *MockTierMockRecorder
+	isgomock struct{}
+}
+
+// MockTierMockRecorder is the mock recorder for MockTier.
+type MockTierMockRecorder struct {
+	mock *MockTier
+}
+
+// NewMockTier creates a new mock instance.
+func NewMockTier(ctrl *gomock.Controller) *MockTier {
+	mock := &MockTier{ctrl: ctrl}
+	mock.recorder = &MockTierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTier) EXPECT() *MockTierMockRecorder {
+	return m.recorder
+}
+
+// CreateTier mocks base method.
+func (m *MockTier) CreateTier(ctx context.Context, tier models.WalletTier) (*models.WalletTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTier", ctx, tier)
+	ret0, _ := ret[0].(*models.WalletTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTier indicates an expected call of CreateTier.
+func (mr *MockTierMockRecorder) CreateTier(ctx, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTier", reflect.TypeOf((*MockTier)(nil).CreateTier), ctx, tier)
+}
+
+// GetAllTiers mocks base method.
+func (m *MockTier) GetAllTiers(ctx context.Context) ([]models.WalletTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTiers", ctx)
+	ret0, _ := ret[0].([]models.WalletTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTiers indicates an expected call of GetAllTiers.
+func (mr *MockTierMockRecorder) GetAllTiers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTiers", reflect.TypeOf((*MockTier)(nil).GetAllTiers), ctx)
+}
+
+// UpdateTier mocks base method.
+func (m *MockTier) UpdateTier(ctx context.Context, tier models.WalletTier) (*models.WalletTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTier", ctx, tier)
+	ret0, _ := ret[0].(*models.WalletTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTier indicates an expected call of UpdateTier.
+func (mr *MockTierMockRecorder) UpdateTier(ctx, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTier", reflect.TypeOf((*MockTier)(nil).UpdateTier), ctx, tier)
 }
 
 // MockTransaction is a mock of Transaction interface.
 type MockTransaction struct {
 	ctrl     *gomock.Controller
 	recorder *MockTransactionMockRecorder
+	isgomock struct{}
 }
 
 // MockTransactionMockRecorder is the mock recorder for MockTransaction.
@@ -130,30 +271,609 @@ func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
 }
 
 // GetLastTransactions mocks base method.
-func (m *MockTransaction) GetLastTransactions(count int) ([]models.Transaction, error) {
+func (m *MockTransaction) GetLastTransactions(ctx context.Context, count int) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLastTransactions", count)
+	ret := m.ctrl.Call(m, "GetLastTransactions", ctx, count)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLastTransactions indicates an expected call of GetLastTransactions.
-func (mr *MockTransactionMockRecorder) GetLastTransactions(count interface{}) *gomock.Call {
+func (mr *MockTransactionMockRecorder) GetLastTransactions(ctx, count any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTransactions", reflect.TypeOf((*MockTransaction)(nil).GetLastTransactions), count)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTransactions", reflect.TypeOf((*MockTransaction)(nil).GetLastTransactions), ctx, count)
+}
+
+// ListTransactions mocks base method.
+func (m *MockTransaction) ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) (*models.TransactionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, filter, cursor)
+	ret0, _ := ret[0].(*models.TransactionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockTransactionMockRecorder) ListTransactions(ctx, filter, cursor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransaction)(nil).ListTransactions), ctx, filter, cursor)
+}
+
+// QuoteTransfer mocks base method.
+func (m *MockTransaction) QuoteTransfer(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteTransfer", ctx, req)
+	ret0, _ := ret[0].(*models.TransferQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteTransfer indicates an expected call of QuoteTransfer.
+func (mr *MockTransactionMockRecorder) QuoteTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransfer", reflect.TypeOf((*MockTransaction)(nil).QuoteTransfer), ctx, req)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockTransaction) ReverseTransaction(ctx context.Context, id int, req models.ReverseTransactionRequest) (*models.TransactionReversal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", ctx, id, req)
+	ret0, _ := ret[0].(*models.TransactionReversal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockTransactionMockRecorder) ReverseTransaction(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockTransaction)(nil).ReverseTransaction), ctx, id, req)
 }
 
 // TransferFunds mocks base method.
-func (m *MockTransaction) TransferFunds(from, to string, amount float64) error {
+func (m *MockTransaction) TransferFunds(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferFunds", ctx, req)
+	ret0, _ := ret[0].(*models.TransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferFunds indicates an expected call of TransferFunds.
+func (mr *MockTransactionMockRecorder) TransferFunds(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferFunds", reflect.TypeOf((*MockTransaction)(nil).TransferFunds), ctx, req)
+}
+
+// MockLedger is a mock of Ledger interface.
+type MockLedger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerMockRecorder
+	isgomock struct{}
+}
+
+// MockLedgerMockRecorder is the mock recorder for MockLedger.
+type MockLedgerMockRecorder struct {
+	mock *MockLedger
+}
+
+// NewMockLedger creates a new mock instance.
+func NewMockLedger(ctrl *gomock.Controller) *MockLedger {
+	mock := &MockLedger{ctrl: ctrl}
+	mock.recorder = &MockLedgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedger) EXPECT() *MockLedgerMockRecorder {
+	return m.recorder
+}
+
+// RebuildBalances mocks base method.
+func (m *MockLedger) RebuildBalances(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildBalances", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildBalances indicates an expected call of RebuildBalances.
+func (mr *MockLedgerMockRecorder) RebuildBalances(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildBalances", reflect.TypeOf((*MockLedger)(nil).RebuildBalances), ctx)
+}
+
+// Reconcile mocks base method.
+func (m *MockLedger) Reconcile(ctx context.Context) (*models.ReconciliationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx)
+	ret0, _ := ret[0].(*models.ReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockLedgerMockRecorder) Reconcile(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockLedger)(nil).Reconcile), ctx)
+}
+
+// RunReconciler mocks base method.
+func (m *MockLedger) RunReconciler(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunReconciler", ctx, interval)
+}
+
+// RunReconciler indicates an expected call of RunReconciler.
+func (mr *MockLedgerMockRecorder) RunReconciler(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunReconciler", reflect.TypeOf((*MockLedger)(nil).RunReconciler), ctx, interval)
+}
+
+// MockScheduledTransfer is a mock of ScheduledTransfer interface.
+type MockScheduledTransfer struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledTransferMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduledTransferMockRecorder is the mock recorder for MockScheduledTransfer.
+type MockScheduledTransferMockRecorder struct {
+	mock *MockScheduledTransfer
+}
+
+// NewMockScheduledTransfer creates a new mock instance.
+func NewMockScheduledTransfer(ctrl *gomock.Controller) *MockScheduledTransfer {
+	mock := &MockScheduledTransfer{ctrl: ctrl}
+	mock.recorder = &MockScheduledTransferMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledTransfer) EXPECT() *MockScheduledTransferMockRecorder {
+	return m.recorder
+}
+
+// CancelScheduledTransfer mocks base method.
+func (m *MockScheduledTransfer) CancelScheduledTransfer(ctx context.Context, id int) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledTransfer", ctx, id)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledTransfer indicates an expected call of CancelScheduledTransfer.
+func (mr *MockScheduledTransferMockRecorder) CancelScheduledTransfer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledTransfer", reflect.TypeOf((*MockScheduledTransfer)(nil).CancelScheduledTransfer), ctx, id)
+}
+
+// CreateScheduledTransfer mocks base method.
+func (m *MockScheduledTransfer) CreateScheduledTransfer(ctx context.Context, req models.CreateScheduledTransferRequest) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransfer", ctx, req)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransfer indicates an expected call of CreateScheduledTransfer.
+func (mr *MockScheduledTransferMockRecorder) CreateScheduledTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransfer", reflect.TypeOf((*MockScheduledTransfer)(nil).CreateScheduledTransfer), ctx, req)
+}
+
+// ListScheduledTransferRuns mocks base method.
+func (m *MockScheduledTransfer) ListScheduledTransferRuns(ctx context.Context, id int) ([]models.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransferRuns", ctx, id)
+	ret0, _ := ret[0].([]models.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransferRuns indicates an expected call of ListScheduledTransferRuns.
+func (mr *MockScheduledTransferMockRecorder) ListScheduledTransferRuns(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransferRuns", reflect.TypeOf((*MockScheduledTransfer)(nil).ListScheduledTransferRuns), ctx, id)
+}
+
+// ListScheduledTransfers mocks base method.
+func (m *MockScheduledTransfer) ListScheduledTransfers(ctx context.Context, wallet string) ([]models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransfers", ctx, wallet)
+	ret0, _ := ret[0].([]models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransfers indicates an expected call of ListScheduledTransfers.
+func (mr *MockScheduledTransferMockRecorder) ListScheduledTransfers(ctx, wallet any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransfers", reflect.TypeOf((*MockScheduledTransfer)(nil).ListScheduledTransfers), ctx, wallet)
+}
+
+// RunScheduler mocks base method.
+func (m *MockScheduledTransfer) RunScheduler(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunScheduler", ctx, interval)
+}
+
+// RunScheduler indicates an expected call of RunScheduler.
+func (mr *MockScheduledTransferMockRecorder) RunScheduler(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunScheduler", reflect.TypeOf((*MockScheduledTransfer)(nil).RunScheduler), ctx, interval)
+}
+
+// MockHold is a mock of Hold interface.
+type MockHold struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldMockRecorder
+	isgomock struct{}
+}
+
+// MockHoldMockRecorder is the mock recorder for MockHold.
+type MockHoldMockRecorder struct {
+	mock *MockHold
+}
+
+// NewMockHold creates a new mock instance.
+func NewMockHold(ctrl *gomock.Controller) *MockHold {
+	mock := &MockHold{ctrl: ctrl}
+	mock.recorder = &MockHoldMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHold) EXPECT() *MockHoldMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeHold mocks base method.
+func (m *MockHold) AuthorizeHold(ctx context.Context, req models.CreateHoldRequest) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeHold", ctx, req)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeHold indicates an expected call of AuthorizeHold.
+func (mr *MockHoldMockRecorder) AuthorizeHold(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeHold", reflect.TypeOf((*MockHold)(nil).AuthorizeHold), ctx, req)
+}
+
+// CaptureHold mocks base method.
+func (m *MockHold) CaptureHold(ctx context.Context, id int, req models.CaptureHoldRequest) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, id, req)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockHoldMockRecorder) CaptureHold(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockHold)(nil).CaptureHold), ctx, id, req)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockHold) ExpireHolds(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockHoldMockRecorder) ExpireHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockHold)(nil).ExpireHolds), ctx)
+}
+
+// GetHold mocks base method.
+func (m *MockHold) GetHold(ctx context.Context, id int) (*models.Hold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferFunds", from, to, amount)
+	ret := m.ctrl.Call(m, "GetHold", ctx, id)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockHoldMockRecorder) GetHold(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockHold)(nil).GetHold), ctx, id)
+}
+
+// RunHoldExpirer mocks base method.
+func (m *MockHold) RunHoldExpirer(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunHoldExpirer", ctx, interval)
+}
+
+// RunHoldExpirer indicates an expected call of RunHoldExpirer.
+func (mr *MockHoldMockRecorder) RunHoldExpirer(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunHoldExpirer", reflect.TypeOf((*MockHold)(nil).RunHoldExpirer), ctx, interval)
+}
+
+// VoidHold mocks base method.
+func (m *MockHold) VoidHold(ctx context.Context, id int) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, id)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockHoldMockRecorder) VoidHold(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockHold)(nil).VoidHold), ctx, id)
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockIdempotency) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockIdempotencyMockRecorder) DeleteExpiredIdempotencyKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockIdempotency)(nil).DeleteExpiredIdempotencyKeys), ctx)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockIdempotency) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// TransferFunds indicates an expected call of TransferFunds.
-func (mr *MockTransactionMockRecorder) TransferFunds(from, to, amount interface{}) *gomock.Call {
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockIdempotencyMockRecorder) ReleaseIdempotencyKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockIdempotency)(nil).ReleaseIdempotencyKey), ctx, key)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockIdempotency) ReserveIdempotencyKey(ctx context.Context, key, requestHash string) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, key, requestHash)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockIdempotencyMockRecorder) ReserveIdempotencyKey(ctx, key, requestHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockIdempotency)(nil).ReserveIdempotencyKey), ctx, key, requestHash)
+}
+
+// RunIdempotencySweeper mocks base method.
+func (m *MockIdempotency) RunIdempotencySweeper(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunIdempotencySweeper", ctx, interval)
+}
+
+// RunIdempotencySweeper indicates an expected call of RunIdempotencySweeper.
+func (mr *MockIdempotencyMockRecorder) RunIdempotencySweeper(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunIdempotencySweeper", reflect.TypeOf((*MockIdempotency)(nil).RunIdempotencySweeper), ctx, interval)
+}
+
+// SaveIdempotentResponse mocks base method.
+func (m *MockIdempotency) SaveIdempotentResponse(ctx context.Context, key string, statusCode int, responseBody []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", ctx, key, statusCode, responseBody)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse.
+func (mr *MockIdempotencyMockRecorder) SaveIdempotentResponse(ctx, key, statusCode, responseBody any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockIdempotency)(nil).SaveIdempotentResponse), ctx, key, statusCode, responseBody)
+}
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuth) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthMockRecorder) Authenticate(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuth)(nil).Authenticate), ctx, key)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAuth) CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, req)
+	ret0, _ := ret[0].(*models.CreateAPIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAuthMockRecorder) CreateAPIKey(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAuth)(nil).CreateAPIKey), ctx, req)
+}
+
+// EnsureAPIKey mocks base method.
+func (m *MockAuth) EnsureAPIKey(ctx context.Context, name, key string, scopes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureAPIKey", ctx, name, key, scopes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureAPIKey indicates an expected call of EnsureAPIKey.
+func (mr *MockAuthMockRecorder) EnsureAPIKey(ctx, name, key, scopes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureAPIKey", reflect.TypeOf((*MockAuth)(nil).EnsureAPIKey), ctx, name, key, scopes)
+}
+
+// MockSession is a mock of Session interface.
+type MockSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionMockRecorder
+	isgomock struct{}
+}
+
+// MockSessionMockRecorder is the mock recorder for MockSession.
+type MockSessionMockRecorder struct {
+	mock *MockSession
+}
+
+// NewMockSession creates a new mock instance.
+func NewMockSession(ctrl *gomock.Controller) *MockSession {
+	mock := &MockSession{ctrl: ctrl}
+	mock.recorder = &MockSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSession) EXPECT() *MockSessionMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateToken mocks base method.
+func (m *MockSession) AuthenticateToken(ctx context.Context, token string) (*auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateToken", ctx, token)
+	ret0, _ := ret[0].(*auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateToken indicates an expected call of AuthenticateToken.
+func (mr *MockSessionMockRecorder) AuthenticateToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateToken", reflect.TypeOf((*MockSession)(nil).AuthenticateToken), ctx, token)
+}
+
+// CreateUser mocks base method.
+func (m *MockSession) CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, req)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockSessionMockRecorder) CreateUser(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockSession)(nil).CreateUser), ctx, req)
+}
+
+// Login mocks base method.
+func (m *MockSession) Login(ctx context.Context, username, password string) (*models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, username, password)
+	ret0, _ := ret[0].(*models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockSessionMockRecorder) Login(ctx, username, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockSession)(nil).Login), ctx, username, password)
+}
+
+// Logout mocks base method.
+func (m *MockSession) Logout(ctx context.Context, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockSessionMockRecorder) Logout(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockSession)(nil).Logout), ctx, refreshToken)
+}
+
+// Refresh mocks base method.
+func (m *MockSession) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(*models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockSessionMockRecorder) Refresh(ctx, refreshToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferFunds", reflect.TypeOf((*MockTransaction)(nil).TransferFunds), from, to, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockSession)(nil).Refresh), ctx, refreshToken)
 }
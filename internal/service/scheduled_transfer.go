@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/metrics"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"golangTestTask/pkg/money"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// maxScheduledTransferAttempts — максимальное число попыток выполнить регулярный перевод за один срок.
+	maxScheduledTransferAttempts = 5
+	// scheduledTransferRetryDelay — задержка перед первой повторной попыткой; каждая следующая вдвое больше предыдущей.
+	scheduledTransferRetryDelay = time.Minute
+	// minScheduleInterval — минимальный интервал между выполнениями регулярного перевода.
+	minScheduleInterval = time.Minute
+	// scheduledTransferRunsLimit — число последних попыток, которое возвращает ListScheduledTransferRuns.
+	scheduledTransferRunsLimit = 50
+)
+
+// scheduleParser разбирает расписания в формате cron из пяти полей и сокращения вида @monthly и @every.
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type ScheduledTransferService struct {
+	repo        repository.ScheduledTransfer
+	wallet_repo repository.Wallet
+	uow         repository.UnitOfWork
+	transfers   Transaction
+	now         func() time.Time
+}
+
+// NewScheduledTransferService создает новый экземпляр ScheduledTransferService, выполняющий регулярные переводы через transfers.
+func NewScheduledTransferService(repo *repository.Repository, transfers Transaction) *ScheduledTransferService {
+	return &ScheduledTransferService{
+		repo:        repo.ScheduledTransfer,
+		wallet_repo: repo.Wallet,
+		uow:         repo.UnitOfWork,
+		transfers:   transfers,
+		now:         time.Now,
+	}
+}
+
+// CreateScheduledTransfer создает регулярный перевод req и назначает его первое выполнение на ближайший по расписанию срок.
+// Создавать регулярные переводы можно только с кошельков, с которых участнику из ctx разрешено списывать средства;
+// перевод выполняется от имени этого участника.
+// Расписание, сумма, адреса и кошельки проверяются так же, как при переводе: перевод между кошельками в разных валютах
+// допускается только с req.Convert, а сумма должна записываться с точностью валюты отправителя.
+func (s *ScheduledTransferService) CreateScheduledTransfer(ctx context.Context, req models.CreateScheduledTransferRequest) (*models.ScheduledTransfer, error) {
+	if err := checkAddresses(ctx, s.wallet_repo, req.From, req.To); err != nil {
+		return nil, err
+	}
+	if err := checkCanDebit(ctx, req.From); err != nil {
+		return nil, err
+	}
+	if req.From == req.To {
+		return nil, domain.ErrSameWallet
+	}
+	now := s.now().UTC()
+	schedule, err := parseSchedule(req.Schedule, now)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet_from, err := getWallet(ctx, s.wallet_repo, req.From, models.TransactionRoleSender)
+	if err != nil {
+		return nil, err
+	}
+	wallet_to, err := getWallet(ctx, s.wallet_repo, req.To, models.TransactionRoleRecipient)
+	if err != nil {
+		return nil, err
+	}
+	if wallet_from.Status == models.WalletStatusClosed {
+		return nil, domain.NewWalletError(models.TransactionRoleSender, wallet_from.Address, domain.ErrWalletClosed)
+	}
+	if wallet_to.Status == models.WalletStatusClosed {
+		return nil, domain.NewWalletError(models.TransactionRoleRecipient, wallet_to.Address, domain.ErrWalletClosed)
+	}
+	if wallet_from.Currency != wallet_to.Currency && !req.Convert {
+		return nil, domain.ErrCurrencyMismatch
+	}
+	currency, ok := money.LookupCurrency(wallet_from.Currency)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedCurrency, wallet_from.Currency)
+	}
+	if !currency.Fits(req.Amount) {
+		return nil, domain.ErrInvalidAmountPrecision
+	}
+
+	transfer := &models.ScheduledTransfer{
+		From:      req.From,
+		To:        req.To,
+		Amount:    req.Amount,
+		Convert:   req.Convert,
+		Schedule:  req.Schedule,
+		NextRunAt: schedule.Next(now),
+	}
+	if principal := auth.FromContext(ctx); principal.KeyID != 0 {
+		keyID := principal.KeyID
+		transfer.CreatedByKeyID = &keyID
+	} else if principal.UserID != 0 {
+		userID := principal.UserID
+		transfer.CreatedByUserID = &userID
+	}
+	if err := s.repo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// ListScheduledTransfers возвращает регулярные переводы с кошелька wallet. Если wallet не задан, возвращаются переводы
+// со всех кошельков участника из ctx, а для ролей с доступом ко всем кошелькам — все регулярные переводы.
+func (s *ScheduledTransferService) ListScheduledTransfers(ctx context.Context, wallet string) ([]models.ScheduledTransfer, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	var wallets []string
+	switch {
+	case wallet != "":
+		if !principal.CanReadWallet(wallet) {
+			return nil, domain.ErrWalletNotOwned
+		}
+		wallets = []string{wallet}
+	case !principal.HasPermission(auth.PermissionReadAllWallets):
+		// Пустой, а не nil список: у участника без кошельков нет регулярных переводов.
+		wallets = append([]string{}, principal.Wallets...)
+	}
+	return s.repo.List(ctx, wallets)
+}
+
+// CancelScheduledTransfer отменяет регулярный перевод id и возвращает его. Отменить перевод может тот,
+// кому разрешено списывать средства с кошелька отправителя. Выполняемый в этот момент перевод завершается до отмены.
+func (s *ScheduledTransferService) CancelScheduledTransfer(ctx context.Context, id int) (*models.ScheduledTransfer, error) {
+	transfer, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCanDebit(ctx, transfer.From); err != nil {
+		return nil, err
+	}
+	return s.repo.Cancel(ctx, id)
+}
+
+// ListScheduledTransferRuns возвращает последние попытки выполнить регулярный перевод id от новых к старым.
+// Попытки доступны тем, кому разрешено просматривать кошелек отправителя.
+func (s *ScheduledTransferService) ListScheduledTransferRuns(ctx context.Context, id int) ([]models.ScheduledTransferRun, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return nil, domain.ErrUnauthenticated
+	}
+	transfer, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.CanReadWallet(transfer.From) {
+		return nil, domain.ErrWalletNotOwned
+	}
+	return s.repo.ListRuns(ctx, id, scheduledTransferRunsLimit)
+}
+
+// RunScheduler раз в interval выполняет все наступившие регулярные переводы, пока не отменен ctx.
+func (s *ScheduledTransferService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.executeDueTransfers(ctx)
+		}
+	}
+}
+
+// executeDueTransfers выполняет наступившие регулярные переводы по одному, пока они не закончатся,
+// не будет отменен ctx или не произойдет ошибка БД.
+func (s *ScheduledTransferService) executeDueTransfers(ctx context.Context) {
+	for ctx.Err() == nil {
+		executed, err := s.executeNext(ctx)
+		if err != nil {
+			log.Printf("Failed to execute scheduled transfers: %v", err)
+			return
+		}
+		if !executed {
+			return
+		}
+	}
+}
+
+// executeNext выполняет регулярный перевод с самым ранним наступившим сроком и сообщает, был ли такой перевод.
+// Строка регулярного перевода заблокирована, пока выполняется перевод, записывается итог попытки и назначается
+// следующий срок, поэтому параллельные планировщики не выполняют один перевод дважды. Если сервис остановится
+// после перевода, но до фиксации итога, повторная попытка за тот же срок будет отклонена с ошибкой
+// domain.ErrScheduledTransferExecuted и засчитана как выполненная.
+// Перевод выполняется от имени его создателя. Если создателю больше не разрешено списывать средства с кошелька
+// отправителя, попытка записывается как неудавшаяся, а регулярный перевод отменяется.
+func (s *ScheduledTransferService) executeNext(ctx context.Context) (bool, error) {
+	now := s.now().UTC()
+	var run *models.ScheduledTransferRun
+	// cause — ошибка неудавшейся попытки; в итог попытки записывается только ее причина для клиента, а сама ошибка — в лог.
+	var cause error
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		transfer, err := repos.ScheduledTransfer.ClaimDue(ctx, now)
+		if err != nil || transfer == nil {
+			return err
+		}
+		schedule, err := scheduleParser.Parse(transfer.Schedule)
+		if err != nil {
+			return fmt.Errorf("invalid schedule of scheduled transfer %d: %w", transfer.ID, err)
+		}
+
+		principal, err := creator(ctx, repos, transfer)
+		if errors.Is(err, domain.ErrScheduledTransferUnauthorized) {
+			cause = err
+			run = &models.ScheduledTransferRun{
+				ScheduledTransferID: transfer.ID,
+				ScheduledFor:        transfer.NextRunAt,
+				Attempt:             transfer.Attempts + 1,
+				Status:              models.ScheduledTransferRunFailed,
+				Error:               failureReason(err),
+			}
+			if err := repos.ScheduledTransfer.CreateRun(ctx, *run); err != nil {
+				return err
+			}
+			_, err = repos.ScheduledTransfer.Cancel(ctx, transfer.ID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		run, cause = s.execute(auth.WithPrincipal(ctx, principal), transfer, schedule, now)
+		if err := repos.ScheduledTransfer.CreateRun(ctx, *run); err != nil {
+			return err
+		}
+		return repos.ScheduledTransfer.Reschedule(ctx, *transfer)
+	})
+	if err != nil {
+		return false, err
+	}
+	if run == nil {
+		return false, nil
+	}
+
+	metrics.ObserveScheduledTransferRun(run.Status)
+	if run.Status != models.ScheduledTransferRunCompleted {
+		log.Printf("Scheduled transfer %d for %s, attempt %d: %s: %v",
+			run.ScheduledTransferID, run.ScheduledFor.Format(time.RFC3339), run.Attempt, run.Status, cause)
+	}
+	return true, nil
+}
+
+// execute выполняет регулярный перевод transfer за срок transfer.NextRunAt от имени участника из ctx и возвращает итог попытки
+// и ошибку перевода, если попытка не удалась. В итог записывается причина отказа, возвращаемая failureReason.
+// Перевод, не удавшийся из-за временной ошибки, назначается на повторную попытку, пока не исчерпано
+// maxScheduledTransferAttempts попыток. После выполнения или отказа перевод назначается на ближайший после now срок
+// по расписанию schedule: сроки, пропущенные пока сервис был остановлен, не наверстываются.
+func (s *ScheduledTransferService) execute(ctx context.Context, transfer *models.ScheduledTransfer, schedule cron.Schedule, now time.Time) (*models.ScheduledTransferRun, error) {
+	transfer.Attempts++
+	run := &models.ScheduledTransferRun{
+		ScheduledTransferID: transfer.ID,
+		ScheduledFor:        transfer.NextRunAt,
+		Attempt:             transfer.Attempts,
+	}
+
+	result, err := s.transfers.TransferFunds(ctx, models.CreateTransactionRequest{
+		From:                transfer.From,
+		To:                  transfer.To,
+		Amount:              transfer.Amount,
+		Convert:             transfer.Convert,
+		ScheduledTransferID: transfer.ID,
+		ScheduledFor:        transfer.NextRunAt,
+	})
+	switch {
+	case err == nil:
+		run.Status = models.ScheduledTransferRunCompleted
+		run.TransactionID = &result.TransactionID
+	case errors.Is(err, domain.ErrScheduledTransferExecuted):
+		run.Status = models.ScheduledTransferRunCompleted
+		err = nil
+	case isTransient(err) && transfer.Attempts < maxScheduledTransferAttempts:
+		run.Status = models.ScheduledTransferRunRetrying
+		run.Error = failureReason(err)
+		retryAt := now.Add(retryDelay(transfer.Attempts, err))
+		transfer.RetryAt = &retryAt
+		return run, err
+	default:
+		run.Status = models.ScheduledTransferRunFailed
+		run.Error = failureReason(err)
+	}
+
+	transfer.Attempts = 0
+	transfer.RetryAt = nil
+	transfer.NextRunAt = schedule.Next(now)
+	return run, err
+}
+
+// creator возвращает участника, создавшего регулярный перевод transfer; для переводов, созданных сервисом, — auth.System().
+// Если ключ API создателя отозван, пользователь удален или ему больше не разрешено списывать средства с кошелька
+// отправителя, возвращает ошибку, оборачивающую domain.ErrScheduledTransferUnauthorized.
+func creator(ctx context.Context, repos *repository.Repository, transfer *models.ScheduledTransfer) (*auth.Principal, error) {
+	principal := auth.System()
+	switch {
+	case transfer.CreatedByKeyID != nil:
+		apiKey, err := repos.APIKey.GetByID(ctx, *transfer.CreatedByKeyID)
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, fmt.Errorf("%w: API key %d is revoked", domain.ErrScheduledTransferUnauthorized, *transfer.CreatedByKeyID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		principal = apiKeyPrincipal(apiKey)
+	case transfer.CreatedByUserID != nil:
+		user, err := repos.User.GetByID(ctx, *transfer.CreatedByUserID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, fmt.Errorf("%w: user %d not found", domain.ErrScheduledTransferUnauthorized, *transfer.CreatedByUserID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		principal = userPrincipal(user)
+	}
+	if !principal.CanDebit(transfer.From) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrScheduledTransferUnauthorized, principal.Subject())
+	}
+	return principal, nil
+}
+
+// isTransient сообщает, может ли перевод, отклоненный с ошибкой err, удаться при повторной попытке:
+// ошибка вызвана временной недоступностью БД или превышением ограничения на частоту или сумму переводов.
+func isTransient(err error) bool {
+	var limitErr *domain.LimitError
+	return errors.As(err, &limitErr) || repository.IsTransient(err)
+}
+
+// retryDelay возвращает задержку перед повторной попыткой после attempt неудачных попыток с последней ошибкой err.
+// Задержка не меньше времени, через которое снимется превышенное ограничение на переводы.
+func retryDelay(attempt int, err error) time.Duration {
+	delay := scheduledTransferRetryDelay << (attempt - 1)
+	var limitErr *domain.LimitError
+	if errors.As(err, &limitErr) && limitErr.RetryAfter > delay {
+		delay = limitErr.RetryAfter
+	}
+	return delay
+}
+
+// parseSchedule разбирает расписание expr и проверяет, что по нему есть срок после now,
+// а выполнения следуют не чаще, чем раз в minScheduleInterval.
+func parseSchedule(expr string, now time.Time) (cron.Schedule, error) {
+	if expr == "" {
+		return nil, domain.NewValidationError("schedule", "schedule is required")
+	}
+	schedule, err := scheduleParser.Parse(expr)
+	if err != nil {
+		return nil, domain.NewValidationError("schedule", "invalid schedule: "+err.Error())
+	}
+	first := schedule.Next(now)
+	if first.IsZero() {
+		return nil, domain.NewValidationError("schedule", "schedule never fires")
+	}
+	if second := schedule.Next(first); !second.IsZero() && second.Sub(first) < minScheduleInterval {
+		return nil, domain.NewValidationError("schedule", "schedule must not fire more than once a minute")
+	}
+	return schedule, nil
+}
+
+// getWallet возвращает кошелек address. Отсутствие кошелька возвращается как domain.WalletError с ролью кошелька в переводе.
+func getWallet(ctx context.Context, repo repository.Wallet, address string, role models.TransactionRole) (*models.Wallet, error) {
+	wallet, err := repo.Get(ctx, address)
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, domain.NewWalletError(role, address, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
@@ -0,0 +1,490 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	repository_mocks "golangTestTask/internal/repository/mocks"
+	service_mocks "golangTestTask/internal/service/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScheduledTransferService_CreateScheduledTransfer(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	keyID := 1
+	usd := func(address string) *models.Wallet {
+		return &models.Wallet{Address: address, Status: models.WalletStatusActive, Currency: "USD"}
+	}
+
+	tests := []struct {
+		name        string
+		req         models.CreateScheduledTransferRequest
+		mock        func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer)
+		expected    *models.ScheduledTransfer
+		expectedErr string
+	}{
+		{
+			name: "monthly",
+			req:  models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "0 9 1 * *"},
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {
+				w.EXPECT().Get(gomock.Any(), addr1).Return(usd(addr1), nil)
+				w.EXPECT().Get(gomock.Any(), addr2).Return(usd(addr2), nil)
+				r.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, transfer *models.ScheduledTransfer) error {
+					transfer.ID = 3
+					transfer.Status = models.ScheduledTransferStatusActive
+					return nil
+				})
+			},
+			expected: &models.ScheduledTransfer{ID: 3, From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "0 9 1 * *",
+				Status: models.ScheduledTransferStatusActive, NextRunAt: time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC), CreatedByKeyID: &keyID},
+		},
+		{
+			name: "descriptor with conversion",
+			req:  models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.FromInt(50), Convert: true, Schedule: "@daily"},
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {
+				w.EXPECT().Get(gomock.Any(), addr1).Return(usd(addr1), nil)
+				w.EXPECT().Get(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Status: models.WalletStatusActive, Currency: "EUR"}, nil)
+				r.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: &models.ScheduledTransfer{From: addr1, To: addr2, Amount: money.FromInt(50), Convert: true, Schedule: "@daily",
+				NextRunAt: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), CreatedByKeyID: &keyID},
+		},
+		{
+			name:        "invalid sender address",
+			req:         models.CreateScheduledTransferRequest{From: "addr1", To: addr2, Amount: money.FromInt(50), Schedule: "@daily"},
+			mock:        func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {},
+			expectedErr: "invalid wallet address: must be 64 characters long",
+		},
+		{
+			name:        "invalid schedule",
+			req:         models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "every month"},
+			mock:        func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {},
+			expectedErr: "invalid schedule",
+		},
+		{
+			name:        "too frequent",
+			req:         models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "@every 30s"},
+			mock:        func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {},
+			expectedErr: "schedule must not fire more than once a minute",
+		},
+		{
+			name:        "never fires",
+			req:         models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "0 9 30 2 *"},
+			mock:        func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {},
+			expectedErr: "schedule never fires",
+		},
+		{
+			name:        "not owned",
+			req:         models.CreateScheduledTransferRequest{From: addr3, To: addr2, Amount: money.FromInt(50), Schedule: "@daily"},
+			mock:        func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {},
+			expectedErr: "sender wallet is not owned by the caller",
+		},
+		{
+			name: "recipient not found",
+			req:  models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "@daily"},
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {
+				w.EXPECT().Get(gomock.Any(), addr1).Return(usd(addr1), nil)
+				w.EXPECT().Get(gomock.Any(), addr2).Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedErr: "recipient wallet not found",
+		},
+		{
+			name: "currency mismatch",
+			req:  models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "@daily"},
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {
+				w.EXPECT().Get(gomock.Any(), addr1).Return(usd(addr1), nil)
+				w.EXPECT().Get(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Status: models.WalletStatusActive, Currency: "EUR"}, nil)
+			},
+			expectedErr: "sender and recipient wallets have different currencies",
+		},
+		{
+			name: "too precise",
+			req:  models.CreateScheduledTransferRequest{From: addr1, To: addr2, Amount: money.MustParse("0.50"), Schedule: "@daily"},
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockScheduledTransfer) {
+				w.EXPECT().Get(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Status: models.WalletStatusActive, Currency: "JPY"}, nil)
+				w.EXPECT().Get(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Status: models.WalletStatusActive, Currency: "JPY"}, nil)
+			},
+			expectedErr: "amount has more fractional digits than the currency allows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			scheduledRepo := repository_mocks.NewMockScheduledTransfer(ctrl)
+			tt.mock(walletRepo, scheduledRepo)
+
+			service := NewScheduledTransferService(&repository.Repository{Wallet: walletRepo, ScheduledTransfer: scheduledRepo}, nil)
+			service.now = func() time.Time { return now }
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}})
+			result, err := service.CreateScheduledTransfer(ctx, tt.req)
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestScheduledTransferService_ListScheduledTransfers(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   *auth.Principal
+		wallet      string
+		wallets     []string
+		expectedErr error
+	}{
+		{
+			name:      "admin lists all",
+			principal: auth.System(),
+		},
+		{
+			name:      "customer lists own wallets",
+			principal: &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1, addr2}},
+			wallets:   []string{addr1, addr2},
+		},
+		{
+			name:      "customer without wallets",
+			principal: &auth.Principal{KeyID: 1, Role: auth.RoleCustomer},
+			wallets:   []string{},
+		},
+		{
+			name:      "by wallet",
+			principal: &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1, addr2}},
+			wallet:    addr2,
+			wallets:   []string{addr2},
+		},
+		{
+			name:        "foreign wallet",
+			principal:   &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}},
+			wallet:      addr2,
+			expectedErr: domain.ErrWalletNotOwned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scheduledRepo := repository_mocks.NewMockScheduledTransfer(ctrl)
+			if tt.expectedErr == nil {
+				scheduledRepo.EXPECT().List(gomock.Any(), tt.wallets).Return([]models.ScheduledTransfer{{ID: 3}}, nil)
+			}
+
+			service := NewScheduledTransferService(&repository.Repository{ScheduledTransfer: scheduledRepo}, nil)
+			result, err := service.ListScheduledTransfers(auth.WithPrincipal(context.Background(), tt.principal), tt.wallet)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []models.ScheduledTransfer{{ID: 3}}, result)
+			}
+		})
+	}
+}
+
+func TestScheduledTransferService_CancelScheduledTransfer(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        func(r *repository_mocks.MockScheduledTransfer)
+		expectedErr error
+	}{
+		{
+			name: "success",
+			mock: func(r *repository_mocks.MockScheduledTransfer) {
+				r.EXPECT().Get(gomock.Any(), 3).Return(&models.ScheduledTransfer{ID: 3, From: addr1}, nil)
+				r.EXPECT().Cancel(gomock.Any(), 3).Return(&models.ScheduledTransfer{ID: 3, From: addr1, Status: models.ScheduledTransferStatusCancelled}, nil)
+			},
+		},
+		{
+			name: "not found",
+			mock: func(r *repository_mocks.MockScheduledTransfer) {
+				r.EXPECT().Get(gomock.Any(), 3).Return(nil, domain.ErrScheduledTransferNotFound)
+			},
+			expectedErr: domain.ErrScheduledTransferNotFound,
+		},
+		{
+			name: "foreign wallet",
+			mock: func(r *repository_mocks.MockScheduledTransfer) {
+				r.EXPECT().Get(gomock.Any(), 3).Return(&models.ScheduledTransfer{ID: 3, From: addr2}, nil)
+			},
+			expectedErr: domain.ErrWalletNotOwned,
+		},
+		{
+			name: "already cancelled",
+			mock: func(r *repository_mocks.MockScheduledTransfer) {
+				r.EXPECT().Get(gomock.Any(), 3).Return(&models.ScheduledTransfer{ID: 3, From: addr1}, nil)
+				r.EXPECT().Cancel(gomock.Any(), 3).Return(nil, domain.ErrScheduledTransferCancelled)
+			},
+			expectedErr: domain.ErrScheduledTransferCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scheduledRepo := repository_mocks.NewMockScheduledTransfer(ctrl)
+			tt.mock(scheduledRepo)
+
+			service := NewScheduledTransferService(&repository.Repository{ScheduledTransfer: scheduledRepo}, nil)
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}})
+			result, err := service.CancelScheduledTransfer(ctx, 3)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.ScheduledTransferStatusCancelled, result.Status)
+			}
+		})
+	}
+}
+
+func TestScheduledTransferService_ExecuteNext(t *testing.T) {
+	now := time.Date(2025, 2, 1, 9, 0, 30, 0, time.UTC)
+	dueAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	transactionID := 42
+	keyID := 1
+	apiKey := &models.APIKey{ID: keyID, Name: "billing", Wallets: []string{addr1}}
+	retryAt := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name        string
+		attempts    int
+		transferErr error
+		run         models.ScheduledTransferRun
+		rescheduled models.ScheduledTransfer
+	}{
+		{
+			name:        "completed",
+			run:         models.ScheduledTransferRun{Attempt: 1, Status: models.ScheduledTransferRunCompleted, TransactionID: &transactionID},
+			rescheduled: models.ScheduledTransfer{NextRunAt: nextMonth},
+		},
+		{
+			name:        "already executed before restart",
+			transferErr: domain.ErrScheduledTransferExecuted,
+			run:         models.ScheduledTransferRun{Attempt: 1, Status: models.ScheduledTransferRunCompleted},
+			rescheduled: models.ScheduledTransfer{NextRunAt: nextMonth},
+		},
+		{
+			name:        "transient error",
+			transferErr: &pq.Error{Code: "40001", Message: "could not serialize access"},
+			run:         models.ScheduledTransferRun{Attempt: 1, Status: models.ScheduledTransferRunRetrying, Error: "internal error"},
+			rescheduled: models.ScheduledTransfer{NextRunAt: dueAt, RetryAt: retryAt(time.Minute), Attempts: 1},
+		},
+		{
+			name:        "backoff grows with attempts",
+			attempts:    2,
+			transferErr: &pq.Error{Code: "08006", Message: "connection failure"},
+			run:         models.ScheduledTransferRun{Attempt: 3, Status: models.ScheduledTransferRunRetrying, Error: "internal error"},
+			rescheduled: models.ScheduledTransfer{NextRunAt: dueAt, RetryAt: retryAt(4 * time.Minute), Attempts: 3},
+		},
+		{
+			name:        "limit retried after it resets",
+			transferErr: domain.NewLimitError(domain.LimitTransfersPerMinute, 10*time.Minute),
+			run: models.ScheduledTransferRun{Attempt: 1, Status: models.ScheduledTransferRunRetrying,
+				Error: domain.NewLimitError(domain.LimitTransfersPerMinute, 10*time.Minute).Error()},
+			rescheduled: models.ScheduledTransfer{NextRunAt: dueAt, RetryAt: retryAt(10 * time.Minute), Attempts: 1},
+		},
+		{
+			name:        "attempts exhausted",
+			attempts:    maxScheduledTransferAttempts - 1,
+			transferErr: &pq.Error{Code: "40001", Message: "could not serialize access"},
+			run:         models.ScheduledTransferRun{Attempt: maxScheduledTransferAttempts, Status: models.ScheduledTransferRunFailed, Error: "internal error"},
+			rescheduled: models.ScheduledTransfer{NextRunAt: nextMonth},
+		},
+		{
+			name:        "permanent error",
+			transferErr: domain.ErrInsufficientFunds,
+			run:         models.ScheduledTransferRun{Attempt: 1, Status: models.ScheduledTransferRunFailed, Error: domain.ErrInsufficientFunds.Error()},
+			rescheduled: models.ScheduledTransfer{NextRunAt: nextMonth},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transfer := &models.ScheduledTransfer{ID: 3, From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "0 9 1 * *",
+				Status: models.ScheduledTransferStatusActive, NextRunAt: dueAt, Attempts: tt.attempts, CreatedByKeyID: &keyID}
+			scheduledRepo := repository_mocks.NewMockScheduledTransfer(ctrl)
+			apiKeyRepo := repository_mocks.NewMockAPIKey(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{ScheduledTransfer: scheduledRepo, APIKey: apiKeyRepo})
+			})
+			transfers := service_mocks.NewMockTransaction(ctrl)
+
+			scheduledRepo.EXPECT().ClaimDue(gomock.Any(), now).Return(transfer, nil)
+			apiKeyRepo.EXPECT().GetByID(gomock.Any(), keyID).Return(apiKey, nil)
+			transfers.EXPECT().TransferFunds(gomock.Any(), models.CreateTransactionRequest{
+				From: addr1, To: addr2, Amount: money.FromInt(50), ScheduledTransferID: 3, ScheduledFor: dueAt,
+			}).DoAndReturn(func(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferResult, error) {
+				assert.Equal(t, apiKeyPrincipal(apiKey), auth.FromContext(ctx))
+				if tt.transferErr != nil {
+					return nil, tt.transferErr
+				}
+				return &models.TransferResult{TransactionID: transactionID}, nil
+			})
+			tt.run.ScheduledTransferID = 3
+			tt.run.ScheduledFor = dueAt
+			scheduledRepo.EXPECT().CreateRun(gomock.Any(), tt.run).Return(nil)
+			rescheduled := *transfer
+			rescheduled.NextRunAt, rescheduled.RetryAt, rescheduled.Attempts = tt.rescheduled.NextRunAt, tt.rescheduled.RetryAt, tt.rescheduled.Attempts
+			scheduledRepo.EXPECT().Reschedule(gomock.Any(), rescheduled).Return(nil)
+
+			service := NewScheduledTransferService(&repository.Repository{ScheduledTransfer: scheduledRepo, UnitOfWork: uow}, transfers)
+			service.now = func() time.Time { return now }
+			executed, err := service.executeNext(context.Background())
+
+			assert.NoError(t, err)
+			assert.True(t, executed)
+		})
+	}
+}
+
+func TestScheduledTransferService_ExecuteNext_Creator(t *testing.T) {
+	now := time.Date(2025, 2, 1, 9, 0, 30, 0, time.UTC)
+	dueAt := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	keyID, userID := 1, 2
+
+	tests := []struct {
+		name        string
+		keyID       *int
+		userID      *int
+		mock        func(k *repository_mocks.MockAPIKey, u *repository_mocks.MockUser)
+		principal   *auth.Principal
+		expectedErr string
+	}{
+		{
+			name:   "user",
+			userID: &userID,
+			mock: func(k *repository_mocks.MockAPIKey, u *repository_mocks.MockUser) {
+				u.EXPECT().GetByID(gomock.Any(), userID).Return(&models.User{ID: userID, Username: "alice", Role: auth.RoleCustomer, Wallets: []string{addr1}}, nil)
+			},
+			principal: &auth.Principal{UserID: userID, Name: "alice", Role: auth.RoleCustomer, Wallets: []string{addr1}},
+		},
+		{
+			name:      "created by the service",
+			mock:      func(k *repository_mocks.MockAPIKey, u *repository_mocks.MockUser) {},
+			principal: auth.System(),
+		},
+		{
+			name:  "key revoked",
+			keyID: &keyID,
+			mock: func(k *repository_mocks.MockAPIKey, u *repository_mocks.MockUser) {
+				k.EXPECT().GetByID(gomock.Any(), keyID).Return(nil, repository.ErrAPIKeyNotFound)
+			},
+			expectedErr: domain.ErrScheduledTransferUnauthorized.Error(),
+		},
+		{
+			name:  "wallet no longer owned by the key",
+			keyID: &keyID,
+			mock: func(k *repository_mocks.MockAPIKey, u *repository_mocks.MockUser) {
+				k.EXPECT().GetByID(gomock.Any(), keyID).Return(&models.APIKey{ID: keyID, Name: "billing", Wallets: []string{addr2}}, nil)
+			},
+			expectedErr: domain.ErrScheduledTransferUnauthorized.Error(),
+		},
+		{
+			name:   "user deleted",
+			userID: &userID,
+			mock: func(k *repository_mocks.MockAPIKey, u *repository_mocks.MockUser) {
+				u.EXPECT().GetByID(gomock.Any(), userID).Return(nil, domain.ErrUserNotFound)
+			},
+			expectedErr: domain.ErrScheduledTransferUnauthorized.Error(),
+		},
+		{
+			name:   "user role without transfers",
+			userID: &userID,
+			mock: func(k *repository_mocks.MockAPIKey, u *repository_mocks.MockUser) {
+				u.EXPECT().GetByID(gomock.Any(), userID).Return(&models.User{ID: userID, Username: "alice", Role: auth.RoleAuditor, Wallets: []string{addr1}}, nil)
+			},
+			expectedErr: domain.ErrScheduledTransferUnauthorized.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transfer := &models.ScheduledTransfer{ID: 3, From: addr1, To: addr2, Amount: money.FromInt(50), Schedule: "0 9 1 * *",
+				Status: models.ScheduledTransferStatusActive, NextRunAt: dueAt, Attempts: 1, CreatedByKeyID: tt.keyID, CreatedByUserID: tt.userID}
+			scheduledRepo := repository_mocks.NewMockScheduledTransfer(ctrl)
+			apiKeyRepo := repository_mocks.NewMockAPIKey(ctrl)
+			userRepo := repository_mocks.NewMockUser(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{ScheduledTransfer: scheduledRepo, APIKey: apiKeyRepo, User: userRepo})
+			})
+			transfers := service_mocks.NewMockTransaction(ctrl)
+
+			scheduledRepo.EXPECT().ClaimDue(gomock.Any(), now).Return(transfer, nil)
+			tt.mock(apiKeyRepo, userRepo)
+			if tt.expectedErr != "" {
+				scheduledRepo.EXPECT().CreateRun(gomock.Any(), models.ScheduledTransferRun{
+					ScheduledTransferID: 3, ScheduledFor: dueAt, Attempt: 2, Status: models.ScheduledTransferRunFailed, Error: tt.expectedErr,
+				}).Return(nil)
+				scheduledRepo.EXPECT().Cancel(gomock.Any(), 3).Return(&models.ScheduledTransfer{ID: 3, Status: models.ScheduledTransferStatusCancelled}, nil)
+			} else {
+				transfers.EXPECT().TransferFunds(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferResult, error) {
+						assert.Equal(t, tt.principal, auth.FromContext(ctx))
+						return &models.TransferResult{TransactionID: 42}, nil
+					})
+				scheduledRepo.EXPECT().CreateRun(gomock.Any(), gomock.Any()).Return(nil)
+				scheduledRepo.EXPECT().Reschedule(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewScheduledTransferService(&repository.Repository{ScheduledTransfer: scheduledRepo, UnitOfWork: uow}, transfers)
+			service.now = func() time.Time { return now }
+			executed, err := service.executeNext(context.Background())
+
+			assert.NoError(t, err)
+			assert.True(t, executed)
+		})
+	}
+}
+
+func TestScheduledTransferService_ExecuteNext_NothingDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scheduledRepo := repository_mocks.NewMockScheduledTransfer(ctrl)
+	uow := repository_mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+		return fn(&repository.Repository{ScheduledTransfer: scheduledRepo})
+	})
+	scheduledRepo.EXPECT().ClaimDue(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	service := NewScheduledTransferService(&repository.Repository{ScheduledTransfer: scheduledRepo, UnitOfWork: uow}, service_mocks.NewMockTransaction(ctrl))
+	executed, err := service.executeNext(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, executed)
+}
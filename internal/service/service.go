@@ -1,41 +1,171 @@
 package service
 
 import (
+	"context"
+	"golangTestTask/configs"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/fx"
 	"golangTestTask/internal/models"
+	"golangTestTask/internal/quote"
 	"golangTestTask/internal/repository"
+	"golangTestTask/pkg/money"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
 type Wallet interface {
-	// CreateWallet создает новый кошелек.
-	CreateWallet(models.Wallet) error
-	// GetWalletBalance возвращает баланс кошелька по его адресу
-	GetWalletBalance(address string) (float64, error)
+	// CreateWallet создает новый кошелек и возвращает его; пустой адрес генерируется автоматически.
+	CreateWallet(ctx context.Context, wallet models.Wallet) (*models.Wallet, error)
+	// GetWallet возвращает кошелек по его адресу.
+	GetWallet(ctx context.Context, address string) (*models.Wallet, error)
+	// SetWalletStatus переводит кошелек в статус active, frozen или closed.
+	SetWalletStatus(ctx context.Context, address string, status models.WalletStatus) (*models.Wallet, error)
+	// SetWalletTier присваивает кошельку уровень tier.
+	SetWalletTier(ctx context.Context, address string, tier string) (*models.Wallet, error)
+	// GetWalletBalance возвращает общий баланс кошелька, сумму его блокировок средств и доступные средства.
+	GetWalletBalance(ctx context.Context, address string) (*models.WalletBalance, error)
 	// GetAllWallets возвращает баланс кошелька по его адресу
-	GetAllWallets() ([]models.Wallet, error)
-	// CreateRandomWallets создает count кошельков со случайными адресами и balance у.е. на них.
-	CreateRandomWallets(count int, balance float64) error
-	// BaseWallets создает count кошельков со случайными адресами и balance у.е. на них если они еще не созданы.
-	BaseWallets(count int, balance float64) error
+	GetAllWallets(ctx context.Context) ([]models.Wallet, error)
+	// GetWalletStats возвращает количество кошельков и сумму их балансов по статусам.
+	GetWalletStats(ctx context.Context) ([]models.WalletStats, error)
+	// CreateRandomWallets создает count кошельков в валюте currency со случайными адресами и balance у.е. на них.
+	CreateRandomWallets(ctx context.Context, count int, balance money.Amount, currency string) error
+	// BaseWallets создает по count кошельков в каждой из валют currencies, если кошельки еще не созданы.
+	BaseWallets(ctx context.Context, count int, balance money.Amount, currencies []string) error
+}
+
+type Tier interface {
+	// GetAllTiers возвращает все уровни кошельков.
+	GetAllTiers(ctx context.Context) ([]models.WalletTier, error)
+	// CreateTier создает уровень кошельков и возвращает его.
+	CreateTier(ctx context.Context, tier models.WalletTier) (*models.WalletTier, error)
+	// UpdateTier изменяет ограничения уровня кошельков и возвращает его.
+	UpdateTier(ctx context.Context, tier models.WalletTier) (*models.WalletTier, error)
 }
 
 type Transaction interface {
-	// TransferFunds переводит средства между кошельками
-	TransferFunds(from string, to string, amount float64) error
+	// TransferFunds переводит средства между кошельками с учетом комиссии и возвращает итог перевода
+	TransferFunds(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferResult, error)
+	// QuoteTransfer рассчитывает перевод без его выполнения и возвращает подписанный расчет
+	QuoteTransfer(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferQuote, error)
+	// ReverseTransaction отменяет завершенный перевод id полностью или частично и возвращает итог компенсирующей транзакции.
+	ReverseTransaction(ctx context.Context, id int, req models.ReverseTransactionRequest) (*models.TransactionReversal, error)
 	// GetLastTransactions возвращает последние count транзакций.
-	GetLastTransactions(count int) ([]models.Transaction, error)
+	GetLastTransactions(ctx context.Context, count int) ([]models.Transaction, error)
+	// ListTransactions возвращает страницу истории транзакций, подходящих под filter, начиная с позиции cursor.
+	ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) (*models.TransactionPage, error)
+}
+
+type Ledger interface {
+	// RebuildBalances пересчитывает балансы всех кошельков по журналу и возвращает количество исправленных кошельков.
+	RebuildBalances(ctx context.Context) (int64, error)
+	// Reconcile сверяет балансы кошельков с журналом и возвращает отчет о расхождениях.
+	Reconcile(ctx context.Context) (*models.ReconciliationReport, error)
+	// RunReconciler периодически сверяет балансы с журналом и записывает итог в метрики, пока не отменен ctx.
+	RunReconciler(ctx context.Context, interval time.Duration)
+}
+
+type ScheduledTransfer interface {
+	// CreateScheduledTransfer создает регулярный перевод по расписанию и возвращает его.
+	CreateScheduledTransfer(ctx context.Context, req models.CreateScheduledTransferRequest) (*models.ScheduledTransfer, error)
+	// ListScheduledTransfers возвращает регулярные переводы с кошелька wallet или, если он не задан, со всех доступных участнику кошельков.
+	ListScheduledTransfers(ctx context.Context, wallet string) ([]models.ScheduledTransfer, error)
+	// CancelScheduledTransfer отменяет регулярный перевод id и возвращает его.
+	CancelScheduledTransfer(ctx context.Context, id int) (*models.ScheduledTransfer, error)
+	// ListScheduledTransferRuns возвращает последние попытки выполнить регулярный перевод id.
+	ListScheduledTransferRuns(ctx context.Context, id int) ([]models.ScheduledTransferRun, error)
+	// RunScheduler периодически выполняет наступившие регулярные переводы и записывает их итог, пока не отменен ctx.
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+type Hold interface {
+	// AuthorizeHold блокирует средства на кошельке отправителя для последующего перевода и возвращает блокировку.
+	AuthorizeHold(ctx context.Context, req models.CreateHoldRequest) (*models.Hold, error)
+	// GetHold возвращает блокировку средств по ID.
+	GetHold(ctx context.Context, id int) (*models.Hold, error)
+	// CaptureHold переводит получателю всю заблокированную сумму или ее часть и возвращает блокировку.
+	CaptureHold(ctx context.Context, id int, req models.CaptureHoldRequest) (*models.Hold, error)
+	// VoidHold отменяет блокировку средств id и возвращает ее.
+	VoidHold(ctx context.Context, id int) (*models.Hold, error)
+	// ExpireHolds отмечает блокировки с истекшим сроком как истекшие и возвращает их количество.
+	ExpireHolds(ctx context.Context) (int64, error)
+	// RunHoldExpirer периодически отмечает блокировки с истекшим сроком как истекшие, пока не отменен ctx.
+	RunHoldExpirer(ctx context.Context, interval time.Duration)
+}
+
+type Idempotency interface {
+	// ReserveIdempotencyKey резервирует ключ идемпотентности за запросом с хешем requestHash.
+	// Возвращает nil, если запрос нужно выполнить, или запись с сохраненным ответом на уже выполненный запрос.
+	ReserveIdempotencyKey(ctx context.Context, key string, requestHash string) (*models.IdempotencyRecord, error)
+	// SaveIdempotentResponse сохраняет ответ на запрос с ключом идемпотентности.
+	SaveIdempotentResponse(ctx context.Context, key string, statusCode int, responseBody []byte) error
+	// ReleaseIdempotencyKey освобождает ключ, ответ на который не был сохранен.
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	// DeleteExpiredIdempotencyKeys удаляет ключи идемпотентности с истекшим сроком действия.
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	// RunIdempotencySweeper периодически удаляет истекшие ключи идемпотентности, пока не отменен ctx.
+	RunIdempotencySweeper(ctx context.Context, interval time.Duration)
+}
+
+type Auth interface {
+	// Authenticate возвращает участника, которому выдан ключ API key.
+	Authenticate(ctx context.Context, key string) (*auth.Principal, error)
+	// CreateAPIKey создает ключ API и возвращает его значение вместе с сохраненными данными ключа.
+	CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error)
+	// EnsureAPIKey сохраняет заранее известный ключ key, если его еще нет в БД.
+	EnsureAPIKey(ctx context.Context, name string, key string, scopes []string) error
+}
+
+type Session interface {
+	// Login проверяет имя и пароль пользователя и выдает ему токен доступа и токен обновления.
+	Login(ctx context.Context, username string, password string) (*models.TokenPair, error)
+	// Refresh обменивает токен обновления на новую пару токенов.
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	// Logout отзывает токен обновления.
+	Logout(ctx context.Context, refreshToken string) error
+	// AuthenticateToken проверяет токен доступа и возвращает участника — его пользователя.
+	AuthenticateToken(ctx context.Context, token string) (*auth.Principal, error)
+	// CreateUser создает пользователя и возвращает его.
+	CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.User, error)
 }
 
 type Service struct {
 	Wallet
+	Tier
 	Transaction
+	Ledger
+	ScheduledTransfer
+	Hold
+	Idempotency
+	Auth
+	Session
 }
 
-// NewService создает новый экземпляр Service.
-func NewService(repo *repository.Repository) *Service {
+// NewService создает новый экземпляр Service. Токены доступа выпускаются и проверяются через tokens,
+// предварительные расчеты переводов подписываются quotes, курсы для переводов с конвертацией берутся из rates.
+func NewService(repo *repository.Repository, config configs.Config, tokens *auth.TokenIssuer, quotes *quote.Signer, rates fx.RateProvider) *Service {
+	limits := TransferLimits{
+		MaxTransfersPerMinute: config.TransferMaxPerMinute,
+		MaxDailyVolume:        config.TransferMaxDailyVolume,
+	}
+	// Если комиссия не взимается ни в одной валюте, правила не нужны: без них переводы в любой валюте бесплатны.
+	fees := TransferFees{Rules: make(map[string]FeeRule)}
+	if config.FeesEnabled() {
+		for currency, wallet := range config.FeeWallets {
+			fees.Rules[currency] = FeeRule{Schedule: config.FeeScheduleFor(currency), Wallet: wallet}
+		}
+	}
+	transactions := NewTransactionService(repo, limits, fees, quotes, rates)
 	return &Service{
-		Wallet:      NewWalletService(repo.Wallet),
-		Transaction: NewTransactionService(repo.Transaction, repo.Wallet),
+		Wallet:            NewWalletService(repo),
+		Tier:              NewTierService(repo.WalletTier),
+		Transaction:       transactions,
+		Ledger:            NewLedgerService(repo),
+		ScheduledTransfer: NewScheduledTransferService(repo, transactions),
+		Hold:              transactions,
+		Idempotency:       NewIdempotencyService(repo.Idempotency, config.IdempotencyTTL),
+		Auth:              NewAuthService(repo),
+		Session:           NewSessionService(repo, tokens, config.JWTRefreshTTL),
 	}
 }
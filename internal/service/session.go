@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	// maxPasswordLength — ограничение bcrypt: более длинные пароли обрезаются.
+	maxPasswordLength = 72
+	maxUsernameLength = 64
+)
+
+// dummyPasswordHash сравнивается с паролем при входе несуществующего пользователя,
+// чтобы время ответа не выдавало, зарегистрировано ли имя.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+type SessionService struct {
+	user_repo  repository.User
+	token_repo repository.RefreshToken
+	uow        repository.UnitOfWork
+	tokens     *auth.TokenIssuer
+	refreshTTL time.Duration
+}
+
+// NewSessionService создает новый экземпляр SessionService.
+func NewSessionService(repo *repository.Repository, tokens *auth.TokenIssuer, refreshTTL time.Duration) *SessionService {
+	return &SessionService{
+		user_repo:  repo.User,
+		token_repo: repo.RefreshToken,
+		uow:        repo.UnitOfWork,
+		tokens:     tokens,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Login проверяет имя и пароль пользователя и выдает ему токен доступа и токен обновления.
+func (s *SessionService) Login(ctx context.Context, username string, password string) (*models.TokenPair, error) {
+	user, err := s.user_repo.GetByUsername(ctx, username)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+	return s.issueTokens(ctx, s.token_repo, user)
+}
+
+// Refresh обменивает токен обновления на новую пару токенов. Использованный токен обновления отзывается.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	var pair *models.TokenPair
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		userID, err := repos.RefreshToken.Consume(ctx, auth.HashKey(refreshToken))
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return domain.ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		user, err := repos.User.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		pair, err = s.issueTokens(ctx, repos.RefreshToken, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Logout отзывает токен обновления. Повторный выход с тем же токеном не считается ошибкой.
+func (s *SessionService) Logout(ctx context.Context, refreshToken string) error {
+	_, err := s.token_repo.Consume(ctx, auth.HashKey(refreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	return err
+}
+
+// AuthenticateToken проверяет токен доступа и возвращает участника — его пользователя.
+// Роль и кошельки пользователя читаются из БД, поэтому их изменение действует сразу, без перевыпуска токена.
+func (s *SessionService) AuthenticateToken(ctx context.Context, token string) (*auth.Principal, error) {
+	userID, err := s.tokens.Parse(token)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	user, err := s.user_repo.GetByID(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return userPrincipal(user), nil
+}
+
+// userPrincipal возвращает участника — пользователя user.
+func userPrincipal(user *models.User) *auth.Principal {
+	return &auth.Principal{
+		UserID:  user.ID,
+		Name:    user.Username,
+		Role:    user.Role,
+		Wallets: user.Wallets,
+	}
+}
+
+// CreateUser создает пользователя с ролью req.Role (по умолчанию customer) и передает ему во владение кошельки req.Wallets.
+func (s *SessionService) CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.User, error) {
+	username := strings.TrimSpace(req.Username)
+	if username == "" {
+		return nil, domain.NewValidationError("username", "username is required")
+	}
+	if len(username) > maxUsernameLength {
+		return nil, domain.NewValidationError("username", "too long username")
+	}
+	if len(req.Password) < minPasswordLength || len(req.Password) > maxPasswordLength {
+		return nil, domain.NewValidationError("password", "password must be 8 to 72 characters long")
+	}
+	role := req.Role
+	if role == "" {
+		role = auth.RoleCustomer
+	}
+	if !role.Valid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	user := models.User{
+		Username:     username,
+		Role:         role,
+		Wallets:      []string{},
+		PasswordHash: string(passwordHash),
+	}
+	err = s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		if err := repos.User.Create(ctx, &user); err != nil {
+			return err
+		}
+		for _, address := range req.Wallets {
+			if err := repos.User.AddWallet(ctx, user.ID, address); err != nil {
+				if errors.Is(err, domain.ErrWalletNotFound) {
+					return domain.NewValidationError("wallets", "wallet "+address+" not found")
+				}
+				return err
+			}
+			user.Wallets = append(user.Wallets, address)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// issueTokens выпускает пару токенов для user, сохраняя хеш токена обновления через repo.
+func (s *SessionService) issueTokens(ctx context.Context, repo repository.RefreshToken, user *models.User) (*models.TokenPair, error) {
+	accessToken, err := s.tokens.Issue(user.ID, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.Create(ctx, user.ID, auth.HashKey(refreshToken), time.Now().Add(s.refreshTTL)); err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokens.TTL().Seconds()),
+	}, nil
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	repository_mocks "golangTestTask/internal/repository/mocks"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestTokenIssuer() *auth.TokenIssuer {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	return auth.NewTokenIssuer(jwt.SigningMethodHS256, secret, secret, "payment-system", 15*time.Minute)
+}
+
+func TestSessionService_Login(t *testing.T) {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password1"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &models.User{ID: 5, Username: "alice", Role: auth.RoleCustomer, PasswordHash: string(passwordHash)}
+
+	tests := []struct {
+		name        string
+		password    string
+		mock        func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken)
+		expectedErr error
+	}{
+		{
+			name:     "success",
+			password: "password1",
+			mock: func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken) {
+				u.EXPECT().GetByUsername(gomock.Any(), "alice").Return(user, nil)
+				rt.EXPECT().Create(gomock.Any(), 5, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+					assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+					return nil
+				})
+			},
+		},
+		{
+			name:     "wrong password",
+			password: "password2",
+			mock: func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken) {
+				u.EXPECT().GetByUsername(gomock.Any(), "alice").Return(user, nil)
+			},
+			expectedErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown user",
+			password: "password1",
+			mock: func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken) {
+				u.EXPECT().GetByUsername(gomock.Any(), "alice").Return(nil, domain.ErrUserNotFound)
+			},
+			expectedErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:     "repository error",
+			password: "password1",
+			mock: func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken) {
+				u.EXPECT().GetByUsername(gomock.Any(), "alice").Return(nil, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := repository_mocks.NewMockUser(ctrl)
+			tokenRepo := repository_mocks.NewMockRefreshToken(ctrl)
+			tt.mock(userRepo, tokenRepo)
+
+			tokens := newTestTokenIssuer()
+			service := NewSessionService(&repository.Repository{User: userRepo, RefreshToken: tokenRepo}, tokens, time.Hour)
+			pair, err := service.Login(context.Background(), "alice", tt.password)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer", pair.TokenType)
+			assert.Equal(t, 900, pair.ExpiresIn)
+			assert.Regexp(t, "^rt_[0-9a-f]{64}$", pair.RefreshToken)
+			userID, err := tokens.Parse(pair.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, 5, userID)
+		})
+	}
+}
+
+func TestSessionService_Refresh(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken)
+		expectedErr error
+	}{
+		{
+			name: "success",
+			mock: func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken) {
+				rt.EXPECT().Consume(gomock.Any(), auth.HashKey("rt_old")).Return(5, nil)
+				u.EXPECT().GetByID(gomock.Any(), 5).Return(&models.User{ID: 5, Role: auth.RoleCustomer}, nil)
+				rt.EXPECT().Create(gomock.Any(), 5, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "token already used or expired",
+			mock: func(u *repository_mocks.MockUser, rt *repository_mocks.MockRefreshToken) {
+				rt.EXPECT().Consume(gomock.Any(), auth.HashKey("rt_old")).Return(0, repository.ErrRefreshTokenNotFound)
+			},
+			expectedErr: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := repository_mocks.NewMockUser(ctrl)
+			tokenRepo := repository_mocks.NewMockRefreshToken(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{User: userRepo, RefreshToken: tokenRepo})
+			})
+			tt.mock(userRepo, tokenRepo)
+
+			service := NewSessionService(&repository.Repository{UnitOfWork: uow}, newTestTokenIssuer(), time.Hour)
+			pair, err := service.Refresh(context.Background(), "rt_old")
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, "rt_old", pair.RefreshToken)
+		})
+	}
+}
+
+func TestSessionService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tokenRepo := repository_mocks.NewMockRefreshToken(ctrl)
+	tokenRepo.EXPECT().Consume(gomock.Any(), auth.HashKey("rt_old")).Return(0, repository.ErrRefreshTokenNotFound)
+
+	service := NewSessionService(&repository.Repository{RefreshToken: tokenRepo}, newTestTokenIssuer(), time.Hour)
+	assert.NoError(t, service.Logout(context.Background(), "rt_old"))
+}
+
+func TestSessionService_AuthenticateToken(t *testing.T) {
+	tokens := newTestTokenIssuer()
+	token, err := tokens.Issue(5, auth.RoleCustomer)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		token       string
+		mock        func(u *repository_mocks.MockUser)
+		want        *auth.Principal
+		expectedErr error
+	}{
+		{
+			name:  "role and wallets are read from the database",
+			token: token,
+			mock: func(u *repository_mocks.MockUser) {
+				u.EXPECT().GetByID(gomock.Any(), 5).Return(&models.User{ID: 5, Username: "alice", Role: auth.RoleOperator, Wallets: []string{"addr1"}}, nil)
+			},
+			want: &auth.Principal{UserID: 5, Name: "alice", Role: auth.RoleOperator, Wallets: []string{"addr1"}},
+		},
+		{
+			name:        "invalid token",
+			token:       "garbage",
+			mock:        func(u *repository_mocks.MockUser) {},
+			expectedErr: domain.ErrInvalidToken,
+		},
+		{
+			name:  "user deleted",
+			token: token,
+			mock: func(u *repository_mocks.MockUser) {
+				u.EXPECT().GetByID(gomock.Any(), 5).Return(nil, domain.ErrUserNotFound)
+			},
+			expectedErr: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := repository_mocks.NewMockUser(ctrl)
+			tt.mock(userRepo)
+
+			service := NewSessionService(&repository.Repository{User: userRepo}, tokens, time.Hour)
+			got, err := service.AuthenticateToken(context.Background(), tt.token)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSessionService_CreateUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         models.CreateUserRequest
+		mock        func(u *repository_mocks.MockUser, uow *repository_mocks.MockUnitOfWork)
+		wantRole    auth.Role
+		expectedErr error
+	}{
+		{
+			name: "default role",
+			req:  models.CreateUserRequest{Username: "alice", Password: "password1", Wallets: []string{"addr1"}},
+			mock: func(u *repository_mocks.MockUser, uow *repository_mocks.MockUnitOfWork) {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{User: u})
+				})
+				u.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user *models.User) error {
+					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("password1")))
+					user.ID = 9
+					return nil
+				})
+				u.EXPECT().AddWallet(gomock.Any(), 9, "addr1").Return(nil)
+			},
+			wantRole: auth.RoleCustomer,
+		},
+		{
+			name:        "short password",
+			req:         models.CreateUserRequest{Username: "alice", Password: "short"},
+			mock:        func(u *repository_mocks.MockUser, uow *repository_mocks.MockUnitOfWork) {},
+			expectedErr: domain.NewValidationError("password", "password must be 8 to 72 characters long"),
+		},
+		{
+			name:        "invalid role",
+			req:         models.CreateUserRequest{Username: "alice", Password: "password1", Role: "root"},
+			mock:        func(u *repository_mocks.MockUser, uow *repository_mocks.MockUnitOfWork) {},
+			expectedErr: domain.ErrInvalidRole,
+		},
+		{
+			name: "user already exists",
+			req:  models.CreateUserRequest{Username: "alice", Password: "password1", Role: auth.RoleAuditor},
+			mock: func(u *repository_mocks.MockUser, uow *repository_mocks.MockUnitOfWork) {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{User: u})
+				})
+				u.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ErrUserAlreadyExists)
+			},
+			expectedErr: domain.ErrUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := repository_mocks.NewMockUser(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			tt.mock(userRepo, uow)
+
+			service := NewSessionService(&repository.Repository{UnitOfWork: uow}, newTestTokenIssuer(), time.Hour)
+			user, err := service.CreateUser(context.Background(), tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 9, user.ID)
+			assert.Equal(t, tt.wantRole, user.Role)
+			assert.Equal(t, tt.req.Wallets, user.Wallets)
+		})
+	}
+}
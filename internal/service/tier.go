@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"strings"
+)
+
+type TierService struct {
+	repo repository.WalletTier
+}
+
+// NewTierService создает новый экземпляр TierService.
+func NewTierService(repo repository.WalletTier) *TierService {
+	return &TierService{repo: repo}
+}
+
+// GetAllTiers возвращает все уровни кошельков.
+func (s *TierService) GetAllTiers(ctx context.Context) ([]models.WalletTier, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// CreateTier проверяет ограничения уровня кошельков tier, сохраняет его и возвращает.
+func (s *TierService) CreateTier(ctx context.Context, tier models.WalletTier) (*models.WalletTier, error) {
+	tier.Name = strings.TrimSpace(tier.Name)
+	if err := validateTier(tier); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, tier); err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// UpdateTier проверяет новые ограничения уровня кошельков tier, сохраняет их и возвращает уровень.
+// Новые ограничения действуют для всех кошельков уровня начиная со следующего перевода.
+func (s *TierService) UpdateTier(ctx context.Context, tier models.WalletTier) (*models.WalletTier, error) {
+	if err := validateTier(tier); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, tier); err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// validateTier проверяет имя и ограничения уровня кошельков.
+func validateTier(tier models.WalletTier) error {
+	switch {
+	case tier.Name == "":
+		return domain.NewValidationError("name", "name is required")
+	case len(tier.Name) > 32:
+		return domain.NewValidationError("name", "too long name")
+	case tier.MinTransfer <= 0:
+		return domain.NewValidationError("min_transfer", "min_transfer must be positive")
+	case tier.MaxTransfer < 0:
+		return domain.NewValidationError("max_transfer", "max_transfer must not be negative")
+	case tier.MaxTransfer > 0 && tier.MaxTransfer < tier.MinTransfer:
+		return domain.NewValidationError("max_transfer", "max_transfer must not be less than min_transfer")
+	case tier.DailyLimit < 0:
+		return domain.NewValidationError("daily_limit", "daily_limit must not be negative")
+	case tier.MonthlyLimit < 0:
+		return domain.NewValidationError("monthly_limit", "monthly_limit must not be negative")
+	case tier.MonthlyLimit > 0 && tier.DailyLimit > tier.MonthlyLimit:
+		return domain.NewValidationError("daily_limit", "daily_limit must not exceed monthly_limit")
+	case tier.MaxBalance < 0:
+		return domain.NewValidationError("max_balance", "max_balance must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	repository_mocks "golangTestTask/internal/repository/mocks"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTierService_CreateTier(t *testing.T) {
+	tests := []struct {
+		name        string
+		tier        models.WalletTier
+		mock        func(*repository_mocks.MockWalletTier)
+		expected    *models.WalletTier
+		expectedErr error
+	}{
+		{
+			name: "success",
+			tier: models.WalletTier{Name: " verified ", MinTransfer: money.FromInt(1), MaxTransfer: money.FromInt(1000), DailyLimit: money.FromInt(5000)},
+			mock: func(m *repository_mocks.MockWalletTier) {
+				m.EXPECT().Create(gomock.Any(), models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), MaxTransfer: money.FromInt(1000), DailyLimit: money.FromInt(5000)}).Return(nil)
+			},
+			expected: &models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), MaxTransfer: money.FromInt(1000), DailyLimit: money.FromInt(5000)},
+		},
+		{
+			name:        "empty name",
+			tier:        models.WalletTier{Name: " ", MinTransfer: money.FromInt(1)},
+			expectedErr: domain.NewValidationError("name", "name is required"),
+		},
+		{
+			name:        "non-positive minimum",
+			tier:        models.WalletTier{Name: "verified"},
+			expectedErr: domain.NewValidationError("min_transfer", "min_transfer must be positive"),
+		},
+		{
+			name:        "maximum below minimum",
+			tier:        models.WalletTier{Name: "verified", MinTransfer: money.FromInt(10), MaxTransfer: money.FromInt(5)},
+			expectedErr: domain.NewValidationError("max_transfer", "max_transfer must not be less than min_transfer"),
+		},
+		{
+			name:        "daily limit above monthly limit",
+			tier:        models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), DailyLimit: money.FromInt(100), MonthlyLimit: money.FromInt(50)},
+			expectedErr: domain.NewValidationError("daily_limit", "daily_limit must not exceed monthly_limit"),
+		},
+		{
+			name:        "negative max balance",
+			tier:        models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), MaxBalance: -1},
+			expectedErr: domain.NewValidationError("max_balance", "max_balance must not be negative"),
+		},
+		{
+			name: "already exists",
+			tier: models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1)},
+			mock: func(m *repository_mocks.MockWalletTier) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ErrTierAlreadyExists)
+			},
+			expectedErr: domain.ErrTierAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repository_mocks.NewMockWalletTier(ctrl)
+			if tt.mock != nil {
+				tt.mock(repo)
+			}
+
+			service := NewTierService(repo)
+			tier, err := service.CreateTier(context.Background(), tt.tier)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, tier)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, tier)
+			}
+		})
+	}
+}
+
+func TestTierService_UpdateTier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tier := models.WalletTier{Name: "verified", MinTransfer: money.FromInt(1), MaxBalance: money.FromInt(10000)}
+	repo := repository_mocks.NewMockWalletTier(ctrl)
+	repo.EXPECT().Update(gomock.Any(), tier).Return(domain.ErrTierNotFound)
+
+	service := NewTierService(repo)
+	_, err := service.UpdateTier(context.Background(), tier)
+	assert.ErrorIs(t, err, domain.ErrTierNotFound)
+
+	_, err = service.UpdateTier(context.Background(), models.WalletTier{Name: "verified"})
+	assert.Equal(t, domain.NewValidationError("min_transfer", "min_transfer must be positive"), err)
+}
@@ -1,62 +1,960 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/fee"
+	"golangTestTask/internal/fx"
+	"golangTestTask/internal/metrics"
 	"golangTestTask/internal/models"
+	"golangTestTask/internal/quote"
 	"golangTestTask/internal/repository"
+	"golangTestTask/pkg/address"
+	"golangTestTask/pkg/money"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// LimitCurrency — валюта, в которой заданы ограничения на суммы переводов: TransferLimits и ограничения уровней кошельков.
+// Суммы переводов в других валютах пересчитываются в нее по среднему курсу.
+const LimitCurrency = money.DefaultCurrency
+
+// TransferLimits задает ограничения на переводы с одного кошелька. Нулевое значение поля отключает ограничение.
+type TransferLimits struct {
+	// MaxTransfersPerMinute — максимальное число переводов за последние 60 секунд.
+	MaxTransfersPerMinute int
+	// MaxDailyVolume — максимальная сумма переводов за текущие сутки по UTC в LimitCurrency.
+	MaxDailyVolume money.Amount
+}
+
+// FeeRule — тариф комиссии Schedule за переводы с кошельков в одной валюте и кошелек Wallet в той же валюте,
+// на который зачисляются комиссии. Нулевой тариф отключает комиссию в этой валюте.
+type FeeRule struct {
+	Schedule fee.Schedule
+	Wallet   string
+}
+
+// TransferFees задает комиссию за переводы по валютам кошелька отправителя. Если правил нет, комиссия не взимается;
+// перевод с кошелька в валюте без правила отклоняется с ошибкой domain.ErrFeeUnavailable, а не выполняется без комиссии.
+type TransferFees struct {
+	Rules map[string]FeeRule
+}
+
 type TransactionService struct {
-	transaction_repo repository.Transaction
 	wallet_repo      repository.Wallet
+	transaction_repo repository.Transaction
+	hold_repo        repository.Hold
+	uow              repository.UnitOfWork
+	limits           TransferLimits
+	fees             TransferFees
+	quotes           *quote.Signer
+	rates            fx.RateProvider
+	now              func() time.Time
 }
 
-// NewTransactionService создает новый экземпляр TransactionService.
-func NewTransactionService(transaction_repo repository.Transaction, wallet_repo repository.Wallet) *TransactionService {
+// NewTransactionService создает новый экземпляр TransactionService с ограничениями на переводы limits
+// и комиссией за переводы fees. Предварительные расчеты переводов подписываются quotes, а курсы для переводов
+// с конвертацией берутся из rates; если rates равен nil, переводы с конвертацией недоступны.
+func NewTransactionService(repo *repository.Repository, limits TransferLimits, fees TransferFees, quotes *quote.Signer, rates fx.RateProvider) *TransactionService {
 	return &TransactionService{
-		transaction_repo: transaction_repo,
-		wallet_repo:      wallet_repo,
+		wallet_repo:      repo.Wallet,
+		transaction_repo: repo.Transaction,
+		hold_repo:        repo.Hold,
+		uow:              repo.UnitOfWork,
+		limits:           limits,
+		fees:             fees,
+		quotes:           quotes,
+		rates:            rates,
+		now:              time.Now,
+	}
+}
+
+// TransferFunds переводит req.Amount средств из кошелька req.From на кошелек req.To и возвращает итог перевода.
+// Адреса кошельков должны быть в формате пакета address или устаревшими адресами существующих кошельков,
+// иначе возвращается domain.ValidationError.
+// Комиссия по тарифу s.fees для валюты отправителя списывается с него сверх суммы перевода и зачисляется на кошелек комиссий
+// в той же валюте.
+// Балансы изменяются только записью журнала с проводками по кошелькам отправителя, получателя и комиссий;
+// запись транзакции, записи журнала и строки комиссии выполняются атомарно в одной транзакции БД.
+// Если перевод отклонен, в историю записывается транзакция в статусе failed с причиной отказа.
+// Списывать средства можно только с кошелька, принадлежащего участнику из ctx, либо с любого кошелька
+// при наличии у него области доступа admin. Перевод, превышающий ограничения на частоту или суточную сумму
+// переводов с кошелька, отклоняется с ошибкой domain.LimitError, а нарушающий ограничения уровней кошельков —
+// с соответствующей ошибкой предметной области. Средства отправителя, заблокированные активными блокировками,
+// для перевода недоступны.
+// Сумма указывается в валюте отправителя и должна записываться с точностью этой валюты. Перевод между кошельками
+// в разных валютах выполняется, только если запрошена конвертация (req.Convert), иначе отклоняется с ошибкой
+// domain.ErrCurrencyMismatch. При конвертации получателю зачисляется сумма по текущему курсу s.rates, округленная вниз
+// до точности его валюты, а обе части перевода с курсом и спредом сохраняются вместе с транзакцией.
+// Если задан req.QuoteID, перевод выполняется с комиссией и курсом из предварительного расчета. Расчет должен быть действителен
+// и выдан на тот же перевод, иначе возвращается domain.ErrInvalidQuote, domain.ErrQuoteExpired или domain.ErrQuoteMismatch.
+// Расчет одноразовый: его использование записывается в той же транзакции БД, что и перевод, а повторный перевод
+// по тому же расчету отклоняется с ошибкой domain.ErrQuoteUsed.
+// Если задан req.ScheduledTransferID, транзакция помечается регулярным переводом и сроком req.ScheduledFor; повторный перевод
+// за тот же срок отклоняется с ошибкой domain.ErrScheduledTransferExecuted и в историю не записывается.
+func (s *TransactionService) TransferFunds(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferResult, error) {
+	if err := checkAddresses(ctx, s.wallet_repo, req.From, req.To); err != nil {
+		return nil, err
+	}
+	var terms *quote.Terms
+	if req.QuoteID != "" {
+		verified, err := s.verifyQuote(req)
+		if err != nil {
+			return nil, err
+		}
+		terms = &verified
+	}
+
+	// Попытки списания с чужого кошелька не записываются в историю, чтобы посторонний не мог засорять историю владельца.
+	if err := checkCanDebit(ctx, req.From); err != nil {
+		metrics.ObserveTransfer(req.Amount, "", err)
+		return nil, err
+	}
+
+	var scheduledTransferID *int
+	var scheduledFor *time.Time
+	if req.ScheduledTransferID != 0 {
+		scheduledTransferID, scheduledFor = &req.ScheduledTransferID, &req.ScheduledFor
+	}
+
+	// currency — валюта кошелька отправителя; остается пустой, если его не удалось заблокировать.
+	var currency string
+	result := &models.TransferResult{Amount: req.Amount}
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		fee_address, err := s.feeWallet(ctx, repos.Wallet, req.From, terms)
+		if err != nil {
+			return err
+		}
+		plan, err := lockTransfer(ctx, repos.Wallet, req.From, req.To, fee_address)
+		if err != nil {
+			return err
+		}
+		currency = plan.wallet_from.Currency
+		if terms != nil {
+			plan.quote_nonce = terms.Nonce
+		}
+		if err := s.checkTransfer(ctx, repos, plan, req, terms); err != nil {
+			return err
+		}
+		result.Currency = currency
+		result.Fee = plan.fee
+		result.Total = req.Amount + plan.fee
+		result.Conversion = plan.conversion()
+
+		result.TransactionID, err = recordTransfer(ctx, repos, plan, models.Transaction{
+			From:                req.From,
+			To:                  req.To,
+			Amount:              req.Amount,
+			Currency:            currency,
+			Status:              models.TransactionStatusCompleted,
+			ScheduledTransferID: scheduledTransferID,
+			ScheduledFor:        scheduledFor,
+		})
+		return err
+	})
+	metrics.ObserveTransfer(req.Amount, currency, err)
+	if errors.Is(err, domain.ErrScheduledTransferExecuted) {
+		// Перевод за этот срок уже записан в историю, повторная попытка не является отказом.
+		return nil, err
+	}
+	if err != nil {
+		// Транзакция БД перевода откатена, поэтому неудачная попытка записывается отдельно.
+		// Запись не зависит от отмены ctx, чтобы попытка, прерванная отключением клиента, тоже попала в историю.
+		reason := failureReason(err)
+		if reason == failureReasonInternal {
+			log.Printf("Transfer from %q to %q failed: %v", req.From, req.To, err)
+		}
+		if _, recordErr := s.transaction_repo.Create(context.WithoutCancel(ctx), models.Transaction{
+			From:                req.From,
+			To:                  req.To,
+			Amount:              req.Amount,
+			Currency:            currency,
+			Status:              models.TransactionStatusFailed,
+			FailureReason:       reason,
+			ScheduledTransferID: scheduledTransferID,
+			ScheduledFor:        scheduledFor,
+		}); recordErr != nil {
+			log.Printf("Failed to record failed transfer from %q to %q: %v", req.From, req.To, recordErr)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// QuoteTransfer рассчитывает перевод req, не изменяя кошельки: выполняет те же проверки, что и TransferFunds,
+// и возвращает комиссию, балансы после перевода и подписанный идентификатор расчета, действующий в течение срока s.quotes.
+// Для перевода с конвертацией расчет фиксирует текущий курс.
+func (s *TransactionService) QuoteTransfer(ctx context.Context, req models.CreateTransactionRequest) (*models.TransferQuote, error) {
+	if err := checkAddresses(ctx, s.wallet_repo, req.From, req.To); err != nil {
+		return nil, err
+	}
+	if err := checkCanDebit(ctx, req.From); err != nil {
+		return nil, err
+	}
+
+	var rate *fx.Rate
+	result := &models.TransferQuote{From: req.From, To: req.To, Amount: req.Amount}
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		fee_address, err := s.feeWallet(ctx, repos.Wallet, req.From, nil)
+		if err != nil {
+			return err
+		}
+		plan, err := lockTransfer(ctx, repos.Wallet, req.From, req.To, fee_address)
+		if err != nil {
+			return err
+		}
+		if err := s.checkTransfer(ctx, repos, plan, req, nil); err != nil {
+			return err
+		}
+		result.Currency = plan.wallet_from.Currency
+		result.Fee = plan.fee
+		result.Total = req.Amount + plan.fee
+		result.Conversion = plan.conversion()
+		rate = plan.rate
+		result.SenderBalanceAfter = plan.wallet_from.Balance - result.Total
+		if auth.FromContext(ctx).CanReadWallet(req.To) {
+			balance := plan.wallet_to.Balance + plan.credit
+			if plan.wallet_fee == plan.wallet_to {
+				balance += plan.fee
+			}
+			result.RecipientBalanceAfter = &balance
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.ExpiresAt = s.now().Add(s.quotes.TTL()).UTC().Truncate(time.Second)
+	result.QuoteID, err = s.quotes.Sign(quote.Terms{
+		From:      req.From,
+		To:        req.To,
+		Amount:    req.Amount,
+		Fee:       result.Fee,
+		Rate:      rate,
+		ExpiresAt: result.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReverseTransaction отменяет завершенный перевод id компенсирующей транзакцией в обратном направлении и возвращает ее итог.
+// req.Amount задает возвращаемую отправителю сумму в его валюте; если он не задан, возвращается вся сумма перевода.
+// Для перевода с конвертацией с получателя списывается возвращаемая сумма, пересчитанная по курсу исходного перевода
+// и округленная вниз, поэтому при полной отмене он возвращает ровно зачисленную ему сумму. Комиссия за перевод не возвращается.
+// Компенсирующая транзакция, запись журнала и смена статуса исходного перевода на reversed выполняются атомарно
+// в одной транзакции БД. Перевод можно отменить только один раз, в том числе частично; отменять компенсирующие
+// транзакции нельзя. Ограничения уровней кошельков не применяются, а замороженные кошельки участвуют в отмене,
+// чтобы можно было вернуть средства с кошелька, замороженного из-за ошибочного платежа. Средства получателя,
+// заблокированные его активными блокировками, для отмены недоступны. Отмена доступна только
+// участнику с разрешением auth.PermissionReverseTransactions.
+func (s *TransactionService) ReverseTransaction(ctx context.Context, id int, req models.ReverseTransactionRequest) (*models.TransactionReversal, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return nil, domain.ErrUnauthenticated
+	}
+	if !principal.HasPermission(auth.PermissionReverseTransactions) {
+		return nil, domain.ErrForbidden
+	}
+
+	var result *models.TransactionReversal
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		// Строка исходного перевода блокируется первой, поэтому параллельные отмены одного перевода выполняются по очереди.
+		original, err := repos.Transaction.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case original.Status == models.TransactionStatusReversed:
+			return domain.ErrTransactionAlreadyReversed
+		case original.Status != models.TransactionStatusCompleted, original.ReversalOf != nil:
+			return domain.ErrTransactionNotReversible
+		}
+		refund := original.Amount
+		if req.Amount != nil {
+			refund = *req.Amount
+		}
+		if refund <= 0 || refund > original.Amount {
+			return domain.NewValidationError("amount", "Amount must be positive and not exceed the transfer amount")
+		}
+
+		wallet_from, wallet_to, _, err := lockWallets(ctx, repos.Wallet, original.From, original.To, "")
+		if err != nil {
+			return err
+		}
+		if wallet_from.Status == models.WalletStatusClosed {
+			return domain.NewWalletError(models.TransactionRoleSender, wallet_from.Address, domain.ErrWalletClosed)
+		}
+		debit, conversion, err := reversalDebit(ctx, repos.Transaction, original.ID, wallet_from, wallet_to, refund)
+		if err != nil {
+			return err
+		}
+		// Заблокированные средства получателя обещаны по его собственным блокировкам, и списание их при отмене
+		// оставило бы эти блокировки без обеспечения.
+		held, err := repos.Hold.Held(ctx, wallet_to.Address, s.now().UTC())
+		if err != nil {
+			return err
+		}
+		if wallet_to.Balance-held < debit {
+			return domain.NewWalletError(models.TransactionRoleRecipient, wallet_to.Address, domain.ErrInsufficientFunds)
+		}
+
+		reversalID, err := repos.Transaction.Create(ctx, models.Transaction{
+			From:       wallet_to.Address,
+			To:         wallet_from.Address,
+			Amount:     debit,
+			Currency:   wallet_to.Currency,
+			Status:     models.TransactionStatusCompleted,
+			ReversalOf: &original.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := postEntry(ctx, repos.Ledger, reversalEntry(wallet_from, wallet_to, refund, debit, reversalID)); err != nil {
+			return err
+		}
+		if conversion != nil {
+			conversion.TransactionID = reversalID
+			if err := repos.Transaction.CreateConversion(ctx, *conversion); err != nil {
+				return err
+			}
+		}
+		if err := repos.Transaction.UpdateStatus(ctx, original.ID, models.TransactionStatusReversed); err != nil {
+			return err
+		}
+		result = &models.TransactionReversal{
+			TransactionID:  reversalID,
+			OriginalID:     original.ID,
+			From:           wallet_to.Address,
+			To:             wallet_from.Address,
+			Currency:       wallet_to.Currency,
+			Amount:         debit,
+			RefundCurrency: wallet_from.Currency,
+			Refund:         refund,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
 }
 
-// TransferFunds переводит amount средств из кошелька from на кошелек to.
-func (s *TransactionService) TransferFunds(from string, to string, amount float64) error {
-	var wallet_from, wallet_to *models.Wallet
-	wallet_from, err := s.wallet_repo.Get(from)
+// reversalDebit возвращает сумму, списываемую с получателя wallet_to при возврате refund отправителю wallet_from
+// по переводу transactionID. Сумма возврата должна записываться с точностью валюты отправителя. Для кошельков
+// в разных валютах она пересчитывается по курсу, сохраненному вместе с переводом, тем же способом, что и зачисление получателю,
+// и возвращаются сведения о конвертации отмены с обратными курсами; для кошельков в одной валюте они равны nil.
+func reversalDebit(ctx context.Context, repo repository.Transaction, transactionID int, wallet_from *models.Wallet, wallet_to *models.Wallet, refund money.Amount) (money.Amount, *models.TransactionConversion, error) {
+	currency, ok := money.LookupCurrency(wallet_from.Currency)
+	if !ok {
+		return 0, nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedCurrency, wallet_from.Currency)
+	}
+	if !currency.Fits(refund) {
+		return 0, nil, domain.ErrInvalidAmountPrecision
+	}
+	if wallet_from.Currency == wallet_to.Currency {
+		return refund, nil, nil
+	}
+
+	conversion, err := repo.GetConversion(ctx, transactionID)
 	if err != nil {
-		return fmt.Errorf("sender %w", err)
+		return 0, nil, err
 	}
-	if wallet_from.Balance < amount {
-		return errors.New("insufficient funds")
+	currency, ok = money.LookupCurrency(wallet_to.Currency)
+	if !ok {
+		return 0, nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedCurrency, wallet_to.Currency)
 	}
-	wallet_to, err = s.wallet_repo.Get(to)
+	debit, err := conversion.Rate.Convert(refund)
 	if err != nil {
-		return fmt.Errorf("recipient %w", err)
+		return 0, nil, err
+	}
+	debit = currency.Truncate(debit)
+	if debit == 0 {
+		return 0, nil, domain.ErrAmountBelowMinimum
 	}
 
-	wallet_from.Balance -= amount
-	wallet_to.Balance += amount
-	if err := s.wallet_repo.Update(wallet_from); err != nil {
+	// Сверка восстанавливает зачисление отправителю по сведениям о конвертации, поэтому они сохраняются и для отмены.
+	one := money.MustParseRate("1")
+	midRate, err := one.Div(conversion.MidRate)
+	if err != nil {
+		return 0, nil, err
+	}
+	rate, err := one.Div(conversion.Rate)
+	if err != nil {
+		return 0, nil, err
+	}
+	return debit, &models.TransactionConversion{
+		DebitCurrency:  wallet_to.Currency,
+		DebitAmount:    debit,
+		CreditCurrency: wallet_from.Currency,
+		CreditAmount:   refund,
+		MidRate:        midRate,
+		Rate:           rate,
+		SpreadBP:       conversion.SpreadBP,
+	}, nil
+}
+
+// verifyQuote проверяет, что расчет req.QuoteID подписан сервисом, не истек и выдан на перевод req,
+// и возвращает его условия.
+func (s *TransactionService) verifyQuote(req models.CreateTransactionRequest) (quote.Terms, error) {
+	terms, err := s.quotes.Verify(req.QuoteID)
+	if err != nil {
+		return terms, err
+	}
+	if terms.From != req.From || terms.To != req.To || terms.Amount != req.Amount || terms.Rate != nil && !req.Convert {
+		return terms, domain.ErrQuoteMismatch
+	}
+	if !s.now().Before(terms.ExpiresAt) {
+		return terms, domain.ErrQuoteExpired
+	}
+	return terms, nil
+}
+
+// transferPlan — заблокированные кошельки перевода, комиссия за него и сумма зачисления получателю.
+type transferPlan struct {
+	wallet_from *models.Wallet
+	wallet_to   *models.Wallet
+	// wallet_fee — кошелек комиссий; nil, если комиссия за перевод не взимается.
+	wallet_fee *models.Wallet
+	fee        money.Amount
+	// credit — сумма зачисления в валюте получателя; rate — курс конвертации или nil для перевода в одной валюте.
+	credit money.Amount
+	rate   *fx.Rate
+	// hold — списываемая блокировка средств отправителя или nil для обычного перевода.
+	hold *models.Hold
+	// quote_nonce — одноразовое значение использованного предварительного расчета или пустая строка.
+	quote_nonce string
+}
+
+// conversion возвращает сведения о зачислении получателю или nil, если перевод выполняется без конвертации.
+func (p *transferPlan) conversion() *models.Conversion {
+	if p.rate == nil {
+		return nil
+	}
+	return &models.Conversion{Currency: p.wallet_to.Currency, Amount: p.credit, Rate: p.rate.Applied, SpreadBP: p.rate.SpreadBP}
+}
+
+// feeWallet возвращает адрес кошелька комиссий в валюте кошелька from, который нужно заблокировать при переводе с него,
+// или пустую строку, если комиссия не взимается. Переводы с самого кошелька комиссий ею не облагаются.
+// Если для валюты отправителя нет правила s.fees, возвращает domain.ErrFeeUnavailable.
+// terms — условия предварительного расчета или nil, если перевод выполняется по текущему тарифу.
+func (s *TransactionService) feeWallet(ctx context.Context, repo repository.Wallet, from string, terms *quote.Terms) (string, error) {
+	if len(s.fees.Rules) == 0 || (terms != nil && terms.Fee == 0) {
+		return "", nil
+	}
+	// Валюта кошелька не меняется, поэтому ее можно прочитать до блокировки кошельков перевода.
+	wallet_from, err := repo.Get(ctx, from)
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		// Отсутствие кошелька отправителя сообщит lockTransfer вместе с его ролью в переводе.
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	rule, ok := s.fees.Rules[wallet_from.Currency]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", domain.ErrFeeUnavailable, wallet_from.Currency)
+	}
+	if from == rule.Wallet || (terms == nil && rule.Schedule.Kind == fee.KindNone) {
+		return "", nil
+	}
+	return rule.Wallet, nil
+}
+
+// recordTransfer записывает завершенный перевод transaction по плану plan: транзакцию, запись журнала с проводками
+// по кошелькам перевода, комиссию, сведения о конвертации и использование предварительного расчета. Возвращает ID транзакции.
+func recordTransfer(ctx context.Context, repos *repository.Repository, plan *transferPlan, transaction models.Transaction) (int, error) {
+	id, err := repos.Transaction.Create(ctx, transaction)
+	if err != nil {
+		return 0, err
+	}
+	if err := postEntry(ctx, repos.Ledger, transferEntry(plan, transaction.Amount, id)); err != nil {
+		return 0, err
+	}
+	if plan.wallet_fee != nil {
+		if err := repos.Transaction.CreateFee(ctx, models.TransactionFee{
+			TransactionID: id,
+			Wallet:        plan.wallet_fee.Address,
+			Amount:        plan.fee,
+		}); err != nil {
+			return 0, err
+		}
+	}
+	if plan.rate != nil {
+		if err := repos.Transaction.CreateConversion(ctx, models.TransactionConversion{
+			TransactionID:  id,
+			DebitCurrency:  plan.wallet_from.Currency,
+			DebitAmount:    transaction.Amount,
+			CreditCurrency: plan.wallet_to.Currency,
+			CreditAmount:   plan.credit,
+			MidRate:        plan.rate.Mid,
+			Rate:           plan.rate.Applied,
+			SpreadBP:       plan.rate.SpreadBP,
+		}); err != nil {
+			return 0, err
+		}
+	}
+	if plan.quote_nonce != "" {
+		if err := repos.Transaction.CreateQuote(ctx, models.TransactionQuote{TransactionID: id, Nonce: plan.quote_nonce}); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// lockTransfer блокирует кошельки перевода с кошелька from на кошелек to, а если задан fee_address — и кошелек комиссий.
+func lockTransfer(ctx context.Context, repo repository.Wallet, from string, to string, fee_address string) (*transferPlan, error) {
+	if from == to {
+		return nil, domain.ErrSameWallet
+	}
+	wallet_from, wallet_to, wallet_fee, err := lockWallets(ctx, repo, from, to, fee_address)
+	if err != nil {
+		return nil, err
+	}
+	return &transferPlan{wallet_from: wallet_from, wallet_to: wallet_to, wallet_fee: wallet_fee}, nil
+}
+
+// checkTransfer проверяет, что перевод req по плану plan возможен: кошельки активны, сумма записывается с точностью
+// валюты отправителя, перевод укладывается в ограничения и у отправителя хватает средств с учетом комиссии.
+// Средства, заблокированные активными блокировками отправителя, кроме списываемой plan.hold, для перевода недоступны.
+// Сумма зачисления сохраняется в plan.credit: для кошельков в разных валютах она пересчитывается по курсу из предварительного
+// расчета terms или текущему курсу s.rates, который сохраняется в plan.rate.
+// Комиссия (из terms или рассчитанная по тарифу для валюты отправителя) сохраняется в plan.fee; кошелек комиссий
+// в другой валюте означает ошибку конфигурации.
+func (s *TransactionService) checkTransfer(ctx context.Context, repos *repository.Repository, plan *transferPlan, req models.CreateTransactionRequest, terms *quote.Terms) error {
+	if err := checkWalletActive(plan.wallet_from, models.TransactionRoleSender); err != nil {
 		return err
 	}
-	if err := s.wallet_repo.Update(wallet_to); err != nil {
+	if err := checkWalletActive(plan.wallet_to, models.TransactionRoleRecipient); err != nil {
 		return err
 	}
-	s.transaction_repo.Create(models.Transaction{
-		From:   from,
-		To:     to,
-		Amount: amount,
-	})
+	if plan.wallet_from.Currency != plan.wallet_to.Currency && !req.Convert {
+		return domain.ErrCurrencyMismatch
+	}
+	currency, ok := money.LookupCurrency(plan.wallet_from.Currency)
+	if !ok {
+		return fmt.Errorf("%w: %q", domain.ErrUnsupportedCurrency, plan.wallet_from.Currency)
+	}
+	if !currency.Fits(req.Amount) {
+		return domain.ErrInvalidAmountPrecision
+	}
+	if err := s.convert(ctx, plan, req.Amount, terms); err != nil {
+		return err
+	}
+	// Кошелек отправителя заблокирован, поэтому параллельные переводы с него не могут одновременно пройти проверку лимитов.
+	if err := s.checkLimits(ctx, repos.Transaction, plan.wallet_from, req.Amount); err != nil {
+		return err
+	}
+	if err := s.checkTierLimits(ctx, repos, plan.wallet_from, plan.wallet_to, req.Amount, plan.credit); err != nil {
+		return err
+	}
+
+	plan.fee = 0
+	if plan.wallet_fee != nil {
+		if plan.wallet_fee.Currency != plan.wallet_from.Currency {
+			return fmt.Errorf("fee wallet %q for %s has currency %s", plan.wallet_fee.Address, plan.wallet_from.Currency, plan.wallet_fee.Currency)
+		}
+		if terms != nil {
+			plan.fee = terms.Fee
+		} else {
+			plan.fee = currency.Round(s.fees.Rules[plan.wallet_from.Currency].Schedule.Fee(req.Amount))
+		}
+	}
+	if plan.fee == 0 {
+		plan.wallet_fee = nil
+	}
+	held, err := repos.Hold.Held(ctx, plan.wallet_from.Address, s.now().UTC())
+	if err != nil {
+		return err
+	}
+	if plan.hold != nil {
+		held -= plan.hold.Amount
+	}
+	if plan.wallet_from.Balance-held < req.Amount+plan.fee {
+		return domain.ErrInsufficientFunds
+	}
+	return nil
+}
+
+// convert рассчитывает сумму зачисления получателю перевода amount по плану plan. Для кошельков в одной валюте
+// она равна amount, иначе пересчитывается по курсу из terms или текущему курсу s.rates и округляется вниз
+// до точности валюты получателя. Курс из terms должен быть выдан на ту же пару валют; применить его можно только один раз,
+// потому что расчет отмечается использованным вместе с переводом (recordTransfer).
+func (s *TransactionService) convert(ctx context.Context, plan *transferPlan, amount money.Amount, terms *quote.Terms) error {
+	plan.credit, plan.rate = amount, nil
+	from, to := plan.wallet_from.Currency, plan.wallet_to.Currency
+	if from == to {
+		return nil
+	}
+	currency, ok := money.LookupCurrency(to)
+	if !ok {
+		return fmt.Errorf("%w: %q", domain.ErrUnsupportedCurrency, to)
+	}
+
+	var rate fx.Rate
+	switch {
+	case terms != nil:
+		if terms.Rate == nil || terms.Rate.From != from || terms.Rate.To != to {
+			return domain.ErrQuoteMismatch
+		}
+		rate = *terms.Rate
+	case s.rates == nil:
+		return domain.ErrConversionUnavailable
+	default:
+		var err error
+		if rate, err = s.rates.Rate(ctx, from, to); err != nil {
+			return err
+		}
+	}
+
+	credit, err := rate.Applied.Convert(amount)
+	if err != nil {
+		return err
+	}
+	plan.credit = currency.Truncate(credit)
+	if plan.credit == 0 {
+		return domain.ErrAmountBelowMinimum
+	}
+	plan.rate = &rate
+	return nil
+}
+
+// checkCanDebit проверяет, что участник из ctx может списывать средства с кошелька address.
+func checkCanDebit(ctx context.Context, address string) error {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return domain.ErrUnauthenticated
+	}
+	if !principal.CanDebit(address) {
+		return domain.NewWalletError(models.TransactionRoleSender, address, domain.ErrWalletNotOwned)
+	}
+	return nil
+}
+
+// transferFailures — ошибки предметной области, текст которых записывается как причина неудачного перевода
+// в историю транзакций и в итоги попыток регулярных переводов.
+var transferFailures = []error{
+	domain.ErrWalletNotFound,
+	domain.ErrWalletFrozen,
+	domain.ErrWalletClosed,
+	domain.ErrAmountBelowMinimum,
+	domain.ErrAmountAboveMaximum,
+	domain.ErrDailyLimitExceeded,
+	domain.ErrMonthlyLimitExceeded,
+	domain.ErrMaxBalanceExceeded,
+	domain.ErrUnsupportedCurrency,
+	domain.ErrInvalidAmountPrecision,
+	domain.ErrCurrencyMismatch,
+	domain.ErrConversionUnavailable,
+	domain.ErrFeeUnavailable,
+	domain.ErrInsufficientFunds,
+	domain.ErrSameWallet,
+	domain.ErrHoldNotActive,
+	domain.ErrHoldExpired,
+	domain.ErrInvalidQuote,
+	domain.ErrQuoteExpired,
+	domain.ErrQuoteMismatch,
+	domain.ErrQuoteUsed,
+	domain.ErrForbidden,
+	domain.ErrWalletNotOwned,
+	domain.ErrScheduledTransferUnauthorized,
+}
+
+// failureReasonInternal — причина неудачного перевода, отклоненного из-за внутренней ошибки сервиса.
+const failureReasonInternal = "internal error"
+
+// failureReason возвращает причину отказа в переводе для истории транзакций. История видна клиентам, поэтому
+// в нее записывается только текст ошибки предметной области без подробностей, добавленных при оборачивании,
+// а остальные ошибки записываются как failureReasonInternal; их текст TransferFunds пишет в лог.
+func failureReason(err error) string {
+	var walletErr *domain.WalletError
+	if errors.As(err, &walletErr) {
+		return string(walletErr.Role) + " " + failureReason(walletErr.Err)
+	}
+	var limitErr *domain.LimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Error()
+	}
+	for _, known := range transferFailures {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return failureReasonInternal
+}
+
+// checkAddresses проверяет адреса отправителя from и получателя to функцией checkAddress.
+func checkAddresses(ctx context.Context, repo repository.Wallet, from string, to string) error {
+	if err := checkAddress(ctx, repo, "from", from); err != nil {
+		return err
+	}
+	return checkAddress(ctx, repo, "to", to)
+}
+
+// checkAddress проверяет, что value — адрес кошелька в текущем формате пакета address, и возвращает domain.ValidationError
+// с именем поля field, если это не так. Адрес, не прошедший проверку версии или контрольной суммы, может быть устаревшим
+// адресом, выданным до введения формата, поэтому он принимается, только если кошелек с ним существует.
+// Так опечатка в адресе текущего формата, в том числе в байте версии, не принимается за устаревший адрес.
+func checkAddress(ctx context.Context, repo repository.Wallet, field string, value string) error {
+	if err := address.ValidateFormat(value); err != nil {
+		return domain.NewValidationError(field, err.Error())
+	}
+	err := address.ValidateCurrent(value)
+	if err == nil {
+		return nil
+	}
+	if _, getErr := repo.Get(ctx, value); !errors.Is(getErr, domain.ErrWalletNotFound) {
+		return getErr
+	}
+	return domain.NewValidationError(field, err.Error())
+}
+
+// checkLimits проверяет, что перевод amount с кошелька wallet не превышает ограничений s.limits.
+// При превышении возвращает domain.LimitError со временем, через которое перевод станет возможен.
+func (s *TransactionService) checkLimits(ctx context.Context, repo repository.Transaction, wallet *models.Wallet, amount money.Amount) error {
+	now := s.now().UTC()
+	if s.limits.MaxTransfersPerMinute > 0 {
+		activity, err := repo.OutgoingSince(ctx, wallet.Address, now.Add(-time.Minute))
+		if err != nil {
+			return err
+		}
+		if activity.Count >= s.limits.MaxTransfersPerMinute {
+			// Лимит освободится, когда самый ранний перевод в окне станет старше минуты.
+			return domain.NewLimitError(domain.LimitTransfersPerMinute, activity.First.Add(time.Minute).Sub(now))
+		}
+	}
+	if s.limits.MaxDailyVolume > 0 {
+		dayStart := now.Truncate(24 * time.Hour)
+		activity, err := repo.OutgoingSince(ctx, wallet.Address, dayStart)
+		if err != nil {
+			return err
+		}
+		volume, err := s.inLimitCurrency(ctx, wallet.Currency, activity.Volume+amount)
+		if err != nil {
+			return err
+		}
+		if volume > s.limits.MaxDailyVolume {
+			return domain.NewLimitError(domain.LimitDailyVolume, dayStart.Add(24*time.Hour).Sub(now))
+		}
+	}
+	return nil
+}
+
+// checkTierLimits проверяет перевод amount по ограничениям уровней кошельков: сумму перевода и суммы переводов
+// за сутки и месяц — по уровню отправителя, баланс после зачисления credit — по уровню получателя.
+// Ограничения уровней заданы в LimitCurrency, поэтому суммы сравниваются с ними после пересчета (inLimitCurrency).
+func (s *TransactionService) checkTierLimits(ctx context.Context, repos *repository.Repository, wallet_from *models.Wallet, wallet_to *models.Wallet, amount money.Amount, credit money.Amount) error {
+	tier_from, err := repos.WalletTier.Get(ctx, wallet_from.Tier)
+	if err != nil {
+		return err
+	}
+	if tier_from.MinTransfer > 0 || tier_from.MaxTransfer > 0 {
+		limited, err := s.inLimitCurrency(ctx, wallet_from.Currency, amount)
+		if err != nil {
+			return err
+		}
+		if limited < tier_from.MinTransfer {
+			return domain.ErrAmountBelowMinimum
+		}
+		if tier_from.MaxTransfer > 0 && limited > tier_from.MaxTransfer {
+			return domain.ErrAmountAboveMaximum
+		}
+	}
+
+	now := s.now().UTC()
+	if tier_from.DailyLimit > 0 {
+		activity, err := repos.Transaction.OutgoingSince(ctx, wallet_from.Address, now.Truncate(24*time.Hour))
+		if err != nil {
+			return err
+		}
+		volume, err := s.inLimitCurrency(ctx, wallet_from.Currency, activity.Volume+amount)
+		if err != nil {
+			return err
+		}
+		if volume > tier_from.DailyLimit {
+			return domain.NewWalletError(models.TransactionRoleSender, wallet_from.Address, domain.ErrDailyLimitExceeded)
+		}
+	}
+	if tier_from.MonthlyLimit > 0 {
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		activity, err := repos.Transaction.OutgoingSince(ctx, wallet_from.Address, monthStart)
+		if err != nil {
+			return err
+		}
+		volume, err := s.inLimitCurrency(ctx, wallet_from.Currency, activity.Volume+amount)
+		if err != nil {
+			return err
+		}
+		if volume > tier_from.MonthlyLimit {
+			return domain.NewWalletError(models.TransactionRoleSender, wallet_from.Address, domain.ErrMonthlyLimitExceeded)
+		}
+	}
+
+	tier_to := tier_from
+	if wallet_to.Tier != wallet_from.Tier {
+		if tier_to, err = repos.WalletTier.Get(ctx, wallet_to.Tier); err != nil {
+			return err
+		}
+	}
+	if tier_to.MaxBalance > 0 {
+		balance, err := s.inLimitCurrency(ctx, wallet_to.Currency, wallet_to.Balance+credit)
+		if err != nil {
+			return err
+		}
+		if balance > tier_to.MaxBalance {
+			return domain.NewWalletError(models.TransactionRoleRecipient, wallet_to.Address, domain.ErrMaxBalanceExceeded)
+		}
+	}
+	return nil
+}
+
+// inLimitCurrency пересчитывает сумму amount в валюте currency в LimitCurrency по текущему среднему курсу s.rates
+// с округлением вниз. Суммы переводов за прошлые периоды тоже пересчитываются по текущему курсу.
+// Если курсов нет, суммы в других валютах сравнить с ограничениями нельзя, и возвращается domain.ErrConversionUnavailable.
+func (s *TransactionService) inLimitCurrency(ctx context.Context, currency string, amount money.Amount) (money.Amount, error) {
+	if currency == LimitCurrency || amount == 0 {
+		return amount, nil
+	}
+	if s.rates == nil {
+		return 0, fmt.Errorf("%w: transfer limits are set in %s", domain.ErrConversionUnavailable, LimitCurrency)
+	}
+	rate, err := s.rates.Rate(ctx, currency, LimitCurrency)
+	if err != nil {
+		return 0, err
+	}
+	return rate.Mid.Convert(amount)
+}
+
+// lockWallets блокирует кошельки отправителя и получателя, а если задан fee — и кошелек комиссий, в порядке возрастания адресов,
+// чтобы встречные переводы между одними и теми же кошельками не приводили к взаимной блокировке.
+// Если кошелек комиссий совпадает с кошельком отправителя или получателя, возвращается тот же объект.
+func lockWallets(ctx context.Context, repo repository.Wallet, from string, to string, fee string) (*models.Wallet, *models.Wallet, *models.Wallet, error) {
+	addresses := []string{from, to}
+	if fee != "" && fee != from && fee != to {
+		addresses = append(addresses, fee)
+	}
+	slices.Sort(addresses)
+
+	locked := make(map[string]*models.Wallet, len(addresses))
+	for _, address := range addresses {
+		var wallet *models.Wallet
+		var err error
+		switch address {
+		case from:
+			wallet, err = lockWallet(ctx, repo, address, models.TransactionRoleSender)
+		case to:
+			wallet, err = lockWallet(ctx, repo, address, models.TransactionRoleRecipient)
+		default:
+			// Отсутствие кошелька комиссий — ошибка конфигурации сервиса, а не запроса, поэтому она не раскрывается клиенту как wallet_not_found.
+			if wallet, err = repo.GetForUpdate(ctx, address); err != nil {
+				err = fmt.Errorf("failed to lock fee wallet %q: %v", address, err)
+			}
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		locked[address] = wallet
+	}
+	return locked[from], locked[to], locked[fee], nil
+}
+
+// lockWallet блокирует кошелек address. Отсутствие кошелька возвращается как domain.WalletError с ролью кошелька в переводе.
+func lockWallet(ctx context.Context, repo repository.Wallet, address string, role models.TransactionRole) (*models.Wallet, error) {
+	wallet, err := repo.GetForUpdate(ctx, address)
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, domain.NewWalletError(role, address, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// checkWalletActive проверяет, что с кошельком можно проводить операции, дополняя ошибку ролью кошелька в переводе.
+func checkWalletActive(wallet *models.Wallet, role models.TransactionRole) error {
+	switch wallet.Status {
+	case models.WalletStatusFrozen:
+		return domain.NewWalletError(role, wallet.Address, domain.ErrWalletFrozen)
+	case models.WalletStatusClosed:
+		return domain.NewWalletError(role, wallet.Address, domain.ErrWalletClosed)
+	}
 	return nil
 }
 
 // GetLastTransactions возвращает последние count транзакций.
-func (s *TransactionService) GetLastTransactions(count int) ([]models.Transaction, error) {
+func (s *TransactionService) GetLastTransactions(ctx context.Context, count int) ([]models.Transaction, error) {
 	var last_transactions []models.Transaction
-	last_transactions, err := s.transaction_repo.Getlast(count)
+	last_transactions, err := s.transaction_repo.Getlast(ctx, count)
 	if err != nil {
 		return nil, err
 	}
 	return last_transactions, nil
 }
+
+// ListTransactions возвращает страницу истории транзакций, подходящих под filter, в порядке от новых к старым.
+// Пустой cursor означает первую страницу; курсор следующей страницы возвращается в TransactionPage.NextCursor.
+func (s *TransactionService) ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) (*models.TransactionPage, error) {
+	if cursor != "" {
+		beforeID, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.BeforeID = beforeID
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	pageSize := filter.Limit
+
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница.
+	filter.Limit++
+	transactions, err := s.transaction_repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TransactionPage{Transactions: transactions}
+	if len(transactions) > pageSize {
+		page.Transactions = transactions[:pageSize]
+		page.NextCursor = encodeCursor(page.Transactions[pageSize-1].ID)
+	}
+	return page, nil
+}
+
+// encodeCursor кодирует позицию в истории транзакций в непрозрачный для клиента курсор.
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("v1:" + strconv.Itoa(id)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, domain.ErrInvalidCursor
+	}
+	idStr, ok := strings.CutPrefix(string(raw), "v1:")
+	if !ok {
+		return 0, domain.ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return 0, domain.ErrInvalidCursor
+	}
+	return id, nil
+}
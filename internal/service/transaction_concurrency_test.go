@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/models"
+	"golangTestTask/internal/repository"
+	"golangTestTask/pkg/address"
+	"golangTestTask/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore — хранилище кошельков в памяти, эмулирующее транзакции PostgreSQL:
+// GetForUpdate берет блокировку строки до конца транзакции, изменения видны другим только после фиксации.
+type memStore struct {
+	mu           sync.Mutex
+	balances     map[string]money.Amount
+	rows         map[string]*sync.Mutex
+	transactions []models.Transaction
+	failed       []models.Transaction
+}
+
+func newMemStore(wallets map[string]money.Amount) *memStore {
+	s := &memStore{
+		balances: make(map[string]money.Amount, len(wallets)),
+		rows:     make(map[string]*sync.Mutex, len(wallets)),
+	}
+	for address, balance := range wallets {
+		s.balances[address] = balance
+		s.rows[address] = &sync.Mutex{}
+	}
+	return s
+}
+
+func (s *memStore) WithTx(ctx context.Context, fn func(repos *repository.Repository) error) error {
+	tx := &memTx{store: s, pending: make(map[string]money.Amount)}
+	defer tx.release()
+
+	if err := fn(&repository.Repository{
+		Wallet:      &memWalletRepo{tx: tx},
+		WalletTier:  memTierRepo{},
+		Transaction: &memTransactionRepo{tx: tx},
+		Ledger:      &memLedgerRepo{tx: tx},
+		Hold:        memHoldRepo{},
+		UnitOfWork:  s,
+	}); err != nil {
+		return err
+	}
+	tx.commit()
+	return nil
+}
+
+func (s *memStore) total() money.Amount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total money.Amount
+	for _, balance := range s.balances {
+		total += balance
+	}
+	return total
+}
+
+type memTx struct {
+	store        *memStore
+	locked       []string
+	pending      map[string]money.Amount
+	transactions []models.Transaction
+}
+
+func (tx *memTx) lock(address string) error {
+	row, ok := tx.store.rows[address]
+	if !ok {
+		return domain.ErrWalletNotFound
+	}
+	for _, locked := range tx.locked {
+		if locked == address {
+			return nil
+		}
+	}
+	row.Lock()
+	tx.locked = append(tx.locked, address)
+	// Уступаем планировщик, как это происходит при сетевом обращении к БД, чтобы переводы действительно пересекались.
+	runtime.Gosched()
+	return nil
+}
+
+func (tx *memTx) read(address string) money.Amount {
+	if balance, ok := tx.pending[address]; ok {
+		return balance
+	}
+	tx.store.mu.Lock()
+	defer tx.store.mu.Unlock()
+	return tx.store.balances[address]
+}
+
+func (tx *memTx) commit() {
+	tx.store.mu.Lock()
+	defer tx.store.mu.Unlock()
+	for address, balance := range tx.pending {
+		tx.store.balances[address] = balance
+	}
+	tx.store.transactions = append(tx.store.transactions, tx.transactions...)
+}
+
+func (tx *memTx) release() {
+	for i := len(tx.locked) - 1; i >= 0; i-- {
+		tx.store.rows[tx.locked[i]].Unlock()
+	}
+}
+
+// memWalletRepo реализует только методы repository.Wallet, используемые при переводе.
+type memWalletRepo struct {
+	repository.Wallet
+	tx *memTx
+}
+
+func (r *memWalletRepo) GetForUpdate(ctx context.Context, address string) (*models.Wallet, error) {
+	if err := r.tx.lock(address); err != nil {
+		return nil, err
+	}
+	return &models.Wallet{Address: address, Currency: "USD", Balance: r.tx.read(address), Status: models.WalletStatusActive, Tier: models.DefaultWalletTier}, nil
+}
+
+// memLedgerRepo применяет проводки по кошелькам к балансам транзакции; сами записи журнала не сохраняются.
+type memLedgerRepo struct {
+	repository.Ledger
+	tx *memTx
+}
+
+func (r *memLedgerRepo) Post(ctx context.Context, entry *models.JournalEntry) error {
+	for _, posting := range entry.Postings {
+		if posting.Wallet != "" {
+			r.tx.pending[posting.Wallet] = r.tx.read(posting.Wallet) + posting.Amount
+		}
+	}
+	return nil
+}
+
+// memTierRepo возвращает уровень без ограничений, кроме минимальной суммы перевода.
+type memTierRepo struct {
+	repository.WalletTier
+}
+
+func (memTierRepo) Get(ctx context.Context, name string) (*models.WalletTier, error) {
+	return &models.WalletTier{Name: name, MinTransfer: money.MustParse("0.01")}, nil
+}
+
+// memHoldRepo — хранилище без блокировок средств.
+type memHoldRepo struct {
+	repository.Hold
+}
+
+func (memHoldRepo) Held(ctx context.Context, address string, now time.Time) (money.Amount, error) {
+	return 0, nil
+}
+
+type memTransactionRepo struct {
+	repository.Transaction
+	tx *memTx
+}
+
+func (r *memTransactionRepo) Create(ctx context.Context, transaction models.Transaction) (int, error) {
+	r.tx.transactions = append(r.tx.transactions, transaction)
+	return len(r.tx.transactions), nil
+}
+
+// memFailedRepo записывает неудачные попытки перевода вне транзакции, сразу в хранилище.
+type memFailedRepo struct {
+	repository.Transaction
+	store *memStore
+}
+
+func (r *memFailedRepo) Create(ctx context.Context, transaction models.Transaction) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.failed = append(r.store.failed, transaction)
+	return len(r.store.failed), nil
+}
+
+const (
+	concurrentWallets        = 10
+	concurrentInitialBalance = 100
+	concurrentTransfers      = 500
+)
+
+// concurrentTransfer — перевод из плана параллельных переводов.
+type concurrentTransfer struct {
+	from, to string
+	amount   money.Amount
+}
+
+// planConcurrentTransfers возвращает count случайных переводов между кошельками addresses.
+// План воспроизводим: при одинаковом числе кошельков переводы выбираются одинаково.
+func planConcurrentTransfers(addresses []string, count int) []concurrentTransfer {
+	rnd := rand.New(rand.NewSource(1))
+	plan := make([]concurrentTransfer, count)
+	for i := range plan {
+		from := rnd.Intn(len(addresses))
+		to := (from + 1 + rnd.Intn(len(addresses)-1)) % len(addresses)
+		plan[i] = concurrentTransfer{from: addresses[from], to: addresses[to], amount: money.Amount(1 + rnd.Int63n(4000))}
+	}
+	return plan
+}
+
+// runConcurrentTransfers одновременно выполняет переводы plan через service и возвращает число выполненных переводов
+// и отклоненных из-за нехватки средств. Любая другая ошибка проваливает тест, как и переводы, не завершившиеся за timeout.
+func runConcurrentTransfers(t *testing.T, service *TransactionService, plan []concurrentTransfer, timeout time.Duration) (succeeded, rejected int) {
+	t.Helper()
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	ctx := auth.WithPrincipal(context.Background(), auth.System())
+	start := make(chan struct{})
+	for _, tr := range plan {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: tr.from, To: tr.to, Amount: tr.amount})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case err.Error() == "insufficient funds":
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	close(start)
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("transfers did not finish: possible deadlock between wallet locks")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return succeeded, rejected
+}
+
+func TestTransactionService_TransferFunds_Concurrent(t *testing.T) {
+	wallets := make(map[string]money.Amount, concurrentWallets)
+	addresses := make([]string, 0, concurrentWallets)
+	for i := 0; i < concurrentWallets; i++ {
+		var payload [address.PayloadSize]byte
+		payload[0] = byte(i)
+		addr := address.Encode(payload)
+		wallets[addr] = money.FromInt(concurrentInitialBalance)
+		addresses = append(addresses, addr)
+	}
+	store := newMemStore(wallets)
+	service := NewTransactionService(&repository.Repository{
+		Transaction: &memFailedRepo{store: store},
+		UnitOfWork:  store,
+	}, TransferLimits{}, TransferFees{}, nil, nil)
+
+	succeeded, rejected := runConcurrentTransfers(t, service, planConcurrentTransfers(addresses, concurrentTransfers), 10*time.Second)
+
+	require.Equal(t, concurrentTransfers, succeeded+rejected)
+	assert.Equal(t, money.FromInt(concurrentWallets*concurrentInitialBalance), store.total())
+	assert.Len(t, store.transactions, succeeded)
+	assert.Len(t, store.failed, rejected)
+	for _, transaction := range store.transactions {
+		assert.Equal(t, models.TransactionStatusCompleted, transaction.Status)
+	}
+	for _, transaction := range store.failed {
+		assert.Equal(t, models.TransactionStatusFailed, transaction.Status)
+		assert.Equal(t, "insufficient funds", transaction.FailureReason)
+	}
+	for address, balance := range store.balances {
+		assert.GreaterOrEqual(t, balance, money.Amount(0), "wallet %s has negative balance", address)
+	}
+}
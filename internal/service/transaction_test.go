@@ -1,73 +1,102 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
+	"golangTestTask/internal/fee"
+	"golangTestTask/internal/fx"
 	"golangTestTask/internal/models"
+	"golangTestTask/internal/quote"
 	"golangTestTask/internal/repository"
 	repository_mocks "golangTestTask/internal/repository/mocks"
+	"golangTestTask/pkg/money"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+// addr1, addr2, addr3 и feeAddr — адреса кошельков в формате pkg/address.
+const (
+	addr1   = "011111111111111111111111111111111111111111111111111111119b3556e5"
+	addr2   = "01222222222222222222222222222222222222222222222222222222b42432d6"
+	addr3   = "01444444444444444444444444444444444444444444444444444444ea06fab0"
+	feeAddr = "01333333333333333333333333333333333333333333333333333333aed4eec7"
+)
+
 func TestTransactionService_TransferFunds(t *testing.T) {
 	type mockBehavior struct {
-		getFrom    func(r *repository_mocks.MockWallet, from string, balance float64)
-		getTo      func(r *repository_mocks.MockWallet, to string, balance float64)
-		updateFrom func(r *repository_mocks.MockWallet, wallet *models.Wallet)
-		updateTo   func(r *repository_mocks.MockWallet, wallet *models.Wallet)
-		createTx   func(r *repository_mocks.MockTransaction, tx models.Transaction)
+		getFrom   func(r *repository_mocks.MockWallet, from string, balance money.Amount)
+		getTo     func(r *repository_mocks.MockWallet, to string, balance money.Amount)
+		createTx  func(r *repository_mocks.MockTransaction, tx models.Transaction)
+		postEntry func(r *repository_mocks.MockLedger, entry *models.JournalEntry)
 	}
 
 	tests := []struct {
 		name         string
 		from         string
 		to           string
-		amount       float64
+		amount       money.Amount
 		mockBehavior mockBehavior
 		wantErr      bool
 		expectedErr  string
+		// currency — валюта, записанная в транзакцию; пуста, если кошельки не удалось заблокировать.
+		currency string
+		// failureReason — причина отказа, записываемая в историю, если она отличается от expectedErr.
+		failureReason string
 	}{
 		{
-			name:   "successful transfer",
-			from:   "addr1",
-			to:     "addr2",
-			amount: 10.5,
+			name:     "successful transfer",
+			from:     addr1,
+			to:       addr2,
+			amount:   money.MustParse("10.50"),
+			currency: "USD",
 			mockBehavior: mockBehavior{
-				getFrom: func(r *repository_mocks.MockWallet, from string, balance float64) {
-					r.EXPECT().Get(from).Return(&models.Wallet{
-						Address: from,
-						Balance: 100.0,
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(&models.Wallet{
+						Address:  from,
+						Currency: "USD",
+						Balance:  balance,
 					}, nil)
 				},
-				getTo: func(r *repository_mocks.MockWallet, to string, balance float64) {
-					r.EXPECT().Get(to).Return(&models.Wallet{
-						Address: to,
-						Balance: 50.0,
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(&models.Wallet{
+						Address:  to,
+						Currency: "USD",
+						Balance:  balance,
 					}, nil)
 				},
-				updateFrom: func(r *repository_mocks.MockWallet, wallet *models.Wallet) {
-					r.EXPECT().Update(wallet).Return(nil)
-				},
-				updateTo: func(r *repository_mocks.MockWallet, wallet *models.Wallet) {
-					r.EXPECT().Update(wallet).Return(nil)
-				},
 				createTx: func(r *repository_mocks.MockTransaction, tx models.Transaction) {
-					r.EXPECT().Create(tx).Return(nil)
+					r.EXPECT().Create(gomock.Any(), tx).Return(1, nil)
+				},
+				postEntry: func(r *repository_mocks.MockLedger, entry *models.JournalEntry) {
+					r.EXPECT().Post(gomock.Any(), entry).Return(nil)
 				},
 			},
 			wantErr: false,
 		},
 		{
 			name:   "sender not found",
-			from:   "unknown",
-			to:     "addr2",
-			amount: 10.5,
+			from:   addr3,
+			to:     addr2,
+			amount: money.MustParse("10.50"),
 			mockBehavior: mockBehavior{
-				getFrom: func(r *repository_mocks.MockWallet, from string, balance float64) {
-					r.EXPECT().Get(from).Return(nil, repository.ErrWalletNotFound)
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(&models.Wallet{
+						Address:  to,
+						Currency: "USD",
+						Balance:  balance,
+					}, nil)
+				},
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(nil, domain.ErrWalletNotFound)
 				},
 			},
 			wantErr:     true,
@@ -75,33 +104,52 @@ func TestTransactionService_TransferFunds(t *testing.T) {
 		},
 		{
 			name:   "recipient not found",
-			from:   "addr1",
-			to:     "unknown",
-			amount: 10.5,
+			from:   addr1,
+			to:     addr3,
+			amount: money.MustParse("10.50"),
 			mockBehavior: mockBehavior{
-				getFrom: func(r *repository_mocks.MockWallet, from string, balance float64) {
-					r.EXPECT().Get(from).Return(&models.Wallet{
-						Address: from,
-						Balance: 100.0,
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(&models.Wallet{
+						Address:  from,
+						Currency: "USD",
+						Balance:  balance,
 					}, nil)
 				},
-				getTo: func(r *repository_mocks.MockWallet, to string, balance float64) {
-					r.EXPECT().Get(to).Return(nil, repository.ErrWalletNotFound)
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(nil, domain.ErrWalletNotFound)
 				},
 			},
 			wantErr:     true,
 			expectedErr: "recipient wallet not found",
 		},
 		{
-			name:   "insufficient funds",
-			from:   "addr1",
-			to:     "addr2",
-			amount: 150.0,
+			name:         "same wallet",
+			from:         addr1,
+			to:           addr1,
+			amount:       money.MustParse("10.50"),
+			mockBehavior: mockBehavior{},
+			wantErr:      true,
+			expectedErr:  "sender and recipient wallets must differ",
+		},
+		{
+			name:     "insufficient funds",
+			from:     addr1,
+			to:       addr2,
+			amount:   money.MustParse("150.00"),
+			currency: "USD",
 			mockBehavior: mockBehavior{
-				getFrom: func(r *repository_mocks.MockWallet, from string, balance float64) {
-					r.EXPECT().Get(from).Return(&models.Wallet{
-						Address: from,
-						Balance: 100.0,
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(&models.Wallet{
+						Address:  from,
+						Currency: "USD",
+						Balance:  balance,
+					}, nil)
+				},
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(&models.Wallet{
+						Address:  to,
+						Currency: "USD",
+						Balance:  balance,
 					}, nil)
 				},
 			},
@@ -109,29 +157,119 @@ func TestTransactionService_TransferFunds(t *testing.T) {
 			expectedErr: "insufficient funds",
 		},
 		{
-			name:   "update sender failed",
-			from:   "addr1",
-			to:     "addr2",
-			amount: 10.5,
+			name:     "sender frozen",
+			from:     addr1,
+			to:       addr2,
+			amount:   money.MustParse("10.50"),
+			currency: "USD",
 			mockBehavior: mockBehavior{
-				getFrom: func(r *repository_mocks.MockWallet, from string, balance float64) {
-					r.EXPECT().Get(from).Return(&models.Wallet{
-						Address: from,
-						Balance: 100.0,
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(&models.Wallet{
+						Address:  from,
+						Currency: "USD",
+						Balance:  balance,
+						Status:   models.WalletStatusFrozen,
+					}, nil)
+				},
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(&models.Wallet{
+						Address:  to,
+						Currency: "USD",
+						Balance:  balance,
+						Status:   models.WalletStatusActive,
 					}, nil)
 				},
-				getTo: func(r *repository_mocks.MockWallet, to string, balance float64) {
-					r.EXPECT().Get(to).Return(&models.Wallet{
-						Address: to,
-						Balance: 50.0,
+			},
+			wantErr:     true,
+			expectedErr: "sender wallet is frozen",
+		},
+		{
+			name:     "recipient closed",
+			from:     addr1,
+			to:       addr2,
+			amount:   money.MustParse("10.50"),
+			currency: "USD",
+			mockBehavior: mockBehavior{
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(&models.Wallet{
+						Address:  from,
+						Currency: "USD",
+						Balance:  balance,
+						Status:   models.WalletStatusActive,
 					}, nil)
 				},
-				updateFrom: func(r *repository_mocks.MockWallet, wallet *models.Wallet) {
-					r.EXPECT().Update(wallet).Return(errors.New("update failed"))
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(&models.Wallet{
+						Address:  to,
+						Currency: "USD",
+						Balance:  balance,
+						Status:   models.WalletStatusClosed,
+					}, nil)
 				},
 			},
 			wantErr:     true,
-			expectedErr: "update failed",
+			expectedErr: "recipient wallet is closed",
+		},
+		{
+			name:     "post entry failed",
+			from:     addr1,
+			to:       addr2,
+			amount:   money.MustParse("10.50"),
+			currency: "USD",
+			mockBehavior: mockBehavior{
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(&models.Wallet{
+						Address:  from,
+						Currency: "USD",
+						Balance:  balance,
+					}, nil)
+				},
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(&models.Wallet{
+						Address:  to,
+						Currency: "USD",
+						Balance:  balance,
+					}, nil)
+				},
+				createTx: func(r *repository_mocks.MockTransaction, tx models.Transaction) {
+					r.EXPECT().Create(gomock.Any(), tx).Return(1, nil)
+				},
+				postEntry: func(r *repository_mocks.MockLedger, entry *models.JournalEntry) {
+					r.EXPECT().Post(gomock.Any(), entry).Return(errors.New("post failed"))
+				},
+			},
+			wantErr:       true,
+			expectedErr:   "post failed",
+			failureReason: "internal error",
+		},
+		{
+			name:     "create transaction failed",
+			from:     addr1,
+			to:       addr2,
+			amount:   money.MustParse("10.50"),
+			currency: "USD",
+			mockBehavior: mockBehavior{
+				getFrom: func(r *repository_mocks.MockWallet, from string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), from).Return(&models.Wallet{
+						Address:  from,
+						Currency: "USD",
+						Balance:  balance,
+					}, nil)
+				},
+				getTo: func(r *repository_mocks.MockWallet, to string, balance money.Amount) {
+					r.EXPECT().GetForUpdate(gomock.Any(), to).Return(&models.Wallet{
+						Address:  to,
+						Currency: "USD",
+						Balance:  balance,
+					}, nil)
+				},
+				createTx: func(r *repository_mocks.MockTransaction, tx models.Transaction) {
+					r.EXPECT().Create(gomock.Any(), tx).Return(0, errors.New("insert failed"))
+				},
+			},
+			wantErr:       true,
+			expectedErr:   "insert failed",
+			failureReason: "internal error",
 		},
 	}
 
@@ -141,36 +279,58 @@ func TestTransactionService_TransferFunds(t *testing.T) {
 			defer ctrl.Finish()
 
 			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
 			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+			})
+			tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
 
 			if tt.mockBehavior.getFrom != nil {
-				tt.mockBehavior.getFrom(walletRepo, tt.from, 100.0)
+				tt.mockBehavior.getFrom(walletRepo, tt.from, money.FromInt(100))
 			}
 			if tt.mockBehavior.getTo != nil {
-				tt.mockBehavior.getTo(walletRepo, tt.to, 50.0)
+				tt.mockBehavior.getTo(walletRepo, tt.to, money.FromInt(50))
 			}
-			if tt.mockBehavior.updateFrom != nil {
-				tt.mockBehavior.updateFrom(walletRepo, &models.Wallet{
-					Address: tt.from,
-					Balance: 100.0 - tt.amount,
+			if tt.mockBehavior.createTx != nil {
+				tt.mockBehavior.createTx(txRepo, models.Transaction{
+					From:     tt.from,
+					To:       tt.to,
+					Amount:   tt.amount,
+					Currency: tt.currency,
+					Status:   models.TransactionStatusCompleted,
 				})
 			}
-			if tt.mockBehavior.updateTo != nil {
-				tt.mockBehavior.updateTo(walletRepo, &models.Wallet{
-					Address: tt.to,
-					Balance: 50.0 + tt.amount,
+			if tt.mockBehavior.postEntry != nil {
+				tt.mockBehavior.postEntry(ledgerRepo, &models.JournalEntry{
+					Kind:          models.JournalEntryKindTransfer,
+					TransactionID: 1,
+					Postings: []models.Posting{
+						{Wallet: tt.from, Currency: "USD", Amount: -tt.amount},
+						{Wallet: tt.to, Currency: "USD", Amount: tt.amount},
+					},
 				})
 			}
-			if tt.mockBehavior.createTx != nil {
-				tt.mockBehavior.createTx(txRepo, models.Transaction{
-					From:   tt.from,
-					To:     tt.to,
-					Amount: tt.amount,
-				})
+			if tt.wantErr {
+				reason := tt.expectedErr
+				if tt.failureReason != "" {
+					reason = tt.failureReason
+				}
+				txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+					From:          tt.from,
+					To:            tt.to,
+					Amount:        tt.amount,
+					Currency:      tt.currency,
+					Status:        models.TransactionStatusFailed,
+					FailureReason: reason,
+				}).Return(1, nil)
 			}
 
-			service := NewTransactionService(txRepo, walletRepo)
-			err := service.TransferFunds(tt.from, tt.to, tt.amount)
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{tt.from}})
+			_, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: tt.from, To: tt.to, Amount: tt.amount})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -182,45 +342,172 @@ func TestTransactionService_TransferFunds(t *testing.T) {
 	}
 }
 
-func TestTransactionService_GetLastTransactions(t *testing.T) {
+func TestTransactionService_TransferFunds_Limits(t *testing.T) {
+	now := time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC)
+	limits := TransferLimits{MaxTransfersPerMinute: 3, MaxDailyVolume: money.FromInt(100)}
+
 	tests := []struct {
-		name           string
-		count          int
-		mockBehavior   func(r *repository_mocks.MockTransaction, count int)
-		expectedResult []models.Transaction
-		wantErr        bool
+		name          string
+		amount        money.Amount
+		perMinute     models.TransferActivity
+		daily         *models.TransferActivity
+		expectedLimit string
+		retryAfter    time.Duration
 	}{
 		{
-			name:  "successful get last transactions",
-			count: 2,
-			mockBehavior: func(r *repository_mocks.MockTransaction, count int) {
-				r.EXPECT().Getlast(count).Return([]models.Transaction{
-					{From: "addr1", To: "addr2", Amount: 10.5},
-					{From: "addr2", To: "addr1", Amount: 5.0},
-				}, nil)
+			name:      "within limits",
+			amount:    money.FromInt(40),
+			perMinute: models.TransferActivity{Count: 2, Volume: money.FromInt(20), First: now.Add(-30 * time.Second)},
+			daily:     &models.TransferActivity{Count: 5, Volume: money.FromInt(60), First: now.Add(-time.Hour)},
+		},
+		{
+			name:          "too many transfers per minute",
+			amount:        money.FromInt(10),
+			perMinute:     models.TransferActivity{Count: 3, Volume: money.FromInt(30), First: now.Add(-45 * time.Second)},
+			expectedLimit: domain.LimitTransfersPerMinute,
+			retryAfter:    15 * time.Second,
+		},
+		{
+			name:          "daily volume exceeded",
+			amount:        money.MustParse("40.01"),
+			perMinute:     models.TransferActivity{Count: 1, Volume: money.FromInt(10), First: now.Add(-10 * time.Second)},
+			daily:         &models.TransferActivity{Count: 5, Volume: money.FromInt(60), First: now.Add(-time.Hour)},
+			expectedLimit: domain.LimitDailyVolume,
+			retryAfter:    6 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+			})
+			tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+
+			walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+			walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD", Balance: money.FromInt(50)}, nil)
+			txRepo.EXPECT().OutgoingSince(gomock.Any(), addr1, now.Add(-time.Minute)).Return(tt.perMinute, nil)
+			if tt.daily != nil {
+				txRepo.EXPECT().OutgoingSince(gomock.Any(), addr1, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)).Return(*tt.daily, nil)
+			}
+			if tt.expectedLimit == "" {
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(1, nil)
+				ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil)
+			} else {
+				txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+					From:          addr1,
+					To:            addr2,
+					Amount:        tt.amount,
+					Currency:      "USD",
+					Status:        models.TransactionStatusFailed,
+					FailureReason: "limit exceeded: " + tt.expectedLimit,
+				}).Return(1, nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, limits, TransferFees{}, nil, nil)
+			service.now = func() time.Time { return now }
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}})
+			_, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: addr1, To: addr2, Amount: tt.amount})
+
+			if tt.expectedLimit == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var limitErr *domain.LimitError
+			if assert.ErrorAs(t, err, &limitErr) {
+				assert.Equal(t, tt.expectedLimit, limitErr.Limit)
+				assert.Equal(t, tt.retryAfter, limitErr.RetryAfter)
+			}
+			assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_TierLimits(t *testing.T) {
+	now := time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC)
+	dayStart := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	senderTier := &models.WalletTier{
+		Name:         "standard",
+		MinTransfer:  money.FromInt(1),
+		MaxTransfer:  money.FromInt(50),
+		DailyLimit:   money.FromInt(60),
+		MonthlyLimit: money.FromInt(200),
+	}
+	recipientTier := &models.WalletTier{Name: "basic", MinTransfer: money.MustParse("0.01"), MaxBalance: money.FromInt(80)}
+
+	tests := []struct {
+		name        string
+		amount      money.Amount
+		mock        func(tiers *repository_mocks.MockWalletTier, txs *repository_mocks.MockTransaction)
+		expectedErr error
+		role        models.TransactionRole
+	}{
+		{
+			name:   "within limits",
+			amount: money.FromInt(20),
+			mock: func(tiers *repository_mocks.MockWalletTier, txs *repository_mocks.MockTransaction) {
+				tiers.EXPECT().Get(gomock.Any(), "standard").Return(senderTier, nil)
+				txs.EXPECT().OutgoingSince(gomock.Any(), addr1, dayStart).Return(models.TransferActivity{Volume: money.FromInt(40)}, nil)
+				txs.EXPECT().OutgoingSince(gomock.Any(), addr1, monthStart).Return(models.TransferActivity{Volume: money.FromInt(180)}, nil)
+				tiers.EXPECT().Get(gomock.Any(), "basic").Return(recipientTier, nil)
 			},
-			expectedResult: []models.Transaction{
-				{From: "addr1", To: "addr2", Amount: 10.5},
-				{From: "addr2", To: "addr1", Amount: 5.0},
+		},
+		{
+			name:   "below minimum",
+			amount: money.MustParse("0.99"),
+			mock: func(tiers *repository_mocks.MockWalletTier, txs *repository_mocks.MockTransaction) {
+				tiers.EXPECT().Get(gomock.Any(), "standard").Return(senderTier, nil)
 			},
-			wantErr: false,
+			expectedErr: domain.ErrAmountBelowMinimum,
 		},
 		{
-			name:  "repository error",
-			count: 2,
-			mockBehavior: func(r *repository_mocks.MockTransaction, count int) {
-				r.EXPECT().Getlast(count).Return(nil, errors.New("db error"))
+			name:   "above maximum",
+			amount: money.MustParse("50.01"),
+			mock: func(tiers *repository_mocks.MockWalletTier, txs *repository_mocks.MockTransaction) {
+				tiers.EXPECT().Get(gomock.Any(), "standard").Return(senderTier, nil)
 			},
-			wantErr: true,
+			expectedErr: domain.ErrAmountAboveMaximum,
 		},
 		{
-			name:  "empty result",
-			count: 2,
-			mockBehavior: func(r *repository_mocks.MockTransaction, count int) {
-				r.EXPECT().Getlast(count).Return([]models.Transaction{}, nil)
+			name:   "daily limit exceeded",
+			amount: money.FromInt(21),
+			mock: func(tiers *repository_mocks.MockWalletTier, txs *repository_mocks.MockTransaction) {
+				tiers.EXPECT().Get(gomock.Any(), "standard").Return(senderTier, nil)
+				txs.EXPECT().OutgoingSince(gomock.Any(), addr1, dayStart).Return(models.TransferActivity{Volume: money.FromInt(40)}, nil)
 			},
-			expectedResult: []models.Transaction{},
-			wantErr:        false,
+			expectedErr: domain.ErrDailyLimitExceeded,
+			role:        models.TransactionRoleSender,
+		},
+		{
+			name:   "monthly limit exceeded",
+			amount: money.FromInt(21),
+			mock: func(tiers *repository_mocks.MockWalletTier, txs *repository_mocks.MockTransaction) {
+				tiers.EXPECT().Get(gomock.Any(), "standard").Return(senderTier, nil)
+				txs.EXPECT().OutgoingSince(gomock.Any(), addr1, dayStart).Return(models.TransferActivity{}, nil)
+				txs.EXPECT().OutgoingSince(gomock.Any(), addr1, monthStart).Return(models.TransferActivity{Volume: money.FromInt(180)}, nil)
+			},
+			expectedErr: domain.ErrMonthlyLimitExceeded,
+			role:        models.TransactionRoleSender,
+		},
+		{
+			name:   "recipient max balance exceeded",
+			amount: money.FromInt(31),
+			mock: func(tiers *repository_mocks.MockWalletTier, txs *repository_mocks.MockTransaction) {
+				tiers.EXPECT().Get(gomock.Any(), "standard").Return(senderTier, nil)
+				txs.EXPECT().OutgoingSince(gomock.Any(), addr1, gomock.Any()).Return(models.TransferActivity{}, nil).Times(2)
+				tiers.EXPECT().Get(gomock.Any(), "basic").Return(recipientTier, nil)
+			},
+			expectedErr: domain.ErrMaxBalanceExceeded,
+			role:        models.TransactionRoleRecipient,
 		},
 	}
 
@@ -229,18 +516,1452 @@ func TestTransactionService_GetLastTransactions(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
 			txRepo := repository_mocks.NewMockTransaction(ctrl)
-			tt.mockBehavior(txRepo, tt.count)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+			})
 
-			service := NewTransactionService(txRepo, nil)
-			result, err := service.GetLastTransactions(tt.count)
-
-			if tt.wantErr {
-				assert.Error(t, err)
+			walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100), Tier: "standard"}, nil)
+			walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD", Balance: money.FromInt(50), Tier: "basic"}, nil)
+			tt.mock(tierRepo, txRepo)
+			if tt.expectedErr == nil {
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(1, nil)
+				ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil)
 			} else {
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(1, nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			service.now = func() time.Time { return now }
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}})
+			_, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: addr1, To: addr2, Amount: tt.amount})
+
+			if tt.expectedErr == nil {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedResult, result)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.role != "" {
+				var walletErr *domain.WalletError
+				if assert.ErrorAs(t, err, &walletErr) {
+					assert.Equal(t, tt.role, walletErr.Role)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_LimitCurrency(t *testing.T) {
+	now := time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC)
+	dayStart := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	// Ограничения заданы в долларах: 100 USD = 15000 JPY, 50 USD = 7500 JPY, 80 USD = 12000 JPY.
+	limits := TransferLimits{MaxDailyVolume: money.FromInt(100)}
+	tier := &models.WalletTier{Name: "standard", MaxTransfer: money.FromInt(50), MaxBalance: money.FromInt(80)}
+	rates, err := fx.NewStaticProvider(fx.Table{Base: "USD", Rates: map[string]money.Rate{"JPY": money.MustParseRate("150")}, SpreadBP: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		amount           money.Amount
+		dailyVolume      money.Amount
+		recipientBalance money.Amount
+		rates            fx.RateProvider
+		expectedErr      error
+	}{
+		{
+			name:        "within limits in base currency",
+			amount:      money.FromInt(6000),
+			dailyVolume: money.FromInt(9000),
+			rates:       rates,
+		},
+		{
+			name:        "daily volume exceeded in base currency",
+			amount:      money.FromInt(6200),
+			dailyVolume: money.FromInt(9000),
+			rates:       rates,
+			expectedErr: domain.ErrLimitExceeded,
+		},
+		{
+			name:        "above tier maximum in base currency",
+			amount:      money.FromInt(7600),
+			rates:       rates,
+			expectedErr: domain.ErrAmountAboveMaximum,
+		},
+		{
+			name:             "recipient max balance exceeded in base currency",
+			amount:           money.FromInt(7000),
+			recipientBalance: money.FromInt(6000),
+			rates:            rates,
+			expectedErr:      domain.ErrMaxBalanceExceeded,
+		},
+		{
+			name:        "no exchange rate",
+			amount:      money.FromInt(6000),
+			dailyVolume: money.FromInt(9000),
+			expectedErr: domain.ErrConversionUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+			})
+			tierRepo.EXPECT().Get(gomock.Any(), "standard").Return(tier, nil).AnyTimes()
+
+			walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "JPY", Balance: money.FromInt(100000), Tier: "standard"}, nil)
+			walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "JPY", Balance: tt.recipientBalance, Tier: "standard"}, nil)
+			txRepo.EXPECT().OutgoingSince(gomock.Any(), addr1, dayStart).Return(models.TransferActivity{Volume: tt.dailyVolume}, nil)
+			txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(1, nil)
+			if tt.expectedErr == nil {
+				ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, limits, TransferFees{}, nil, tt.rates)
+			service.now = func() time.Time { return now }
+			_, err := service.TransferFunds(auth.WithPrincipal(context.Background(), auth.System()), models.CreateTransactionRequest{From: addr1, To: addr2, Amount: tt.amount})
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_Fees(t *testing.T) {
+	fees := TransferFees{Rules: map[string]FeeRule{
+		"USD": {Schedule: fee.Schedule{Kind: fee.KindPercentage, Flat: money.MustParse("0.30"), RateBP: 100}, Wallet: feeAddr},
+	}}
+
+	tests := []struct {
+		name           string
+		from           string
+		to             string
+		amount         money.Amount
+		balances       map[string]money.Amount
+		expectedResult *models.TransferResult
+		expectedErr    error
+		// expectedBalances — балансы кошельков после применения проводок перевода.
+		expectedBalances map[string]money.Amount
+	}{
+		{
+			name:     "fee credited to fee wallet",
+			from:     addr1,
+			to:       addr2,
+			amount:   money.FromInt(10),
+			balances: map[string]money.Amount{addr1: money.FromInt(100), addr2: money.FromInt(50), feeAddr: money.FromInt(1)},
+			expectedResult: &models.TransferResult{
+				TransactionID: 1,
+				Currency:      "USD",
+				Amount:        money.FromInt(10),
+				Fee:           money.MustParse("0.40"),
+				Total:         money.MustParse("10.40"),
+			},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("89.60"), addr2: money.FromInt(60), feeAddr: money.MustParse("1.40")},
+		},
+		{
+			name:     "fee wallet is recipient",
+			from:     addr1,
+			to:       feeAddr,
+			amount:   money.FromInt(10),
+			balances: map[string]money.Amount{addr1: money.FromInt(100), feeAddr: money.FromInt(1)},
+			expectedResult: &models.TransferResult{
+				TransactionID: 1,
+				Currency:      "USD",
+				Amount:        money.FromInt(10),
+				Fee:           money.MustParse("0.40"),
+				Total:         money.MustParse("10.40"),
+			},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("89.60"), feeAddr: money.MustParse("11.40")},
+		},
+		{
+			name:     "no fee from fee wallet",
+			from:     feeAddr,
+			to:       addr2,
+			amount:   money.FromInt(10),
+			balances: map[string]money.Amount{feeAddr: money.FromInt(10), addr2: money.FromInt(50)},
+			expectedResult: &models.TransferResult{
+				TransactionID: 1,
+				Currency:      "USD",
+				Amount:        money.FromInt(10),
+				Total:         money.FromInt(10),
+			},
+			expectedBalances: map[string]money.Amount{feeAddr: 0, addr2: money.FromInt(60)},
+		},
+		{
+			name:        "insufficient funds for fee",
+			from:        addr1,
+			to:          addr2,
+			amount:      money.FromInt(10),
+			balances:    map[string]money.Amount{addr1: money.MustParse("10.39"), addr2: money.FromInt(50), feeAddr: money.FromInt(1)},
+			expectedErr: domain.ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+			})
+			tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+
+			readWallets(walletRepo, nil)
+			for address, balance := range tt.balances {
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), address).Return(&models.Wallet{Address: address, Currency: "USD", Balance: balance}, nil)
+			}
+			var posted *models.JournalEntry
+			if tt.expectedErr == nil {
+				ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) error {
+					posted = entry
+					return nil
+				})
+				txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+					From:     tt.from,
+					To:       tt.to,
+					Amount:   tt.amount,
+					Currency: "USD",
+					Status:   models.TransactionStatusCompleted,
+				}).Return(1, nil)
+				if tt.expectedResult.Fee > 0 {
+					txRepo.EXPECT().CreateFee(gomock.Any(), models.TransactionFee{
+						TransactionID: 1,
+						Wallet:        feeAddr,
+						Amount:        tt.expectedResult.Fee,
+					}).Return(nil)
+				}
+			} else {
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(1, nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, fees, nil, nil)
+			result, err := service.TransferFunds(auth.WithPrincipal(context.Background(), auth.System()), models.CreateTransactionRequest{From: tt.from, To: tt.to, Amount: tt.amount})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, tt.expectedBalances, applyEntry(tt.balances, posted))
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_Currencies(t *testing.T) {
+	// Комиссия в иенах задана отдельным тарифом: общий тариф после округления до целых иен давал бы нулевую комиссию.
+	fees := TransferFees{Rules: map[string]FeeRule{
+		"USD": {Schedule: fee.Schedule{Kind: fee.KindPercentage, Flat: money.MustParse("0.30"), RateBP: 100}, Wallet: feeAddr},
+		"JPY": {Schedule: fee.Schedule{Kind: fee.KindFlat, Flat: money.FromInt(5)}, Wallet: addr3},
+	}}
+
+	tests := []struct {
+		name string
+		req  models.CreateTransactionRequest
+		// currencies — валюты кошельков, которые блокируются при переводе.
+		currencies     map[string]string
+		expectedResult *models.TransferResult
+		expectedErr    error
+		// expectedFailure — текст ошибки перевода.
+		expectedFailure string
+		// failureReason — причина отказа, записываемая в историю, если она отличается от expectedFailure.
+		failureReason string
+	}{
+		{
+			name:            "currency mismatch",
+			req:             models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10)},
+			currencies:      map[string]string{addr1: "USD", addr2: "EUR", feeAddr: "USD"},
+			expectedErr:     domain.ErrCurrencyMismatch,
+			expectedFailure: domain.ErrCurrencyMismatch.Error(),
+		},
+		{
+			name:            "conversion requested",
+			req:             models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10), Convert: true},
+			currencies:      map[string]string{addr1: "USD", addr2: "EUR", feeAddr: "USD"},
+			expectedErr:     domain.ErrConversionUnavailable,
+			expectedFailure: domain.ErrConversionUnavailable.Error(),
+		},
+		{
+			name:            "amount too precise for currency",
+			req:             models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50")},
+			currencies:      map[string]string{addr1: "JPY", addr2: "JPY", addr3: "JPY"},
+			expectedErr:     domain.ErrInvalidAmountPrecision,
+			expectedFailure: domain.ErrInvalidAmountPrecision.Error(),
+		},
+		{
+			name:       "fee in sender currency",
+			req:        models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10)},
+			currencies: map[string]string{addr1: "JPY", addr2: "JPY", addr3: "JPY"},
+			expectedResult: &models.TransferResult{
+				TransactionID: 1,
+				Currency:      "JPY",
+				Amount:        money.FromInt(10),
+				Fee:           money.FromInt(5),
+				Total:         money.FromInt(15),
+			},
+		},
+		{
+			// Кошелек отправителя в евро не блокируется: перевод отклоняется при выборе кошелька комиссий.
+			name:            "no fee wallet in sender currency",
+			req:             models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10)},
+			expectedErr:     domain.ErrFeeUnavailable,
+			expectedFailure: domain.ErrFeeUnavailable.Error() + ": EUR",
+			failureReason:   domain.ErrFeeUnavailable.Error(),
+		},
+		{
+			name:            "fee wallet in another currency",
+			req:             models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10)},
+			currencies:      map[string]string{addr1: "JPY", addr2: "JPY", addr3: "USD"},
+			expectedFailure: `fee wallet "` + addr3 + `" for JPY has currency USD`,
+			failureReason:   "internal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+			})
+			// Без ограничений уровня суммы в иенах не нужно пересчитывать в валюту ограничений.
+			tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{}, nil).AnyTimes()
+
+			sender := tt.currencies[addr1]
+			if sender == "" {
+				sender = "EUR"
+			}
+			readWallets(walletRepo, map[string]string{addr1: sender})
+			for address, currency := range tt.currencies {
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), address).Return(&models.Wallet{Address: address, Currency: currency, Balance: money.FromInt(100)}, nil)
+			}
+			if tt.expectedFailure == "" {
+				txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+					From:     addr1,
+					To:       addr2,
+					Amount:   money.FromInt(10),
+					Currency: "JPY",
+					Status:   models.TransactionStatusCompleted,
+				}).Return(1, nil)
+				ledgerRepo.EXPECT().Post(gomock.Any(), &models.JournalEntry{
+					Kind:          models.JournalEntryKindTransfer,
+					TransactionID: 1,
+					Postings: []models.Posting{
+						{Wallet: addr1, Currency: "JPY", Amount: -money.FromInt(10)},
+						{Wallet: addr2, Currency: "JPY", Amount: money.FromInt(10)},
+						{Wallet: addr1, Currency: "JPY", Amount: -money.FromInt(5)},
+						{Wallet: addr3, Currency: "JPY", Amount: money.FromInt(5)},
+					},
+				}).Return(nil)
+				txRepo.EXPECT().CreateFee(gomock.Any(), models.TransactionFee{TransactionID: 1, Wallet: addr3, Amount: money.FromInt(5)}).Return(nil)
+			} else {
+				// Если перевод отклонен до блокировки кошельков, валюта отправителя в историю не записывается.
+				reason := tt.expectedFailure
+				if tt.failureReason != "" {
+					reason = tt.failureReason
+				}
+				txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+					From:          tt.req.From,
+					To:            tt.req.To,
+					Amount:        tt.req.Amount,
+					Currency:      tt.currencies[tt.req.From],
+					Status:        models.TransactionStatusFailed,
+					FailureReason: reason,
+				}).Return(2, nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, fees, nil, nil)
+			result, err := service.TransferFunds(auth.WithPrincipal(context.Background(), auth.System()), tt.req)
+
+			if tt.expectedFailure != "" {
+				assert.EqualError(t, err, tt.expectedFailure)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+				assert.Nil(t, result)
+				return
 			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
 		})
 	}
 }
+
+func TestTransactionService_TransferFunds_Conversion(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fees := TransferFees{Rules: map[string]FeeRule{
+		"USD": {Schedule: fee.Schedule{Kind: fee.KindPercentage, Flat: money.MustParse("0.30"), RateBP: 100}, Wallet: feeAddr},
+	}}
+	rates, err := fx.NewStaticProvider(fx.Table{
+		Base:     "USD",
+		Rates:    map[string]money.Rate{"EUR": money.MustParseRate("0.92"), "JPY": money.MustParseRate("150")},
+		SpreadBP: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := quote.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	quotedRate := &fx.Rate{From: "USD", To: "EUR", Mid: money.MustParseRate("0.95"), Applied: money.MustParseRate("0.90"), SpreadBP: 50}
+
+	tests := []struct {
+		name       string
+		req        models.CreateTransactionRequest
+		terms      *quote.Terms
+		currencies map[string]string
+		// expectedBalances — балансы кошельков после применения проводок перевода.
+		expectedBalances   map[string]money.Amount
+		expectedFee        money.Amount
+		expectedConversion *models.TransactionConversion
+		expectedErr        error
+	}{
+		{
+			name:             "converted at current rate",
+			req:              models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Convert: true},
+			currencies:       map[string]string{addr1: "USD", addr2: "EUR", feeAddr: "USD"},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("89.09"), addr2: money.MustParse("109.61"), feeAddr: money.MustParse("100.41")},
+			expectedFee:      money.MustParse("0.41"),
+			expectedConversion: &models.TransactionConversion{
+				TransactionID:  1,
+				DebitCurrency:  "USD",
+				DebitAmount:    money.MustParse("10.50"),
+				CreditCurrency: "EUR",
+				CreditAmount:   money.MustParse("9.61"),
+				MidRate:        money.MustParseRate("0.92"),
+				Rate:           money.MustParseRate("0.9154"),
+				SpreadBP:       50,
+			},
+		},
+		{
+			name:             "credit truncated to currency precision",
+			req:              models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Convert: true},
+			currencies:       map[string]string{addr1: "USD", addr2: "JPY", feeAddr: "USD"},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("89.09"), addr2: money.FromInt(1667), feeAddr: money.MustParse("100.41")},
+			expectedFee:      money.MustParse("0.41"),
+			expectedConversion: &models.TransactionConversion{
+				TransactionID:  1,
+				DebitCurrency:  "USD",
+				DebitAmount:    money.MustParse("10.50"),
+				CreditCurrency: "JPY",
+				CreditAmount:   money.FromInt(1567),
+				MidRate:        money.MustParseRate("150"),
+				Rate:           money.MustParseRate("149.25"),
+				SpreadBP:       50,
+			},
+		},
+		{
+			name:             "quoted rate applied",
+			req:              models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Convert: true},
+			terms:            &quote.Terms{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Fee: money.MustParse("0.40"), Rate: quotedRate, ExpiresAt: now.Add(30 * time.Second), Nonce: "q1"},
+			currencies:       map[string]string{addr1: "USD", addr2: "EUR", feeAddr: "USD"},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("89.10"), addr2: money.MustParse("109.45"), feeAddr: money.MustParse("100.40")},
+			expectedFee:      money.MustParse("0.40"),
+			expectedConversion: &models.TransactionConversion{
+				TransactionID:  1,
+				DebitCurrency:  "USD",
+				DebitAmount:    money.MustParse("10.50"),
+				CreditCurrency: "EUR",
+				CreditAmount:   money.MustParse("9.45"),
+				MidRate:        money.MustParseRate("0.95"),
+				Rate:           money.MustParseRate("0.90"),
+				SpreadBP:       50,
+			},
+		},
+		{
+			name:        "quoted conversion without convert flag",
+			req:         models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50")},
+			terms:       &quote.Terms{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Rate: quotedRate, ExpiresAt: now.Add(30 * time.Second)},
+			expectedErr: domain.ErrQuoteMismatch,
+		},
+		{
+			name:        "quoted rate for another currency pair",
+			req:         models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Convert: true},
+			terms:       &quote.Terms{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Rate: quotedRate, ExpiresAt: now.Add(30 * time.Second)},
+			currencies:  map[string]string{addr1: "USD", addr2: "JPY"},
+			expectedErr: domain.ErrQuoteMismatch,
+		},
+		{
+			name:        "no rate for currency pair",
+			req:         models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Convert: true},
+			currencies:  map[string]string{addr1: "USD", addr2: "GBP", feeAddr: "USD"},
+			expectedErr: domain.ErrConversionUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+
+			if tt.terms != nil {
+				id, err := signer.Sign(*tt.terms)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tt.req.QuoteID = id
+			}
+			if tt.currencies != nil {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+				})
+			}
+			readWallets(walletRepo, nil)
+			balances := make(map[string]money.Amount, len(tt.currencies))
+			for address, currency := range tt.currencies {
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), address).Return(&models.Wallet{Address: address, Currency: currency, Balance: money.FromInt(100)}, nil)
+				balances[address] = money.FromInt(100)
+			}
+			var posted *models.JournalEntry
+			if tt.expectedConversion != nil {
+				ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) error {
+					posted = entry
+					return nil
+				})
+				txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+					From:     addr1,
+					To:       addr2,
+					Amount:   tt.req.Amount,
+					Currency: "USD",
+					Status:   models.TransactionStatusCompleted,
+				}).Return(1, nil)
+				txRepo.EXPECT().CreateFee(gomock.Any(), models.TransactionFee{TransactionID: 1, Wallet: feeAddr, Amount: tt.expectedFee}).Return(nil)
+				txRepo.EXPECT().CreateConversion(gomock.Any(), *tt.expectedConversion).Return(nil)
+				if tt.terms != nil {
+					txRepo.EXPECT().CreateQuote(gomock.Any(), models.TransactionQuote{TransactionID: 1, Nonce: tt.terms.Nonce}).Return(nil)
+				}
+			} else if tt.currencies != nil {
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(2, nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, fees, signer, rates)
+			service.now = func() time.Time { return now }
+			result, err := service.TransferFunds(auth.WithPrincipal(context.Background(), auth.System()), tt.req)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &models.TransferResult{
+				TransactionID: 1,
+				Currency:      "USD",
+				Amount:        tt.req.Amount,
+				Fee:           tt.expectedFee,
+				Total:         tt.req.Amount + tt.expectedFee,
+				Conversion: &models.Conversion{
+					Currency: tt.expectedConversion.CreditCurrency,
+					Amount:   tt.expectedConversion.CreditAmount,
+					Rate:     tt.expectedConversion.Rate,
+					SpreadBP: tt.expectedConversion.SpreadBP,
+				},
+			}, result)
+			assert.Equal(t, tt.expectedBalances, applyEntry(balances, posted))
+			assert.Contains(t, posted.Postings, models.Posting{Account: models.LedgerAccountExchange, Currency: "USD", Amount: tt.req.Amount})
+			assert.Contains(t, posted.Postings, models.Posting{
+				Account:  models.LedgerAccountExchange,
+				Currency: tt.expectedConversion.CreditCurrency,
+				Amount:   -tt.expectedConversion.CreditAmount,
+			})
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_QuotedRateSingleUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer := quote.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	quoteID, err := signer.Sign(quote.Terms{
+		From:      addr1,
+		To:        addr2,
+		Amount:    money.MustParse("10.50"),
+		Rate:      &fx.Rate{From: "USD", To: "EUR", Mid: money.MustParseRate("0.95"), Applied: money.MustParseRate("0.90"), SpreadBP: 50},
+		ExpiresAt: now.Add(30 * time.Second),
+	})
+	require.NoError(t, err)
+
+	walletRepo := repository_mocks.NewMockWallet(ctrl)
+	tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+	txRepo := repository_mocks.NewMockTransaction(ctrl)
+	ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+	uow := repository_mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+		return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+	}).Times(2)
+	tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+	walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil).Times(2)
+	walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "EUR", Balance: money.FromInt(100)}, nil).Times(2)
+	ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	txRepo.EXPECT().CreateConversion(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	var transactions []models.Transaction
+	txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, transaction models.Transaction) (int, error) {
+		transactions = append(transactions, transaction)
+		return len(transactions), nil
+	}).Times(3)
+	// Хранилище использованных расчетов, как первичный ключ transaction_quotes.
+	used := make(map[string]int)
+	txRepo.EXPECT().CreateQuote(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, q models.TransactionQuote) error {
+		if _, ok := used[q.Nonce]; ok {
+			return domain.ErrQuoteUsed
+		}
+		used[q.Nonce] = q.TransactionID
+		return nil
+	}).Times(2)
+
+	service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, signer, nil)
+	service.now = func() time.Time { return now }
+	ctx := auth.WithPrincipal(context.Background(), auth.System())
+	req := models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.MustParse("10.50"), Convert: true, QuoteID: quoteID}
+
+	first, err := service.TransferFunds(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, &models.Conversion{Currency: "EUR", Amount: money.MustParse("9.45"), Rate: money.MustParseRate("0.90"), SpreadBP: 50}, first.Conversion)
+
+	second, err := service.TransferFunds(ctx, req)
+	assert.ErrorIs(t, err, domain.ErrQuoteUsed)
+	assert.Nil(t, second)
+	assert.Len(t, used, 1)
+	if assert.Len(t, transactions, 3) {
+		assert.Equal(t, models.TransactionStatusFailed, transactions[2].Status)
+		assert.Equal(t, "quote has already been used", transactions[2].FailureReason)
+	}
+}
+
+func TestTransactionService_QuoteTransfer_Conversion(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rates, err := fx.NewStaticProvider(fx.Table{Base: "USD", Rates: map[string]money.Rate{"EUR": money.MustParseRate("0.92")}, SpreadBP: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	walletRepo := repository_mocks.NewMockWallet(ctrl)
+	tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+	uow := repository_mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+		return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Hold: noHolds(ctrl)})
+	})
+	tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+	walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+	walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "EUR", Balance: money.FromInt(50)}, nil)
+
+	signer := quote.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	service := NewTransactionService(&repository.Repository{UnitOfWork: uow}, TransferLimits{}, TransferFees{}, signer, rates)
+	service.now = func() time.Time { return now }
+	req := models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10), Convert: true}
+	result, err := service.QuoteTransfer(auth.WithPrincipal(context.Background(), auth.System()), req)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	recipientBalance := money.MustParse("59.15")
+	assert.Equal(t, &recipientBalance, result.RecipientBalanceAfter)
+	assert.Equal(t, &models.Conversion{Currency: "EUR", Amount: money.MustParse("9.15"), Rate: money.MustParseRate("0.9154"), SpreadBP: 50}, result.Conversion)
+	terms, err := signer.Verify(result.QuoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, &fx.Rate{From: "USD", To: "EUR", Mid: money.MustParseRate("0.92"), Applied: money.MustParseRate("0.9154"), SpreadBP: 50}, terms.Rate)
+}
+
+func TestTransactionService_QuoteTransfer(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fees := TransferFees{Rules: map[string]FeeRule{
+		"USD": {Schedule: fee.Schedule{Kind: fee.KindPercentage, Flat: money.MustParse("0.30"), RateBP: 100}, Wallet: feeAddr},
+	}}
+	recipientBalance := money.FromInt(60)
+
+	tests := []struct {
+		name          string
+		principal     *auth.Principal
+		balance       money.Amount
+		expectedQuote *models.TransferQuote
+		expectedErr   error
+	}{
+		{
+			name:      "owner sees only sender balance",
+			principal: &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}},
+			balance:   money.FromInt(100),
+			expectedQuote: &models.TransferQuote{
+				From:               addr1,
+				To:                 addr2,
+				Currency:           "USD",
+				Amount:             money.FromInt(10),
+				Fee:                money.MustParse("0.40"),
+				Total:              money.MustParse("10.40"),
+				SenderBalanceAfter: money.MustParse("89.60"),
+				ExpiresAt:          now.Add(time.Minute),
+			},
+		},
+		{
+			name:      "admin sees recipient balance",
+			principal: auth.System(),
+			balance:   money.FromInt(100),
+			expectedQuote: &models.TransferQuote{
+				From:                  addr1,
+				To:                    addr2,
+				Currency:              "USD",
+				Amount:                money.FromInt(10),
+				Fee:                   money.MustParse("0.40"),
+				Total:                 money.MustParse("10.40"),
+				SenderBalanceAfter:    money.MustParse("89.60"),
+				RecipientBalanceAfter: &recipientBalance,
+				ExpiresAt:             now.Add(time.Minute),
+			},
+		},
+		{
+			name:        "insufficient funds for fee",
+			principal:   auth.System(),
+			balance:     money.MustParse("10.39"),
+			expectedErr: domain.ErrInsufficientFunds,
+		},
+		{
+			name:        "wallet not owned",
+			principal:   &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr2}},
+			expectedErr: domain.ErrWalletNotOwned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+			if !errors.Is(tt.expectedErr, domain.ErrWalletNotOwned) {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Hold: noHolds(ctrl)})
+				})
+				readWallets(walletRepo, nil)
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: tt.balance}, nil)
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD", Balance: money.FromInt(50)}, nil)
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), feeAddr).Return(&models.Wallet{Address: feeAddr, Currency: "USD"}, nil)
+			}
+
+			signer := quote.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, fees, signer, nil)
+			service.now = func() time.Time { return now }
+			result, err := service.QuoteTransfer(auth.WithPrincipal(context.Background(), tt.principal), models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10)})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			terms, err := signer.Verify(result.QuoteID)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, terms.Nonce)
+			terms.Nonce = ""
+			assert.Equal(t, quote.Terms{From: addr1, To: addr2, Amount: money.FromInt(10), Fee: money.MustParse("0.40"), ExpiresAt: now.Add(time.Minute)}, terms)
+			result.QuoteID = ""
+			assert.Equal(t, tt.expectedQuote, result)
+		})
+	}
+}
+
+func TestTransactionService_QuoteTransfer_InvalidAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Расчет на адрес с опечаткой отклоняется до обращения к кошелькам и не подписывается.
+	mistyped := addr2[:63] + "7"
+	walletRepo := repository_mocks.NewMockWallet(ctrl)
+	walletRepo.EXPECT().Get(gomock.Any(), mistyped).Return(nil, domain.ErrWalletNotFound)
+	uow := repository_mocks.NewMockUnitOfWork(ctrl)
+
+	signer := quote.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	service := NewTransactionService(&repository.Repository{Wallet: walletRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, signer, nil)
+	result, err := service.QuoteTransfer(auth.WithPrincipal(context.Background(), auth.System()), models.CreateTransactionRequest{From: addr1, To: mistyped, Amount: money.FromInt(10)})
+
+	assert.Equal(t, domain.NewValidationError("to", "invalid wallet address: checksum mismatch"), err)
+	assert.Nil(t, result)
+}
+
+func TestTransactionService_TransferFunds_Quote(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fees := TransferFees{Rules: map[string]FeeRule{
+		"USD": {Schedule: fee.Schedule{Kind: fee.KindFlat, Flat: money.MustParse("0.40")}, Wallet: feeAddr},
+	}}
+	signer := quote.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	sign := func(terms quote.Terms) string {
+		id, err := signer.Sign(terms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	// Тариф изменился после выдачи расчета: перевод должен пройти с комиссией из расчета.
+	valid := quote.Terms{From: addr1, To: addr2, Amount: money.FromInt(10), Fee: money.MustParse("0.25"), ExpiresAt: now.Add(30 * time.Second), Nonce: "q1"}
+	expired := valid
+	expired.ExpiresAt = now
+
+	tests := []struct {
+		name        string
+		quoteID     string
+		amount      money.Amount
+		quoteErr    error
+		expectedErr error
+	}{
+		{name: "quoted fee applied", quoteID: sign(valid), amount: money.FromInt(10)},
+		{name: "already used", quoteID: sign(valid), amount: money.FromInt(10), quoteErr: domain.ErrQuoteUsed, expectedErr: domain.ErrQuoteUsed},
+		{name: "expired", quoteID: sign(expired), amount: money.FromInt(10), expectedErr: domain.ErrQuoteExpired},
+		{name: "other amount", quoteID: sign(valid), amount: money.FromInt(11), expectedErr: domain.ErrQuoteMismatch},
+		{name: "forged", quoteID: "forged.quote", amount: money.FromInt(10), expectedErr: domain.ErrInvalidQuote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			tierRepo := repository_mocks.NewMockWalletTier(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			if tt.expectedErr == nil || tt.quoteErr != nil {
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{Wallet: walletRepo, WalletTier: tierRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: noHolds(ctrl)})
+				})
+				tierRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&models.WalletTier{MinTransfer: money.MustParse("0.01")}, nil).AnyTimes()
+				readWallets(walletRepo, nil)
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr1).Return(&models.Wallet{Address: addr1, Currency: "USD", Balance: money.FromInt(100)}, nil)
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), addr2).Return(&models.Wallet{Address: addr2, Currency: "USD", Balance: money.FromInt(50)}, nil)
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), feeAddr).Return(&models.Wallet{Address: feeAddr, Currency: "USD"}, nil)
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(3, nil)
+				ledgerRepo.EXPECT().Post(gomock.Any(), &models.JournalEntry{
+					Kind:          models.JournalEntryKindTransfer,
+					TransactionID: 3,
+					Postings: []models.Posting{
+						{Wallet: addr1, Currency: "USD", Amount: -money.FromInt(10)},
+						{Wallet: addr2, Currency: "USD", Amount: money.FromInt(10)},
+						{Wallet: addr1, Currency: "USD", Amount: -money.MustParse("0.25")},
+						{Wallet: feeAddr, Currency: "USD", Amount: money.MustParse("0.25")},
+					},
+				}).Return(nil)
+				txRepo.EXPECT().CreateFee(gomock.Any(), models.TransactionFee{TransactionID: 3, Wallet: feeAddr, Amount: money.MustParse("0.25")}).Return(nil)
+				txRepo.EXPECT().CreateQuote(gomock.Any(), models.TransactionQuote{TransactionID: 3, Nonce: "q1"}).Return(tt.quoteErr)
+			}
+			if tt.quoteErr != nil {
+				// Транзакция БД с переводом откатывается, а отказ записывается в историю.
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(4, nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, fees, signer, nil)
+			service.now = func() time.Time { return now }
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr1}})
+			result, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: addr1, To: addr2, Amount: tt.amount, QuoteID: tt.quoteID})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &models.TransferResult{TransactionID: 3, Currency: "USD", Amount: money.FromInt(10), Fee: money.MustParse("0.25"), Total: money.MustParse("10.25")}, result)
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_Authorization(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   *auth.Principal
+		expectedErr error
+	}{
+		{
+			name:        "unauthenticated",
+			principal:   nil,
+			expectedErr: domain.ErrUnauthenticated,
+		},
+		{
+			name:        "sender wallet not owned",
+			principal:   &auth.Principal{KeyID: 1, Role: auth.RoleCustomer, Wallets: []string{addr2}},
+			expectedErr: domain.NewWalletError(models.TransactionRoleSender, addr1, domain.ErrWalletNotOwned),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Ни транзакция БД, ни запись о неудачном переводе не ожидаются.
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, tt.principal)
+			}
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			_, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: addr1, To: addr2, Amount: money.FromInt(10)})
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "domain error", err: domain.ErrInsufficientFunds, expected: "insufficient funds"},
+		{name: "wrapped domain error", err: fmt.Errorf("%w: EUR", domain.ErrFeeUnavailable), expected: "transfer fee is not configured for the sender wallet currency"},
+		{name: "wallet error", err: domain.NewWalletError(models.TransactionRoleRecipient, addr2, domain.ErrWalletFrozen), expected: "recipient wallet is frozen"},
+		{name: "limit error", err: domain.NewLimitError("transfers_per_minute", time.Minute), expected: "limit exceeded: transfers_per_minute"},
+		{name: "internal error", err: errors.New(`pq: relation "wallets" does not exist`), expected: "internal error"},
+		{name: "wrapped internal error", err: fmt.Errorf("failed to create transaction: %w", errors.New("connection refused")), expected: "internal error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, failureReason(tt.err))
+		})
+	}
+}
+
+func TestTransactionService_TransferFunds_InvalidAddress(t *testing.T) {
+	// legacy — устаревший адрес существующего кошелька, который начинается с байта текущей версии.
+	legacy := "01" + strings.Repeat("e240d825", 7) + "e240d8"
+	// legacyVersion — устаревший адрес существующего кошелька с первым байтом, отличным от текущей версии.
+	legacyVersion := strings.Repeat("e240d825", 8)
+
+	tests := []struct {
+		name        string
+		from        string
+		to          string
+		mock        func(w *repository_mocks.MockWallet, r *repository_mocks.MockTransaction, u *repository_mocks.MockUnitOfWork)
+		expectedErr error
+	}{
+		{
+			name: "sender",
+			from: "addr1",
+			to:   addr2,
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockTransaction, u *repository_mocks.MockUnitOfWork) {
+			},
+			expectedErr: domain.NewValidationError("from", "invalid wallet address: must be 64 characters long"),
+		},
+		{
+			name: "recipient checksum",
+			from: addr1,
+			to:   addr2[:63] + "7",
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockTransaction, u *repository_mocks.MockUnitOfWork) {
+				w.EXPECT().Get(gomock.Any(), addr2[:63]+"7").Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedErr: domain.NewValidationError("to", "invalid wallet address: checksum mismatch"),
+		},
+		{
+			name: "recipient version",
+			from: addr1,
+			to:   "02" + addr2[2:],
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockTransaction, u *repository_mocks.MockUnitOfWork) {
+				w.EXPECT().Get(gomock.Any(), "02"+addr2[2:]).Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedErr: domain.NewValidationError("to", "invalid wallet address: unknown version 2"),
+		},
+		{
+			name: "existing legacy sender",
+			from: legacyVersion,
+			to:   addr2,
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockTransaction, u *repository_mocks.MockUnitOfWork) {
+				w.EXPECT().Get(gomock.Any(), legacyVersion).Return(&models.Wallet{Address: legacyVersion}, nil)
+				u.EXPECT().WithTx(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+				r.EXPECT().Create(gomock.Any(), gomock.Any()).Return(0, nil)
+			},
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "existing legacy recipient",
+			from: addr1,
+			to:   legacy,
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockTransaction, u *repository_mocks.MockUnitOfWork) {
+				w.EXPECT().Get(gomock.Any(), legacy).Return(&models.Wallet{Address: legacy}, nil)
+				u.EXPECT().WithTx(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+				r.EXPECT().Create(gomock.Any(), gomock.Any()).Return(0, nil)
+			},
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "lookup error",
+			from: legacy,
+			to:   addr2,
+			mock: func(w *repository_mocks.MockWallet, r *repository_mocks.MockTransaction, u *repository_mocks.MockUnitOfWork) {
+				w.EXPECT().Get(gomock.Any(), legacy).Return(nil, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Неверный адрес отклоняется до перевода, поэтому неудачный перевод в историю не записывается.
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			tt.mock(walletRepo, txRepo, uow)
+
+			ctx := auth.WithPrincipal(context.Background(), auth.System())
+			service := NewTransactionService(&repository.Repository{Wallet: walletRepo, Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			_, err := service.TransferFunds(ctx, models.CreateTransactionRequest{From: tt.from, To: tt.to, Amount: money.FromInt(10)})
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestTransactionService_GetLastTransactions(t *testing.T) {
+	tests := []struct {
+		name           string
+		count          int
+		mockBehavior   func(r *repository_mocks.MockTransaction, count int)
+		expectedResult []models.Transaction
+		wantErr        bool
+	}{
+		{
+			name:  "successful get last transactions",
+			count: 2,
+			mockBehavior: func(r *repository_mocks.MockTransaction, count int) {
+				r.EXPECT().Getlast(gomock.Any(), count).Return([]models.Transaction{
+					{From: addr1, To: addr2, Amount: money.MustParse("10.50")},
+					{From: addr2, To: addr1, Amount: money.MustParse("5.00")},
+				}, nil)
+			},
+			expectedResult: []models.Transaction{
+				{From: addr1, To: addr2, Amount: money.MustParse("10.50")},
+				{From: addr2, To: addr1, Amount: money.MustParse("5.00")},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "repository error",
+			count: 2,
+			mockBehavior: func(r *repository_mocks.MockTransaction, count int) {
+				r.EXPECT().Getlast(gomock.Any(), count).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:  "empty result",
+			count: 2,
+			mockBehavior: func(r *repository_mocks.MockTransaction, count int) {
+				r.EXPECT().Getlast(gomock.Any(), count).Return([]models.Transaction{}, nil)
+			},
+			expectedResult: []models.Transaction{},
+			wantErr:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			tt.mockBehavior(txRepo, tt.count)
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo}, TransferLimits{}, TransferFees{}, nil, nil)
+			result, err := service.GetLastTransactions(context.Background(), tt.count)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestTransactionService_ListTransactions(t *testing.T) {
+	page := func(ids ...int) []models.Transaction {
+		transactions := make([]models.Transaction, 0, len(ids))
+		for _, id := range ids {
+			transactions = append(transactions, models.Transaction{ID: id, From: addr1, To: addr2, Amount: money.FromInt(1)})
+		}
+		return transactions
+	}
+
+	tests := []struct {
+		name           string
+		filter         models.TransactionFilter
+		cursor         string
+		mockBehavior   func(r *repository_mocks.MockTransaction)
+		expectedResult *models.TransactionPage
+		expectedErr    error
+	}{
+		{
+			name:   "last page",
+			filter: models.TransactionFilter{Wallet: addr1, Limit: 3},
+			mockBehavior: func(r *repository_mocks.MockTransaction) {
+				r.EXPECT().List(gomock.Any(), models.TransactionFilter{Wallet: addr1, Limit: 4}).Return(page(5, 4), nil)
+			},
+			expectedResult: &models.TransactionPage{Transactions: page(5, 4)},
+		},
+		{
+			name:   "has next page",
+			filter: models.TransactionFilter{Limit: 2},
+			mockBehavior: func(r *repository_mocks.MockTransaction) {
+				r.EXPECT().List(gomock.Any(), models.TransactionFilter{Limit: 3}).Return(page(9, 8, 7), nil)
+			},
+			expectedResult: &models.TransactionPage{Transactions: page(9, 8), NextCursor: encodeCursor(8)},
+		},
+		{
+			name:   "cursor and default limit",
+			cursor: encodeCursor(8),
+			mockBehavior: func(r *repository_mocks.MockTransaction) {
+				r.EXPECT().List(gomock.Any(), models.TransactionFilter{BeforeID: 8, Limit: DefaultPageSize + 1}).Return(page(7), nil)
+			},
+			expectedResult: &models.TransactionPage{Transactions: page(7)},
+		},
+		{
+			name:   "limit is clamped",
+			filter: models.TransactionFilter{Limit: 1000},
+			mockBehavior: func(r *repository_mocks.MockTransaction) {
+				r.EXPECT().List(gomock.Any(), models.TransactionFilter{Limit: MaxPageSize + 1}).Return(page(), nil)
+			},
+			expectedResult: &models.TransactionPage{Transactions: page()},
+		},
+		{
+			name:         "invalid cursor",
+			cursor:       "not-a-cursor",
+			mockBehavior: func(r *repository_mocks.MockTransaction) {},
+			expectedErr:  domain.ErrInvalidCursor,
+		},
+		{
+			name: "repository error",
+			mockBehavior: func(r *repository_mocks.MockTransaction) {
+				r.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			tt.mockBehavior(txRepo)
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo}, TransferLimits{}, TransferFees{}, nil, nil)
+			result, err := service.ListTransactions(context.Background(), tt.filter, tt.cursor)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestTransactionService_ReverseTransaction(t *testing.T) {
+	completed := func(from string, to string, amount string) *models.Transaction {
+		return &models.Transaction{ID: 7, From: from, To: to, Amount: money.MustParse(amount), Currency: "USD", Status: models.TransactionStatusCompleted}
+	}
+	partial := money.MustParse("5.00")
+	tooPrecise := money.MustParse("0.50")
+	reversalOf := 3
+	conversion := &models.TransactionConversion{
+		TransactionID:  7,
+		DebitCurrency:  "USD",
+		DebitAmount:    money.MustParse("10.50"),
+		CreditCurrency: "EUR",
+		CreditAmount:   money.MustParse("9.61"),
+		MidRate:        money.MustParseRate("0.92"),
+		Rate:           money.MustParseRate("0.9154"),
+		SpreadBP:       50,
+	}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		original  *models.Transaction
+		amount    *money.Amount
+		// currencies — валюты заблокированных кошельков; nil, если до блокировки кошельков дело не доходит.
+		currencies map[string]string
+		// recipientBalance — баланс получателя исходного перевода; по умолчанию 100.
+		recipientBalance money.Amount
+		// recipientHeld — средства получателя, заблокированные его активными блокировками.
+		recipientHeld money.Amount
+		expected      *models.TransactionReversal
+		// expectedBalances — балансы кошельков после применения проводок отмены.
+		expectedBalances map[string]money.Amount
+		expectedErr      error
+		// expectedField — поле ошибки проверки domain.ValidationError.
+		expectedField string
+	}{
+		{
+			name:       "full reversal",
+			original:   completed(addr1, addr2, "10.50"),
+			currencies: map[string]string{addr1: "USD", addr2: "USD"},
+			expected: &models.TransactionReversal{TransactionID: 8, OriginalID: 7, From: addr2, To: addr1,
+				Currency: "USD", Amount: money.MustParse("10.50"), RefundCurrency: "USD", Refund: money.MustParse("10.50")},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("110.50"), addr2: money.MustParse("89.50")},
+		},
+		{
+			name:       "partial reversal",
+			original:   completed(addr1, addr2, "10.50"),
+			amount:     &partial,
+			currencies: map[string]string{addr1: "USD", addr2: "USD"},
+			expected: &models.TransactionReversal{TransactionID: 8, OriginalID: 7, From: addr2, To: addr1,
+				Currency: "USD", Amount: partial, RefundCurrency: "USD", Refund: partial},
+			expectedBalances: map[string]money.Amount{addr1: money.FromInt(105), addr2: money.FromInt(95)},
+		},
+		{
+			name:       "full reversal of conversion",
+			original:   completed(addr1, addr2, "10.50"),
+			currencies: map[string]string{addr1: "USD", addr2: "EUR"},
+			expected: &models.TransactionReversal{TransactionID: 8, OriginalID: 7, From: addr2, To: addr1,
+				Currency: "EUR", Amount: money.MustParse("9.61"), RefundCurrency: "USD", Refund: money.MustParse("10.50")},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("110.50"), addr2: money.MustParse("90.39")},
+		},
+		{
+			name:       "partial reversal of conversion",
+			original:   completed(addr1, addr2, "10.50"),
+			amount:     &partial,
+			currencies: map[string]string{addr1: "USD", addr2: "EUR"},
+			expected: &models.TransactionReversal{TransactionID: 8, OriginalID: 7, From: addr2, To: addr1,
+				Currency: "EUR", Amount: money.MustParse("4.57"), RefundCurrency: "USD", Refund: partial},
+			expectedBalances: map[string]money.Amount{addr1: money.FromInt(105), addr2: money.MustParse("95.43")},
+		},
+		{
+			name:             "recipient spent the funds",
+			original:         completed(addr1, addr2, "10.50"),
+			currencies:       map[string]string{addr1: "USD", addr2: "USD"},
+			recipientBalance: money.MustParse("10.49"),
+			expectedErr:      domain.ErrInsufficientFunds,
+		},
+		{
+			name:          "recipient funds on hold",
+			original:      completed(addr1, addr2, "10.50"),
+			currencies:    map[string]string{addr1: "USD", addr2: "USD"},
+			recipientHeld: money.MustParse("89.51"),
+			expectedErr:   domain.ErrInsufficientFunds,
+		},
+		{
+			name:          "recipient funds partly on hold",
+			original:      completed(addr1, addr2, "10.50"),
+			currencies:    map[string]string{addr1: "USD", addr2: "USD"},
+			recipientHeld: money.MustParse("89.50"),
+			expected: &models.TransactionReversal{TransactionID: 8, OriginalID: 7, From: addr2, To: addr1,
+				Currency: "USD", Amount: money.MustParse("10.50"), RefundCurrency: "USD", Refund: money.MustParse("10.50")},
+			expectedBalances: map[string]money.Amount{addr1: money.MustParse("110.50"), addr2: money.MustParse("89.50")},
+		},
+		{
+			name:        "amount too precise",
+			original:    completed(addr1, addr2, "10.50"),
+			amount:      &tooPrecise,
+			currencies:  map[string]string{addr1: "JPY", addr2: "JPY"},
+			expectedErr: domain.ErrInvalidAmountPrecision,
+		},
+		{
+			name:          "amount exceeds transfer",
+			original:      completed(addr1, addr2, "4.00"),
+			amount:        &partial,
+			expectedField: "amount",
+		},
+		{
+			name:        "already reversed",
+			original:    &models.Transaction{ID: 7, From: addr1, To: addr2, Amount: money.MustParse("10.50"), Status: models.TransactionStatusReversed},
+			expectedErr: domain.ErrTransactionAlreadyReversed,
+		},
+		{
+			name:        "failed transfer",
+			original:    &models.Transaction{ID: 7, From: addr1, To: addr2, Amount: money.MustParse("10.50"), Status: models.TransactionStatusFailed},
+			expectedErr: domain.ErrTransactionNotReversible,
+		},
+		{
+			name: "reversal of reversal",
+			original: &models.Transaction{ID: 7, From: addr2, To: addr1, Amount: money.MustParse("10.50"),
+				Status: models.TransactionStatusCompleted, ReversalOf: &reversalOf},
+			expectedErr: domain.ErrTransactionNotReversible,
+		},
+		{
+			name:        "transaction not found",
+			expectedErr: domain.ErrTransactionNotFound,
+		},
+		{
+			name:        "not an administrator",
+			principal:   &auth.Principal{Name: "support", Role: auth.RoleOperator},
+			expectedErr: domain.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			txRepo := repository_mocks.NewMockTransaction(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			holdRepo := repository_mocks.NewMockHold(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			holdRepo.EXPECT().Held(gomock.Any(), addr2, gomock.Any()).Return(tt.recipientHeld, nil).AnyTimes()
+
+			principal := tt.principal
+			if principal == nil {
+				principal = auth.System()
+				uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+					return fn(&repository.Repository{Wallet: walletRepo, Transaction: txRepo, Ledger: ledgerRepo, Hold: holdRepo})
+				})
+				if tt.original != nil {
+					txRepo.EXPECT().GetForUpdate(gomock.Any(), 7).Return(tt.original, nil)
+				} else {
+					txRepo.EXPECT().GetForUpdate(gomock.Any(), 7).Return(nil, domain.ErrTransactionNotFound)
+				}
+			}
+			balances := make(map[string]money.Amount, len(tt.currencies))
+			for address, currency := range tt.currencies {
+				balance := money.FromInt(100)
+				if address == addr2 && tt.recipientBalance != 0 {
+					balance = tt.recipientBalance
+				}
+				walletRepo.EXPECT().GetForUpdate(gomock.Any(), address).Return(&models.Wallet{Address: address, Currency: currency, Balance: balance}, nil)
+				balances[address] = balance
+			}
+			if tt.currencies[addr1] != tt.currencies[addr2] {
+				txRepo.EXPECT().GetConversion(gomock.Any(), 7).Return(conversion, nil)
+			}
+			var posted *models.JournalEntry
+			if tt.expected != nil {
+				txRepo.EXPECT().Create(gomock.Any(), models.Transaction{
+					From:       tt.expected.From,
+					To:         tt.expected.To,
+					Amount:     tt.expected.Amount,
+					Currency:   tt.expected.Currency,
+					Status:     models.TransactionStatusCompleted,
+					ReversalOf: &tt.original.ID,
+				}).Return(8, nil)
+				ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *models.JournalEntry) error {
+					posted = entry
+					return nil
+				})
+				if tt.currencies[addr1] != tt.currencies[addr2] {
+					txRepo.EXPECT().CreateConversion(gomock.Any(), models.TransactionConversion{
+						TransactionID:  8,
+						DebitCurrency:  "EUR",
+						DebitAmount:    tt.expected.Amount,
+						CreditCurrency: "USD",
+						CreditAmount:   tt.expected.Refund,
+						MidRate:        money.MustParseRate("1.08695652"),
+						Rate:           money.MustParseRate("1.09241861"),
+						SpreadBP:       50,
+					}).Return(nil)
+				}
+				txRepo.EXPECT().UpdateStatus(gomock.Any(), 7, models.TransactionStatusReversed).Return(nil)
+			}
+
+			service := NewTransactionService(&repository.Repository{Transaction: txRepo, UnitOfWork: uow}, TransferLimits{}, TransferFees{}, nil, nil)
+			result, err := service.ReverseTransaction(auth.WithPrincipal(context.Background(), principal), 7, models.ReverseTransactionRequest{Amount: tt.amount})
+
+			if tt.expectedField != "" {
+				var validationErr *domain.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.expectedField, validationErr.Field)
+				return
+			}
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, models.JournalEntryKindReversal, posted.Kind)
+			assert.Equal(t, 8, posted.TransactionID)
+			assert.Equal(t, tt.expectedBalances, applyEntry(balances, posted))
+		})
+	}
+}
+
+// applyEntry возвращает балансы кошельков balances после применения к ним проводок записи журнала entry.
+// Проводки по системным счетам не учитываются.
+func applyEntry(balances map[string]money.Amount, entry *models.JournalEntry) map[string]money.Amount {
+	result := make(map[string]money.Amount, len(balances))
+	for address, balance := range balances {
+		result[address] = balance
+	}
+	if entry == nil {
+		return result
+	}
+	for _, posting := range entry.Postings {
+		if posting.Wallet != "" {
+			result[posting.Wallet] += posting.Amount
+		}
+	}
+	return result
+}
+
+// readWallets разрешает чтение кошельков без блокировки (выбор кошелька комиссий по валюте отправителя):
+// кошелек возвращается в валюте из currencies, а если ее там нет — в USD.
+func readWallets(walletRepo *repository_mocks.MockWallet, currencies map[string]string) {
+	walletRepo.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, address string) (*models.Wallet, error) {
+		currency, ok := currencies[address]
+		if !ok {
+			currency = "USD"
+		}
+		return &models.Wallet{Address: address, Currency: currency}, nil
+	}).AnyTimes()
+}
+
+// noHolds возвращает репозиторий блокировок средств, в котором у кошельков нет заблокированных средств.
+func noHolds(ctrl *gomock.Controller) *repository_mocks.MockHold {
+	holdRepo := repository_mocks.NewMockHold(ctrl)
+	holdRepo.EXPECT().Held(gomock.Any(), gomock.Any(), gomock.Any()).Return(money.Amount(0), nil).AnyTimes()
+	return holdRepo
+}
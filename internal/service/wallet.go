@@ -1,66 +1,223 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
 	"golangTestTask/internal/repository"
-	"golangTestTask/pkg/utils"
+	"golangTestTask/pkg/address"
+	"golangTestTask/pkg/money"
+	"time"
 )
 
 type WalletService struct {
-	repo repository.Wallet
+	repo      repository.Wallet
+	hold_repo repository.Hold
+	uow       repository.UnitOfWork
+	now       func() time.Time
 }
 
 // NewWalletService создает новый экземпляр WalletService.
-func NewWalletService(repo repository.Wallet) *WalletService {
+func NewWalletService(repo *repository.Repository) *WalletService {
 	return &WalletService{
-		repo: repo,
+		repo:      repo.Wallet,
+		hold_repo: repo.Hold,
+		uow:       repo.UnitOfWork,
+		now:       time.Now,
 	}
 }
 
-// CreateWallet создает новый кошелек.
-func (s *WalletService) CreateWallet(wallet models.Wallet) error {
-	if err := s.repo.Create(&wallet); err != nil {
-		return err
+// CreateWallet создает новый кошелек. Если адрес не указан, его генерирует репозиторий при сохранении, а указанный
+// адрес должен быть в текущей версии формата пакета address (устаревшие адреса для новых кошельков не принимаются),
+// иначе возвращается domain.ValidationError. Статус по умолчанию — active, уровень — models.DefaultWalletTier, валюта — money.DefaultCurrency.
+// Неподдерживаемая валюта отклоняется с ошибкой domain.ErrUnsupportedCurrency, а баланс, не записываемый
+// с точностью валюты, — с ошибкой domain.ErrInvalidAmountPrecision.
+// Начальный баланс зачисляется записью журнала со счета models.LedgerAccountEquity в той же транзакции БД,
+// что и создание кошелька. Кошелек, созданный по ключу API или пользователем, передается во владение создателю в ней же.
+func (s *WalletService) CreateWallet(ctx context.Context, wallet models.Wallet) (*models.Wallet, error) {
+	if wallet.Address != "" {
+		if err := address.ValidateCurrent(wallet.Address); err != nil {
+			return nil, domain.NewValidationError("address", err.Error())
+		}
 	}
-	return nil
+	if wallet.Status == "" {
+		wallet.Status = models.WalletStatusActive
+	}
+	if wallet.Tier == "" {
+		wallet.Tier = models.DefaultWalletTier
+	}
+	if wallet.Currency == "" {
+		wallet.Currency = money.DefaultCurrency
+	}
+	currency, ok := money.LookupCurrency(wallet.Currency)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedCurrency, wallet.Currency)
+	}
+	wallet.Currency = currency.Code
+	if !currency.Fits(wallet.Balance) {
+		return nil, domain.ErrInvalidAmountPrecision
+	}
+
+	principal := auth.FromContext(ctx)
+	owned := principal != nil && (principal.KeyID != 0 || principal.UserID != 0)
+	if !owned && wallet.Balance == 0 {
+		if err := s.repo.Create(ctx, &wallet); err != nil {
+			return nil, err
+		}
+		return &wallet, nil
+	}
+
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		// Кошелек создается пустым, а начальный баланс зачисляется записью журнала, как и любое другое движение средств.
+		balance := wallet.Balance
+		wallet.Balance = 0
+		if err := repos.Wallet.Create(ctx, &wallet); err != nil {
+			return err
+		}
+		if balance != 0 {
+			if err := postEntry(ctx, repos.Ledger, openingEntry(&wallet, balance)); err != nil {
+				return err
+			}
+			wallet.Balance = balance
+		}
+		if !owned {
+			return nil
+		}
+		if principal.KeyID != 0 {
+			return repos.APIKey.AddWallet(ctx, principal.KeyID, wallet.Address)
+		}
+		return repos.User.AddWallet(ctx, principal.UserID, wallet.Address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// GetWallet возвращает кошелек по его адресу.
+func (s *WalletService) GetWallet(ctx context.Context, address string) (*models.Wallet, error) {
+	return s.repo.Get(ctx, address)
 }
 
-// GetWalletBalance возвращает баланс кошелька по его адресу
-func (s *WalletService) GetWalletBalance(address string) (float64, error) {
-	wallet, err := s.repo.Get(address)
+// SetWalletStatus переводит кошелек в статус status.
+// Допустимы переходы active <-> frozen и active/frozen -> closed; закрыть можно только кошелек с нулевым балансом.
+func (s *WalletService) SetWalletStatus(ctx context.Context, address string, status models.WalletStatus) (*models.Wallet, error) {
+	if !status.Valid() {
+		return nil, domain.ErrInvalidWalletStatus
+	}
+
+	var wallet *models.Wallet
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		var err error
+		wallet, err = repos.Wallet.GetForUpdate(ctx, address)
+		if err != nil {
+			return err
+		}
+		if wallet.Status == status {
+			return nil
+		}
+		if wallet.Status == models.WalletStatusClosed {
+			return domain.ErrWalletClosed
+		}
+		if status == models.WalletStatusClosed && wallet.Balance != 0 {
+			return domain.ErrWalletNotEmpty
+		}
+		if err := repos.Wallet.UpdateStatus(ctx, address, status); err != nil {
+			return err
+		}
+		wallet.Status = status
+		return nil
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return wallet.Balance, nil
+	return wallet, nil
+}
+
+// SetWalletTier присваивает кошельку уровень tier. Уровень закрытого кошелька изменить нельзя.
+func (s *WalletService) SetWalletTier(ctx context.Context, address string, tier string) (*models.Wallet, error) {
+	var wallet *models.Wallet
+	err := s.uow.WithTx(ctx, func(repos *repository.Repository) error {
+		var err error
+		wallet, err = repos.Wallet.GetForUpdate(ctx, address)
+		if err != nil {
+			return err
+		}
+		if wallet.Tier == tier {
+			return nil
+		}
+		if wallet.Status == models.WalletStatusClosed {
+			return domain.ErrWalletClosed
+		}
+		if err := repos.Wallet.UpdateTier(ctx, address, tier); err != nil {
+			return err
+		}
+		wallet.Tier = tier
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// GetWalletBalance возвращает баланс кошелька по его адресу: общий баланс, сумму активных блокировок средств
+// и доступные для переводов средства.
+func (s *WalletService) GetWalletBalance(ctx context.Context, address string) (*models.WalletBalance, error) {
+	wallet, err := s.repo.Get(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	held, err := s.hold_repo.Held(ctx, address, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &models.WalletBalance{
+		Address:   wallet.Address,
+		Balance:   wallet.Balance,
+		Held:      held,
+		Available: wallet.Balance - held,
+		Currency:  wallet.Currency,
+	}, nil
 }
 
 // GetAllWallets возвращает все кошельки в базе данных
-func (s *WalletService) GetAllWallets() ([]models.Wallet, error) {
+func (s *WalletService) GetAllWallets(ctx context.Context) ([]models.Wallet, error) {
 	var wallets []models.Wallet
-	wallets, err := s.repo.GetAll()
+	wallets, err := s.repo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return wallets, nil
 }
 
-// CreateRandomWallets создает count кошельков со случайными адресами и balance у.е. на них.
-func (s *WalletService) CreateRandomWallets(count int, balance float64) error {
+// GetWalletStats возвращает количество кошельков и сумму их балансов по статусам.
+func (s *WalletService) GetWalletStats(ctx context.Context) ([]models.WalletStats, error) {
+	return s.repo.Stats(ctx)
+}
+
+// CreateRandomWallets создает count кошельков в валюте currency со случайными адресами и balance у.е. на них.
+func (s *WalletService) CreateRandomWallets(ctx context.Context, count int, balance money.Amount, currency string) error {
 	for i := 0; i < count; i++ {
-		s.CreateWallet(models.Wallet{
-			Address: utils.GenerateAddress(),
-			Balance: balance,
+		s.CreateWallet(ctx, models.Wallet{
+			Balance:  balance,
+			Currency: currency,
 		})
 	}
 	return nil
 }
 
-// BaseWallets создает count кошельков со случайными адресами и balance у.е. на них если они еще не созданы.
-func (s *WalletService) BaseWallets(count int, balance float64) error {
-	if s.repo.Existence() {
+// BaseWallets создает по count кошельков в каждой из валют currencies со случайными адресами и balance у.е. на них
+// если они еще не созданы.
+func (s *WalletService) BaseWallets(ctx context.Context, count int, balance money.Amount, currencies []string) error {
+	if s.repo.Existence(ctx) {
 		return errors.New("wallets already exists")
 	}
-	s.CreateRandomWallets(count, balance)
+	for _, currency := range currencies {
+		s.CreateRandomWallets(ctx, count, balance, currency)
+	}
 	return nil
 }
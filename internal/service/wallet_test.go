@@ -1,43 +1,146 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"golangTestTask/internal/auth"
+	"golangTestTask/internal/domain"
 	"golangTestTask/internal/models"
 	"golangTestTask/internal/repository"
 	repository_mocks "golangTestTask/internal/repository/mocks"
+	"golangTestTask/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+// walletAddress — адрес в формате pkg/address для кошельков, создаваемых с адресом клиента.
+const walletAddress = "011111111111111111111111111111111111111111111111111111119b3556e5"
+
+// assignAddress имитирует WalletPostgres.Create для кошелька без адреса: записывает в него сгенерированный адрес.
+func assignAddress(ctx context.Context, wallet *models.Wallet) error {
+	wallet.Address = walletAddress
+	return nil
+}
+
 func TestWalletService_CreateWallet(t *testing.T) {
 	tests := []struct {
 		name        string
 		wallet      models.Wallet
-		mock        func(*repository_mocks.MockWallet, *models.Wallet)
+		mock        func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger)
+		expected    *models.Wallet
 		expectedErr error
 	}{
 		{
 			name: "success",
 			wallet: models.Wallet{
-				Address: "addr1",
-				Balance: 100.0,
+				Address: walletAddress,
+				Balance: money.FromInt(100),
 			},
-			mock: func(m *repository_mocks.MockWallet, w *models.Wallet) {
-				m.EXPECT().Create(w).Return(nil)
+			mock: func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				w.EXPECT().Create(gomock.Any(), &models.Wallet{
+					Address:  walletAddress,
+					Status:   models.WalletStatusActive,
+					Tier:     models.DefaultWalletTier,
+					Currency: "USD",
+				}).Return(nil)
+				l.EXPECT().Post(gomock.Any(), &models.JournalEntry{
+					Kind: models.JournalEntryKindOpening,
+					Postings: []models.Posting{
+						{Account: models.LedgerAccountEquity, Currency: "USD", Amount: -money.FromInt(100)},
+						{Wallet: walletAddress, Currency: "USD", Amount: money.FromInt(100)},
+					},
+				}).Return(nil)
+			},
+			expected: &models.Wallet{
+				Address:  walletAddress,
+				Balance:  money.FromInt(100),
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
 			},
 			expectedErr: nil,
 		},
+		{
+			name:   "empty wallet",
+			wallet: models.Wallet{Address: walletAddress},
+			mock: func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				w.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: &models.Wallet{
+				Address:  walletAddress,
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "USD",
+			},
+		},
+		{
+			name: "currency code normalized",
+			wallet: models.Wallet{
+				Address:  walletAddress,
+				Currency: "jpy",
+				Balance:  money.FromInt(1000),
+			},
+			mock: func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				w.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				l.EXPECT().Post(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: &models.Wallet{
+				Address:  walletAddress,
+				Balance:  money.FromInt(1000),
+				Status:   models.WalletStatusActive,
+				Tier:     models.DefaultWalletTier,
+				Currency: "JPY",
+			},
+		},
+		{
+			name:        "invalid address",
+			wallet:      models.Wallet{Address: "addr1"},
+			mock:        func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {},
+			expectedErr: errors.New("invalid wallet address: must be 64 characters long"),
+		},
+		{
+			name:        "legacy address",
+			wallet:      models.Wallet{Address: strings.Repeat("e240d825", 8)},
+			mock:        func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {},
+			expectedErr: errors.New("invalid wallet address: unknown version 226"),
+		},
+		{
+			name:        "unsupported currency",
+			wallet:      models.Wallet{Address: walletAddress, Currency: "XXX"},
+			mock:        func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {},
+			expectedErr: errors.New(`unsupported currency: "XXX"`),
+		},
+		{
+			name:        "balance too precise for currency",
+			wallet:      models.Wallet{Address: walletAddress, Currency: "JPY", Balance: money.MustParse("10.50")},
+			mock:        func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {},
+			expectedErr: domain.ErrInvalidAmountPrecision,
+		},
 		{
 			name: "repository error",
 			wallet: models.Wallet{
-				Address: "addr1",
-				Balance: 100.0,
+				Address: walletAddress,
+				Balance: money.FromInt(100),
+			},
+			mock: func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				w.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "opening entry failed",
+			wallet: models.Wallet{
+				Address: walletAddress,
+				Balance: money.FromInt(100),
 			},
-			mock: func(m *repository_mocks.MockWallet, w *models.Wallet) {
-				m.EXPECT().Create(w).Return(errors.New("db error"))
+			mock: func(w *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				w.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				l.EXPECT().Post(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
 			},
 			expectedErr: errors.New("db error"),
 		},
@@ -48,49 +151,269 @@ func TestWalletService_CreateWallet(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockRepo := repository_mocks.NewMockWallet(ctrl)
-			tt.mock(mockRepo, &tt.wallet)
+			walletRepo := repository_mocks.NewMockWallet(ctrl)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: walletRepo, Ledger: ledgerRepo})
+			}).AnyTimes()
+			tt.mock(walletRepo, ledgerRepo)
 
-			service := NewWalletService(mockRepo)
-			err := service.CreateWallet(tt.wallet)
+			service := NewWalletService(&repository.Repository{Wallet: walletRepo, UnitOfWork: uow})
+			wallet, err := service.CreateWallet(context.Background(), tt.wallet)
 
 			if tt.expectedErr != nil {
 				assert.EqualError(t, err, tt.expectedErr.Error())
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, wallet)
+			}
+		})
+	}
+}
+
+func TestWalletService_CreateWallet_GeneratesAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Адрес генерирует репозиторий при сохранении, чтобы повторить попытку при совпадении с существующим.
+	mockRepo := repository_mocks.NewMockWallet(ctrl)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, wallet *models.Wallet) error {
+		assert.Empty(t, wallet.Address)
+		return assignAddress(ctx, wallet)
+	})
+
+	service := NewWalletService(&repository.Repository{Wallet: mockRepo})
+	wallet, err := service.CreateWallet(context.Background(), models.Wallet{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, walletAddress, wallet.Address)
+	assert.Equal(t, models.WalletStatusActive, wallet.Status)
+	assert.Equal(t, money.Amount(0), wallet.Balance)
+}
+
+func TestWalletService_CreateWallet_AssignsOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	walletRepo := repository_mocks.NewMockWallet(ctrl)
+	apiKeyRepo := repository_mocks.NewMockAPIKey(ctrl)
+	uow := repository_mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+		return fn(&repository.Repository{Wallet: walletRepo, APIKey: apiKeyRepo})
+	})
+	walletRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	apiKeyRepo.EXPECT().AddWallet(gomock.Any(), 3, walletAddress).Return(nil)
+
+	service := NewWalletService(&repository.Repository{Wallet: walletRepo, UnitOfWork: uow})
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: 3, Role: auth.RoleCustomer})
+	wallet, err := service.CreateWallet(ctx, models.Wallet{Address: walletAddress})
+
+	assert.NoError(t, err)
+	assert.Equal(t, walletAddress, wallet.Address)
+}
+
+func TestWalletService_SetWalletStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      models.WalletStatus
+		mock        func(*repository_mocks.MockWallet)
+		expected    *models.Wallet
+		expectedErr error
+	}{
+		{
+			name:   "freeze active wallet",
+			status: models.WalletStatusFrozen,
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Balance: money.FromInt(10), Status: models.WalletStatusActive}, nil)
+				m.EXPECT().UpdateStatus(gomock.Any(), "addr1", models.WalletStatusFrozen).Return(nil)
+			},
+			expected: &models.Wallet{Address: "addr1", Balance: money.FromInt(10), Status: models.WalletStatusFrozen},
+		},
+		{
+			name:   "unfreeze frozen wallet",
+			status: models.WalletStatusActive,
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusFrozen}, nil)
+				m.EXPECT().UpdateStatus(gomock.Any(), "addr1", models.WalletStatusActive).Return(nil)
+			},
+			expected: &models.Wallet{Address: "addr1", Status: models.WalletStatusActive},
+		},
+		{
+			name:   "close empty wallet",
+			status: models.WalletStatusClosed,
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusFrozen}, nil)
+				m.EXPECT().UpdateStatus(gomock.Any(), "addr1", models.WalletStatusClosed).Return(nil)
+			},
+			expected: &models.Wallet{Address: "addr1", Status: models.WalletStatusClosed},
+		},
+		{
+			name:   "same status is no-op",
+			status: models.WalletStatusFrozen,
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusFrozen}, nil)
+			},
+			expected: &models.Wallet{Address: "addr1", Status: models.WalletStatusFrozen},
+		},
+		{
+			name:   "close wallet with balance",
+			status: models.WalletStatusClosed,
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Balance: money.FromInt(10), Status: models.WalletStatusActive}, nil)
+			},
+			expectedErr: domain.ErrWalletNotEmpty,
+		},
+		{
+			name:   "reopen closed wallet",
+			status: models.WalletStatusActive,
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusClosed}, nil)
+			},
+			expectedErr: domain.ErrWalletClosed,
+		},
+		{
+			name:        "unknown status",
+			status:      models.WalletStatus("deleted"),
+			mock:        func(m *repository_mocks.MockWallet) {},
+			expectedErr: domain.ErrInvalidWalletStatus,
+		},
+		{
+			name:   "wallet not found",
+			status: models.WalletStatusFrozen,
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(nil, domain.ErrWalletNotFound)
+			},
+			expectedErr: domain.ErrWalletNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository_mocks.NewMockWallet(ctrl)
+			tt.mock(mockRepo)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: mockRepo})
+			}).AnyTimes()
+
+			service := NewWalletService(&repository.Repository{Wallet: mockRepo, UnitOfWork: uow})
+			wallet, err := service.SetWalletStatus(context.Background(), "addr1", tt.status)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, wallet)
+			}
+		})
+	}
+}
+
+func TestWalletService_SetWalletTier(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        func(*repository_mocks.MockWallet)
+		expected    *models.Wallet
+		expectedErr error
+	}{
+		{
+			name: "success",
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusActive, Tier: models.DefaultWalletTier}, nil)
+				m.EXPECT().UpdateTier(gomock.Any(), "addr1", "verified").Return(nil)
+			},
+			expected: &models.Wallet{Address: "addr1", Status: models.WalletStatusActive, Tier: "verified"},
+		},
+		{
+			name: "same tier",
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusActive, Tier: "verified"}, nil)
+			},
+			expected: &models.Wallet{Address: "addr1", Status: models.WalletStatusActive, Tier: "verified"},
+		},
+		{
+			name: "closed wallet",
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusClosed, Tier: models.DefaultWalletTier}, nil)
+			},
+			expectedErr: domain.ErrWalletClosed,
+		},
+		{
+			name: "tier not found",
+			mock: func(m *repository_mocks.MockWallet) {
+				m.EXPECT().GetForUpdate(gomock.Any(), "addr1").Return(&models.Wallet{Address: "addr1", Status: models.WalletStatusActive, Tier: models.DefaultWalletTier}, nil)
+				m.EXPECT().UpdateTier(gomock.Any(), "addr1", "verified").Return(domain.ErrTierNotFound)
+			},
+			expectedErr: domain.ErrTierNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository_mocks.NewMockWallet(ctrl)
+			tt.mock(mockRepo)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: mockRepo})
+			})
+
+			service := NewWalletService(&repository.Repository{Wallet: mockRepo, UnitOfWork: uow})
+			wallet, err := service.SetWalletTier(context.Background(), "addr1", "verified")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, wallet)
 			}
 		})
 	}
 }
 
 func TestWalletService_GetWalletBalance(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
 	tests := []struct {
 		name        string
 		address     string
-		mock        func(*repository_mocks.MockWallet, string)
-		expectedBal float64
+		mock        func(*repository_mocks.MockWallet, *repository_mocks.MockHold, string)
+		expected    *models.WalletBalance
 		expectedErr error
 	}{
 		{
 			name:    "success",
 			address: "addr1",
-			mock: func(m *repository_mocks.MockWallet, addr string) {
-				m.EXPECT().Get(addr).Return(&models.Wallet{
-					Address: addr,
-					Balance: 100.0,
+			mock: func(m *repository_mocks.MockWallet, h *repository_mocks.MockHold, addr string) {
+				m.EXPECT().Get(gomock.Any(), addr).Return(&models.Wallet{
+					Address:  addr,
+					Balance:  money.FromInt(100),
+					Currency: "USD",
 				}, nil)
+				h.EXPECT().Held(gomock.Any(), addr, now).Return(money.FromInt(30), nil)
+			},
+			expected: &models.WalletBalance{
+				Address:   "addr1",
+				Balance:   money.FromInt(100),
+				Held:      money.FromInt(30),
+				Available: money.FromInt(70),
+				Currency:  "USD",
 			},
-			expectedBal: 100.0,
 			expectedErr: nil,
 		},
 		{
 			name:    "wallet not found",
 			address: "unknown",
-			mock: func(m *repository_mocks.MockWallet, addr string) {
-				m.EXPECT().Get(addr).Return(nil, repository.ErrWalletNotFound)
+			mock: func(m *repository_mocks.MockWallet, h *repository_mocks.MockHold, addr string) {
+				m.EXPECT().Get(gomock.Any(), addr).Return(nil, domain.ErrWalletNotFound)
 			},
-			expectedBal: 0,
-			expectedErr: repository.ErrWalletNotFound,
+			expected:    nil,
+			expectedErr: domain.ErrWalletNotFound,
 		},
 	}
 
@@ -100,12 +423,14 @@ func TestWalletService_GetWalletBalance(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockRepo := repository_mocks.NewMockWallet(ctrl)
-			tt.mock(mockRepo, tt.address)
+			holdRepo := repository_mocks.NewMockHold(ctrl)
+			tt.mock(mockRepo, holdRepo, tt.address)
 
-			service := NewWalletService(mockRepo)
-			balance, err := service.GetWalletBalance(tt.address)
+			service := NewWalletService(&repository.Repository{Wallet: mockRepo, Hold: holdRepo})
+			service.now = func() time.Time { return now }
+			balance, err := service.GetWalletBalance(context.Background(), tt.address)
 
-			assert.Equal(t, tt.expectedBal, balance)
+			assert.Equal(t, tt.expected, balance)
 			assert.ErrorIs(t, err, tt.expectedErr)
 		})
 	}
@@ -116,12 +441,18 @@ func TestWalletService_CreateRandomWallets(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repository_mocks.NewMockWallet(ctrl)
+	ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+	uow := repository_mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+		return fn(&repository.Repository{Wallet: mockRepo, Ledger: ledgerRepo})
+	}).Times(3)
 
-	// Ожидаем 3 вызова Create
-	mockRepo.EXPECT().Create(gomock.Any()).Times(3).Return(nil)
+	// Ожидаем 3 вызова Create, каждый с записью начального баланса в журнал
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(assignAddress)
+	ledgerRepo.EXPECT().Post(gomock.Any(), gomock.Any()).Times(3).Return(nil)
 
-	service := NewWalletService(mockRepo)
-	err := service.CreateRandomWallets(3, 100.0)
+	service := NewWalletService(&repository.Repository{Wallet: mockRepo, UnitOfWork: uow})
+	err := service.CreateRandomWallets(context.Background(), 3, money.FromInt(100), "USD")
 
 	assert.NoError(t, err)
 }
@@ -130,26 +461,48 @@ func TestWalletService_BaseWallets(t *testing.T) {
 	tests := []struct {
 		name        string
 		count       int
-		balance     float64
-		mock        func(*repository_mocks.MockWallet)
+		balance     money.Amount
+		currencies  []string
+		mock        func(*repository_mocks.MockWallet, *repository_mocks.MockLedger)
 		expectedErr error
 	}{
 		{
-			name:    "create new wallets",
-			count:   3,
-			balance: 100.0,
-			mock: func(m *repository_mocks.MockWallet) {
-				m.EXPECT().Existence().Return(false)
-				m.EXPECT().Create(gomock.Any()).Times(3).Return(nil)
+			name:       "create new wallets",
+			count:      3,
+			balance:    money.FromInt(100),
+			currencies: []string{"USD"},
+			mock: func(m *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				m.EXPECT().Existence(gomock.Any()).Return(false)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(assignAddress)
+				l.EXPECT().Post(gomock.Any(), gomock.Any()).Times(3).Return(nil)
 			},
 			expectedErr: nil,
 		},
 		{
-			name:    "wallets already exist",
-			count:   3,
-			balance: 100.0,
-			mock: func(m *repository_mocks.MockWallet) {
-				m.EXPECT().Existence().Return(true)
+			name:       "wallets in each currency",
+			count:      2,
+			balance:    money.FromInt(100),
+			currencies: []string{"USD", "EUR"},
+			mock: func(m *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				m.EXPECT().Existence(gomock.Any()).Return(false)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, wallet *models.Wallet) error {
+					assert.Equal(t, "USD", wallet.Currency)
+					return assignAddress(ctx, wallet)
+				}).Times(2)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, wallet *models.Wallet) error {
+					assert.Equal(t, "EUR", wallet.Currency)
+					return assignAddress(ctx, wallet)
+				}).Times(2)
+				l.EXPECT().Post(gomock.Any(), gomock.Any()).Times(4).Return(nil)
+			},
+		},
+		{
+			name:       "wallets already exist",
+			count:      3,
+			balance:    money.FromInt(100),
+			currencies: []string{"USD"},
+			mock: func(m *repository_mocks.MockWallet, l *repository_mocks.MockLedger) {
+				m.EXPECT().Existence(gomock.Any()).Return(true)
 			},
 			expectedErr: errors.New("wallets already exists"),
 		},
@@ -161,10 +514,15 @@ func TestWalletService_BaseWallets(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockRepo := repository_mocks.NewMockWallet(ctrl)
-			tt.mock(mockRepo)
+			ledgerRepo := repository_mocks.NewMockLedger(ctrl)
+			uow := repository_mocks.NewMockUnitOfWork(ctrl)
+			uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repos *repository.Repository) error) error {
+				return fn(&repository.Repository{Wallet: mockRepo, Ledger: ledgerRepo})
+			}).AnyTimes()
+			tt.mock(mockRepo, ledgerRepo)
 
-			service := NewWalletService(mockRepo)
-			err := service.BaseWallets(tt.count, tt.balance)
+			service := NewWalletService(&repository.Repository{Wallet: mockRepo, UnitOfWork: uow})
+			err := service.BaseWallets(context.Background(), tt.count, tt.balance, tt.currencies)
 
 			if tt.expectedErr != nil {
 				assert.EqualError(t, err, tt.expectedErr.Error())
@@ -0,0 +1,5 @@
+DROP INDEX idx_transactions_created_at_id;
+DROP INDEX idx_transactions_to_address_id;
+DROP INDEX idx_transactions_from_address_id;
+
+ALTER TABLE transactions DROP COLUMN created_at;
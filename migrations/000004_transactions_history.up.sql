@@ -0,0 +1,5 @@
+ALTER TABLE transactions ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX idx_transactions_from_address_id ON transactions (from_address, id DESC);
+CREATE INDEX idx_transactions_to_address_id ON transactions (to_address, id DESC);
+CREATE INDEX idx_transactions_created_at_id ON transactions (created_at, id DESC);
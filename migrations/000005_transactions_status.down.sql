@@ -0,0 +1,6 @@
+DROP INDEX idx_transactions_status_id;
+
+ALTER TABLE transactions
+    DROP COLUMN completed_at,
+    DROP COLUMN failure_reason,
+    DROP COLUMN status;
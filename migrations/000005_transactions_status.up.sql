@@ -0,0 +1,9 @@
+ALTER TABLE transactions
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'completed'
+        CHECK (status IN ('pending', 'completed', 'failed', 'reversed')),
+    ADD COLUMN failure_reason TEXT,
+    ADD COLUMN completed_at TIMESTAMPTZ;
+
+UPDATE transactions SET completed_at = created_at;
+
+CREATE INDEX idx_transactions_status_id ON transactions (status, id DESC);
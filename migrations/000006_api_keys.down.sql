@@ -0,0 +1,2 @@
+DROP TABLE api_key_wallets;
+DROP TABLE api_keys;
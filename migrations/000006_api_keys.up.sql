@@ -0,0 +1,16 @@
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE TABLE api_key_wallets (
+    api_key_id INTEGER NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+    wallet_address VARCHAR(64) NOT NULL REFERENCES wallets (address),
+    PRIMARY KEY (api_key_id, wallet_address)
+);
+
+CREATE INDEX idx_api_key_wallets_wallet_address ON api_key_wallets (wallet_address);
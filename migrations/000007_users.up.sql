@@ -0,0 +1,27 @@
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(64) NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    role VARCHAR(16) NOT NULL DEFAULT 'customer'
+        CHECK (role IN ('customer', 'operator', 'auditor', 'admin')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_wallets (
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    wallet_address VARCHAR(64) NOT NULL REFERENCES wallets (address),
+    PRIMARY KEY (user_id, wallet_address)
+);
+
+CREATE INDEX idx_user_wallets_wallet_address ON user_wallets (wallet_address);
+
+CREATE TABLE refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
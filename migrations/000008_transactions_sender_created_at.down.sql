@@ -0,0 +1 @@
+DROP INDEX idx_transactions_from_address_created_at;
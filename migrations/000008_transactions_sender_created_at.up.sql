@@ -0,0 +1 @@
+CREATE INDEX idx_transactions_from_address_created_at ON transactions (from_address, created_at);
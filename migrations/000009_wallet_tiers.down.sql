@@ -0,0 +1,3 @@
+ALTER TABLE wallets DROP COLUMN tier;
+
+DROP TABLE wallet_tiers;
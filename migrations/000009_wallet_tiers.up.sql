@@ -0,0 +1,15 @@
+CREATE TABLE wallet_tiers (
+    name VARCHAR(32) PRIMARY KEY,
+    min_transfer DECIMAL(15, 2) NOT NULL DEFAULT 0.01 CHECK (min_transfer > 0),
+    max_transfer DECIMAL(15, 2) NOT NULL DEFAULT 0 CHECK (max_transfer >= 0),
+    daily_limit DECIMAL(15, 2) NOT NULL DEFAULT 0 CHECK (daily_limit >= 0),
+    monthly_limit DECIMAL(15, 2) NOT NULL DEFAULT 0 CHECK (monthly_limit >= 0),
+    max_balance DECIMAL(15, 2) NOT NULL DEFAULT 0 CHECK (max_balance >= 0)
+);
+
+INSERT INTO wallet_tiers (name) VALUES ('standard');
+
+ALTER TABLE wallets
+    ADD COLUMN tier VARCHAR(32) NOT NULL DEFAULT 'standard' REFERENCES wallet_tiers (name);
+
+CREATE INDEX idx_wallets_tier ON wallets (tier);
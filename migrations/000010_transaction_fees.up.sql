@@ -0,0 +1,9 @@
+CREATE TABLE transaction_fees (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id),
+    wallet_address VARCHAR(64) NOT NULL REFERENCES wallets (address),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_transaction_fees_transaction_id ON transaction_fees (transaction_id);
@@ -0,0 +1,8 @@
+ALTER TABLE wallets
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Валюта транзакции — валюта кошелька отправителя. У неудачных переводов с несуществующего кошелька она неизвестна.
+ALTER TABLE transactions
+    ADD COLUMN currency CHAR(3);
+
+UPDATE transactions SET currency = 'USD';
@@ -0,0 +1,14 @@
+CREATE TABLE transaction_conversions (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id),
+    debit_currency CHAR(3) NOT NULL,
+    debit_amount DECIMAL(15, 2) NOT NULL CHECK (debit_amount > 0),
+    credit_currency CHAR(3) NOT NULL,
+    credit_amount DECIMAL(15, 2) NOT NULL CHECK (credit_amount > 0),
+    mid_rate DECIMAL(20, 8) NOT NULL CHECK (mid_rate > 0),
+    rate DECIMAL(20, 8) NOT NULL CHECK (rate > 0),
+    spread_bp INTEGER NOT NULL CHECK (spread_bp >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_transaction_conversions_transaction_id ON transaction_conversions (transaction_id);
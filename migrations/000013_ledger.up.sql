@@ -0,0 +1,35 @@
+CREATE TABLE journal_entries (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('opening', 'transfer')),
+    transaction_id INTEGER REFERENCES transactions (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_journal_entries_transaction_id ON journal_entries (transaction_id);
+
+-- Проводка изменяет баланс ровно одного счета: кошелька wallet_address или системного счета account.
+-- Положительная сумма зачисляется на счет, отрицательная списывается с него.
+CREATE TABLE postings (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES journal_entries (id),
+    wallet_address VARCHAR(64) REFERENCES wallets (address),
+    account VARCHAR(64),
+    currency CHAR(3) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount <> 0),
+    CHECK ((wallet_address IS NULL) <> (account IS NULL))
+);
+
+CREATE INDEX idx_postings_entry_id ON postings (entry_id);
+CREATE INDEX idx_postings_wallet_address ON postings (wallet_address);
+
+-- Балансы кошельков, существовавших до появления журнала, переносятся одной начальной записью
+-- с обратной проводкой на системный счет equity в каждой валюте.
+INSERT INTO journal_entries (kind)
+SELECT 'opening' WHERE EXISTS (SELECT 1 FROM wallets WHERE balance <> 0);
+
+INSERT INTO postings (entry_id, wallet_address, currency, amount)
+SELECT currval('journal_entries_id_seq'), address, currency, balance FROM wallets WHERE balance <> 0;
+
+INSERT INTO postings (entry_id, account, currency, amount)
+SELECT currval('journal_entries_id_seq'), 'equity', currency, -SUM(balance) FROM wallets WHERE balance <> 0
+GROUP BY currency HAVING SUM(balance) <> 0;
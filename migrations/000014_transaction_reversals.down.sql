@@ -0,0 +1,6 @@
+ALTER TABLE journal_entries DROP CONSTRAINT journal_entries_kind_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_kind_check CHECK (kind IN ('opening', 'transfer'));
+
+DROP INDEX idx_transactions_reversal_of;
+
+ALTER TABLE transactions DROP COLUMN reversal_of;
@@ -0,0 +1,8 @@
+-- reversal_of связывает компенсирующую транзакцию с отмененным переводом. Уникальный индекс
+-- не дает отменить один перевод дважды.
+ALTER TABLE transactions ADD COLUMN reversal_of INTEGER REFERENCES transactions (id);
+
+CREATE UNIQUE INDEX idx_transactions_reversal_of ON transactions (reversal_of) WHERE reversal_of IS NOT NULL;
+
+ALTER TABLE journal_entries DROP CONSTRAINT journal_entries_kind_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_kind_check CHECK (kind IN ('opening', 'transfer', 'reversal'));
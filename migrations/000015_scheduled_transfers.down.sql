@@ -0,0 +1,8 @@
+DROP INDEX idx_transactions_scheduled_run;
+
+ALTER TABLE transactions
+    DROP COLUMN scheduled_for,
+    DROP COLUMN scheduled_transfer_id;
+
+DROP TABLE IF EXISTS scheduled_transfer_runs;
+DROP TABLE IF EXISTS scheduled_transfers;
@@ -0,0 +1,40 @@
+CREATE TABLE scheduled_transfers (
+    id SERIAL PRIMARY KEY,
+    from_address VARCHAR(64) NOT NULL REFERENCES wallets (address),
+    to_address VARCHAR(64) NOT NULL REFERENCES wallets (address),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    convert_currency BOOLEAN NOT NULL DEFAULT false,
+    schedule VARCHAR(128) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled')),
+    next_run_at TIMESTAMPTZ NOT NULL,
+    retry_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    cancelled_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_scheduled_transfers_due ON scheduled_transfers ((COALESCE(retry_at, next_run_at))) WHERE status = 'active';
+CREATE INDEX idx_scheduled_transfers_from_address ON scheduled_transfers (from_address);
+CREATE INDEX idx_scheduled_transfers_to_address ON scheduled_transfers (to_address);
+
+CREATE TABLE scheduled_transfer_runs (
+    id SERIAL PRIMARY KEY,
+    scheduled_transfer_id INTEGER NOT NULL REFERENCES scheduled_transfers (id),
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    attempt INTEGER NOT NULL CHECK (attempt > 0),
+    status VARCHAR(16) NOT NULL CHECK (status IN ('completed', 'retrying', 'failed')),
+    transaction_id INTEGER REFERENCES transactions (id),
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_scheduled_transfer_runs_scheduled_transfer_id ON scheduled_transfer_runs (scheduled_transfer_id, id DESC);
+
+-- Транзакции регулярного перевода помечаются сроком, за который они выполнены. Уникальный индекс гарантирует,
+-- что за один срок выполняется не больше одного перевода, даже если планировщик повторит его после сбоя.
+ALTER TABLE transactions
+    ADD COLUMN scheduled_transfer_id INTEGER REFERENCES scheduled_transfers (id),
+    ADD COLUMN scheduled_for TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_transactions_scheduled_run ON transactions (scheduled_transfer_id, scheduled_for)
+    WHERE status <> 'failed' AND scheduled_transfer_id IS NOT NULL;
@@ -0,0 +1,18 @@
+CREATE TABLE holds (
+    id SERIAL PRIMARY KEY,
+    from_address VARCHAR(64) NOT NULL REFERENCES wallets (address),
+    to_address VARCHAR(64) NOT NULL REFERENCES wallets (address),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    convert_currency BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'captured', 'voided', 'expired')),
+    captured_amount DECIMAL(15, 2) CHECK (captured_amount > 0 AND captured_amount <= amount),
+    transaction_id INTEGER REFERENCES transactions (id),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finalized_at TIMESTAMPTZ
+);
+
+-- Заблокированная сумма кошелька — сумма его активных блокировок, поэтому они индексируются отдельно.
+CREATE INDEX idx_holds_active ON holds (from_address, expires_at) WHERE status = 'active';
+CREATE INDEX idx_holds_to_address ON holds (to_address);
@@ -0,0 +1,9 @@
+-- Предварительный расчет перевода можно использовать только один раз: его одноразовое значение сохраняется
+-- в той же транзакции БД, что и перевод, а первичный ключ не дает использовать расчет повторно.
+CREATE TABLE transaction_quotes (
+    nonce VARCHAR(64) PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_transaction_quotes_transaction_id ON transaction_quotes (transaction_id);
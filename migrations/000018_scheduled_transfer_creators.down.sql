@@ -0,0 +1 @@
+ALTER TABLE scheduled_transfers DROP COLUMN IF EXISTS created_by_user_id, DROP COLUMN IF EXISTS created_by_key_id;
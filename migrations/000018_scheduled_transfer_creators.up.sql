@@ -0,0 +1,19 @@
+-- Регулярный перевод выполняется от имени создавшего его участника — ключа API или пользователя, — и его право списывать
+-- средства с кошелька отправителя проверяется при каждом выполнении. Если оба столбца пусты, перевод создан самим сервисом.
+ALTER TABLE scheduled_transfers
+    ADD COLUMN created_by_key_id INTEGER REFERENCES api_keys (id),
+    ADD COLUMN created_by_user_id INTEGER REFERENCES users (id),
+    ADD CHECK (created_by_key_id IS NULL OR created_by_user_id IS NULL);
+
+-- Создатель регулярных переводов, созданных раньше, не сохранялся. Им назначается владелец кошелька отправителя:
+-- действующий ключ API, а если его нет — пользователь. Переводы с кошельков без владельца могли создать
+-- только администраторы, и они по-прежнему выполняются от имени сервиса.
+UPDATE scheduled_transfers t SET created_by_key_id = (
+    SELECT MIN(w.api_key_id) FROM api_key_wallets w JOIN api_keys k ON k.id = w.api_key_id
+    WHERE w.wallet_address = t.from_address AND k.revoked_at IS NULL
+);
+
+UPDATE scheduled_transfers t SET created_by_user_id = (
+    SELECT MIN(w.user_id) FROM user_wallets w WHERE w.wallet_address = t.from_address
+)
+WHERE created_by_key_id IS NULL;
@@ -0,0 +1,5 @@
+-- Одинаковые ключи разных участников нельзя сохранить без столбца subject, поэтому все ключи удаляются.
+DELETE FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys DROP COLUMN subject;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
@@ -0,0 +1,7 @@
+-- Ключи идемпотентности действуют в пределах участника: разные клиенты могут использовать одинаковые ключи,
+-- не получая чужих ответов. Сохраненные раньше ключи не привязаны к участнику и удаляются.
+DELETE FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD COLUMN subject VARCHAR(255) NOT NULL;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (subject, key);
@@ -0,0 +1,89 @@
+// Package address описывает формат адресов кошельков и проверку адресов, полученных от клиентов.
+//
+// Адрес — 64 шестнадцатеричные цифры в нижнем регистре, кодирующие 32 байта: байт версии формата,
+// PayloadSize байт полезной нагрузки и контрольную сумму CRC-32 (IEEE, big-endian) предыдущих байтов.
+// Контрольная сумма позволяет отклонить адрес с опечаткой до обращения к БД.
+//
+// Адреса, созданные до введения формата, — тоже 64 шестнадцатеричные цифры в нижнем регистре, но случайные и без
+// версии и контрольной суммы. По виду устаревший адрес неотличим от адреса текущего формата с опечаткой, в том числе
+// в байте версии, поэтому ValidateCurrent его отклоняет. ValidateFormat проверяет только длину и символы адреса,
+// а принимать ли адрес, не прошедший ValidateCurrent, решает вызывающий, например по наличию кошелька с ним.
+package address
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+const (
+	// Length — длина адреса в символах.
+	Length = 2 * size
+	// Version — текущая версия формата адреса, записываемая в его первый байт.
+	Version byte = 1
+	// PayloadSize — размер полезной нагрузки адреса в байтах.
+	PayloadSize = 27
+)
+
+const (
+	checksumSize = 4
+	size         = 1 + PayloadSize + checksumSize
+)
+
+var ErrInvalidAddress = errors.New("invalid wallet address")
+
+// Encode возвращает адрес текущей версии формата с полезной нагрузкой payload.
+func Encode(payload [PayloadSize]byte) string {
+	var b [size]byte
+	b[0] = Version
+	copy(b[1:], payload[:])
+	binary.BigEndian.PutUint32(b[size-checksumSize:], crc32.ChecksumIEEE(b[:size-checksumSize]))
+	return hex.EncodeToString(b[:])
+}
+
+// Generate возвращает новый адрес текущей версии формата со случайной полезной нагрузкой из crypto/rand.
+// Адрес непредсказуем, но не гарантированно уникален: совпадение с существующим адресом проверяется при сохранении кошелька.
+func Generate() (string, error) {
+	var payload [PayloadSize]byte
+	if _, err := rand.Read(payload[:]); err != nil {
+		return "", fmt.Errorf("failed to generate wallet address: %w", err)
+	}
+	return Encode(payload), nil
+}
+
+// ValidateCurrent проверяет, что s — адрес текущей версии формата с верной контрольной суммой;
+// устаревшие адреса отклоняются. Ошибка оборачивает ErrInvalidAddress и описывает, что именно не так с адресом.
+func ValidateCurrent(s string) error {
+	if err := ValidateFormat(s); err != nil {
+		return err
+	}
+	b, _ := hex.DecodeString(s)
+	if b[0] != Version {
+		return fmt.Errorf("%w: unknown version %d", ErrInvalidAddress, b[0])
+	}
+	if binary.BigEndian.Uint32(b[size-checksumSize:]) != crc32.ChecksumIEEE(b[:size-checksumSize]) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidAddress)
+	}
+	return nil
+}
+
+// ValidateFormat проверяет, что s имеет вид адреса любой версии формата или устаревшего адреса: Length шестнадцатеричных
+// цифр в нижнем регистре. Версия и контрольная сумма не проверяются.
+func ValidateFormat(s string) error {
+	if len(s) != Length {
+		return fmt.Errorf("%w: must be %d characters long", ErrInvalidAddress, Length)
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLowerHex(s[i]) {
+			return fmt.Errorf("%w: must contain only lowercase hexadecimal digits", ErrInvalidAddress)
+		}
+	}
+	return nil
+}
+
+func isLowerHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f')
+}
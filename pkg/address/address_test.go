@@ -0,0 +1,89 @@
+package address
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	var payload [PayloadSize]byte
+	for i := range payload {
+		payload[i] = 0x11
+	}
+
+	got := Encode(payload)
+	assert.Equal(t, "011111111111111111111111111111111111111111111111111111119b3556e5", got)
+	assert.NoError(t, ValidateCurrent(got))
+}
+
+func TestGenerate(t *testing.T) {
+	first, err := Generate()
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateCurrent(first))
+
+	second, err := Generate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestValidateCurrent(t *testing.T) {
+	const valid = "01222222222222222222222222222222222222222222222222222222b42432d6"
+
+	tests := []struct {
+		name    string
+		address string
+		wantErr string
+	}{
+		{name: "valid", address: valid},
+		{name: "too short", address: valid[:63], wantErr: "invalid wallet address: must be 64 characters long"},
+		{name: "uppercase", address: strings.ToUpper(valid), wantErr: "invalid wallet address: must contain only lowercase hexadecimal digits"},
+		{name: "not hex", address: "01zz" + valid[4:], wantErr: "invalid wallet address: must contain only lowercase hexadecimal digits"},
+		{name: "typo in version", address: "02" + valid[2:], wantErr: "invalid wallet address: unknown version 2"},
+		{name: "typo in payload", address: valid[:10] + "3" + valid[11:], wantErr: "invalid wallet address: checksum mismatch"},
+		{name: "typo in checksum", address: valid[:63] + "7", wantErr: "invalid wallet address: checksum mismatch"},
+		{name: "legacy address", address: strings.Repeat("e240d825", 8), wantErr: "invalid wallet address: unknown version 226"},
+		{name: "legacy address with current version", address: strings.Repeat("01e240d8", 8), wantErr: "invalid wallet address: checksum mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCurrent(tt.address)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidAddress)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	const valid = "01222222222222222222222222222222222222222222222222222222b42432d6"
+
+	tests := []struct {
+		name    string
+		address string
+		wantErr string
+	}{
+		{name: "valid", address: valid},
+		{name: "typo in payload", address: valid[:10] + "3" + valid[11:]},
+		{name: "legacy address", address: strings.Repeat("e240d825", 8)},
+		{name: "too short", address: valid[:63], wantErr: "invalid wallet address: must be 64 characters long"},
+		{name: "uppercase", address: strings.ToUpper(valid), wantErr: "invalid wallet address: must contain only lowercase hexadecimal digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFormat(tt.address)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidAddress)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
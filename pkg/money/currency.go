@@ -0,0 +1,60 @@
+package money
+
+import "strings"
+
+// DefaultCurrency — валюта кошельков, для которых валюта не указана, и всех кошельков, созданных до появления валют.
+const DefaultCurrency = "USD"
+
+// Currency — валюта ISO 4217 и число знаков после запятой (Precision) в суммах в ней.
+// Суммы хранятся с Scale знаками, поэтому поддерживаются только валюты с Precision не больше Scale.
+type Currency struct {
+	Code      string
+	Precision int
+}
+
+// currencies — поддерживаемые валюты.
+var currencies = map[string]Currency{
+	"USD": {Code: "USD", Precision: 2},
+	"EUR": {Code: "EUR", Precision: 2},
+	"GBP": {Code: "GBP", Precision: 2},
+	"CHF": {Code: "CHF", Precision: 2},
+	"CNY": {Code: "CNY", Precision: 2},
+	"RUB": {Code: "RUB", Precision: 2},
+	"JPY": {Code: "JPY", Precision: 0},
+	"KRW": {Code: "KRW", Precision: 0},
+}
+
+// LookupCurrency возвращает валюту по ее коду ISO 4217 без учета регистра и сообщает, поддерживается ли она.
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := currencies[strings.ToUpper(code)]
+	return currency, ok
+}
+
+// step возвращает наименьшую сумму, представимую в валюте c, в минимальных единицах Amount.
+func (c Currency) step() Amount {
+	step := Amount(1)
+	for i := c.Precision; i < Scale; i++ {
+		step *= 10
+	}
+	return step
+}
+
+// Fits сообщает, записывается ли сумма a не более чем Precision знаками после запятой.
+func (c Currency) Fits(a Amount) bool {
+	return a%c.step() == 0
+}
+
+// Round округляет сумму a до Precision знаков после запятой, половину — от нуля.
+func (c Currency) Round(a Amount) Amount {
+	step := c.step()
+	if a < 0 {
+		return -c.Round(-a)
+	}
+	return (a + step/2) / step * step
+}
+
+// Truncate отбрасывает у суммы a знаки после запятой сверх Precision.
+func (c Currency) Truncate(a Amount) Amount {
+	step := c.step()
+	return a / step * step
+}
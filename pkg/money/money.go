@@ -0,0 +1,167 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale — количество знаков после запятой, с которым хранятся суммы (соответствует DECIMAL(15, 2) в БД).
+const Scale = 2
+
+const unit = 100 // 10^Scale
+
+// MaxAmount — наибольшая по модулю сумма, которую можно сохранить в столбце DECIMAL(15, 2): 9999999999999.99.
+const MaxAmount Amount = 999_999_999_999_999
+
+var (
+	ErrInvalidAmount         = errors.New("invalid amount")
+	ErrTooManyFractionDigits = fmt.Errorf("amount has more than %d fractional digits", Scale)
+	ErrAmountOverflow        = errors.New("amount is too large")
+)
+
+// Amount — денежная сумма в минимальных единицах (сотых долях у.е.).
+// Все вычисления выполняются в целых числах, поэтому суммы не накапливают ошибку округления.
+// В JSON сумма кодируется строкой ("10.50"), в БД передается как NUMERIC.
+type Amount int64
+
+// FromInt возвращает сумму, равную units целым у.е.
+func FromInt(units int64) Amount {
+	return Amount(units * unit)
+}
+
+// Parse разбирает десятичную запись суммы вида "10", "10.5" или "-0.05".
+// Запись с экспонентой и более чем Scale знаками после запятой отклоняется.
+func Parse(s string) (Amount, error) {
+	str := s
+	negative := false
+	switch {
+	case strings.HasPrefix(str, "-"):
+		negative = true
+		str = str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(str, ".")
+	if intPart == "" || (hasDot && fracPart == "") || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if len(fracPart) > Scale {
+		return 0, fmt.Errorf("%w: %q", ErrTooManyFractionDigits, s)
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units > math.MaxInt64/unit {
+		return 0, fmt.Errorf("%w: %q", ErrAmountOverflow, s)
+	}
+	fracPart += strings.Repeat("0", Scale-len(fracPart))
+	minor, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	amount := Amount(units*unit + minor)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// MustParse работает как Parse, но паникует при ошибке. Предназначена для констант и тестов.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String возвращает десятичную запись суммы с Scale знаками после запятой, например "10.50".
+func (a Amount) String() string {
+	sign := ""
+	v := uint64(a)
+	if a < 0 {
+		sign = "-"
+		v = uint64(-a)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, v/unit, Scale, v%unit)
+}
+
+// Float64 возвращает сумму в целых единицах валюты. Результат может терять точность,
+// поэтому его можно использовать только там, где она не важна, например в метриках.
+func (a Amount) Float64() float64 {
+	return float64(a) / unit
+}
+
+// MarshalJSON кодирует сумму строкой, чтобы клиенты не теряли точность при разборе в float.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON принимает сумму как строкой ("10.50"), так и числом (10.50).
+// Число разбирается из исходной записи без промежуточного float64. Суммы, которые по модулю больше MaxAmount
+// и не помещаются в столбцы сумм в БД, отклоняются с ошибкой ErrAmountOverflow.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, s)
+		}
+		s = unquoted
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if parsed > MaxAmount || parsed < -MaxAmount {
+		return fmt.Errorf("%w: %s", ErrAmountOverflow, s)
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan реализует sql.Scanner для чтения значений NUMERIC из БД.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		*a = FromInt(v)
+		return nil
+	case float64:
+		*a = Amount(math.Round(v * unit))
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into Amount", src)
+	}
+}
+
+func (a *Amount) scanString(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value реализует driver.Valuer: сумма передается в БД десятичной строкой без потери точности.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
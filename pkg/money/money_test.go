@@ -0,0 +1,173 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Amount
+		wantErr error
+	}{
+		{name: "Integer", input: "10", want: 1000},
+		{name: "One Fractional Digit", input: "10.5", want: 1050},
+		{name: "Two Fractional Digits", input: "0.01", want: 1},
+		{name: "Negative", input: "-0.05", want: -5},
+		{name: "Explicit Plus", input: "+3.20", want: 320},
+		{name: "Too Many Fractional Digits", input: "0.001", wantErr: ErrTooManyFractionDigits},
+		{name: "Exponent", input: "1e2", wantErr: ErrInvalidAmount},
+		{name: "Empty", input: "", wantErr: ErrInvalidAmount},
+		{name: "Trailing Dot", input: "1.", wantErr: ErrInvalidAmount},
+		{name: "Leading Dot", input: ".5", wantErr: ErrInvalidAmount},
+		{name: "Overflow", input: "999999999999999999999", wantErr: ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "0.00", Amount(0).String())
+	assert.Equal(t, "0.30", (MustParse("0.1") + MustParse("0.2")).String())
+	assert.Equal(t, "100.00", FromInt(100).String())
+	assert.Equal(t, "-0.05", Amount(-5).String())
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var v struct {
+		Amount Amount `json:"amount"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"10.50"}`), &v))
+	assert.Equal(t, Amount(1050), v.Amount)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":0.1}`), &v))
+	assert.Equal(t, Amount(10), v.Amount)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":10.555}`), &v), ErrTooManyFractionDigits)
+
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":"0.10"}`, string(data))
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"9999999999999.99"}`), &v))
+	assert.Equal(t, MaxAmount, v.Amount)
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"-9999999999999.99"}`), &v))
+	assert.Equal(t, -MaxAmount, v.Amount)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"10000000000000"}`), &v), ErrAmountOverflow)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":-10000000000000.00}`), &v), ErrAmountOverflow)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"90000000000000000"}`), &v), ErrAmountOverflow)
+}
+
+func TestAmount_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Amount
+		wantErr bool
+	}{
+		{name: "Numeric Bytes", src: []byte("100.00"), want: 10000},
+		{name: "String", src: "0.07", want: 7},
+		{name: "Int64", src: int64(3), want: 300},
+		{name: "Float64", src: 20.1, want: 2010},
+		{name: "Nil", src: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Amount
+			err := got.Scan(tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCurrency(t *testing.T) {
+	usd, ok := LookupCurrency("usd")
+	assert.True(t, ok)
+	assert.Equal(t, Currency{Code: "USD", Precision: 2}, usd)
+	jpy, ok := LookupCurrency("JPY")
+	assert.True(t, ok)
+
+	_, ok = LookupCurrency("XXX")
+	assert.False(t, ok)
+
+	assert.True(t, usd.Fits(MustParse("10.55")))
+	assert.True(t, jpy.Fits(MustParse("1050")))
+	assert.False(t, jpy.Fits(MustParse("10.50")))
+
+	assert.Equal(t, MustParse("10.55"), usd.Round(MustParse("10.55")))
+	assert.Equal(t, MustParse("11"), jpy.Round(MustParse("10.50")))
+	assert.Equal(t, MustParse("10"), jpy.Round(MustParse("10.49")))
+	assert.Equal(t, MustParse("-11"), jpy.Round(MustParse("-10.50")))
+	assert.Equal(t, MustParse("10"), jpy.Truncate(MustParse("10.99")))
+}
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Rate
+		wantErr bool
+	}{
+		{input: "1", want: Rate(100000000)},
+		{input: "0.9177", want: Rate(91770000)},
+		{input: "151.35", want: Rate(15135000000)},
+		{input: "0.00000001", want: Rate(1)},
+		{input: "0.000000001", wantErr: true},
+		{input: "0", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "1e2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRate(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRate)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	rate := MustParseRate("0.9177")
+	assert.Equal(t, "0.91770000", rate.String())
+	data, err := json.Marshal(rate)
+	assert.NoError(t, err)
+	assert.Equal(t, `"0.91770000"`, string(data))
+
+	converted, err := rate.Convert(MustParse("10.50"))
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("9.63"), converted) // 9.63585 округляется вниз
+
+	converted, err = MustParseRate("151.35").Convert(MustParse("10.00"))
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("1513.50"), converted)
+
+	cross, err := MustParseRate("0.92").Div(MustParseRate("150"))
+	assert.NoError(t, err)
+	assert.Equal(t, MustParseRate("0.00613333"), cross)
+
+	_, err = MustParseRate("1000").Convert(Amount(math.MaxInt64 / 10))
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+}
@@ -0,0 +1,131 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale — количество знаков после запятой, с которым хранятся курсы обмена (соответствует DECIMAL(20, 8) в БД).
+const RateScale = 8
+
+const rateUnit = 100000000 // 10^RateScale
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate — курс обмена: сколько единиц валюты зачисления приходится на одну единицу валюты списания,
+// в 10^-RateScale долях. Как и Amount, кодируется в JSON строкой ("0.91770000"), а в БД передается как NUMERIC.
+type Rate int64
+
+// ParseRate разбирает десятичную запись положительного курса вида "1", "0.9177" или "151.35".
+// Запись с более чем RateScale знаками после запятой отклоняется.
+func ParseRate(s string) (Rate, error) {
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" || (hasDot && fracPart == "") || !isDigits(intPart) || !isDigits(fracPart) || len(fracPart) > RateScale {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units > math.MaxInt64/rateUnit {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	fracPart += strings.Repeat("0", RateScale-len(fracPart))
+	minor, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	rate := Rate(units*rateUnit + minor)
+	if rate == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
+
+// MustParseRate работает как ParseRate, но паникует при ошибке. Предназначена для констант и тестов.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Div возвращает кросс-курс r/d, округленный вниз до RateScale знаков. Например, если r — курс USD/EUR,
+// а d — курс USD/JPY, результат — курс JPY/EUR. Слишком малый или большой результат возвращается как ErrInvalidRate.
+func (r Rate) Div(d Rate) (Rate, error) {
+	v := new(big.Int).Mul(big.NewInt(int64(r)), big.NewInt(rateUnit))
+	v.Quo(v, big.NewInt(int64(d)))
+	if !v.IsInt64() || v.Sign() <= 0 {
+		return 0, ErrInvalidRate
+	}
+	return Rate(v.Int64()), nil
+}
+
+// Convert возвращает сумму a, пересчитанную по курсу r и округленную вниз до Scale знаков.
+// Округление вниз гарантирует, что при конвертации не зачисляется больше, чем дает курс.
+func (r Rate) Convert(a Amount) (Amount, error) {
+	v := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(r)))
+	v.Quo(v, big.NewInt(rateUnit))
+	if !v.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	return Amount(v.Int64()), nil
+}
+
+// String возвращает десятичную запись курса с RateScale знаками после запятой, например "0.91770000".
+func (r Rate) String() string {
+	return fmt.Sprintf("%d.%0*d", r/rateUnit, RateScale, r%rateUnit)
+}
+
+// MarshalJSON кодирует курс строкой, чтобы клиенты не теряли точность при разборе в float.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+// UnmarshalJSON принимает курс как строкой ("0.9177"), так и числом (0.9177).
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRate, s)
+		}
+		s = unquoted
+	}
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan реализует sql.Scanner для чтения значений NUMERIC из БД.
+func (r *Rate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return r.scanString(string(v))
+	case string:
+		return r.scanString(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T into Rate", src)
+	}
+}
+
+func (r *Rate) scanString(s string) error {
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value реализует driver.Valuer: курс передается в БД десятичной строкой без потери точности.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}